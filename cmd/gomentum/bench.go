@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"gomentum/internal/planner"
+)
+
+// benchWords make up task titles so text searches hit a realistic share
+var benchWords = []string{"review", "write", "call", "plan", "report", "design", "fix", "email", "meeting", "read"}
+
+// runBench fills a scratch database with tasks and times the planner
+// operations the TUI, reminders and agent rely on
+func runBench(args []string) int {
+	usage := func() int {
+		fmt.Fprintln(os.Stderr, "Usage: gomentum bench [--tasks 10000] [--runs 20]")
+		return 2
+	}
+	n, runs := 10000, 20
+	for i := 0; i < len(args); i++ {
+		if i+1 >= len(args) {
+			return usage()
+		}
+		v, err := strconv.Atoi(args[i+1])
+		if err != nil || v <= 0 {
+			return usage()
+		}
+		switch args[i] {
+		case "--tasks":
+			n = v
+		case "--runs":
+			runs = v
+		default:
+			return usage()
+		}
+		i++
+	}
+
+	dir, err := os.MkdirTemp("", "gomentum-bench-")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+	p, err := planner.NewPlanner(filepath.Join(dir, "bench.db"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer p.Close()
+
+	now := time.Now()
+	rng := rand.New(rand.NewPCG(1, 2))
+	start := time.Now()
+	if _, err := p.AddTasks(benchTasks(rng, n, now)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Populated %d tasks in %v\n\n", n, time.Since(start).Round(time.Millisecond))
+
+	export := filepath.Join(dir, "plan.md")
+	ops := []struct {
+		name string
+		fn   func() error
+	}{
+		{"ListTasks", func() error { _, err := p.ListTasks(); return err }},
+		{"GetUpcomingTasks", func() error { _, err := p.GetUpcomingTasks(10 * time.Minute); return err }},
+		{"CheckOverlap", func() error {
+			at := now.Add(time.Duration(rng.IntN(30*24)) * time.Hour)
+			_, err := p.CheckOverlap(at, at.Add(time.Hour), 0)
+			return err
+		}},
+		{"QueryTasks (text)", func() error {
+			_, err := p.QueryTasks(planner.Filter{Text: benchWords[rng.IntN(len(benchWords))]})
+			return err
+		}},
+		{"QueryTasks (week)", func() error {
+			from := now.AddDate(0, 0, -rng.IntN(300))
+			_, err := p.QueryTasks(planner.Filter{From: from, To: from.AddDate(0, 0, 7)})
+			return err
+		}},
+		{"ExportToMarkdown", func() error { return p.ExportToMarkdown(export) }},
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "operation\truns\tavg\tp95\tmax")
+	for _, op := range ops {
+		times := make([]time.Duration, runs)
+		for i := range times {
+			start := time.Now()
+			if err := op.fn(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error in %s: %v\n", op.name, err)
+				return 1
+			}
+			times[i] = time.Since(start)
+		}
+		slices.Sort(times)
+		var total time.Duration
+		for _, t := range times {
+			total += t
+		}
+		fmt.Fprintf(w, "%s\t%d\t%v\t%v\t%v\n", op.name, runs,
+			benchRound(total/time.Duration(runs)), benchRound(times[(runs*95-1)/100]), benchRound(times[runs-1]))
+	}
+	w.Flush()
+	return 0
+}
+
+// benchTasks spreads n tasks over the past year and the next month. Past
+// tasks are reminded and mostly completed, like in a database that has
+// been in use.
+func benchTasks(rng *rand.Rand, n int, now time.Time) []planner.Task {
+	from := now.AddDate(-1, 0, 0)
+	span := now.AddDate(0, 1, 0).Sub(from)
+	tasks := make([]planner.Task, n)
+	for i := range tasks {
+		start := from.Add(time.Duration(rng.Int64N(int64(span)))).Truncate(15 * time.Minute)
+		t := planner.Task{
+			Title:     fmt.Sprintf("%s %s #%d", benchWords[rng.IntN(len(benchWords))], benchWords[rng.IntN(len(benchWords))], i),
+			StartTime: start,
+			EndTime:   start.Add(time.Duration(30+15*rng.IntN(7)) * time.Minute),
+			Status:    planner.StatusPending,
+			Priority:  planner.Priorities[rng.IntN(len(planner.Priorities))],
+			Flexible:  rng.IntN(4) > 0,
+		}
+		if start.Before(now) {
+			t.Reminded = true
+			if rng.IntN(10) < 8 {
+				t.Status = planner.StatusCompleted
+			}
+		}
+		tasks[i] = t
+	}
+	return tasks
+}
+
+func benchRound(d time.Duration) time.Duration {
+	if d > time.Millisecond {
+		return d.Round(10 * time.Microsecond)
+	}
+	return d.Round(time.Microsecond)
+}
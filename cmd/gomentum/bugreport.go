@@ -0,0 +1,143 @@
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"gomentum/internal/config"
+	"gomentum/internal/planner"
+	"gomentum/internal/version"
+)
+
+const (
+	// maxLogTail is how much of the end of the log goes into a report
+	maxLogTail = 2 << 20
+	// maxPanics is how many of the most recent panics are extracted
+	maxPanics = 5
+	// panicLogMsg marks panics logged by main
+	panicLogMsg = `msg="Recovered from panic"`
+)
+
+// runBugreport zips diagnostics to attach to a GitHub issue
+func runBugreport(args []string) int {
+	if len(args) > 1 {
+		fmt.Fprintln(os.Stderr, "Usage: gomentum bugreport [file.zip]")
+		return 2
+	}
+	out := fmt.Sprintf("gomentum-bugreport-%s.zip", time.Now().Format("20060102-150405"))
+	if len(args) == 1 {
+		out = args[0]
+	}
+
+	cfg, dir, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+
+	add := func(name string, data []byte) {
+		w, err := zw.Create(name)
+		if err == nil {
+			_, err = w.Write(data)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to add %s: %v\n", name, err)
+		}
+	}
+	// Missing pieces are noted in the report instead of failing it
+	missing := func(name string, err error) {
+		add(name, []byte(fmt.Sprintf("unavailable: %v\n", err)))
+	}
+
+	add("info.txt", []byte(fmt.Sprintf("gomentum %s\ngo %s\nos %s/%s\ngenerated %s\n",
+		version.Version, runtime.Version(), runtime.GOOS, runtime.GOARCH, time.Now().Format(time.RFC3339))))
+
+	if data, err := config.Redacted(cfg); err != nil {
+		missing("config.yaml", err)
+	} else {
+		add("config.yaml", data)
+	}
+
+	if p, err := planner.NewPlanner(cfg.Database.Path); err != nil {
+		missing("schema.sql", err)
+	} else {
+		schema, err := p.Schema()
+		p.Close()
+		if err != nil {
+			missing("schema.sql", err)
+		} else {
+			add("schema.sql", []byte(schema))
+		}
+	}
+
+	logPath := filepath.Join(dir, "gomentum.log")
+	if tail, err := readTail(logPath, maxLogTail); err != nil {
+		missing("gomentum.log", err)
+	} else {
+		add("gomentum.log", tail)
+		add("panics.txt", recentPanics(tail, maxPanics))
+	}
+
+	if err := zw.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Bug report written to %s\n", out)
+	fmt.Println("Secrets in the config are redacted, but the log may mention task titles; review it before attaching the file to an issue.")
+	return 0
+}
+
+// readTail returns up to max bytes from the end of a file
+func readTail(path string, max int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > max {
+		if _, err := f.Seek(-max, io.SeekEnd); err != nil {
+			return nil, err
+		}
+	}
+	return io.ReadAll(f)
+}
+
+// recentPanics extracts the last n panic entries from the log, most recent last
+func recentPanics(log []byte, n int) []byte {
+	var panics []string
+	sc := bufio.NewScanner(bytes.NewReader(log))
+	sc.Buffer(make([]byte, 64*1024), 1<<20)
+	for sc.Scan() {
+		if line := sc.Text(); strings.Contains(line, panicLogMsg) {
+			// Stacks are logged as one quoted value; restore the line breaks
+			panics = append(panics, strings.ReplaceAll(strings.ReplaceAll(line, `\n`, "\n"), `\t`, "\t"))
+		}
+	}
+	if len(panics) > n {
+		panics = panics[len(panics)-n:]
+	}
+	if len(panics) == 0 {
+		return []byte("No panics found in the log.\n")
+	}
+	return []byte(strings.Join(panics, "\n\n") + "\n")
+}
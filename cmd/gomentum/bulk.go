@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"gomentum/internal/planner"
+)
+
+// runBulk completes, purges or shifts many tasks in one transaction
+func runBulk(args []string) int {
+	usage := func() int {
+		fmt.Fprintln(os.Stderr, "Usage: gomentum bulk complete <YYYY-MM-DD> [YYYY-MM-DD] | purge <days> | shift <minutes>")
+		return 2
+	}
+	if len(args) < 2 {
+		return usage()
+	}
+
+	var run func(p *planner.Planner) (string, error)
+	switch args[0] {
+	case "complete":
+		if len(args) > 3 {
+			return usage()
+		}
+		from, err := time.ParseInLocation("2006-01-02", args[1], time.Local)
+		if err != nil {
+			return usage()
+		}
+		to := from
+		if len(args) == 3 {
+			if to, err = time.ParseInLocation("2006-01-02", args[2], time.Local); err != nil || to.Before(from) {
+				return usage()
+			}
+		}
+		run = func(p *planner.Planner) (string, error) {
+			n, err := p.CompleteRange(from, to.AddDate(0, 0, 1))
+			return fmt.Sprintf("Marked %d tasks as completed", n), err
+		}
+
+	case "purge":
+		days, err := strconv.Atoi(args[1])
+		if err != nil || days < 0 || len(args) != 2 {
+			return usage()
+		}
+		run = func(p *planner.Planner) (string, error) {
+			n, err := p.PurgeCompleted(time.Now().AddDate(0, 0, -days))
+			return fmt.Sprintf("Deleted %d completed tasks", n), err
+		}
+
+	case "shift":
+		minutes, err := strconv.Atoi(args[1])
+		if err != nil || minutes == 0 || len(args) != 2 {
+			return usage()
+		}
+		run = func(p *planner.Planner) (string, error) {
+			moved, fixed, err := p.ShiftRemainingToday(time.Now(), time.Duration(minutes)*time.Minute)
+			msg := fmt.Sprintf("Moved %d tasks by %d minutes", moved, minutes)
+			if fixed > 0 {
+				msg += fmt.Sprintf("; %d fixed tasks were left in place", fixed)
+			}
+			return msg, err
+		}
+
+	default:
+		return usage()
+	}
+
+	cfg, _, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+
+	p, err := planner.NewPlanner(cfg.Database.Path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer p.Close()
+
+	msg, err := run(p)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Println(msg)
+	return 0
+}
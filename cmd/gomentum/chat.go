@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"gomentum/internal/agent"
+	gmcp "gomentum/internal/mcp"
+	"gomentum/internal/planner"
+)
+
+// runChat runs a single agent turn and prints the reply. It is meant for
+// scripts and editor integrations: the reply goes to stdout, errors to
+// stderr, and the exit code reports success.
+func runChat(args []string) int {
+	stream := false
+	var words []string
+	for _, a := range args {
+		switch a {
+		case "--stream":
+			stream = true
+		default:
+			words = append(words, a)
+		}
+	}
+
+	prompt := strings.TrimSpace(strings.Join(words, " "))
+	if prompt == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
+			return 1
+		}
+		prompt = strings.TrimSpace(string(data))
+	}
+	if prompt == "" {
+		fmt.Fprintln(os.Stderr, "Usage: gomentum chat [--stream] <message | ->")
+		return 2
+	}
+
+	cfg, _, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+	p, err := planner.NewPlanner(cfg.Database.Path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer p.Close()
+
+	ag, err := agent.NewAgent(cfg, gmcp.NewServer(p), p)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var onToken func(string)
+	if stream {
+		onToken = func(tok string) { fmt.Print(tok) }
+	}
+	resp, err := ag.Chat(ctx, prompt, onToken)
+	if err != nil {
+		if stream {
+			fmt.Println()
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if stream {
+		fmt.Println()
+	} else {
+		fmt.Println(strings.TrimSpace(resp.Text))
+	}
+	return 0
+}
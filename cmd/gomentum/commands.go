@@ -0,0 +1,390 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gomentum/internal/calendar"
+	"gomentum/internal/config"
+	"gomentum/internal/daemon"
+	"gomentum/internal/i18n"
+	"gomentum/internal/instance"
+	"gomentum/internal/netconf"
+	"gomentum/internal/planner"
+	"gomentum/internal/projects"
+	"gomentum/internal/schedule"
+	"gomentum/internal/stats"
+	"gomentum/internal/telemetry"
+	"gomentum/internal/tray"
+	"gomentum/internal/update"
+	"gomentum/internal/version"
+)
+
+// command is a CLI subcommand. The table drives dispatch, help output and
+// shell completion.
+type command struct {
+	name    string
+	args    string // Argument synopsis shown in help
+	summary string
+	// Subcommands and flags offered by shell completion
+	completions []string
+	// Whether the first argument is a task ID (completed dynamically)
+	taskArg bool
+	run     func(args []string) int
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{name: "quickadd", args: "<text> | --popup", summary: "Send a request to the running Gomentum instance, or ask for it in a popup", completions: []string{"--popup"}, run: runQuickAdd},
+		{name: "chat", args: "[--stream] <message | ->", summary: "Run one agent turn, print the reply and exit", completions: []string{"--stream"}, run: runChat},
+		{name: "done", args: "<id>", summary: "Mark a task as completed", taskArg: true, run: runDone},
+		{name: "rpc", summary: "Speak line-delimited JSON-RPC 2.0 on stdin/stdout for editor plugins (alias: --json-rpc)", run: runRPC},
+		{name: "invite", args: "<id> <email>...", summary: "Email a calendar invite (.ics) for a task via SMTP", taskArg: true, run: runInvite},
+		{name: "export", args: "markdown [file] | ics [file] | csv [file] | qr [--share] [--invert]", summary: "Export the plan to markdown, iCalendar or CSV, or show today's plan as a QR code", completions: []string{"markdown", "ics", "csv", "qr", "--share", "--invert"}, run: runExport},
+		{name: "share", args: "day|week [date] | list | revoke <token>", summary: "Create a read-only link to a day or week of the plan (server mode)", completions: []string{"day", "week", "list", "revoke", "--details", "--expires"}, run: runShare},
+		{name: "bulk", args: "complete <from> [to] | purge <days> | shift <min>", summary: "Complete a date range, delete old finished tasks or shift the rest of today", completions: []string{"complete", "purge", "shift"}, run: runBulk},
+		{name: "workflow", args: "list | apply <id> | discard <id>", summary: "Review, apply or drop multi-step plans the agent proposed", completions: []string{"list", "apply", "discard"}, run: runWorkflow},
+		{name: "sql", args: "<SELECT ... | ->", summary: "Run a read-only SQL query against the tasks database and print a table", run: runSQL},
+		{name: "import", args: "<format> [--dry-run] <file | ->", summary: "Import tasks from another tool (taskwarrior) or screen time (activitywatch, rescuetime)", completions: importFormats, run: runImport},
+		{name: "reminders", args: "pull | push <id>", summary: "Import the Apple Reminders inbox list or push a task to it (macOS)", completions: []string{"pull", "push"}, run: runReminders},
+		{name: "outlook", args: "login | logout | sync", summary: "Sign in to Microsoft Graph or sync the Outlook calendar now", completions: []string{"login", "logout", "sync"}, run: runOutlook},
+		{name: "daemon", args: "[install [--print]]", summary: "Run reminders and the server in the background (no TUI)", completions: []string{"install"}, run: runDaemon},
+		{name: "serve", summary: "Container entrypoint: daemon with the HTTP/SSE server enabled, logging to stderr", run: runServe},
+		{name: "tray", summary: "Run the daemon with a tray icon and quick-add popup (Windows)", run: func([]string) int { return runTray() }},
+		{name: "update", args: "[--check] [--force]", summary: "Download and install the latest release", completions: []string{"--check", "--force"}, run: runUpdate},
+		{name: "bench", args: "[--tasks N] [--runs N]", summary: "Time planner queries against a scratch database filled with N tasks", completions: []string{"--tasks", "--runs"}, run: runBench},
+		{name: "completion", args: "<shell>", summary: "Print a completion script (bash, zsh, fish, powershell)", completions: completionShells, run: runCompletion},
+		{name: "bugreport", args: "[file.zip]", summary: "Zip the log, redacted config, schema and recent panics for a GitHub issue", run: runBugreport},
+		{name: "env", summary: "List the GOMENTUM_* environment variables that override config keys", run: runEnv},
+		{name: "version", summary: "Print the version", run: func([]string) int {
+			fmt.Println("gomentum", version.Version)
+			return 0
+		}},
+		{name: "help", summary: "Show this help", run: func([]string) int {
+			printUsage()
+			return 0
+		}},
+	}
+}
+
+// runCommand dispatches a CLI subcommand and returns the process exit code
+func runCommand(name string, args []string) int {
+	switch name {
+	case "-h", "--help":
+		name = "help"
+	case "--version":
+		name = "version"
+	case "--json-rpc":
+		name = "rpc"
+	case completeCommand:
+		return runComplete(args)
+	}
+
+	for _, c := range commands {
+		if c.name == name {
+			defer telemetry.Count("cmd." + name)
+			return c.run(args)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", name)
+	printUsage()
+	return 2
+}
+
+func printUsage() {
+	fmt.Println("Usage: gomentum [--plain] [command]")
+	fmt.Println()
+	fmt.Println("Without a command, the interactive TUI is started.")
+	fmt.Println("--plain uses linear, screen-reader friendly output instead.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	for _, c := range commands {
+		fmt.Printf("  %-32s %s\n", strings.TrimSpace(c.name+" "+c.args), c.summary)
+	}
+}
+
+// runQuickAdd forwards text to the running instance, which hands it to its agent
+func runQuickAdd(args []string) int {
+	if slices.Contains(args, "--popup") {
+		return runQuickAddPopup()
+	}
+	text := strings.TrimSpace(strings.Join(args, " "))
+	if text == "" {
+		fmt.Fprintln(os.Stderr, "Usage: gomentum quickadd <text> | --popup")
+		return 2
+	}
+
+	dir, err := config.DefaultDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	resp, err := instance.Send(dir, instance.Request{Command: "quickadd", Text: text})
+	if errors.Is(err, instance.ErrNotRunning) {
+		fmt.Fprintln(os.Stderr, "Gomentum is not running. Start it first with `gomentum`.")
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if !resp.OK {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Message)
+		return 1
+	}
+
+	fmt.Println(resp.Message)
+	return 0
+}
+
+// loadConfig loads ~/.gomentum/config.yaml for commands that run without the TUI.
+// Inside a git repository, database.project may switch to the project database.
+func loadConfig() (*config.Config, string, error) {
+	dir, err := config.DefaultDir()
+	if err != nil {
+		return nil, "", err
+	}
+	cfg, err := config.LoadConfig(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		return nil, "", err
+	}
+	// Commands never prompt, so ask mode only picks up an existing project database
+	if _, err := cfg.UseProjectDatabase(nil); err != nil {
+		return nil, "", err
+	}
+	if err := i18n.Init(cfg.Locale); err != nil {
+		return nil, "", err
+	}
+	i18n.Configure(cfg.Format)
+	telemetry.Init(cfg.Telemetry, dir)
+	if err := netconf.Configure(cfg.Network); err != nil {
+		return nil, "", err
+	}
+	calendar.SetSecondary(cfg.SecondaryCalendar)
+	schedule.Configure(cfg.Schedule)
+	stats.Configure(cfg.Activity)
+	projects.Configure(cfg.TagIcons.Field, cfg.Projects)
+	planner.ConfigureStatuses(cfg.Statuses)
+	return cfg, dir, nil
+}
+
+func runDaemon(args []string) int {
+	if len(args) > 0 && args[0] == "install" {
+		return runDaemonInstall(args[1:])
+	}
+
+	cfg, dir, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+	if err := daemon.Run(cfg, dir); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func runDaemonInstall(args []string) int {
+	printOnly := len(args) > 0 && args[0] == "--print"
+
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error locating executable: %v\n", err)
+		return 1
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	dir, err := config.DefaultDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	sf, err := daemon.GenerateService(exe, dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if printOnly {
+		fmt.Print(sf.Content)
+		return 0
+	}
+
+	if err := daemon.InstallService(sf); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Service file written to %s\n", sf.Path)
+	fmt.Printf("Enable it with:\n  %s\n", sf.Activate)
+	return 0
+}
+
+func runTray() int {
+	if runtime.GOOS != "windows" {
+		fmt.Fprintln(os.Stderr, "Tray mode is only available on Windows. Use `gomentum daemon` instead.")
+		return 1
+	}
+
+	cfg, dir, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+	d, err := daemon.New(cfg, dir, "tray")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer d.Close()
+
+	if err := tray.Run(d); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func runUpdate(args []string) int {
+	checkOnly, force := false, false
+	for _, a := range args {
+		switch a {
+		case "--check":
+			checkOnly = true
+		case "--force":
+			force = true
+		default:
+			fmt.Fprintf(os.Stderr, "Unknown flag: %s\n", a)
+			return 2
+		}
+	}
+
+	// The network switch is honoured even without a valid LLM config
+	if cfg, _, err := loadConfig(); err == nil && cfg.Update.DisableNetwork {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", update.ErrNetworkDisabled)
+		return 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	u := update.NewUpdater()
+	rel, err := u.Latest(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Current version: %s\nLatest release:  %s\n", version.Version, rel.Tag)
+	if !force && !update.Newer(rel.Tag, version.Version) {
+		if version.Version == "dev" {
+			fmt.Println("This is a development build; use --force to replace it with the release.")
+		} else {
+			fmt.Println("Gomentum is up to date.")
+		}
+		return 0
+	}
+	if checkOnly {
+		fmt.Printf("An update is available: %s\n", rel.URL)
+		return 0
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error locating executable: %v\n", err)
+		return 1
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+
+	fmt.Println("Downloading and verifying...")
+	if err := u.Apply(ctx, rel, exe); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Updated to %s. Restart Gomentum to use the new version.\n", rel.Tag)
+	return 0
+}
+
+// runDone marks a task as completed
+func runDone(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: gomentum done <id>")
+		return 2
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid task ID: %s\n", args[0])
+		return 2
+	}
+
+	cfg, _, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+	p, err := planner.NewPlanner(cfg.Database.Path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer p.Close()
+
+	task, err := p.GetTask(id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	task.Status = planner.StatusCompleted
+	if err := p.UpdateTask(task); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Completed task %d: %s\n", task.ID, task.Title)
+	return 0
+}
+
+// runEnv prints every supported environment override
+func runEnv([]string) int {
+	fmt.Println("Every config key can be set through the environment or a .env file")
+	fmt.Println("(in the working directory or ~/.gomentum). Environment variables win over config.yaml.")
+	fmt.Println()
+	for _, v := range config.EnvVars() {
+		fmt.Printf("  %-36s %s\n", v.Name, v.Type)
+	}
+	return 0
+}
+
+// runServe is the Docker entrypoint. Configuration comes from the
+// environment; logs go to stderr so `docker logs` shows them.
+func runServe([]string) int {
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	})))
+
+	cfg, dir, err := loadConfig()
+	if err != nil {
+		slog.Error("Failed to load config", "error", err)
+		return 1
+	}
+	if err := daemon.Serve(cfg, dir); err != nil {
+		slog.Error("Server failed", "error", err)
+		return 1
+	}
+	return 0
+}
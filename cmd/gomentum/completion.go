@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gomentum/internal/planner"
+)
+
+// completeCommand is the hidden command the completion scripts call back into.
+// It receives the words after "gomentum" (the last one being the word under the
+// cursor) and prints one "value<TAB>description" candidate per line.
+const completeCommand = "__complete"
+
+var completionShells = []string{"bash", "zsh", "fish", "powershell"}
+
+const bashCompletion = `# gomentum bash completion
+# Add to ~/.bashrc: source <(gomentum completion bash)
+_gomentum() {
+    local IFS=$'\n'
+    COMPREPLY=($(gomentum __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null | cut -f1))
+}
+complete -F _gomentum gomentum
+`
+
+const zshCompletion = `#compdef gomentum
+# Add to ~/.zshrc: source <(gomentum completion zsh)
+_gomentum() {
+    local -a candidates
+    local line
+    for line in "${(@f)$(gomentum __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}"; do
+        [[ -n "$line" ]] && candidates+=("${line/$'\t'/:}")
+    done
+    _describe 'gomentum' candidates
+}
+compdef _gomentum gomentum
+`
+
+const fishCompletion = `# gomentum fish completion
+# Save as ~/.config/fish/completions/gomentum.fish
+function __gomentum_complete
+    set -l tokens (commandline -opc) (commandline -ct)
+    gomentum __complete $tokens[2..-1] 2>/dev/null
+end
+complete -c gomentum -f -a '(__gomentum_complete)'
+`
+
+const powershellCompletion = `# gomentum PowerShell completion
+# Add to $PROFILE: gomentum completion powershell | Out-String | Invoke-Expression
+Register-ArgumentCompleter -Native -CommandName gomentum, gomentum.exe -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+    $words = @($commandAst.CommandElements | Select-Object -Skip 1 | ForEach-Object { $_.ToString() })
+    if ($wordToComplete -eq '') { $words += '""' }
+    & gomentum __complete @words 2>$null | ForEach-Object {
+        $value, $desc = $_ -split "` + "`" + `t", 2
+        if (-not $desc) { $desc = $value }
+        [System.Management.Automation.CompletionResult]::new($value, $value, 'ParameterValue', $desc)
+    }
+}
+`
+
+// runCompletion prints the completion script for a shell
+func runCompletion(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: gomentum completion <%s>\n", strings.Join(completionShells, "|"))
+		return 2
+	}
+
+	switch args[0] {
+	case "bash":
+		fmt.Print(bashCompletion)
+	case "zsh":
+		fmt.Print(zshCompletion)
+	case "fish":
+		fmt.Print(fishCompletion)
+	case "powershell", "pwsh":
+		fmt.Print(powershellCompletion)
+	default:
+		fmt.Fprintf(os.Stderr, "Unsupported shell: %s\n", args[0])
+		return 2
+	}
+	return 0
+}
+
+// runComplete prints completion candidates for the words typed so far
+func runComplete(words []string) int {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	current := strings.Trim(words[len(words)-1], `"'`)
+	prior := words[:len(words)-1]
+
+	var candidates []string
+	if len(prior) == 0 {
+		for _, c := range commands {
+			candidates = append(candidates, c.name+"\t"+c.summary)
+		}
+	} else {
+		for _, c := range commands {
+			if c.name != prior[0] || len(prior) != 1 {
+				continue
+			}
+			if c.taskArg {
+				candidates = append(candidates, taskCandidates()...)
+			}
+			candidates = append(candidates, c.completions...)
+		}
+	}
+
+	for _, cand := range candidates {
+		if strings.HasPrefix(cand, current) {
+			fmt.Println(cand)
+		}
+	}
+	return 0
+}
+
+// taskCandidates lists open tasks as "id<TAB>title" pairs. Errors are
+// swallowed: completion must never print noise into the shell.
+func taskCandidates() []string {
+	cfg, _, err := loadConfig()
+	if err != nil {
+		return nil
+	}
+	p, err := planner.NewPlanner(cfg.Database.Path)
+	if err != nil {
+		return nil
+	}
+	defer p.Close()
+
+	tasks, err := p.ListTasks()
+	if err != nil {
+		return nil
+	}
+
+	var out []string
+	for _, t := range tasks {
+		if t.Status.Done() {
+			continue
+		}
+		title := strings.ReplaceAll(t.Title, "\t", " ")
+		out = append(out, strconv.Itoa(t.ID)+"\t"+title)
+	}
+	return out
+}
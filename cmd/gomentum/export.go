@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gomentum/internal/exporter"
+	"gomentum/internal/i18n"
+	"gomentum/internal/planner"
+	"gomentum/internal/schedule"
+	"gomentum/internal/server"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// maxQRTitle keeps the compact plan small enough for a scannable code
+const maxQRTitle = 40
+
+// exportFiles are the default file names per export format
+var exportFiles = map[string]string{exporter.Markdown: "plan.md", exporter.ICS: "schedule.ics", exporter.CSV: "tasks.csv"}
+
+// runExport writes the plan to a markdown, iCalendar or CSV file or shows
+// it as a QR code
+func runExport(args []string) int {
+	usage := func() int {
+		fmt.Fprintln(os.Stderr, "Usage: gomentum export markdown [file] | ics [file] | csv [file] | qr [--share] [--invert]")
+		return 2
+	}
+	if len(args) == 0 || (exportFiles[args[0]] == "" && args[0] != "qr") {
+		return usage()
+	}
+
+	cfg, _, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+	p, err := planner.NewPlanner(cfg.Database.Path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer p.Close()
+
+	if args[0] != "qr" {
+		filename := exportFiles[args[0]]
+		if len(args) > 1 {
+			filename = args[1]
+		}
+		tasks, err := p.ListTasks()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		f, err := os.Create(filename)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		err = exporter.Write(f, args[0], tasks)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Tasks exported to %s\n", filename)
+		return 0
+	}
+
+	share, invert := false, false
+	for _, arg := range args[1:] {
+		switch arg {
+		case "--share":
+			share = true
+		case "--invert":
+			invert = true
+		default:
+			return usage()
+		}
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var content string
+	if share {
+		s, err := p.CreateShare(today, today.AddDate(0, 0, 1), false, now.Add(7*24*time.Hour))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		content = server.ShareURL(cfg.Server, s.Token)
+	} else {
+		tasks, err := p.ListTasks()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		content = compactPlan(tasks, today)
+	}
+
+	q, err := qrcode.New(content, qrcode.Low)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Print(q.ToSmallString(invert))
+	if share {
+		fmt.Println(content)
+	}
+	return 0
+}
+
+// compactPlan renders the day's tasks one per line, e.g. "09:00-10:00 Standup"
+func compactPlan(tasks []planner.Task, day time.Time) string {
+	end := day.AddDate(0, 0, 1)
+	lines := []string{i18n.FormatDate(day)}
+	for _, t := range tasks {
+		if !schedule.Busy(t) || !t.StartTime.Before(end) || !t.EndTime.After(day) {
+			continue
+		}
+		title := []rune(t.Title)
+		if len(title) > maxQRTitle {
+			title = append(title[:maxQRTitle-1], '…')
+		}
+		lines = append(lines, fmt.Sprintf("%s-%s %s", i18n.FormatTime(t.StartTime), i18n.FormatTime(t.EndTime), string(title)))
+	}
+	if len(lines) == 1 {
+		lines = append(lines, i18n.T("plain.no_tasks"))
+	}
+	return strings.Join(lines, "\n")
+}
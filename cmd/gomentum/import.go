@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"gomentum/internal/importer"
+	"gomentum/internal/planner"
+	"gomentum/internal/schedule"
+)
+
+// importFormats lists the task formats, then the screen time ones
+var importFormats = append([]string{"taskwarrior"}, importer.ActivityFormats()...)
+
+// runImport reads a foreign export and adds its tasks, or the screen time
+// of an activity format, to the database
+func runImport(args []string) int {
+	dryRun := false
+	var positional []string
+	for _, a := range args {
+		if a == "--dry-run" {
+			dryRun = true
+		} else {
+			positional = append(positional, a)
+		}
+	}
+	if len(positional) != 2 {
+		fmt.Fprintln(os.Stderr, "Usage: gomentum import <format> [--dry-run] <file | ->")
+		fmt.Fprintf(os.Stderr, "Formats: %s\n", strings.Join(importFormats, ", "))
+		return 2
+	}
+	format, path := positional[0], positional[1]
+
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		r = f
+	}
+	if importer.IsActivity(format) {
+		return importActivity(format, r, dryRun)
+	}
+
+	var items []importer.Item
+	var res importer.Result
+	var err error
+	switch format {
+	case "taskwarrior":
+		items, res, err = importer.Taskwarrior(r)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown import format: %s\n", format)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	for _, s := range res.Skipped {
+		fmt.Fprintf(os.Stderr, "Skipped %s\n", s)
+	}
+	if dryRun {
+		for _, it := range items {
+			fmt.Printf("%s - %s  %s\n", it.Start.Format("2006-01-02 15:04"), it.End.Format("15:04"), it.Title)
+		}
+		fmt.Printf("%d tasks would be imported\n", len(items))
+		return 0
+	}
+
+	cfg, _, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+	p, err := planner.NewPlanner(cfg.Database.Path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer p.Close()
+
+	n, err := importer.Save(p, items)
+	fmt.Printf("Imported %d tasks\n", n)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// importActivity stores the screen time recorded by a time tracker, for
+// the focus part of get_stats
+func importActivity(format string, r io.Reader, dryRun bool) int {
+	spans, res, err := importer.Activity(format, r)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	for _, s := range res.Skipped {
+		fmt.Fprintf(os.Stderr, "Skipped %s\n", s)
+	}
+	if dryRun {
+		var total time.Duration
+		for _, a := range spans {
+			total += a.End.Sub(a.Start)
+		}
+		if len(spans) > 0 {
+			fmt.Printf("%s - %s\n", spans[0].Start.Format("2006-01-02 15:04"), spans[len(spans)-1].End.Format("2006-01-02 15:04"))
+		}
+		fmt.Printf("%d spans (%s) would be imported\n", len(spans), schedule.FormatHours(total))
+		return 0
+	}
+
+	cfg, _, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+	p, err := planner.NewPlanner(cfg.Database.Path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer p.Close()
+
+	n, err := p.AddActivity(spans)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Imported %d new activity spans (%d already known)\n", n, len(spans)-n)
+	return 0
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"net/mail"
+	"os"
+	"strconv"
+	"time"
+
+	"gomentum/internal/i18n"
+	"gomentum/internal/invite"
+	"gomentum/internal/mailer"
+	"gomentum/internal/planner"
+)
+
+// runInvite mails a calendar invite for a task to the given addresses
+func runInvite(args []string) int {
+	if len(args) < 2 {
+		fmt.Fprintln(os.Stderr, "Usage: gomentum invite <id> <email>...")
+		return 2
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid task ID: %s\n", args[0])
+		return 2
+	}
+	var attendees []*mail.Address
+	for _, arg := range args[1:] {
+		addr, err := mail.ParseAddress(arg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid email address %q: %v\n", arg, err)
+			return 2
+		}
+		attendees = append(attendees, addr)
+	}
+
+	cfg, _, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+	organizer, err := mailer.From(cfg.SMTP)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	p, err := planner.NewPlanner(cfg.Database.Path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer p.Close()
+
+	task, err := p.GetTask(id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	msg := mailer.Message{
+		To:      attendees,
+		Subject: i18n.T("invite.subject", task.Title),
+		Text: i18n.T("invite.body", task.Title, i18n.FormatDateLong(task.StartTime),
+			i18n.FormatTime(task.StartTime), i18n.FormatTime(task.EndTime), task.StartTime.Format("MST"), task.Description),
+		Calendar: invite.Request(task, organizer, attendees, time.Now()),
+	}
+	if err := mailer.Send(cfg.SMTP, msg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Sent an invite for %q to %d recipients\n", task.Title, len(attendees))
+	return 0
+}
@@ -6,7 +6,9 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime/debug"
 
+	"gomentum/internal/telemetry"
 	"gomentum/internal/tui"
 )
 
@@ -14,6 +16,8 @@ func main() {
 	// Global panic handler to prevent window closing on crash
 	defer func() {
 		if r := recover(); r != nil {
+			telemetry.RecordPanic(r)
+			slog.Error("Recovered from panic", "panic", fmt.Sprint(r), "stack", string(debug.Stack()))
 			fmt.Println("Recovered from panic:", r)
 			tui.WaitPressEnter()
 		}
@@ -45,8 +49,25 @@ func main() {
 	}))
 	slog.SetDefault(logger)
 
+	// Global flags come before the subcommand
+	args := os.Args[1:]
+	var opts tui.Options
+	for len(args) > 0 && args[0] == "--plain" {
+		opts.Plain = true
+		args = args[1:]
+	}
+
+	// Subcommands run without the TUI
+	if len(args) > 0 {
+		code := runCommand(args[0], args[1:])
+		if err := telemetry.Save(); err != nil {
+			slog.Warn("Failed to save telemetry", "error", err)
+		}
+		os.Exit(code)
+	}
+
 	fmt.Println("Gomentum: CLI Planning Agent")
-	tui.Start()
+	tui.Start(opts)
 
 	// Pause before exit to keep window open
 	fmt.Println("\nProgram finished.")
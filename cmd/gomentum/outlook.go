@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"gomentum/internal/outlook"
+	"gomentum/internal/planner"
+)
+
+// runOutlook signs in to Microsoft Graph or runs a one-off sync
+func runOutlook(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: gomentum outlook login | logout | sync")
+		return 2
+	}
+
+	cfg, dir, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+	if cfg.LocalOnly && args[0] != "logout" {
+		fmt.Fprintln(os.Stderr, "Outlook is unavailable with local_only set")
+		return 1
+	}
+	auth := outlook.NewAuth(cfg.Outlook, dir)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	switch args[0] {
+	case "login":
+		err = auth.Login(ctx, func(msg string) { fmt.Println(msg) })
+		if err == nil {
+			fmt.Println("Signed in to Outlook.")
+		}
+	case "logout":
+		err = auth.Logout()
+		if err == nil {
+			fmt.Println("Signed out of Outlook.")
+		}
+	case "sync":
+		var p *planner.Planner
+		p, err = planner.NewPlanner(cfg.Database.Path)
+		if err != nil {
+			break
+		}
+		defer p.Close()
+
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		defer cancel()
+		var res outlook.SyncResult
+		res, err = outlook.Sync(ctx, outlook.NewClient(auth), p, cfg.Outlook)
+		if err == nil {
+			fmt.Printf("Busy blocks: %d added, %d updated, %d removed. Tasks pushed: %d\n", res.Added, res.Updated, res.Removed, res.Pushed)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown outlook command: %s\n", args[0])
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
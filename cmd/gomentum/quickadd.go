@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"gomentum/internal/config"
+	"gomentum/internal/instance"
+	"gomentum/internal/planner"
+	"gomentum/internal/popup"
+)
+
+// runQuickAddPopup asks for a request in a popup, for binding to a global
+// hotkey. The running instance hands it to its agent; when none runs, the
+// text is kept in the inbox as a backlog task for /triage-inbox.
+func runQuickAddPopup() int {
+	text, err := popup.Input("Gomentum", "What should I plan?")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if text == "" {
+		return 0
+	}
+
+	dir, err := config.DefaultDir()
+	if err != nil {
+		popup.Notify("Gomentum", "Quick-add failed: "+err.Error())
+		return 1
+	}
+	resp, err := instance.Send(dir, instance.Request{Command: "quickadd", Text: text})
+	switch {
+	case errors.Is(err, instance.ErrNotRunning):
+		if err := captureInbox(text); err != nil {
+			popup.Notify("Gomentum", "Quick-add failed: "+err.Error())
+			return 1
+		}
+		popup.Notify("Gomentum", "Gomentum is not running; saved to the inbox: "+text)
+		return 0
+	case err != nil:
+		popup.Notify("Gomentum", "Quick-add failed: "+err.Error())
+		return 1
+	case !resp.OK:
+		popup.Notify("Gomentum", "Quick-add failed: "+resp.Message)
+		return 1
+	}
+	popup.Notify("Gomentum", resp.Message)
+	return 0
+}
+
+// captureInbox adds text as a backlog task, which blocks no time until
+// it is triaged
+func captureInbox(text string) error {
+	cfg, _, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	p, err := planner.NewPlanner(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+
+	now := time.Now()
+	task, err := p.AddTask(text, "", now, now)
+	if err != nil {
+		return err
+	}
+	task.Status = planner.StatusBacklog
+	return p.UpdateTask(task)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"gomentum/internal/agent"
+	"gomentum/internal/applereminders"
+	gmcp "gomentum/internal/mcp"
+	"gomentum/internal/planner"
+)
+
+// runReminders syncs with Apple Reminders once, outside the daemon
+func runReminders(args []string) int {
+	if len(args) == 0 || (args[0] != "pull" && args[0] != "push") || (args[0] == "push") != (len(args) == 2) {
+		fmt.Fprintln(os.Stderr, "Usage: gomentum reminders pull | push <id>")
+		return 2
+	}
+
+	cfg, _, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+	p, err := planner.NewPlanner(cfg.Database.Path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer p.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	list := cfg.AppleReminders.List
+
+	if args[0] == "push" {
+		id, err := strconv.Atoi(args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid task ID: %s\n", args[1])
+			return 2
+		}
+		task, err := p.GetTask(id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if err := applereminders.Push(ctx, list, task); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Added %q to the %s list\n", task.Title, list)
+		return 0
+	}
+
+	ag, err := agent.NewAgent(cfg, gmcp.NewServer(p), p)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	n, err := applereminders.Pull(ctx, list, p, func(ctx context.Context, text string) error {
+		_, err := ag.Chat(ctx, text, nil)
+		return err
+	})
+	fmt.Printf("Imported %d reminders from %s\n", n, list)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
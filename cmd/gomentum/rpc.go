@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"gomentum/internal/agent"
+	"gomentum/internal/hooks"
+	gmcp "gomentum/internal/mcp"
+	"gomentum/internal/planner"
+	"gomentum/internal/rpc"
+)
+
+// runRPC serves the editor protocol on stdio. Nothing but protocol
+// messages may be written to stdout; diagnostics go to stderr and the log.
+func runRPC([]string) int {
+	cfg, _, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+	p, err := planner.NewPlanner(cfg.Database.Path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer p.Close()
+
+	if err := hooks.Validate(cfg.Hooks); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	engine := hooks.NewEngine(cfg.Hooks)
+	defer engine.Close()
+
+	srv := rpc.NewServer(p, cfg.Focus, engine, func() (agent.Agent, error) {
+		return agent.NewAgent(cfg, gmcp.NewServer(p), p)
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := srv.Serve(ctx, os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gomentum/internal/i18n"
+	"gomentum/internal/planner"
+	"gomentum/internal/server"
+)
+
+// runShare creates, lists and revokes read-only plan links
+func runShare(args []string) int {
+	usage := func() int {
+		fmt.Fprintln(os.Stderr, "Usage: gomentum share day|week [YYYY-MM-DD] [--details] [--expires 168h]")
+		fmt.Fprintln(os.Stderr, "       gomentum share list | revoke <token>")
+		return 2
+	}
+	if len(args) == 0 {
+		return usage()
+	}
+
+	cfg, _, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+	p, err := planner.NewPlanner(cfg.Database.Path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer p.Close()
+
+	switch args[0] {
+	case "list":
+		shares, err := p.ListShares()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		now := time.Now()
+		for _, s := range shares {
+			state := "expires " + i18n.FormatDateTime(s.ExpiresAt)
+			if s.Expired(now) {
+				state = "expired"
+			}
+			fmt.Printf("%s  %s – %s  %s\n", server.ShareURL(cfg.Server, s.Token),
+				i18n.FormatDate(s.From), i18n.FormatDate(s.To.Add(-time.Second)), state)
+		}
+		return 0
+
+	case "revoke":
+		if len(args) != 2 {
+			return usage()
+		}
+		if err := p.RevokeShare(args[1]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Println("Share revoked")
+		return 0
+
+	case "day", "week":
+	default:
+		return usage()
+	}
+
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	details := false
+	expiresIn := 7 * 24 * time.Hour
+	rest := args[1:]
+	for i := 0; i < len(rest); i++ {
+		switch arg := rest[i]; {
+		case arg == "--details":
+			details = true
+		case arg == "--expires" && i+1 < len(rest):
+			i++
+			d, err := time.ParseDuration(rest[i])
+			if err != nil || d <= 0 {
+				fmt.Fprintf(os.Stderr, "Invalid duration: %s\n", rest[i])
+				return 2
+			}
+			expiresIn = d
+		default:
+			d, err := time.ParseInLocation("2006-01-02", arg, now.Location())
+			if err != nil {
+				return usage()
+			}
+			from = d
+		}
+	}
+
+	to := from.AddDate(0, 0, 1)
+	if args[0] == "week" {
+		from = i18n.StartOfWeek(from)
+		to = from.AddDate(0, 0, 7)
+	}
+
+	share, err := p.CreateShare(from, to, details, now.Add(expiresIn))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Println(server.ShareURL(cfg.Server, share.Token))
+	fmt.Printf("Read-only until %s. Revoke with `gomentum share revoke %s`.\n", i18n.FormatDateTime(share.ExpiresAt), share.Token)
+	if !cfg.Server.Enabled {
+		fmt.Println("Note: the link only works while the server runs (server.enabled with `gomentum daemon`, or `gomentum serve`).")
+	}
+	return 0
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gomentum/internal/planner"
+)
+
+// sqlMaxRows caps the rows printed by `gomentum sql`
+const sqlMaxRows = 1000
+
+// runSQL runs a read-only query against the tasks database and prints a table
+func runSQL(args []string) int {
+	query := strings.TrimSpace(strings.Join(args, " "))
+	if query == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
+			return 1
+		}
+		query = strings.TrimSpace(string(data))
+	}
+	if query == "" {
+		fmt.Fprintln(os.Stderr, "Usage: gomentum sql <SELECT ... | ->")
+		fmt.Fprintln(os.Stderr, "Tables: tasks, external_links, shares, chat_history")
+		return 2
+	}
+
+	cfg, _, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+	p, err := planner.NewPlanner(cfg.Database.Path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer p.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	res, err := p.ReadOnlyQuery(ctx, query, sqlMaxRows)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(res.Columns, "\t"))
+	rule := make([]string, len(res.Columns))
+	for i, c := range res.Columns {
+		rule[i] = strings.Repeat("-", len(c))
+	}
+	fmt.Fprintln(w, strings.Join(rule, "\t"))
+	for _, row := range res.Rows {
+		for i, v := range row {
+			// Keep multi-line descriptions on one table row
+			row[i] = strings.NewReplacer("\r", "", "\n", " ", "\t", " ").Replace(v)
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+
+	if res.Truncated {
+		fmt.Printf("(first %d rows shown; add a LIMIT or narrow the query)\n", sqlMaxRows)
+	} else {
+		fmt.Printf("(%d rows)\n", len(res.Rows))
+	}
+	return 0
+}
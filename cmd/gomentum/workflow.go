@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"gomentum/internal/i18n"
+	"gomentum/internal/planner"
+)
+
+// runWorkflow lists, applies or discards workflows the agent proposed
+func runWorkflow(args []string) int {
+	usage := func() int {
+		fmt.Fprintln(os.Stderr, "Usage: gomentum workflow list | apply <id> | discard <id>")
+		return 2
+	}
+	if len(args) == 0 {
+		return usage()
+	}
+	var id int
+	switch args[0] {
+	case "list":
+		if len(args) != 1 {
+			return usage()
+		}
+	case "apply", "discard":
+		var err error
+		if len(args) != 2 {
+			return usage()
+		}
+		if id, err = strconv.Atoi(args[1]); err != nil {
+			return usage()
+		}
+	default:
+		return usage()
+	}
+
+	cfg, _, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+	p, err := planner.NewPlanner(cfg.Database.Path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer p.Close()
+
+	switch args[0] {
+	case "list":
+		list, err := p.ListWorkflows(planner.WorkflowProposed)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		for _, w := range list {
+			fmt.Printf("%d  %s  %d changes, proposed %s\n", w.ID, w.Name, len(w.Changes), i18n.FormatDateTime(w.CreatedAt))
+			for i, c := range w.Changes {
+				fmt.Printf("    %d. %s\n", i+1, describeChange(c))
+			}
+		}
+	case "apply":
+		added, err := p.ApplyWorkflow(id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Workflow %d applied (%d tasks added)\n", id, len(added))
+	case "discard":
+		if err := p.DiscardWorkflow(id); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Workflow %d discarded\n", id)
+	}
+	return 0
+}
+
+// describeChange renders a change on one line, e.g. "move #3 to Fri 14:00-15:00"
+func describeChange(c planner.Change) string {
+	slot := func() string {
+		return fmt.Sprintf("%s %s-%s", i18n.FormatDate(c.StartTime), i18n.FormatTime(c.StartTime), i18n.FormatTime(c.EndTime))
+	}
+	switch c.Op {
+	case planner.ChangeAdd:
+		return fmt.Sprintf("add %q at %s", c.Title, slot())
+	case planner.ChangeMove:
+		return fmt.Sprintf("move #%d to %s", c.TaskID, slot())
+	case planner.ChangeStatus:
+		return fmt.Sprintf("set #%d to %s", c.TaskID, c.Status)
+	default:
+		return fmt.Sprintf("%s #%d", c.Op, c.TaskID)
+	}
+}
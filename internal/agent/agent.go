@@ -3,16 +3,18 @@ package agent
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"io"
 	"log/slog"
 	"strings"
 	"time"
 
 	"gomentum/internal/config"
+	"gomentum/internal/debuglog"
+	"gomentum/internal/i18n"
 	gmcp "gomentum/internal/mcp"
 	"gomentum/internal/planner"
+	"gomentum/internal/redact"
+	"gomentum/internal/search"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	openai "github.com/sashabaranov/go-openai"
@@ -20,33 +22,95 @@ import (
 
 // Agent defines the interface for our planning agent
 type Agent interface {
-	// Chat sends a message to the agent and returns the response
-	// onToken is called for each token generated by the LLM
-	Chat(ctx context.Context, prompt string, onToken func(string)) (string, error)
+	// Chat sends a message to the agent and returns the reply along with
+	// the tools it executed. onToken is called for each token generated by
+	// the LLM.
+	Chat(ctx context.Context, prompt string, onToken func(string)) (Response, error)
+	// Rewind drops the last turn from the history so its prompt can be
+	// sent again or edited. Tasks the turn changed stay as they are.
+	Rewind() error
 }
 
 // OpenAIAgent implements Agent for OpenAI-compatible APIs (e.g., DeepSeek)
 type OpenAIAgent struct {
-	client    *openai.Client
+	// The model and its fallbacks, and the one the current turn uses
+	backends []backend
+	current  int
+	// Runs the tool calls of a turn when llm.tool_model is set
+	tools     *backend
 	cfg       *config.Config
 	mcpServer *gmcp.Server
 	planner   *planner.Planner
 	history   []openai.ChatCompletionMessage // In-memory history including tool calls
+	// Summary of the turns dropped from history; see compactHistory
+	summary string
+	// History as it was before each recent turn, newest last
+	checkpoints []checkpoint
+	// Masks personal data sent to the LLM; nil when agent.redact is off
+	redactor *redact.Redactor
 }
 
-// NewAgent creates a new agent
+// NewAgent creates a new agent for the configured LLM provider
 func NewAgent(cfg *config.Config, mcpServer *gmcp.Server, p *planner.Planner) (Agent, error) {
-	clientConfig := openai.DefaultConfig(cfg.LLM.APIKey)
-	clientConfig.BaseURL = cfg.LLM.BaseURL
+	backends, err := newBackends(cfg.LLM)
+	if err != nil {
+		return nil, err
+	}
+	var tools *backend
+	if llm, ok := cfg.LLM.Tools(); ok {
+		client, err := NewClient(llm)
+		if err != nil {
+			return nil, err
+		}
+		tools = &backend{llm: llm, client: client}
+	}
+	return newAgent(cfg, mcpServer, p, backends, tools)
+}
 
-	client := openai.NewClientWithConfig(clientConfig)
+// NewAgentWithClient creates an agent that talks to the given client, e.g.
+// a scripted FakeClient in tests
+func NewAgentWithClient(cfg *config.Config, mcpServer *gmcp.Server, p *planner.Planner, client ChatClient) (Agent, error) {
+	llm := cfg.LLM
+	llm.Fallbacks, llm.ToolModel = nil, nil
+	return newAgent(cfg, mcpServer, p, []backend{{llm: llm, client: client}}, nil)
+}
+
+func newAgent(cfg *config.Config, mcpServer *gmcp.Server, p *planner.Planner, backends []backend, tools *backend) (Agent, error) {
+	if cfg.Agent.SQLTool {
+		mcpServer.EnableSQL()
+	}
+	mcpServer.EnableGitActivity(cfg.Agent.GitRepos)
+	mcpServer.SetExports(cfg.Export.Dir, cfg.Export.Filename)
+	searcher, err := search.New(cfg.Embeddings, cfg.Agent.Redact, p)
+	if err != nil {
+		return nil, err
+	}
+	mcpServer.EnableSemanticSearch(searcher)
+	debuglog.Configure(cfg.Agent.DebugLog, config.Secrets(cfg))
+
+	// Any remote model needs the redactor
+	var redactor *redact.Redactor
+	all := backends
+	if tools != nil {
+		all = append(all[:len(all):len(all)], *tools)
+	}
+	for _, b := range all {
+		if redact.Remote(b.llm) {
+			if redactor, err = redact.New(cfg.Agent.Redact); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
 
 	agent := &OpenAIAgent{
-		client:    client,
+		backends:  backends,
+		tools:     tools,
 		cfg:       cfg,
 		mcpServer: mcpServer,
 		planner:   p,
 		history:   []openai.ChatCompletionMessage{},
+		redactor:  redactor,
 	}
 
 	// Load history from DB
@@ -81,9 +145,10 @@ func (a *OpenAIAgent) loadHistory() error {
 }
 
 // Chat implements the Agent interface
-func (a *OpenAIAgent) Chat(ctx context.Context, prompt string, onToken func(string)) (string, error) {
+func (a *OpenAIAgent) Chat(ctx context.Context, prompt string, onToken func(string)) (Response, error) {
 	// Static system prompt: force live time from tool, never cached clock
-	systemPrompt := "You are Gomentum, a helpful planning assistant. ALWAYS call the tool `current_time` before any time reasoning or scheduling to get the freshest local timestamp (RFC3339 with offset). Treat the latest `current_time` result as the only authoritative 'now' and ignore any earlier timestamps in the conversation. When calling tools with start_time or end_time, use RFC3339 with the SAME timezone offset as the current time; do not convert to UTC. If the user provides a relative time (like 'tomorrow', 'next Monday'), first call `current_time`, then calculate the absolute date and EXECUTE the scheduling tool immediately. Do not ask for confirmation unless the time is ambiguous. Be concise."
+	systemPrompt := "You are Gomentum, a helpful planning assistant. ALWAYS call the tool `current_time` before any time reasoning or scheduling to get the freshest local timestamp (RFC3339 with offset). Treat the latest `current_time` result as the only authoritative 'now' and ignore any earlier timestamps in the conversation. When calling tools with start_time or end_time, use RFC3339 with the SAME timezone offset as the current time; do not convert to UTC. If the user provides a relative time (like 'tomorrow', 'next Monday'), first call `current_time`, then calculate the absolute date and EXECUTE the scheduling tool immediately. Do not ask for confirmation unless the time is ambiguous. For dates tied to the Chinese lunar calendar or its festivals (e.g. 'the day before Mid-Autumn Festival'), call `lunar_date` instead of guessing. If a task is longer than any free slot, call `split_task` to spread it over several sessions. When the user gives a deadline and an effort estimate rather than a time slot, call `schedule_deadline`. If a more important task must go into an occupied slot, call `bump_and_schedule`, show the proposed moves and apply them only after the user approves. Add meetings and other appointments with flexible=false so they are never moved automatically. For repeating tasks such as 'standup every weekday at 9am', call `add_task` once with a recurrence instead of adding each occurrence. To categorize tasks (e.g. work, errand), give them `tags` with `add_task` or `update_task`; `update_task` replaces the whole list, so include the tags to keep. When asked to review or clean up old tasks, call `review_stale` and go through the proposals with the user. After an import, or when asked about tasks entered twice, call `find_duplicates` and fold the confirmed duplicates together with `merge_tasks`. To record details like a client, billing code or ticket ID on a task, call `set_task_fields`. For questions about how a past period went (productivity, completion, busiest day), call `get_stats` and answer from its numbers. To find particular tasks in the history (by period, status, priority, text or custom field), call `query_tasks` rather than reading the whole list. When the user describes a task only vaguely (e.g. 'that thing about the dentist'), find it with `semantic_search`. For bulk changes use `complete_range`, `shift_today` (e.g. 'I'm running 30 minutes late') and `purge_completed`; purging deletes permanently, so confirm with the user first. To move a chosen set of tasks or every upcoming task of a project, call `shift_tasks` once instead of many `update_task` calls and mention any conflicts it reports. To exchange the slots of two tasks, call `swap_tasks`. When one task has to be done before another (e.g. 'order parts' before 'assemble'), call `link_tasks`; a task whose blocked_by lists open tasks cannot be completed until they are. For plans with several steps (such as reorganizing a day), gather the tasks, save every change with `propose_changes`, show the plan and call `apply_workflow` only after the user confirms; check `list_workflows` when the user wants to resume an interrupted plan. To check whether the user was notified about a task, call `reminder_history`. When you mention a task, write its ID as #ID (e.g. #12) so the user can jump to it; prompts may reference tasks the same way and then carry their details. To tweak a proposed plan by item number (e.g. 'swap items 2 and 3'), call `revise_workflow` instead of proposing a new one. Be concise."
+	systemPrompt += a.planContext()
 
 	if len(a.history) > 0 && a.history[0].Role == openai.ChatMessageRoleSystem {
 		a.history[0].Content = systemPrompt
@@ -94,6 +159,11 @@ func (a *OpenAIAgent) Chat(ctx context.Context, prompt string, onToken func(stri
 		}}, a.history...)
 	}
 
+	// Each turn starts with the preferred model again
+	a.current = 0
+	a.compactHistory(ctx)
+	a.checkpoint()
+
 	// Add user message to history and DB
 	a.history = append(a.history, openai.ChatCompletionMessage{
 		Role:    openai.ChatMessageRoleUser,
@@ -112,87 +182,35 @@ func (a *OpenAIAgent) Chat(ctx context.Context, prompt string, onToken func(stri
 	// Prepare tools
 	tools := a.getOpenAITools()
 
+	// Each tool call is credited with the tasks that changed while it ran
+	var actions []Action
+	before := a.snapshot()
+	var turn planner.AgentUsage
+
 	// Loop to handle tool calls
 	// Safety: Limit max iterations to prevent infinite loops
 	maxIterations := 10
 	for i := 0; i < maxIterations; i++ {
+		if refusal := a.budgetRefusal(turn, false); refusal != "" {
+			return a.refuse(refusal, nil, actions, onToken), nil
+		}
+
 		// Sliding Window: Select messages for context
 		contextMessages := a.getContextMessages()
 
-		stream, err := a.client.CreateChatCompletionStream(
-			ctx,
-			openai.ChatCompletionRequest{
-				Model:    a.cfg.LLM.Model,
-				Messages: contextMessages,
-				Tools:    tools,
-				Stream:   true,
-			},
-		)
-		if err != nil {
-			return "", err
+		// The model is set per backend
+		req := openai.ChatCompletionRequest{
+			Messages: a.redactMessages(contextMessages),
+			Tools:    tools,
+			Stream:   true,
+			// Ask for token usage only when a budget needs it
+			StreamOptions: streamOptions(a.cfg.Agent.Budget),
 		}
-		defer stream.Close()
-
-		var (
-			fullContent string
-			toolCalls   []openai.ToolCall
-		)
-
-		// Stream loop
-		for {
-			response, err := stream.Recv()
-			if errors.Is(err, io.EOF) {
-				break
-			}
-			if err != nil {
-				return "", fmt.Errorf("stream error: %v", err)
-			}
-
-			if len(response.Choices) == 0 {
-				continue
-			}
-
-			delta := response.Choices[0].Delta
-
-			// Handle content delta
-			if delta.Content != "" {
-				fullContent += delta.Content
-				if onToken != nil {
-					onToken(delta.Content)
-				}
-			}
-
-			// Handle tool calls delta
-			// Note: Tool calls are streamed in parts. We need to accumulate them.
-			// The go-openai library's Delta.ToolCalls usually contains the index and partial data.
-			for _, tc := range delta.ToolCalls {
-				// Ensure slice is large enough
-				if tc.Index != nil {
-					idx := *tc.Index
-					for len(toolCalls) <= idx {
-						toolCalls = append(toolCalls, openai.ToolCall{})
-					}
-					// Update ID
-					if tc.ID != "" {
-						toolCalls[idx].ID = tc.ID
-						toolCalls[idx].Type = tc.Type
-					}
-					// Update Function Name
-					if tc.Function.Name != "" {
-						if toolCalls[idx].Function.Name == "" {
-							toolCalls[idx].Function.Name = tc.Function.Name
-						} else {
-							toolCalls[idx].Function.Name += tc.Function.Name
-						}
-					}
-					// Update Function Arguments
-					if tc.Function.Arguments != "" {
-						toolCalls[idx].Function.Arguments += tc.Function.Arguments
-					}
-				}
-			}
+		res, err := a.route(ctx, req, contextMessages, &turn, onToken)
+		if err != nil {
+			return Response{Actions: actions}, err
 		}
-		stream.Close()
+		fullContent, toolCalls, cutOff := res.content, res.toolCalls, res.cutOff
 
 		// Construct the full message
 		msg := openai.ChatCompletionMessage{
@@ -201,6 +219,7 @@ func (a *OpenAIAgent) Chat(ctx context.Context, prompt string, onToken func(stri
 			ToolCalls: toolCalls,
 		}
 		a.history = append(a.history, msg)
+		a.recordUsage(&turn, callUsage(res, contextMessages, msg))
 
 		// If there are no tool calls, we are done
 		if len(toolCalls) == 0 {
@@ -208,11 +227,21 @@ func (a *OpenAIAgent) Chat(ctx context.Context, prompt string, onToken func(stri
 			if err := a.planner.SaveMessage(openai.ChatMessageRoleAssistant, fullContent); err != nil {
 				slog.Error("Failed to save assistant message", "error", err)
 			}
-			return fullContent, nil
+			if cutOff != nil {
+				note := i18n.T("agent.cut_off", cutOff)
+				if onToken != nil {
+					onToken("\n\n" + note)
+				}
+				return Response{Text: fullContent + "\n\n" + note, Actions: actions}, nil
+			}
+			return Response{Text: fullContent, Actions: actions}, nil
 		}
 
 		// Handle tool calls
-		for _, toolCall := range toolCalls {
+		for i, toolCall := range toolCalls {
+			if refusal := a.budgetRefusal(turn, true); refusal != "" {
+				return a.refuse(refusal, toolCalls[i:], actions, onToken), nil
+			}
 			slog.Info("Calling tool", "tool", toolCall.Function.Name)
 			// Visual feedback for tool calls (since we are streaming, we might want to print a newline first)
 			if onToken != nil {
@@ -230,7 +259,8 @@ func (a *OpenAIAgent) Chat(ctx context.Context, prompt string, onToken func(stri
 				continue
 			}
 
-			result, err := a.mcpServer.CallTool(ctx, toolCall.Function.Name, args)
+			result, err := a.callTool(ctx, toolCall.Function.Name, args)
+			a.recordUsage(&turn, planner.AgentUsage{ToolCalls: 1})
 			content := ""
 			if err != nil {
 				content = fmt.Sprintf("Error: %v", err)
@@ -242,6 +272,17 @@ func (a *OpenAIAgent) Chat(ctx context.Context, prompt string, onToken func(stri
 				}
 			}
 
+			debuglog.Log(debuglog.Tool, map[string]any{"tool": toolCall.Function.Name, "arguments": args, "result": content})
+			after := a.snapshot()
+			actions = append(actions, Action{
+				Tool:      toolCall.Function.Name,
+				Arguments: args,
+				Failed:    err != nil || result.IsError,
+				Result:    strings.TrimSpace(content),
+				TaskIDs:   changedTaskIDs(before, after),
+			})
+			before = after
+
 			a.history = append(a.history, openai.ChatCompletionMessage{
 				Role:       openai.ChatMessageRoleTool,
 				Content:    content,
@@ -251,7 +292,7 @@ func (a *OpenAIAgent) Chat(ctx context.Context, prompt string, onToken func(stri
 		// Loop continues to send tool results back to LLM
 	}
 
-	return "", fmt.Errorf("max iterations reached")
+	return Response{Actions: actions}, fmt.Errorf("max iterations reached")
 }
 
 func (a *OpenAIAgent) getContextMessages() []openai.ChatCompletionMessage {
@@ -261,6 +302,9 @@ func (a *OpenAIAgent) getContextMessages() []openai.ChatCompletionMessage {
 	}
 
 	systemMsg := a.history[0]
+	if a.summary != "" {
+		systemMsg.Content += "\n\nSummary of the earlier conversation: " + a.summary
+	}
 	remaining := a.history[1:]
 
 	maxHistory := a.cfg.Agent.MaxHistory
@@ -0,0 +1,138 @@
+package agent
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"gomentum/internal/config"
+	"gomentum/internal/i18n"
+	"gomentum/internal/planner"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// The budget of agent.budget is checked before every LLM call and every
+// tool call. Usage is recorded per day in the database; a turn that hits
+// a limit stops with a refusal instead of an error, keeping what it did.
+
+// budgeted reports whether any budget limit is set
+func budgeted(b config.BudgetConfig) bool {
+	return b.PerTurn != (config.BudgetLimits{}) || b.PerDay != (config.BudgetLimits{})
+}
+
+// countsTokens reports whether a limit needs the token usage of each call
+func countsTokens(b config.BudgetConfig) bool {
+	return b.PerTurn.Tokens > 0 || b.PerTurn.Cost > 0 || b.PerDay.Tokens > 0 || b.PerDay.Cost > 0
+}
+
+// recordUsage adds u to the turn and to today's usage
+func (a *OpenAIAgent) recordUsage(turn *planner.AgentUsage, u planner.AgentUsage) {
+	if !budgeted(a.cfg.Agent.Budget) {
+		return
+	}
+	*turn = turn.Add(u)
+	if err := a.planner.RecordAgentUsage(time.Now(), u); err != nil {
+		slog.Warn("Failed to record agent usage", "error", err)
+	}
+}
+
+// callUsage is the usage of the LLM call that produced r: as the provider
+// reported it, or else estimated at four characters per token. The cost
+// uses the prices of the model that answered.
+func callUsage(r reply, sent []openai.ChatCompletionMessage, msg openai.ChatCompletionMessage) planner.AgentUsage {
+	var u planner.AgentUsage
+	if r.usage != nil && r.usage.TotalTokens > 0 {
+		u.PromptTokens, u.CompletionTokens = r.usage.PromptTokens, r.usage.CompletionTokens
+	} else {
+		u.PromptTokens = estimateTokens(sent...)
+		u.CompletionTokens = estimateTokens(msg)
+	}
+	u.Cost = (float64(u.PromptTokens)*r.llm.InputPrice + float64(u.CompletionTokens)*r.llm.OutputPrice) / 1e6
+	return u
+}
+
+func estimateTokens(msgs ...openai.ChatCompletionMessage) int {
+	chars := 0
+	for _, m := range msgs {
+		chars += len(m.Content)
+		for _, tc := range m.ToolCalls {
+			chars += len(tc.Function.Name) + len(tc.Function.Arguments)
+		}
+	}
+	return (chars + 3) / 4
+}
+
+// budgetRefusal explains why the agent must stop before its next LLM call
+// or, with tool set, its next tool call. It is "" while within budget.
+func (a *OpenAIAgent) budgetRefusal(turn planner.AgentUsage, tool bool) string {
+	b := a.cfg.Agent.Budget
+	if limit := exceeded(b.PerTurn, turn, tool, b.Currency); limit != "" {
+		return i18n.T("agent.budget_turn", limit)
+	}
+	if b.PerDay == (config.BudgetLimits{}) {
+		return ""
+	}
+	day, err := a.planner.AgentUsageOn(time.Now())
+	if err != nil {
+		slog.Warn("Failed to read agent usage", "error", err)
+		return ""
+	}
+	if limit := exceeded(b.PerDay, day, tool, b.Currency); limit != "" {
+		return i18n.T("agent.budget_day", limit)
+	}
+	return ""
+}
+
+// exceeded names the limit u has reached: the tool-call limit before a
+// tool call, the token or cost limit before an LLM call
+func exceeded(l config.BudgetLimits, u planner.AgentUsage, tool bool, currency string) string {
+	if tool {
+		if l.ToolCalls > 0 && u.ToolCalls >= l.ToolCalls {
+			return i18n.T("agent.budget_tool_calls", l.ToolCalls)
+		}
+		return ""
+	}
+	if l.Tokens > 0 && u.Tokens() >= l.Tokens {
+		return i18n.T("agent.budget_tokens", l.Tokens)
+	}
+	if l.Cost > 0 && u.Cost >= l.Cost {
+		return i18n.T("agent.budget_cost", strings.TrimSpace(fmt.Sprintf("%.2f %s", l.Cost, currency)))
+	}
+	return ""
+}
+
+// refuse ends the turn with a budget refusal as the reply. Tool calls the
+// model asked for but that were not run get a result saying so, which
+// keeps the history valid for the next request.
+func (a *OpenAIAgent) refuse(refusal string, skipped []openai.ToolCall, actions []Action, onToken func(string)) Response {
+	slog.Warn("Agent budget exceeded", "reason", refusal)
+	for _, tc := range skipped {
+		a.history = append(a.history, openai.ChatCompletionMessage{
+			Role:       openai.ChatMessageRoleTool,
+			Content:    "Not run: " + refusal,
+			ToolCallID: tc.ID,
+		})
+	}
+	a.history = append(a.history, openai.ChatCompletionMessage{
+		Role:    openai.ChatMessageRoleAssistant,
+		Content: refusal,
+	})
+	if err := a.planner.SaveMessage(openai.ChatMessageRoleAssistant, refusal); err != nil {
+		slog.Error("Failed to save assistant message", "error", err)
+	}
+	if onToken != nil {
+		onToken("\n" + refusal)
+	}
+	return Response{Text: refusal, Actions: actions}
+}
+
+// streamOptions asks the provider to report token usage at the end of the
+// stream, which only budgets on tokens or cost need
+func streamOptions(b config.BudgetConfig) *openai.StreamOptions {
+	if !countsTokens(b) {
+		return nil
+	}
+	return &openai.StreamOptions{IncludeUsage: true}
+}
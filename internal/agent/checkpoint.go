@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// maxCheckpoints is how many turns can be rewound
+const maxCheckpoints = 10
+
+// ErrNothingToRewind is returned by Rewind when no turn of this session
+// is left to take back
+var ErrNothingToRewind = errors.New("no turn to rewind")
+
+// checkpoint is the history before a turn. Pruning rewrites the history
+// during a turn, so it is kept as a copy rather than an index.
+type checkpoint struct {
+	history []openai.ChatCompletionMessage
+	summary string
+	stored  int // ID of the newest stored message before the turn
+}
+
+// checkpoint remembers the history before a new turn starts
+func (a *OpenAIAgent) checkpoint() {
+	stored, err := a.planner.LastMessageID()
+	if err != nil {
+		slog.Warn("Failed to checkpoint chat history", "error", err)
+		return
+	}
+	a.checkpoints = append(a.checkpoints, checkpoint{
+		history: append([]openai.ChatCompletionMessage(nil), a.history...),
+		summary: a.summary,
+		stored:  stored,
+	})
+	if len(a.checkpoints) > maxCheckpoints {
+		a.checkpoints = a.checkpoints[1:]
+	}
+}
+
+// Rewind implements the Agent interface
+func (a *OpenAIAgent) Rewind() error {
+	if len(a.checkpoints) == 0 {
+		return ErrNothingToRewind
+	}
+	cp := a.checkpoints[len(a.checkpoints)-1]
+	if err := a.planner.DeleteMessagesAfter(cp.stored); err != nil {
+		return fmt.Errorf("failed to rewind: %w", err)
+	}
+	a.checkpoints = a.checkpoints[:len(a.checkpoints)-1]
+	a.history, a.summary = cp.history, cp.summary
+	return nil
+}
@@ -0,0 +1,51 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	"gomentum/internal/config"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// LLM providers selectable with llm.provider (or LLM_PROVIDER)
+const (
+	ProviderOpenAI = "openai" // Any OpenAI-compatible API; the default
+	ProviderFake   = "fake"   // Offline, deterministic replies (see FakeClient)
+)
+
+// ChatClient is the part of the OpenAI API the agent uses
+type ChatClient interface {
+	CreateChatCompletionStream(ctx context.Context, req openai.ChatCompletionRequest) (ChatStream, error)
+}
+
+// ChatStream yields the chunks of one streamed completion
+type ChatStream interface {
+	// Recv returns the next chunk, or io.EOF after the last one
+	Recv() (openai.ChatCompletionStreamResponse, error)
+	Close() error
+}
+
+// NewClient creates the client for the configured provider
+func NewClient(cfg config.LLMConfig) (ChatClient, error) {
+	switch cfg.Provider {
+	case "", ProviderOpenAI:
+		clientConfig := openai.DefaultConfig(cfg.APIKey)
+		clientConfig.BaseURL = cfg.BaseURL
+		return openAIClient{openai.NewClientWithConfig(clientConfig)}, nil
+	case ProviderFake:
+		return NewFakeClient(cfg.FakeScript)
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q (use %s or %s)", cfg.Provider, ProviderOpenAI, ProviderFake)
+	}
+}
+
+// openAIClient adapts *openai.Client, whose stream is a concrete type
+type openAIClient struct {
+	client *openai.Client
+}
+
+func (c openAIClient) CreateChatCompletionStream(ctx context.Context, req openai.ChatCompletionRequest) (ChatStream, error) {
+	return c.client.CreateChatCompletionStream(ctx, req)
+}
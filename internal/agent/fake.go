@@ -0,0 +1,110 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// FakeClient answers without a network or API key, for offline demos and
+// integration tests. It plays back a script of replies in order; once the
+// script is used up (or without one) it echoes the latest user message,
+// so the same conversation always gives the same output.
+type FakeClient struct {
+	mu     sync.Mutex
+	script []FakeReply
+}
+
+// FakeReply is one scripted completion: text, tool calls or both
+type FakeReply struct {
+	Content   string         `json:"content,omitempty"`
+	ToolCalls []FakeToolCall `json:"tool_calls,omitempty"`
+}
+
+// FakeToolCall is a tool the scripted model asks for
+type FakeToolCall struct {
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// NewFakeClient loads the script from a JSON array of replies. An empty
+// path gives a client that only echoes.
+func NewFakeClient(scriptPath string) (*FakeClient, error) {
+	c := &FakeClient{}
+	if scriptPath == "" {
+		return c, nil
+	}
+	data, err := os.ReadFile(scriptPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fake LLM script: %w", err)
+	}
+	if err := json.Unmarshal(data, &c.script); err != nil {
+		return nil, fmt.Errorf("invalid fake LLM script %s: %w", scriptPath, err)
+	}
+	return c, nil
+}
+
+// NewScriptedClient plays back the given replies
+func NewScriptedClient(script []FakeReply) *FakeClient {
+	return &FakeClient{script: script}
+}
+
+// CreateChatCompletionStream implements ChatClient
+func (c *FakeClient) CreateChatCompletionStream(ctx context.Context, req openai.ChatCompletionRequest) (ChatStream, error) {
+	c.mu.Lock()
+	var reply FakeReply
+	if len(c.script) > 0 {
+		reply, c.script = c.script[0], c.script[1:]
+	} else {
+		reply = FakeReply{Content: "(offline) " + lastUserMessage(req.Messages)}
+	}
+	c.mu.Unlock()
+
+	delta := openai.ChatCompletionStreamChoiceDelta{Role: openai.ChatMessageRoleAssistant, Content: reply.Content}
+	for i, tc := range reply.ToolCalls {
+		args, err := json.Marshal(tc.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		index := i
+		delta.ToolCalls = append(delta.ToolCalls, openai.ToolCall{
+			Index:    &index,
+			ID:       fmt.Sprintf("fake_call_%d", i),
+			Type:     openai.ToolTypeFunction,
+			Function: openai.FunctionCall{Name: tc.Name, Arguments: string(args)},
+		})
+	}
+	return &fakeStream{chunks: []openai.ChatCompletionStreamResponse{{
+		Choices: []openai.ChatCompletionStreamChoice{{Delta: delta}},
+	}}}, nil
+}
+
+func lastUserMessage(msgs []openai.ChatCompletionMessage) string {
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].Role == openai.ChatMessageRoleUser {
+			return msgs[i].Content
+		}
+	}
+	return ""
+}
+
+// fakeStream returns its chunks one by one, then io.EOF
+type fakeStream struct {
+	chunks []openai.ChatCompletionStreamResponse
+}
+
+func (s *fakeStream) Recv() (openai.ChatCompletionStreamResponse, error) {
+	if len(s.chunks) == 0 {
+		return openai.ChatCompletionStreamResponse{}, io.EOF
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+func (s *fakeStream) Close() error { return nil }
@@ -0,0 +1,123 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"gomentum/internal/planner"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// Once the history grows past agent.max_history messages, the oldest turns
+// are summarized by the LLM and dropped, so long sessions keep their
+// context without sending every message again. The summary travels in
+// the system prompt.
+
+// summaryPrompt asks the model to condense the dropped turns
+const summaryPrompt = "Summarize the conversation below between a user and their planning assistant, for the assistant's own later reference. Keep what still matters: the user's requests and preferences, decisions made, the IDs and titles of tasks that were discussed or changed, and open questions. Leave out greetings and the details of tool calls. Reply with the summary only, in at most 200 words, in the language of the conversation."
+
+// toolResultLimit bounds how much of each tool result goes into the
+// summary request
+const toolResultLimit = 500
+
+// compactHistory summarizes and drops the oldest turns when the history
+// holds more than agent.max_history messages, keeping about half of them.
+// It cuts only before a user message so tool calls keep their results. If
+// the summary fails the history stays as it is, and getContextMessages
+// still sends no more than the limit.
+func (a *OpenAIAgent) compactHistory(ctx context.Context) {
+	limit := a.cfg.Agent.MaxHistory
+	if limit <= 0 || len(a.history)-1 <= limit {
+		return
+	}
+	cut := 0
+	for i := len(a.history) - limit/2; i < len(a.history); i++ {
+		if a.history[i].Role == openai.ChatMessageRoleUser {
+			cut = i
+			break
+		}
+	}
+	if cut == 0 {
+		// The latest turn alone is longer than half the limit
+		for i := len(a.history) - 1; i > 1; i-- {
+			if a.history[i].Role == openai.ChatMessageRoleUser {
+				cut = i
+				break
+			}
+		}
+	}
+	if cut <= 1 {
+		return
+	}
+
+	var turn planner.AgentUsage
+	if a.budgetRefusal(turn, false) != "" {
+		return
+	}
+	summary, err := a.summarize(ctx, a.history[1:cut], &turn)
+	if err != nil {
+		slog.Warn("Failed to summarize chat history", "error", err)
+		return
+	}
+	a.summary = summary
+	a.history = append(a.history[:1:1], a.history[cut:]...)
+	slog.Debug("Summarized chat history", "messages", cut-1)
+}
+
+// summarize asks the LLM to fold msgs into the current summary
+func (a *OpenAIAgent) summarize(ctx context.Context, msgs []openai.ChatCompletionMessage, turn *planner.AgentUsage) (string, error) {
+	var b strings.Builder
+	if a.summary != "" {
+		fmt.Fprintf(&b, "Summary of the conversation so far:\n%s\n\nConversation since:\n", a.summary)
+	}
+	for _, m := range msgs {
+		if isTimeMessage(m) {
+			continue
+		}
+		switch m.Role {
+		case openai.ChatMessageRoleUser:
+			fmt.Fprintf(&b, "User: %s\n", m.Content)
+		case openai.ChatMessageRoleAssistant:
+			if m.Content != "" {
+				fmt.Fprintf(&b, "Assistant: %s\n", m.Content)
+			}
+			for _, tc := range m.ToolCalls {
+				fmt.Fprintf(&b, "Assistant called %s(%s)\n", tc.Function.Name, tc.Function.Arguments)
+			}
+		case openai.ChatMessageRoleTool:
+			content := strings.TrimSpace(m.Content)
+			if r := []rune(content); len(r) > toolResultLimit {
+				content = string(r[:toolResultLimit]) + "…"
+			}
+			fmt.Fprintf(&b, "Tool result: %s\n", content)
+		}
+	}
+
+	sent := []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: summaryPrompt},
+		{Role: openai.ChatMessageRoleUser, Content: b.String()},
+	}
+	r, err := a.complete(ctx, openai.ChatCompletionRequest{
+		Messages:      a.redactMessages(sent),
+		Stream:        true,
+		StreamOptions: streamOptions(a.cfg.Agent.Budget),
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+	a.recordUsage(turn, callUsage(r, sent, openai.ChatCompletionMessage{
+		Role:    openai.ChatMessageRoleAssistant,
+		Content: r.content,
+	}))
+	if r.cutOff != nil {
+		return "", r.cutOff
+	}
+	summary := strings.TrimSpace(r.content)
+	if summary == "" {
+		return "", fmt.Errorf("the model returned an empty summary")
+	}
+	return summary, nil
+}
@@ -0,0 +1,25 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gomentum/internal/planner"
+)
+
+// planContext describes the plan the model proposed today and the user
+// has not confirmed yet, so follow-ups like "swap items 2 and 3" refer to
+// its saved items rather than to whatever the chat history still holds
+func (a *OpenAIAgent) planContext() string {
+	now := time.Now()
+	w, err := a.planner.LatestWorkflow(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()))
+	if err != nil {
+		if !errors.Is(err, planner.ErrNotFound) {
+			slog.Warn("Failed to load the proposed plan", "error", err)
+		}
+		return ""
+	}
+	return fmt.Sprintf(" Your latest plan, workflow %d '%s', is waiting for confirmation. Its items are:\n%sWhen the user refers to items by number, they mean these.", w.ID, w.Name, w.Outline())
+}
@@ -0,0 +1,50 @@
+package agent
+
+import (
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// With agent.redact enabled and a remote LLM, each request is redacted on
+// its way out and each reply restored on its way in. The history, the
+// database and the tools only ever see the real values.
+
+// redactMessages returns a redacted copy of msgs for the LLM
+func (a *OpenAIAgent) redactMessages(msgs []openai.ChatCompletionMessage) []openai.ChatCompletionMessage {
+	if a.redactor == nil {
+		return msgs
+	}
+	out := make([]openai.ChatCompletionMessage, len(msgs))
+	for i, m := range msgs {
+		m.Content = a.redactor.Redact(m.Content)
+		if len(m.ToolCalls) > 0 {
+			calls := make([]openai.ToolCall, len(m.ToolCalls))
+			for j, tc := range m.ToolCalls {
+				tc.Function.Arguments = a.redactor.Redact(tc.Function.Arguments)
+				calls[j] = tc
+			}
+			m.ToolCalls = calls
+		}
+		out[i] = m
+	}
+	return out
+}
+
+// restoreReply puts the real values back into a reply of the LLM
+func (a *OpenAIAgent) restoreReply(content string, toolCalls []openai.ToolCall) (string, []openai.ToolCall) {
+	if a.redactor == nil {
+		return content, toolCalls
+	}
+	for i := range toolCalls {
+		toolCalls[i].Function.Arguments = a.redactor.RestoreJSON(toolCalls[i].Function.Arguments)
+	}
+	return a.redactor.Restore(content), toolCalls
+}
+
+// restoreStream wraps onToken so streamed tokens show the real values.
+// flush must be called once the stream ends.
+func (a *OpenAIAgent) restoreStream(onToken func(string)) (emit func(string), flush func()) {
+	if a.redactor == nil || onToken == nil {
+		return onToken, func() {}
+	}
+	return a.redactor.Stream(onToken)
+}
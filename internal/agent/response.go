@@ -0,0 +1,77 @@
+package agent
+
+import (
+	"log/slog"
+	"reflect"
+	"sort"
+
+	"gomentum/internal/planner"
+)
+
+// Response is the result of one agent turn
+type Response struct {
+	Text    string   `json:"text"`    // The final reply
+	Actions []Action `json:"actions"` // Tools executed during the turn, in order
+}
+
+// Action is one tool call the agent executed
+type Action struct {
+	Tool      string                 `json:"tool"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+	Failed    bool                   `json:"failed,omitempty"`
+	Result    string                 `json:"result,omitempty"` // Text the tool returned
+	// Tasks the call added, changed or deleted
+	TaskIDs []int `json:"task_ids,omitempty"`
+}
+
+// TaskIDs returns every task the turn affected, each once, in the order
+// they were first touched
+func (r Response) TaskIDs() []int {
+	seen := make(map[int]bool)
+	var ids []int
+	for _, a := range r.Actions {
+		for _, id := range a.TaskIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// snapshot reads the task list to attribute changes to tool calls. A nil
+// snapshot means the list could not be read and no changes are reported.
+func (a *OpenAIAgent) snapshot() map[int]planner.Task {
+	tasks, err := a.planner.ListTasks()
+	if err != nil {
+		slog.Warn("Failed to read tasks for the turn summary", "error", err)
+		return nil
+	}
+	byID := make(map[int]planner.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+	return byID
+}
+
+// changedTaskIDs lists the tasks that were added, changed or deleted
+// between two snapshots
+func changedTaskIDs(before, after map[int]planner.Task) []int {
+	if before == nil || after == nil {
+		return nil
+	}
+	var ids []int
+	for id, t := range after {
+		if prev, ok := before[id]; !ok || !reflect.DeepEqual(prev, t) {
+			ids = append(ids, id)
+		}
+	}
+	for id := range before {
+		if _, ok := after[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
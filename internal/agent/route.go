@@ -0,0 +1,45 @@
+package agent
+
+import (
+	"context"
+	"log/slog"
+
+	"gomentum/internal/planner"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// With llm.tool_model set, each call of a turn goes to the tool model
+// first. Its reply is kept when it runs tools, which covers the many
+// calls of a bulk operation; a reply meant for the user is dropped and the
+// main model writes it instead, so the user only ever reads the main
+// model.
+
+// route makes one call of a turn. sent and turn account for a tool model
+// reply that is dropped, as it was paid for all the same.
+func (a *OpenAIAgent) route(ctx context.Context, req openai.ChatCompletionRequest, sent []openai.ChatCompletionMessage, turn *planner.AgentUsage, onToken func(string)) (reply, error) {
+	if a.tools == nil {
+		return a.complete(ctx, req, onToken)
+	}
+	// Nothing is streamed: the reply may yet be dropped
+	r, err := a.stream(ctx, *a.tools, req, nil)
+	switch {
+	case err != nil:
+		if ctx.Err() != nil {
+			return reply{}, err
+		}
+		slog.Warn("Tool model failed, using the main model", "backend", a.tools.String(), "error", err)
+	case len(r.toolCalls) > 0 && r.cutOff == nil:
+		slog.Debug("Tool calls made by the tool model", "backend", a.tools.String(), "calls", len(r.toolCalls))
+		if r.content != "" && onToken != nil {
+			onToken(r.content)
+		}
+		return r, nil
+	default:
+		a.recordUsage(turn, callUsage(r, sent, openai.ChatCompletionMessage{
+			Role:    openai.ChatMessageRoleAssistant,
+			Content: r.content,
+		}))
+	}
+	return a.complete(ctx, req, onToken)
+}
@@ -0,0 +1,168 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/url"
+
+	"gomentum/internal/config"
+	"gomentum/internal/debuglog"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// backend is one model of the llm.fallbacks chain
+type backend struct {
+	llm    config.LLMConfig
+	client ChatClient
+}
+
+// String names the backend in logs, e.g. "gpt-4o-mini at api.openai.com"
+func (b backend) String() string {
+	if b.llm.Provider == ProviderFake {
+		return b.llm.Model + " (fake)"
+	}
+	if u, err := url.Parse(b.llm.BaseURL); err == nil && u.Host != "" {
+		return b.llm.Model + " at " + u.Host
+	}
+	return b.llm.Model
+}
+
+// newBackends creates a client for the model and each of its fallbacks
+func newBackends(llm config.LLMConfig) ([]backend, error) {
+	var backends []backend
+	for _, l := range llm.Chain() {
+		client, err := NewClient(l)
+		if err != nil {
+			return nil, err
+		}
+		backends = append(backends, backend{llm: l, client: client})
+	}
+	return backends, nil
+}
+
+// reply is what one streamed completion produced
+type reply struct {
+	content   string
+	toolCalls []openai.ToolCall
+	usage     *openai.Usage
+	cutOff    error            // Why the reply ended early, if it did
+	llm       config.LLMConfig // The model that answered, for its prices
+}
+
+// complete sends req to the current backend and moves down the fallback
+// chain while calls fail before anything was shown to the user. Later
+// calls of the turn stay with the backend that answered.
+func (a *OpenAIAgent) complete(ctx context.Context, req openai.ChatCompletionRequest, onToken func(string)) (reply, error) {
+	for {
+		b := a.backends[a.current]
+		r, err := a.stream(ctx, b, req, onToken)
+		if err == nil {
+			if a.current > 0 {
+				slog.Info("LLM reply served by a fallback", "backend", b.String())
+			}
+			return r, nil
+		}
+		if ctx.Err() != nil || a.current == len(a.backends)-1 {
+			return reply{}, err
+		}
+		next := a.backends[a.current+1]
+		slog.Warn("LLM failed, trying the next model", "backend", b.String(), "next", next.String(), "error", err)
+		a.current++
+	}
+}
+
+// stream runs one streamed completion on b. It fails only when nothing was
+// shown to the user; a reply that breaks off later keeps what arrived.
+func (a *OpenAIAgent) stream(ctx context.Context, b backend, req openai.ChatCompletionRequest, onToken func(string)) (reply, error) {
+	req.Model = b.llm.Model
+	debuglog.Log(debuglog.Request, map[string]any{"backend": b.String(), "request": req})
+	reqCtx, cancel := withTimeout(ctx, a.cfg.Agent.Timeouts.Request)
+	defer cancel()
+	stream, err := b.client.CreateChatCompletionStream(reqCtx, req)
+	if err != nil {
+		if errors.Is(reqCtx.Err(), context.DeadlineExceeded) {
+			err = a.streamError(reqCtx, err)
+		}
+		return reply{}, err
+	}
+	stream = watchIdle(stream, a.cfg.Agent.Timeouts.StreamIdle)
+	defer stream.Close()
+
+	r := reply{llm: b.llm}
+	emit, flush := a.restoreStream(onToken)
+
+	// Stream loop
+	for {
+		response, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			err = a.streamError(reqCtx, err)
+			// Keep the text received so far, unless the user gave up
+			// on the turn or there is none. Half-streamed tool calls
+			// cannot be trusted and are dropped.
+			if r.content == "" || ctx.Err() != nil {
+				return reply{}, err
+			}
+			slog.Warn("LLM reply cut off", "error", err)
+			r.cutOff, r.toolCalls = err, nil
+			break
+		}
+
+		if response.Usage != nil {
+			r.usage = response.Usage
+		}
+		if len(response.Choices) == 0 {
+			continue
+		}
+
+		delta := response.Choices[0].Delta
+
+		// Handle content delta
+		if delta.Content != "" {
+			r.content += delta.Content
+			if emit != nil {
+				emit(delta.Content)
+			}
+		}
+
+		// Handle tool calls delta
+		// Note: Tool calls are streamed in parts. We need to accumulate them.
+		// The go-openai library's Delta.ToolCalls usually contains the index and partial data.
+		for _, tc := range delta.ToolCalls {
+			// Ensure slice is large enough
+			if tc.Index != nil {
+				idx := *tc.Index
+				for len(r.toolCalls) <= idx {
+					r.toolCalls = append(r.toolCalls, openai.ToolCall{})
+				}
+				// Update ID
+				if tc.ID != "" {
+					r.toolCalls[idx].ID = tc.ID
+					r.toolCalls[idx].Type = tc.Type
+				}
+				// Update Function Name
+				if tc.Function.Name != "" {
+					if r.toolCalls[idx].Function.Name == "" {
+						r.toolCalls[idx].Function.Name = tc.Function.Name
+					} else {
+						r.toolCalls[idx].Function.Name += tc.Function.Name
+					}
+				}
+				// Update Function Arguments
+				if tc.Function.Arguments != "" {
+					r.toolCalls[idx].Function.Arguments += tc.Function.Arguments
+				}
+			}
+		}
+	}
+	stream.Close()
+	flush()
+	debuglog.Log(debuglog.Response, map[string]any{"backend": b.String(), "content": r.content, "tool_calls": r.toolCalls, "usage": r.usage, "cut_off": errorText(r.cutOff)})
+	r.content, r.toolCalls = a.restoreReply(r.content, r.toolCalls)
+	return r, nil
+}
@@ -0,0 +1,111 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// withTimeout is context.WithTimeout where d <= 0 means no limit
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// errStreamIdle ends a stream that stopped sending chunks
+var errStreamIdle = errors.New("stream idle")
+
+// idleStream gives up on Recv when no chunk arrives within idle. The
+// abandoned Recv returns once the stream is closed.
+type idleStream struct {
+	ChatStream
+	idle time.Duration
+}
+
+type recvResult struct {
+	resp openai.ChatCompletionStreamResponse
+	err  error
+}
+
+// watchIdle wraps s in an idleStream; idle <= 0 returns s as is
+func watchIdle(s ChatStream, idle time.Duration) ChatStream {
+	if idle <= 0 {
+		return s
+	}
+	return &idleStream{ChatStream: s, idle: idle}
+}
+
+func (s *idleStream) Recv() (openai.ChatCompletionStreamResponse, error) {
+	ch := make(chan recvResult, 1)
+	go func() {
+		resp, err := s.ChatStream.Recv()
+		ch <- recvResult{resp, err}
+	}()
+	timer := time.NewTimer(s.idle)
+	defer timer.Stop()
+	select {
+	case r := <-ch:
+		return r.resp, r.err
+	case <-timer.C:
+		return openai.ChatCompletionStreamResponse{}, errStreamIdle
+	}
+}
+
+// streamError explains why an LLM call ended early. reqCtx is the context
+// of the call, bounded by agent.timeouts.request.
+func (a *OpenAIAgent) streamError(reqCtx context.Context, err error) error {
+	t := a.cfg.Agent.Timeouts
+	switch {
+	case errors.Is(err, errStreamIdle):
+		return fmt.Errorf("the LLM sent nothing for %s (agent.timeouts.stream_idle)", t.StreamIdle)
+	case errors.Is(reqCtx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("the LLM did not finish within %s (agent.timeouts.request)", t.Request)
+	default:
+		return fmt.Errorf("stream error: %v", err)
+	}
+}
+
+// callTool runs a tool within agent.timeouts.tool. A tool that overruns is
+// reported as failed to the model; it is told the call may still finish,
+// as a tool that ignores its context cannot be stopped.
+func (a *OpenAIAgent) callTool(ctx context.Context, name string, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	limit := a.cfg.Agent.Timeouts.Tool
+	if limit <= 0 {
+		return a.mcpServer.CallTool(ctx, name, args)
+	}
+	ctx, cancel := context.WithTimeout(ctx, limit)
+	defer cancel()
+
+	type callResult struct {
+		result *mcp.CallToolResult
+		err    error
+	}
+	ch := make(chan callResult, 1)
+	go func() {
+		result, err := a.mcpServer.CallTool(ctx, name, args)
+		ch <- callResult{result, err}
+	}()
+	select {
+	case r := <-ch:
+		return r.result, r.err
+	case <-ctx.Done():
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("%s did not finish within %s and may still complete; check before retrying", name, limit)
+	}
+}
+
+// errorText is err's message, or "" for nil
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
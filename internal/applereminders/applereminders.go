@@ -0,0 +1,92 @@
+// Package applereminders pulls Apple Reminders (e.g. captured on an iPhone
+// via Siri) into Gomentum and pushes tasks back as reminders. The bridge
+// drives Reminders.app through osascript and therefore only works on macOS.
+package applereminders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"gomentum/internal/config"
+	"gomentum/internal/planner"
+)
+
+// ErrUnsupported is returned on platforms without Reminders.app
+var ErrUnsupported = errors.New("Apple Reminders is only available on macOS")
+
+// DefaultDuration is the length of tasks created from reminders with a due date
+const DefaultDuration = 30 * time.Minute
+
+// Reminder is an incomplete item in a Reminders list
+type Reminder struct {
+	ID    string
+	Title string
+	Notes string
+	Due   time.Time // Zero when the reminder has no due date
+}
+
+// Scheduler turns free text into a task, normally by handing it to the agent
+type Scheduler func(ctx context.Context, text string) error
+
+// Pull consumes the incomplete reminders in list. Reminders with a due date
+// become tasks directly; the rest go through schedule like a quick-add.
+// Each reminder is marked completed in Reminders.app once imported, so the
+// list works as an inbox.
+func Pull(ctx context.Context, list string, p *planner.Planner, schedule Scheduler) (int, error) {
+	reminders, err := Fetch(ctx, list)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, r := range reminders {
+		if r.Due.IsZero() {
+			text := strings.TrimSpace(r.Title + "\n" + r.Notes)
+			if err := schedule(ctx, text); err != nil {
+				return n, fmt.Errorf("failed to schedule reminder %q: %w", r.Title, err)
+			}
+		} else if _, err := p.AddTask(r.Title, r.Notes, r.Due, r.Due.Add(DefaultDuration)); err != nil {
+			return n, err
+		}
+
+		if err := Complete(ctx, r.ID); err != nil {
+			return n, err
+		}
+		slog.Info("Imported Apple reminder", "title", r.Title)
+		n++
+	}
+	return n, nil
+}
+
+// Push creates a reminder for the task, due at its start time
+func Push(ctx context.Context, list string, t planner.Task) error {
+	return Create(ctx, list, Reminder{Title: t.Title, Notes: t.Description, Due: t.StartTime})
+}
+
+// Run pulls from the configured list until ctx is cancelled
+func Run(ctx context.Context, cfg config.AppleRemindersConfig, p *planner.Planner, schedule Scheduler) {
+	interval := cfg.PollInterval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := Pull(ctx, cfg.List, p, schedule); err != nil {
+			slog.Error("Apple Reminders sync failed", "error", err)
+			if errors.Is(err, ErrUnsupported) {
+				return
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,98 @@
+//go:build darwin
+
+package applereminders
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// The helpers are JavaScript for Automation scripts run by osascript.
+// Arguments are passed through argv so no quoting is needed.
+const (
+	fetchScript = `function run(argv) {
+	const app = Application("Reminders");
+	const items = app.lists.byName(argv[0]).reminders.whose({completed: false})();
+	return JSON.stringify(items.map(r => {
+		const due = r.dueDate();
+		return {id: r.id(), name: r.name(), body: r.body() || "", due: due ? due.toISOString() : null};
+	}));
+}`
+	completeScript = `function run(argv) {
+	Application("Reminders").reminders.byId(argv[0]).completed = true;
+}`
+	createScript = `function run(argv) {
+	const app = Application("Reminders");
+	const props = {name: argv[1], body: argv[2]};
+	if (argv[3]) props.dueDate = new Date(argv[3]);
+	const r = app.Reminder(props);
+	app.lists.byName(argv[0]).reminders.push(r);
+	return r.id();
+}`
+)
+
+func osascript(ctx context.Context, script string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "osascript", append([]string{"-l", "JavaScript", "-e", script}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("osascript failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// Fetch returns the incomplete reminders in list
+func Fetch(ctx context.Context, list string) ([]Reminder, error) {
+	out, err := osascript(ctx, fetchScript, list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reminders list %q: %w", list, err)
+	}
+
+	var raw []struct {
+		ID   string  `json:"id"`
+		Name string  `json:"name"`
+		Body string  `json:"body"`
+		Due  *string `json:"due"`
+	}
+	if err := json.Unmarshal(out, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse reminders: %w", err)
+	}
+
+	reminders := make([]Reminder, 0, len(raw))
+	for _, r := range raw {
+		rem := Reminder{ID: r.ID, Title: r.Name, Notes: r.Body}
+		if r.Due != nil {
+			if due, err := time.Parse(time.RFC3339, *r.Due); err == nil {
+				rem.Due = due.Local()
+			}
+		}
+		reminders = append(reminders, rem)
+	}
+	return reminders, nil
+}
+
+// Complete marks a reminder as done
+func Complete(ctx context.Context, id string) error {
+	if _, err := osascript(ctx, completeScript, id); err != nil {
+		return fmt.Errorf("failed to complete reminder: %w", err)
+	}
+	return nil
+}
+
+// Create adds a reminder to list
+func Create(ctx context.Context, list string, r Reminder) error {
+	due := ""
+	if !r.Due.IsZero() {
+		due = r.Due.UTC().Format(time.RFC3339)
+	}
+	if _, err := osascript(ctx, createScript, list, r.Title, r.Notes, due); err != nil {
+		return fmt.Errorf("failed to create reminder: %w", err)
+	}
+	return nil
+}
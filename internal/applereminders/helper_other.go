@@ -0,0 +1,20 @@
+//go:build !darwin
+
+package applereminders
+
+import "context"
+
+// Fetch is only available on macOS
+func Fetch(ctx context.Context, list string) ([]Reminder, error) {
+	return nil, ErrUnsupported
+}
+
+// Complete is only available on macOS
+func Complete(ctx context.Context, id string) error {
+	return ErrUnsupported
+}
+
+// Create is only available on macOS
+func Create(ctx context.Context, list string, r Reminder) error {
+	return ErrUnsupported
+}
@@ -0,0 +1,100 @@
+// Package autoexport keeps copies of the plan as markdown and iCalendar
+// files up to date, e.g. inside a synced folder so other devices always
+// see a fresh copy.
+package autoexport
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gomentum/internal/config"
+	"gomentum/internal/exporter"
+	"gomentum/internal/invite"
+	"gomentum/internal/planner"
+)
+
+// pollInterval is how often the database is checked for changes when
+// exporting on every change
+const pollInterval = 2 * time.Second
+
+// Run writes the exports once, then again every cfg.Interval, or after
+// every change to the tasks if no interval is set, until ctx is cancelled
+func Run(ctx context.Context, cfg config.AutoExportConfig, p *planner.Planner) {
+	if cfg.Markdown == "" && cfg.ICS == "" {
+		slog.Warn("Auto-export is enabled but neither auto_export.markdown nor auto_export.ics is set")
+		return
+	}
+	export := func() {
+		if err := Export(cfg, p); err != nil {
+			slog.Error("Auto-export failed", "error", err)
+		}
+	}
+	export()
+
+	if cfg.Interval <= 0 {
+		if err := p.Watch(ctx, pollInterval, export); err != nil {
+			slog.Error("Auto-export stopped", "error", err)
+		}
+		return
+	}
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			export()
+		}
+	}
+}
+
+// Export writes every configured file now
+func Export(cfg config.AutoExportConfig, p *planner.Planner) error {
+	if cfg.Markdown != "" {
+		err := writeAtomic(exporter.ExpandHome(cfg.Markdown), func(path string) error {
+			return p.ExportToMarkdown(path)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to export %s: %w", cfg.Markdown, err)
+		}
+	}
+	if cfg.ICS != "" {
+		tasks, err := p.ListTasks()
+		if err != nil {
+			return err
+		}
+		data := invite.Publish(tasks, time.Now())
+		err = writeAtomic(exporter.ExpandHome(cfg.ICS), func(path string) error {
+			return os.WriteFile(path, data, 0o644)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to export %s: %w", cfg.ICS, err)
+		}
+	}
+	return nil
+}
+
+// writeAtomic lets write fill a temporary file next to path and then
+// renames it into place, so sync clients never pick up a half-written file
+func writeAtomic(path string, write func(tmp string) error) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	f.Close()
+	defer os.Remove(tmp) // A no-op once renamed
+
+	if err := write(tmp); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
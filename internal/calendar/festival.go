@@ -0,0 +1,116 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Festival is a holiday fixed in the lunar calendar
+type Festival struct {
+	Name    string
+	Chinese string
+	Month   int
+	Day     int // 0 means the last day of the month
+	Aliases []string
+}
+
+var festivals = []Festival{
+	{Name: "Spring Festival", Chinese: "春节", Month: 1, Day: 1, Aliases: []string{"chinese new year", "lunar new year", "new year"}},
+	{Name: "Lantern Festival", Chinese: "元宵节", Month: 1, Day: 15, Aliases: []string{"yuanxiao", "元宵"}},
+	{Name: "Dragon Boat Festival", Chinese: "端午节", Month: 5, Day: 5, Aliases: []string{"duanwu", "端午"}},
+	{Name: "Qixi Festival", Chinese: "七夕", Month: 7, Day: 7, Aliases: []string{"qixi", "chinese valentine's day"}},
+	{Name: "Ghost Festival", Chinese: "中元节", Month: 7, Day: 15, Aliases: []string{"zhongyuan", "中元"}},
+	{Name: "Mid-Autumn Festival", Chinese: "中秋节", Month: 8, Day: 15, Aliases: []string{"mid autumn", "moon festival", "中秋"}},
+	{Name: "Double Ninth Festival", Chinese: "重阳节", Month: 9, Day: 9, Aliases: []string{"chongyang", "重阳"}},
+	{Name: "Laba Festival", Chinese: "腊八节", Month: 12, Day: 8, Aliases: []string{"laba", "腊八"}},
+	{Name: "New Year's Eve", Chinese: "除夕", Month: 12, Day: 0, Aliases: []string{"chinese new year's eve", "chuxi"}},
+}
+
+// Festivals returns the known lunar festivals
+func Festivals() []Festival {
+	return festivals
+}
+
+// FindFestival looks up a festival by English name, Chinese name or alias
+func FindFestival(name string) (Festival, bool) {
+	key := normalizeName(name)
+	for _, f := range festivals {
+		if normalizeName(f.Name) == key || normalizeName(f.Chinese) == key {
+			return f, true
+		}
+		for _, a := range f.Aliases {
+			if normalizeName(a) == key {
+				return f, true
+			}
+		}
+	}
+	return Festival{}, false
+}
+
+func normalizeName(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.NewReplacer("-", " ", "_", " ", "the ", "", " festival", "").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Date returns the Gregorian date of the festival in the given lunar year
+func (f Festival) Date(lunarYear int, loc *time.Location) (time.Time, error) {
+	day := f.Day
+	if day == 0 {
+		if lunarYear < minLunarYear || lunarYear > maxLunarYear {
+			return time.Time{}, fmt.Errorf("lunar year %d is outside the supported range %d-%d", lunarYear, minLunarYear, maxLunarYear)
+		}
+		day = MonthLength(lunarYear, f.Month, false)
+	}
+	return LunarDate{Year: lunarYear, Month: f.Month, Day: day}.ToGregorian(loc)
+}
+
+// Next returns the first occurrence of the festival on or after from
+func (f Festival) Next(from time.Time) (time.Time, error) {
+	today := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	ld, err := ToLunar(today)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// The festival may fall in the current or the next lunar year
+	for _, y := range []int{ld.Year, ld.Year + 1} {
+		d, err := f.Date(y, from.Location())
+		if err != nil {
+			return time.Time{}, err
+		}
+		if !d.Before(today) {
+			return d, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("no upcoming date found for %s", f.Name)
+}
+
+// FestivalOn returns the festival falling on t, if any
+func FestivalOn(t time.Time) (Festival, bool) {
+	ld, err := ToLunar(t)
+	if err != nil || ld.Leap {
+		return Festival{}, false
+	}
+	for _, f := range festivals {
+		day := f.Day
+		if day == 0 {
+			day = MonthLength(ld.Year, f.Month, false)
+		}
+		if f.Month == ld.Month && day == ld.Day {
+			return f, true
+		}
+	}
+	return Festival{}, false
+}
+
+// FestivalNames lists festival names for tool descriptions
+func FestivalNames() []string {
+	var names []string
+	for _, f := range festivals {
+		names = append(names, f.Name)
+	}
+	sort.Strings(names)
+	return names
+}
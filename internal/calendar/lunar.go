@@ -0,0 +1,207 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// lunarInfo encodes the Chinese lunar calendar for 1900-2100.
+// Bits 0-3: leap month (0 = none); bits 4-15: whether months 12..1 have
+// 30 days; bit 16: whether the leap month has 30 days.
+var lunarInfo = [...]int{
+	0x04bd8, 0x04ae0, 0x0a570, 0x054d5, 0x0d260, 0x0d950, 0x16554, 0x056a0, 0x09ad0, 0x055d2, // 1900-1909
+	0x04ae0, 0x0a5b6, 0x0a4d0, 0x0d250, 0x1d255, 0x0b540, 0x0d6a0, 0x0ada2, 0x095b0, 0x14977, // 1910-1919
+	0x04970, 0x0a4b0, 0x0b4b5, 0x06a50, 0x06d40, 0x1ab54, 0x02b60, 0x09570, 0x052f2, 0x04970, // 1920-1929
+	0x06566, 0x0d4a0, 0x0ea50, 0x16a95, 0x05ad0, 0x02b60, 0x186e3, 0x092e0, 0x1c8d7, 0x0c950, // 1930-1939
+	0x0d4a0, 0x1d8a6, 0x0b550, 0x056a0, 0x1a5b4, 0x025d0, 0x092d0, 0x0d2b2, 0x0a950, 0x0b557, // 1940-1949
+	0x06ca0, 0x0b550, 0x15355, 0x04da0, 0x0a5b0, 0x14573, 0x052b0, 0x0a9a8, 0x0e950, 0x06aa0, // 1950-1959
+	0x0aea6, 0x0ab50, 0x04b60, 0x0aae4, 0x0a570, 0x05260, 0x0f263, 0x0d950, 0x05b57, 0x056a0, // 1960-1969
+	0x096d0, 0x04dd5, 0x04ad0, 0x0a4d0, 0x0d4d4, 0x0d250, 0x0d558, 0x0b540, 0x0b6a0, 0x195a6, // 1970-1979
+	0x095b0, 0x049b0, 0x0a974, 0x0a4b0, 0x0b27a, 0x06a50, 0x06d40, 0x0af46, 0x0ab60, 0x09570, // 1980-1989
+	0x04af5, 0x04970, 0x064b0, 0x074a3, 0x0ea50, 0x06b58, 0x05ac0, 0x0ab60, 0x096d5, 0x092e0, // 1990-1999
+	0x0c960, 0x0d954, 0x0d4a0, 0x0da50, 0x07552, 0x056a0, 0x0abb7, 0x025d0, 0x092d0, 0x0cab5, // 2000-2009
+	0x0a950, 0x0b4a0, 0x0baa4, 0x0ad50, 0x055d9, 0x04ba0, 0x0a5b0, 0x15176, 0x052b0, 0x0a930, // 2010-2019
+	0x07954, 0x06aa0, 0x0ad50, 0x05b52, 0x04b60, 0x0a6e6, 0x0a4e0, 0x0d260, 0x0ea65, 0x0d530, // 2020-2029
+	0x05aa0, 0x076a3, 0x096d0, 0x04afb, 0x04ad0, 0x0a4d0, 0x1d0b6, 0x0d250, 0x0d520, 0x0dd45, // 2030-2039
+	0x0b5a0, 0x056d0, 0x055b2, 0x049b0, 0x0a577, 0x0a4b0, 0x0aa50, 0x1b255, 0x06d20, 0x0ada0, // 2040-2049
+	0x14b63, 0x09370, 0x049f8, 0x04970, 0x064b0, 0x168a6, 0x0ea50, 0x06b20, 0x1a6c4, 0x0aae0, // 2050-2059
+	0x0a2e0, 0x0d2e3, 0x0c960, 0x0d557, 0x0d4a0, 0x0da50, 0x05d55, 0x056a0, 0x0a6d0, 0x055d4, // 2060-2069
+	0x052d0, 0x0a9b8, 0x0a950, 0x0b4a0, 0x0b6a6, 0x0ad50, 0x055a0, 0x0aba4, 0x0a5b0, 0x052b0, // 2070-2079
+	0x0b273, 0x06930, 0x07337, 0x06aa0, 0x0ad50, 0x14b55, 0x04b60, 0x0a570, 0x054e4, 0x0d160, // 2080-2089
+	0x0e968, 0x0d520, 0x0daa0, 0x16aa6, 0x056d0, 0x04ae0, 0x0a9d4, 0x0a2d0, 0x0d150, 0x0f252, // 2090-2099
+	0x0d520, // 2100
+}
+
+const (
+	minLunarYear = 1900
+	maxLunarYear = 2100
+)
+
+// lunarEpoch is Gregorian 1900-01-31, the first day of lunar year 1900
+var lunarEpoch = time.Date(1900, 1, 31, 0, 0, 0, 0, time.UTC)
+
+// LunarDate is a date in the Chinese lunar calendar
+type LunarDate struct {
+	Year  int
+	Month int // 1-12
+	Day   int // 1-30
+	Leap  bool
+}
+
+func leapMonth(y int) int {
+	return lunarInfo[y-minLunarYear] & 0xf
+}
+
+func leapDays(y int) int {
+	if leapMonth(y) == 0 {
+		return 0
+	}
+	if lunarInfo[y-minLunarYear]&0x10000 != 0 {
+		return 30
+	}
+	return 29
+}
+
+func monthDays(y, m int) int {
+	if lunarInfo[y-minLunarYear]&(0x10000>>m) != 0 {
+		return 30
+	}
+	return 29
+}
+
+func yearDays(y int) int {
+	sum := 348
+	for i := 0x8000; i > 0x8; i >>= 1 {
+		if lunarInfo[y-minLunarYear]&i != 0 {
+			sum++
+		}
+	}
+	return sum + leapDays(y)
+}
+
+// ToLunar converts a Gregorian date (its local calendar day) to the lunar calendar
+func ToLunar(t time.Time) (LunarDate, error) {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := int(day.Sub(lunarEpoch).Hours() / 24)
+	if offset < 0 {
+		return LunarDate{}, fmt.Errorf("date %s is before the supported lunar range", t.Format("2006-01-02"))
+	}
+
+	year := minLunarYear
+	for ; year <= maxLunarYear; year++ {
+		n := yearDays(year)
+		if offset < n {
+			break
+		}
+		offset -= n
+	}
+	if year > maxLunarYear {
+		return LunarDate{}, fmt.Errorf("date %s is after the supported lunar range", t.Format("2006-01-02"))
+	}
+
+	leap := leapMonth(year)
+	for month := 1; month <= 12; month++ {
+		n := monthDays(year, month)
+		if offset < n {
+			return LunarDate{Year: year, Month: month, Day: offset + 1}, nil
+		}
+		offset -= n
+
+		if month == leap {
+			n = leapDays(year)
+			if offset < n {
+				return LunarDate{Year: year, Month: month, Day: offset + 1, Leap: true}, nil
+			}
+			offset -= n
+		}
+	}
+	return LunarDate{}, fmt.Errorf("failed to convert %s to lunar date", t.Format("2006-01-02"))
+}
+
+// ToGregorian converts a lunar date to the Gregorian calendar in loc
+func (d LunarDate) ToGregorian(loc *time.Location) (time.Time, error) {
+	if d.Year < minLunarYear || d.Year > maxLunarYear {
+		return time.Time{}, fmt.Errorf("lunar year %d is outside the supported range %d-%d", d.Year, minLunarYear, maxLunarYear)
+	}
+	if d.Month < 1 || d.Month > 12 {
+		return time.Time{}, fmt.Errorf("invalid lunar month %d", d.Month)
+	}
+	if d.Leap && leapMonth(d.Year) != d.Month {
+		return time.Time{}, fmt.Errorf("lunar year %d has no leap month %d", d.Year, d.Month)
+	}
+
+	maxDay := monthDays(d.Year, d.Month)
+	if d.Leap {
+		maxDay = leapDays(d.Year)
+	}
+	if d.Day < 1 || d.Day > maxDay {
+		return time.Time{}, fmt.Errorf("lunar month %d of %d has %d days", d.Month, d.Year, maxDay)
+	}
+
+	offset := 0
+	for y := minLunarYear; y < d.Year; y++ {
+		offset += yearDays(y)
+	}
+	leap := leapMonth(d.Year)
+	for m := 1; m < d.Month; m++ {
+		offset += monthDays(d.Year, m)
+		if m == leap {
+			offset += leapDays(d.Year)
+		}
+	}
+	if d.Leap {
+		offset += monthDays(d.Year, d.Month)
+	}
+	offset += d.Day - 1
+
+	g := lunarEpoch.AddDate(0, 0, offset)
+	return time.Date(g.Year(), g.Month(), g.Day(), 0, 0, 0, 0, loc), nil
+}
+
+// MonthLength returns the number of days in a lunar month
+func MonthLength(year, month int, leap bool) int {
+	if leap {
+		return leapDays(year)
+	}
+	return monthDays(year, month)
+}
+
+var (
+	chineseMonths = []string{"正", "二", "三", "四", "五", "六", "七", "八", "九", "十", "冬", "腊"}
+	chineseDigits = []string{"", "一", "二", "三", "四", "五", "六", "七", "八", "九", "十"}
+)
+
+// Chinese renders the date the traditional way, e.g. "八月十五" or "闰四月初一"
+func (d LunarDate) Chinese() string {
+	var b strings.Builder
+	if d.Leap {
+		b.WriteString("闰")
+	}
+	b.WriteString(chineseMonths[d.Month-1])
+	b.WriteString("月")
+
+	switch {
+	case d.Day == 10:
+		b.WriteString("初十")
+	case d.Day == 20:
+		b.WriteString("二十")
+	case d.Day == 30:
+		b.WriteString("三十")
+	case d.Day < 10:
+		b.WriteString("初" + chineseDigits[d.Day])
+	case d.Day < 20:
+		b.WriteString("十" + chineseDigits[d.Day-10])
+	default:
+		b.WriteString("廿" + chineseDigits[d.Day-20])
+	}
+	return b.String()
+}
+
+// String renders the date numerically, e.g. "Lunar 8/15" or "Lunar leap 4/1"
+func (d LunarDate) String() string {
+	if d.Leap {
+		return fmt.Sprintf("Lunar leap %d/%d", d.Month, d.Day)
+	}
+	return fmt.Sprintf("Lunar %d/%d", d.Month, d.Day)
+}
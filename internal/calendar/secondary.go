@@ -0,0 +1,71 @@
+package calendar
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"gomentum/internal/i18n"
+)
+
+// Secondary calendar kinds accepted in config
+const (
+	None  = ""
+	Lunar = "lunar"
+)
+
+var (
+	mu        sync.RWMutex
+	secondary = None
+)
+
+// SetSecondary selects the calendar shown next to Gregorian dates
+func SetSecondary(kind string) {
+	switch kind {
+	case None, Lunar:
+	default:
+		slog.Warn("Unknown secondary calendar, disabling it", "calendar", kind)
+		kind = None
+	}
+	mu.Lock()
+	secondary = kind
+	mu.Unlock()
+}
+
+// FormatSecondary renders t in the configured secondary calendar, or returns
+// an empty string when none is configured.
+func FormatSecondary(t time.Time) string {
+	mu.RLock()
+	kind := secondary
+	mu.RUnlock()
+
+	if kind != Lunar {
+		return ""
+	}
+	ld, err := ToLunar(t.Local())
+	if err != nil {
+		return ""
+	}
+
+	chinese := i18n.Current() == "zh"
+	out := ld.String()
+	if chinese {
+		out = ld.Chinese()
+	}
+	if f, ok := FestivalOn(t.Local()); ok {
+		if chinese {
+			out += " " + f.Chinese
+		} else {
+			out += ", " + f.Name
+		}
+	}
+	return out
+}
+
+// Annotate appends the secondary calendar date in parentheses, if enabled
+func Annotate(date string, t time.Time) string {
+	if s := FormatSecondary(t); s != "" {
+		return date + " (" + s + ")"
+	}
+	return date
+}
@@ -1,8 +1,11 @@
 package config
 
 import (
+	"cmp"
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -12,20 +15,458 @@ type Config struct {
 	LLM      LLMConfig      `yaml:"llm"`
 	Database DatabaseConfig `yaml:"database"`
 	Agent    AgentConfig    `yaml:"agent"`
+	Server   ServerConfig   `yaml:"server"`
+	Update   UpdateConfig   `yaml:"update"`
+	Locale   string         `yaml:"locale"` // "en", "zh"; empty detects from LANG
+	// SecondaryCalendar is shown next to Gregorian dates: "" (none) or "lunar"
+	SecondaryCalendar string `yaml:"secondary_calendar"`
+	// Theme: default, high-contrast, deuteranopia, protanopia or no-color
+	Theme string `yaml:"theme"`
+	// Density of the TUI task list: detailed (two lines per task, default)
+	// or compact (one line, no descriptions). Ctrl+L switches at runtime.
+	Density string       `yaml:"density"`
+	Layout  LayoutConfig `yaml:"layout"`
+	// Icons for task states and priorities: auto (default, detects what
+	// the terminal can draw), emoji, unicode or ascii
+	Icons string `yaml:"icons"`
+	// TagIcons draws a glyph per project (or other custom field) in the task list
+	TagIcons TagIconsConfig `yaml:"tag_icons"`
+	// Projects gives tasks defaults by their project, the value of the
+	// tag_icons field, applied when a task is put in the project
+	Projects map[string]ProjectConfig `yaml:"projects"`
+
+	AppleReminders AppleRemindersConfig `yaml:"apple_reminders"`
+	Outlook        OutlookConfig        `yaml:"outlook"`
+	Focus          FocusConfig          `yaml:"focus"`
+	Reminders      RemindersConfig      `yaml:"reminders"`
+	Hooks          []HookConfig         `yaml:"hooks"`
+	Schedule       ScheduleConfig       `yaml:"schedule"`
+	Review         ReviewConfig         `yaml:"review"`
+	Activity       ActivityConfig       `yaml:"activity"`
+	AutoExport     AutoExportConfig     `yaml:"auto_export"`
+	Export         ExportConfig         `yaml:"export"`
+	SMTP           SMTPConfig           `yaml:"smtp"`
+	Format         FormatConfig         `yaml:"format"`
+	Telemetry      TelemetryConfig      `yaml:"telemetry"`
+	Webhooks       WebhooksConfig       `yaml:"webhooks"`
+	Network        NetworkConfig        `yaml:"network"`
+	Embeddings     EmbeddingsConfig     `yaml:"embeddings"`
+	// LocalOnly refuses to start unless the LLM runs on this machine and
+	// turns off every feature that talks to the network
+	LocalOnly bool `yaml:"local_only"`
+	// Statuses adds custom task statuses to pending, in_progress, completed and backlog
+	Statuses []StatusConfig `yaml:"statuses"`
 }
 
 type LLMConfig struct {
 	APIKey  string `yaml:"api_key"`
 	BaseURL string `yaml:"base_url"`
 	Model   string `yaml:"model"`
+	// Provider is "openai" (default, any compatible API) or "fake" for
+	// offline, deterministic replies that need no API key
+	Provider string `yaml:"provider"`
+	// FakeScript is a JSON file of replies the fake provider plays back
+	FakeScript string `yaml:"fake_script"`
+	// Prices per million prompt and completion tokens of this model, for
+	// the cost limits of agent.budget
+	InputPrice  float64 `yaml:"input_price"`
+	OutputPrice float64 `yaml:"output_price"`
+	// Fallbacks are tried in order when this model fails or times out. A
+	// fallback without a base_url uses this endpoint and its API key; its
+	// own fallbacks are ignored.
+	Fallbacks []LLMConfig `yaml:"fallbacks"`
+	// ToolModel, if set, makes the calls of a turn that run tools, such as
+	// bulk scheduling, and leaves the replies the user reads to this model.
+	// Missing fields are taken from here as for fallbacks.
+	ToolModel *LLMConfig `yaml:"tool_model"`
+}
+
+// Chain returns the model and its fallbacks in the order they are tried,
+// with the gaps of each fallback filled in
+func (l LLMConfig) Chain() []LLMConfig {
+	primary := l
+	primary.Fallbacks, primary.ToolModel = nil, nil
+	chain := []LLMConfig{primary}
+	for _, f := range l.Fallbacks {
+		chain = append(chain, l.fill(f))
+	}
+	return chain
+}
+
+// Tools returns the tool model with its gaps filled in, if one is set
+func (l LLMConfig) Tools() (LLMConfig, bool) {
+	if l.ToolModel == nil || l.ToolModel.Model == "" {
+		return LLMConfig{}, false
+	}
+	return l.fill(*l.ToolModel), true
+}
+
+// fill completes a fallback or tool model from l. Without a base_url it
+// uses the endpoint and API key of l, and without a model also its model
+// and, unless it has its own, its prices.
+func (l LLMConfig) fill(f LLMConfig) LLMConfig {
+	f.Fallbacks, f.ToolModel = nil, nil
+	if f.BaseURL == "" {
+		f.BaseURL, f.APIKey = l.BaseURL, cmp.Or(f.APIKey, l.APIKey)
+		f.Provider = cmp.Or(f.Provider, l.Provider)
+	}
+	if f.Model == "" && f.InputPrice == 0 && f.OutputPrice == 0 {
+		f.InputPrice, f.OutputPrice = l.InputPrice, l.OutputPrice
+	}
+	f.Model = cmp.Or(f.Model, l.Model)
+	return f
+}
+
+// LayoutConfig sets the panes the TUI starts with
+type LayoutConfig struct {
+	Preset  string `yaml:"preset"`  // split (default), chat or tasks
+	Sidebar int    `yaml:"sidebar"` // Task list width in the split layout, in percent
+}
+
+// TagIconsConfig maps values of a custom field to glyphs
+type TagIconsConfig struct {
+	Field string `yaml:"field"` // Custom field to look up, e.g. project
+	// NerdFont enables glyphs from the Nerd Font private use area. Fonts
+	// cannot be detected from a terminal, so they are off by default.
+	NerdFont bool              `yaml:"nerd_font"`
+	Icons    map[string]string `yaml:"icons"` // Field value -> glyph; empty uses built-in ones
+	// Colors gives tasks without their own color label the one of their
+	// field value, e.g. {work: blue, personal: green}
+	Colors map[string]string `yaml:"colors"`
+}
+
+// ProjectConfig is what tasks in one project get unless they say otherwise
+type ProjectConfig struct {
+	Duration     time.Duration `yaml:"duration"`      // Length of tasks added without an end time
+	RemindBefore time.Duration `yaml:"remind_before"` // Remind this long before the start instead of at it
+	Color        string        `yaml:"color"`         // Color label for tasks without one
+	Flexible     *bool         `yaml:"flexible"`      // Whether the tasks may be moved; unset keeps the task's own
+	// Hours confine the tasks to part of the day, e.g. "09:00-12:00";
+	// rebalancing keeps them inside and tasks placed outside are warned about
+	Hours string `yaml:"hours"`
 }
 
 type DatabaseConfig struct {
 	Path string `yaml:"path"`
+	// Project selects a per-repository database inside git repositories:
+	// off (default), auto (use or create <repo>/.gomentum/tasks.db) or ask
+	// (use it if it exists, otherwise offer to create it)
+	Project string `yaml:"project"`
 }
 
 type AgentConfig struct {
-	MaxHistory int `yaml:"max_history"` // Number of messages to keep in context
+	MaxHistory int  `yaml:"max_history"` // Messages kept in context; older turns are summarized
+	SQLTool    bool `yaml:"sql_tool"`    // Let the agent run read-only SQL through the sql_query tool
+	// GitRepos are local repositories whose commits the git_activity tool
+	// reports, for comparing the plan with the work done; empty disables it
+	GitRepos []string `yaml:"git_repos"`
+	// Budget stops the agent once a turn or a day has used this much
+	Budget BudgetConfig `yaml:"budget"`
+	// Redact masks personal data in what is sent to a remote LLM
+	Redact RedactConfig `yaml:"redact"`
+	// DebugLog records every LLM request and response in full
+	DebugLog DebugLogConfig `yaml:"debug_log"`
+	// Timeouts bound LLM requests and tool calls; zero is no limit
+	Timeouts TimeoutsConfig `yaml:"timeouts"`
+}
+
+// TimeoutsConfig keeps a slow LLM or tool from hanging a turn. A reply cut
+// off by a timeout is kept as far as it got.
+type TimeoutsConfig struct {
+	Request    time.Duration `yaml:"request"`     // One LLM call, from sending it to the end of the reply
+	StreamIdle time.Duration `yaml:"stream_idle"` // Longest wait for the next chunk of a streamed reply
+	Tool       time.Duration `yaml:"tool"`        // One tool call
+}
+
+// DebugLogConfig writes the full LLM requests and responses and the tool
+// calls to a file of their own, with secrets masked. /debug turns it on
+// and off while Gomentum runs.
+type DebugLogConfig struct {
+	Enabled  bool   `yaml:"enabled"`   // Start with the log on
+	Path     string `yaml:"path"`      // Default ~/.gomentum/debug.log
+	MaxSize  int    `yaml:"max_size"`  // Megabytes before the file is rotated
+	MaxFiles int    `yaml:"max_files"` // Rotated files kept, as debug.log.1 and so on
+}
+
+// RedactConfig masks personal data in prompts, history and tool results
+// before they reach the LLM, and puts it back into the replies. A model
+// served from localhost is sent everything as is.
+type RedactConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Mask lists what to replace with placeholders such as [EMAIL_1]:
+	// email, phone, or regular expressions
+	Mask []string `yaml:"mask"`
+	// Aliases replace names with stand-ins, e.g. {"Acme Corp": "Client A"}
+	Aliases map[string]string `yaml:"aliases"`
+}
+
+// BudgetConfig caps the tool calls, tokens and estimated cost of the agent
+// per turn (one message) and per local day. Zero limits are off. Costs are
+// estimated from the prices of the model that made each call, so cost
+// limits need the prices of every model under llm.
+type BudgetConfig struct {
+	PerTurn  BudgetLimits `yaml:"per_turn"`
+	PerDay   BudgetLimits `yaml:"per_day"`
+	Currency string       `yaml:"currency"` // Shown with costs, e.g. "USD"
+}
+
+// BudgetLimits are the most the agent may use in a period
+type BudgetLimits struct {
+	ToolCalls int     `yaml:"tool_calls"`
+	Tokens    int     `yaml:"tokens"`
+	Cost      float64 `yaml:"cost"`
+}
+
+// ServerConfig controls the HTTP server (MCP over SSE plus JSON API)
+type ServerConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Addr    string `yaml:"addr"`
+	// BaseURL is the public URL clients reach the server at (e.g. behind a
+	// proxy). When empty, MCP clients get relative message endpoints.
+	BaseURL string `yaml:"base_url"`
+	// Token authenticates requests from outside, as a bearer token: the
+	// MCP endpoints, the task API and /capture. While it is empty,
+	// /capture is off and the rest only answer clients on this machine.
+	Token string `yaml:"token"`
+	// FeedToken only opens the Atom and RSS feeds, which take no other
+	// token; it can sit in a feed reader's URL without granting writes.
+	// While it is empty, the feeds are off.
+	FeedToken string `yaml:"feed_token"`
+}
+
+// NetworkConfig routes the LLM client and the integrations through a
+// proxy and trusts extra certificate authorities, e.g. behind a corporate
+// firewall. Without a proxy, HTTP_PROXY and HTTPS_PROXY apply.
+type NetworkConfig struct {
+	Proxy   string   `yaml:"proxy"`    // http://, https://, socks5:// or socks5h:// URL
+	NoProxy []string `yaml:"no_proxy"` // Hosts reached directly; "example.com" covers its subdomains
+	// CABundle is a PEM file of CAs trusted next to the system ones
+	CABundle string `yaml:"ca_bundle"`
+}
+
+// EmbeddingsConfig sets the model that turns tasks into vectors for
+// semantic search, independent of the chat model
+type EmbeddingsConfig struct {
+	// Provider is "local" (default, a built-in model that matches similar
+	// words and runs offline), "ollama" for a local Ollama server,
+	// "openai" for any compatible /embeddings endpoint, or "off"
+	Provider string `yaml:"provider"`
+	BaseURL  string `yaml:"base_url"` // Default for ollama: http://localhost:11434/v1
+	APIKey   string `yaml:"api_key"`
+	Model    string `yaml:"model"` // Default for ollama: nomic-embed-text
+}
+
+// UpdateConfig controls release checks
+type UpdateConfig struct {
+	CheckOnStartup bool `yaml:"check_on_startup"` // Opt-in check for a newer release when the TUI starts
+	DisableNetwork bool `yaml:"disable_network"`  // Never contact the release server, not even for `gomentum update`
+}
+
+// AppleRemindersConfig controls the macOS Reminders inbox
+type AppleRemindersConfig struct {
+	Enabled      bool          `yaml:"enabled"`       // Pull reminders while the daemon runs
+	List         string        `yaml:"list"`          // Reminders list used as the inbox
+	PollInterval time.Duration `yaml:"poll_interval"` // e.g. "5m"
+}
+
+// OutlookConfig controls the Microsoft Graph calendar sync
+type OutlookConfig struct {
+	Enabled      bool          `yaml:"enabled"`       // Sync while the daemon runs
+	ClientID     string        `yaml:"client_id"`     // Azure app registration (public client, device flow enabled)
+	Tenant       string        `yaml:"tenant"`        // "common", "organizations" or a tenant ID
+	PushTasks    bool          `yaml:"push_tasks"`    // Also create Outlook events for Gomentum tasks
+	Days         int           `yaml:"days"`          // How many days ahead to sync
+	SyncInterval time.Duration `yaml:"sync_interval"` // e.g. "15m"
+}
+
+// RemindersConfig controls how due tasks are announced
+type RemindersConfig struct {
+	Desktop bool        `yaml:"desktop"` // Desktop notification; on by default
+	Sound   SoundConfig `yaml:"sound"`
+	// Email sends reminders to these addresses through the smtp settings
+	Email []string `yaml:"email"`
+	// Routes pick the channels per task; the first match wins and tasks
+	// matching none go out on every configured channel
+	Routes []RouteConfig `yaml:"routes"`
+}
+
+// RouteConfig sends reminders for matching tasks to some channels only.
+// Empty conditions match every task.
+type RouteConfig struct {
+	Priorities []string `yaml:"priorities"` // Any of these; "medium" includes unset
+	Field      string   `yaml:"field"`      // Custom field as key=value, e.g. project=acme
+	Deadline   bool     `yaml:"deadline"`   // Only tasks with a deadline
+	Channels   []string `yaml:"channels"`   // desktop, email, sound; empty silences the reminder
+}
+
+// SoundConfig plays an audio file when a reminder fires
+type SoundConfig struct {
+	Mute   bool `yaml:"mute"`
+	Volume int  `yaml:"volume"` // 0-100
+	// Files maps a priority (low, medium, high, urgent) to an audio file;
+	// "default" covers the others. Without files no sound is played.
+	Files map[string]string `yaml:"files"`
+	// Player replaces the built-in player command, e.g. "mpv --really-quiet".
+	// The file path is appended.
+	Player string `yaml:"player"`
+}
+
+// FocusConfig controls Do Not Disturb during focus timers
+type FocusConfig struct {
+	DND bool `yaml:"dnd"` // Silence OS notifications while a deep-work timer runs
+	// Keywords mark deep-work tasks by title/description; empty means every task
+	Keywords []string `yaml:"keywords"`
+	// macOS Shortcuts that turn Focus on and off
+	MacOnShortcut  string `yaml:"macos_on_shortcut"`
+	MacOffShortcut string `yaml:"macos_off_shortcut"`
+}
+
+// ScheduleConfig describes when work can be scheduled
+type ScheduleConfig struct {
+	DayStart string `yaml:"day_start"` // "09:00"
+	DayEnd   string `yaml:"day_end"`   // "18:00"
+	Weekends bool   `yaml:"weekends"`  // Also schedule on Saturday and Sunday
+	// DailyCapacity is how many hours of planned work a day can take
+	DailyCapacity float64      `yaml:"daily_capacity"`
+	Breaks        BreaksConfig `yaml:"breaks"`
+	Travel        TravelConfig `yaml:"travel"`
+}
+
+// TravelConfig estimates the time to get between the locations of
+// consecutive tasks
+type TravelConfig struct {
+	// Default is the travel time between two different locations without
+	// a route; 0 assumes only the listed routes take time
+	Default time.Duration       `yaml:"default"`
+	Routes  []TravelRouteConfig `yaml:"routes"`
+	// Buffers inserts travel tasks before located tasks when they are
+	// added or moved; otherwise tight travel is only warned about
+	Buffers bool `yaml:"buffers"`
+}
+
+// TravelRouteConfig is the travel time between two locations, both ways
+type TravelRouteConfig struct {
+	From string        `yaml:"from"`
+	To   string        `yaml:"to"`
+	Time time.Duration `yaml:"time"`
+}
+
+// BreaksConfig shapes the breaks schedule_breaks plans between and during
+// long stretches of work
+type BreaksConfig struct {
+	After  time.Duration `yaml:"after"`  // Work without a gap this long earns a break after it
+	Length time.Duration `yaml:"length"` // Also the shortest gap that counts as rest
+	// MicroEvery spaces eye-rest micro-breaks during sessions of two hours
+	// or more; 0 plans none
+	MicroEvery  time.Duration `yaml:"micro_every"`
+	MicroLength time.Duration `yaml:"micro_length"`
+}
+
+// ActivityConfig controls how imported screen time (gomentum import
+// activitywatch|rescuetime) is judged in stats
+type ActivityConfig struct {
+	// Distractions are case-insensitive substrings of app names, window
+	// titles or categories that do not count as focused time
+	Distractions []string `yaml:"distractions"`
+}
+
+// ReviewConfig controls the weekly review of stale tasks
+type ReviewConfig struct {
+	Enabled   bool   `yaml:"enabled"`    // Notify about stale tasks once a week while the daemon runs
+	StaleDays int    `yaml:"stale_days"` // Pending tasks untouched this long are stale
+	Weekday   string `yaml:"weekday"`    // e.g. "monday"
+	Time      string `yaml:"time"`       // "09:00"
+}
+
+// AutoExportConfig keeps exported copies of the plan up to date
+type AutoExportConfig struct {
+	Enabled  bool          `yaml:"enabled"`  // Export while the daemon runs
+	Markdown string        `yaml:"markdown"` // e.g. "~/Dropbox/plan.md"; empty skips it
+	ICS      string        `yaml:"ics"`      // e.g. "~/Dropbox/schedule.ics"; empty skips it
+	Interval time.Duration `yaml:"interval"` // Rewrite this often; 0 rewrites after every change
+}
+
+// WebhooksConfig connects automation platforms such as Zapier or n8n
+type WebhooksConfig struct {
+	// Outgoing are posted a signed JSON payload on task events
+	Outgoing []WebhookConfig `yaml:"outgoing"`
+	// Fields maps task properties to the keys of payloads sent to
+	// /hooks/create-task, e.g. {title: subject, start_time: event.start}.
+	// Unmapped properties are read from keys of the same name.
+	Fields map[string]string `yaml:"fields"`
+	// Secret verifies payloads sent to /hooks/create-task, signed the way
+	// outgoing ones are; while it is empty, the endpoint is off
+	Secret string `yaml:"secret"`
+}
+
+// WebhookConfig is a URL called on task events
+type WebhookConfig struct {
+	URL string `yaml:"url"`
+	// Events to send: task.created, task.updated, task.completed and
+	// task.deleted; empty sends them all
+	Events []string `yaml:"events"`
+	// Secret signs each payload with HMAC-SHA256 in X-Gomentum-Signature
+	Secret string `yaml:"secret"`
+}
+
+// ExportConfig confines the files the agent exports
+type ExportConfig struct {
+	Dir      string `yaml:"dir"`      // The agent may only write here; default ~/.gomentum/exports
+	Filename string `yaml:"filename"` // Default name; {date} and {time} are filled in
+}
+
+// SMTPConfig is the outgoing mail server used to send invites
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"` // 587 uses STARTTLS, 465 implicit TLS
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"` // e.g. "Jane Doe <jane@example.com>"; also the invite organizer
+}
+
+// FormatConfig controls how dates and times are displayed in the TUI,
+// exports and notifications. Date layouts use the placeholders of the
+// locale files, e.g. "{yyyy}-{mm}-{dd}"; empty keeps the locale's layout.
+type FormatConfig struct {
+	Clock     string `yaml:"clock"`      // "24h" or "12h"
+	WeekStart string `yaml:"week_start"` // First day of the week, e.g. "monday" or "sunday"
+	Date      string `yaml:"date"`       // Short date, e.g. "{wd}, {mon} {d}"
+	DateLong  string `yaml:"date_long"`  // Full date, e.g. "{weekday}, {month} {d}, {yyyy}"
+}
+
+// StatusConfig is a user-defined task status
+type StatusConfig struct {
+	Name  string `yaml:"name"`  // e.g. "waiting"
+	Color string `yaml:"color"` // TUI color, e.g. "#D7875F" or "208"
+	Done  bool   `yaml:"done"`  // Counts as finished: no reminders, no blocked time
+}
+
+// TelemetryConfig controls anonymous usage reporting, which is off unless
+// enabled. Reports hold feature usage counts and crash signatures only.
+type TelemetryConfig struct {
+	Enabled  bool          `yaml:"enabled"`  // Opt in to reporting
+	Endpoint string        `yaml:"endpoint"` // URL receiving the JSON reports
+	Interval time.Duration `yaml:"interval"` // Least time between reports, e.g. "24h"
+}
+
+// HookConfig is a shell command run on a Gomentum event (see internal/hooks)
+type HookConfig struct {
+	Event      string `yaml:"event"` // e.g. "pre-focus", "post-focus"
+	Command    string `yaml:"command"`
+	Background bool   `yaml:"background"` // Keep running until the matching post- event
+}
+
+// DefaultDir returns the directory holding config, database, logs and the instance lock.
+// GOMENTUM_HOME overrides it, e.g. to point at a mounted volume in containers.
+func DefaultDir() (string, error) {
+	if dir := os.Getenv("GOMENTUM_HOME"); dir != "" {
+		return dir, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".gomentum"), nil
 }
 
 // LoadConfig loads configuration from file or environment variables
@@ -41,6 +482,69 @@ func LoadConfig(path string) (*Config, error) {
 		},
 		Agent: AgentConfig{
 			MaxHistory: 20,
+			DebugLog:   DebugLogConfig{MaxSize: 10, MaxFiles: 3},
+			Timeouts:   TimeoutsConfig{Request: 2 * time.Minute, StreamIdle: 30 * time.Second, Tool: time.Minute},
+		},
+		Server: ServerConfig{
+			Addr: "127.0.0.1:8765",
+		},
+		Embeddings: EmbeddingsConfig{
+			Provider: "local",
+		},
+		Schedule: ScheduleConfig{
+			DayStart:      "09:00",
+			DayEnd:        "18:00",
+			DailyCapacity: 7,
+			Breaks: BreaksConfig{
+				After:       90 * time.Minute,
+				Length:      15 * time.Minute,
+				MicroEvery:  30 * time.Minute,
+				MicroLength: 2 * time.Minute,
+			},
+			Travel: TravelConfig{
+				Buffers: true,
+			},
+		},
+		Review: ReviewConfig{
+			StaleDays: 14,
+			Weekday:   "monday",
+			Time:      "09:00",
+		},
+		Activity: ActivityConfig{
+			Distractions: []string{"youtube", "netflix", "reddit", "twitter", "x.com", "facebook", "instagram", "tiktok"},
+		},
+		SMTP: SMTPConfig{
+			Port: 587,
+		},
+		Export: ExportConfig{
+			Filename: "plan-{date}.md",
+		},
+		Telemetry: TelemetryConfig{
+			Interval: 24 * time.Hour,
+		},
+		Reminders: RemindersConfig{
+			Desktop: true,
+			Sound:   SoundConfig{Volume: 80},
+		},
+		TagIcons: TagIconsConfig{
+			Field: "project",
+		},
+		Layout: LayoutConfig{
+			Preset:  "split",
+			Sidebar: 30,
+		},
+		Format: FormatConfig{
+			Clock:     "24h",
+			WeekStart: "monday",
+		},
+		Outlook: OutlookConfig{
+			Tenant:       "common",
+			Days:         14,
+			SyncInterval: 15 * time.Minute,
+		},
+		AppleReminders: AppleRemindersConfig{
+			List:         "Gomentum",
+			PollInterval: 5 * time.Minute,
 		},
 	}
 
@@ -56,6 +560,13 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to open config file: %w", err)
 	}
 
+	// .env files: the working directory wins over the config directory
+	for _, envPath := range []string{".env", filepath.Join(filepath.Dir(path), ".env")} {
+		if err := LoadDotEnv(envPath); err != nil {
+			return nil, err
+		}
+	}
+
 	// Override with environment variables if set
 	if apiKey := os.Getenv("LLM_API_KEY"); apiKey != "" {
 		cfg.LLM.APIKey = apiKey
@@ -66,15 +577,57 @@ func LoadConfig(path string) (*Config, error) {
 	if model := os.Getenv("LLM_MODEL"); model != "" {
 		cfg.LLM.Model = model
 	}
+	if provider := os.Getenv("LLM_PROVIDER"); provider != "" {
+		cfg.LLM.Provider = provider
+	}
+
+	// GOMENTUM_* variables can override any key and take precedence
+	if err := applyEnv(cfg); err != nil {
+		return nil, err
+	}
+	if cfg.Export.Dir == "" {
+		cfg.Export.Dir = filepath.Join(filepath.Dir(path), "exports")
+	}
 
 	// Validate
-	if cfg.LLM.APIKey == "" {
-		return nil, fmt.Errorf("LLM API Key is missing. Please set LLM_API_KEY env var or configure it in %s", path)
+	if cfg.LocalOnly {
+		if err := cfg.applyLocalOnly(); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.checkPrices(); err != nil {
+		return nil, err
+	}
+	if cfg.Server.FeedToken != "" && cfg.Server.FeedToken == cfg.Server.Token {
+		return nil, fmt.Errorf("server.feed_token must differ from server.token, which allows writes")
+	}
+	// Local models such as Ollama need no key
+	if cfg.LLM.APIKey == "" && cfg.LLM.Provider != "fake" && !cfg.LocalOnly {
+		return nil, fmt.Errorf("LLM API Key is missing. Please set LLM_API_KEY (or GOMENTUM_LLM_API_KEY) env var or configure it in %s", path)
 	}
 
 	return cfg, nil
 }
 
+// checkPrices rejects cost limits while a model that may be called has no
+// prices, as its calls would never count against them
+func (c *Config) checkPrices() error {
+	b := c.Agent.Budget
+	if b.PerTurn.Cost <= 0 && b.PerDay.Cost <= 0 {
+		return nil
+	}
+	models := c.LLM.Chain()
+	if tools, ok := c.LLM.Tools(); ok {
+		models = append(models, tools)
+	}
+	for _, m := range models {
+		if m.InputPrice <= 0 && m.OutputPrice <= 0 {
+			return fmt.Errorf("agent.budget sets a cost limit, but model %q has no input_price or output_price; set them under llm, for each fallback and for the tool model", m.Model)
+		}
+	}
+	return nil
+}
+
 // SaveConfig saves the configuration to a file
 func SaveConfig(path string, cfg *Config) error {
 	f, err := os.Create(path)
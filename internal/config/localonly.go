@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// applyLocalOnly enforces local_only: the LLM must be served from this
+// machine, and everything that would reach out over the network is
+// turned off. It fails rather than guess when the LLM or the HTTP server
+// could be reached from elsewhere.
+func (c *Config) applyLocalOnly() error {
+	// Fallbacks and the tool model must be local too
+	llms := c.LLM.Chain()
+	if tools, ok := c.LLM.Tools(); ok {
+		llms = append(llms, tools)
+	}
+	for _, llm := range llms {
+		if llm.Provider == "fake" {
+			continue
+		}
+		u, err := url.Parse(llm.BaseURL)
+		if err != nil || u.Hostname() == "" {
+			return fmt.Errorf("local_only: invalid llm.base_url %q", llm.BaseURL)
+		}
+		if !isLoopback(u.Hostname()) {
+			return fmt.Errorf("local_only: llm.base_url %s does not point at this machine; use a local model such as Ollama on http://localhost:11434/v1", llm.BaseURL)
+		}
+	}
+	// The ollama provider defaults to localhost
+	if c.Embeddings.Provider == "openai" || (c.Embeddings.Provider == "ollama" && c.Embeddings.BaseURL != "") {
+		if u, err := url.Parse(c.Embeddings.BaseURL); err != nil || !isLoopback(u.Hostname()) {
+			return fmt.Errorf("local_only: embeddings.base_url %q does not point at this machine; use a local model or embeddings.provider: local", c.Embeddings.BaseURL)
+		}
+	}
+	if c.Server.Enabled {
+		host, _, err := net.SplitHostPort(c.Server.Addr)
+		if err != nil || !isLoopback(host) {
+			return fmt.Errorf("local_only: server.addr %q must listen on a loopback address such as 127.0.0.1", c.Server.Addr)
+		}
+	}
+
+	c.Update.CheckOnStartup = false
+	c.Update.DisableNetwork = true
+	c.Telemetry.Enabled = false
+	c.Outlook.Enabled = false
+	c.Webhooks.Outgoing = nil
+	c.SMTP.Host = ""
+	c.Reminders.Email = nil
+	return nil
+}
+
+// isLoopback reports whether host is localhost, a loopback address or a
+// name that only resolves to loopback addresses
+func isLoopback(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.IsLoopback()
+	}
+	ips, err := net.LookupIP(host)
+	if err != nil || len(ips) == 0 {
+		return false
+	}
+	for _, ip := range ips {
+		if !ip.IsLoopback() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Values of database.project
+const (
+	ProjectOff  = "off"
+	ProjectAuto = "auto"
+	ProjectAsk  = "ask"
+)
+
+// ProjectRoot returns the root of the git repository containing dir, or
+// false outside of one. Worktrees and submodules, where .git is a file,
+// count as well.
+func ProjectRoot(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// ProjectDatabase returns the project-local database of the repository
+// containing dir: <root>/.gomentum/tasks.db
+func ProjectDatabase(dir string) (string, bool) {
+	root, ok := ProjectRoot(dir)
+	if !ok {
+		return "", false
+	}
+	return filepath.Join(root, ".gomentum", "tasks.db"), true
+}
+
+// UseProjectDatabase points Database.Path at the database of the git
+// repository the working directory is in, as database.project asks for.
+// In ask mode, confirm decides whether a missing one is created; a nil
+// confirm keeps the global database. It reports whether the project
+// database is used.
+func (c *Config) UseProjectDatabase(confirm func(path string) bool) (bool, error) {
+	switch c.Database.Project {
+	case "", ProjectOff:
+		return false, nil
+	case ProjectAuto, ProjectAsk:
+	default:
+		return false, fmt.Errorf("invalid database.project %q: want off, auto or ask", c.Database.Project)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return false, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	path, ok := ProjectDatabase(cwd)
+	if !ok {
+		return false, nil
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if c.Database.Project == ProjectAsk && (confirm == nil || !confirm(path)) {
+			return false, nil
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return false, fmt.Errorf("failed to create project database directory: %w", err)
+		}
+	} else if err != nil {
+		return false, fmt.Errorf("failed to check project database: %w", err)
+	}
+	c.Database.Path = path
+	return true, nil
+}
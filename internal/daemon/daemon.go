@@ -0,0 +1,215 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"gomentum/internal/agent"
+	"gomentum/internal/applereminders"
+	"gomentum/internal/autoexport"
+	"gomentum/internal/config"
+	"gomentum/internal/instance"
+	gmcp "gomentum/internal/mcp"
+	"gomentum/internal/outlook"
+	"gomentum/internal/planner"
+	"gomentum/internal/prompts"
+	"gomentum/internal/reminder"
+	"gomentum/internal/review"
+	"gomentum/internal/server"
+	"gomentum/internal/telemetry"
+	"gomentum/internal/webhooks"
+)
+
+// Daemon bundles the headless services: the reminder poller, the HTTP
+// server (when enabled) and the instance bridge.
+type Daemon struct {
+	cfg     *config.Config
+	dir     string
+	lock    *instance.Lock
+	planner *planner.Planner
+	agent   agent.Agent
+	srv     *server.Server
+
+	// The agent keeps conversation state, so turns are serialized
+	chatMu sync.Mutex
+}
+
+// New acquires the instance lock and initializes the planner and agent.
+// mode is recorded in the lock file ("daemon", "tray").
+func New(cfg *config.Config, dir, mode string) (*Daemon, error) {
+	lock, running, err := instance.Acquire(dir, mode)
+	if errors.Is(err, instance.ErrRunning) {
+		return nil, fmt.Errorf("gomentum is already running (PID %d, mode %s)", running.PID, running.Mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := planner.NewPlanner(cfg.Database.Path)
+	if err != nil {
+		lock.Release()
+		return nil, fmt.Errorf("failed to initialize planner: %w", err)
+	}
+
+	ms := gmcp.NewServer(p)
+	if list, err := prompts.Load(prompts.Dir(dir)); err != nil {
+		slog.Warn("Failed to load prompts", "error", err)
+	} else {
+		ms.AddPrompts(list)
+	}
+
+	ag, err := agent.NewAgent(cfg, ms, p)
+	if err != nil {
+		p.Close()
+		lock.Release()
+		return nil, fmt.Errorf("failed to initialize agent: %w", err)
+	}
+
+	d := &Daemon{
+		cfg:     cfg,
+		dir:     dir,
+		lock:    lock,
+		planner: p,
+		agent:   ag,
+	}
+	if cfg.Server.Enabled {
+		d.srv = server.NewServer(cfg.Server, cfg.Webhooks, p, ms)
+	}
+	lock.SetHandler(d.handleBridge)
+	return d, nil
+}
+
+// Planner returns the planner shared by all daemon services
+func (d *Daemon) Planner() *planner.Planner {
+	return d.planner
+}
+
+// Start launches the background services. They stop when ctx is cancelled;
+// onFatal is called if a service fails on its own.
+func (d *Daemon) Start(ctx context.Context, onFatal func(error)) {
+	go reminder.Run(ctx, d.planner, d.cfg, nil)
+	go telemetry.Run(ctx)
+
+	if d.cfg.AutoExport.Enabled {
+		go autoexport.Run(ctx, d.cfg.AutoExport, d.planner)
+	}
+
+	if len(d.cfg.Webhooks.Outgoing) > 0 {
+		go webhooks.Run(ctx, d.cfg.Webhooks, d.planner)
+	}
+
+	if d.cfg.Review.Enabled {
+		go review.Run(ctx, d.cfg.Review, d.planner)
+	}
+	if d.cfg.Outlook.Enabled {
+		go outlook.Run(ctx, d.cfg.Outlook, d.dir, d.planner)
+	}
+	if d.cfg.AppleReminders.Enabled {
+		go applereminders.Run(ctx, d.cfg.AppleReminders, d.planner, func(ctx context.Context, text string) error {
+			_, err := d.Chat(ctx, text)
+			return err
+		})
+	}
+
+	if d.srv != nil {
+		go func() {
+			if err := d.srv.Start(); err != nil {
+				onFatal(err)
+			}
+		}()
+	}
+	slog.Info("Daemon started", "pid", os.Getpid(), "server", d.srv != nil)
+}
+
+// Close shuts down the server, sends a due telemetry report and releases
+// the database and lock
+func (d *Daemon) Close() {
+	telemetry.Close()
+	if d.srv != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := d.srv.Shutdown(ctx); err != nil {
+			slog.Warn("HTTP server shutdown failed", "error", err)
+		}
+	}
+	d.planner.Close()
+	d.lock.Release()
+}
+
+// Chat runs a single agent turn. Turns from different sources are serialized.
+func (d *Daemon) Chat(ctx context.Context, text string) (string, error) {
+	d.chatMu.Lock()
+	defer d.chatMu.Unlock()
+	resp, err := d.agent.Chat(ctx, text, nil)
+	return resp.Text, err
+}
+
+// QuickAdd runs text through the agent in the background and reports the
+// reply through onDone (which may be nil).
+func (d *Daemon) QuickAdd(text string, onDone func(reply string, err error)) {
+	go func() {
+		reply, err := d.Chat(context.Background(), text)
+		if err != nil {
+			slog.Error("Quick-add failed", "error", err)
+		} else {
+			slog.Info("Quick-add handled", "reply", reply)
+		}
+		if onDone != nil {
+			onDone(reply, err)
+		}
+	}()
+}
+
+func (d *Daemon) handleBridge(req instance.Request) instance.Response {
+	switch req.Command {
+	case "quickadd":
+		text := strings.TrimSpace(req.Text)
+		if text == "" {
+			return instance.Response{Message: "empty quick-add text"}
+		}
+		d.QuickAdd(text, nil)
+		return instance.Response{OK: true, Message: "Queued in Gomentum daemon"}
+	default:
+		return instance.Response{Message: fmt.Sprintf("unknown command: %s", req.Command)}
+	}
+}
+
+// Run starts Gomentum headless until SIGINT/SIGTERM is received
+func Run(cfg *config.Config, dir string) error {
+	return run(cfg, dir, "daemon")
+}
+
+// Serve runs the daemon as a container entrypoint: the HTTP server is
+// always enabled and nothing assumes a terminal.
+func Serve(cfg *config.Config, dir string) error {
+	cfg.Server.Enabled = true
+	return run(cfg, dir, "serve")
+}
+
+func run(cfg *config.Config, dir, mode string) error {
+	d, err := New(cfg, dir, mode)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	d.Start(ctx, func(err error) {
+		slog.Error("Daemon service stopped", "error", err)
+		stop()
+	})
+
+	<-ctx.Done()
+	slog.Info("Daemon shutting down")
+	return nil
+}
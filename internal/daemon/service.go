@@ -0,0 +1,117 @@
+package daemon
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"text/template"
+)
+
+const systemdUnit = `[Unit]
+Description=Gomentum planning agent (reminders and server)
+After=network-online.target
+
+[Service]
+Type=simple
+ExecStart={{.Exe}} daemon
+Restart=on-failure
+RestartSec=5
+
+[Install]
+WantedBy=default.target
+`
+
+const launchdPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>{{.Label}}</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>{{.Exe}}</string>
+		<string>daemon</string>
+	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<true/>
+	<key>StandardErrorPath</key>
+	<string>{{.LogDir}}/daemon.err.log</string>
+</dict>
+</plist>
+`
+
+const launchdLabel = "io.github.gomentum.daemon"
+
+// ServiceFile describes a generated service definition
+type ServiceFile struct {
+	Path     string // Where the file should be installed
+	Content  string
+	Activate string // Command the user runs to enable it
+}
+
+// GenerateService renders the service definition for the current OS
+func GenerateService(exe, logDir string) (ServiceFile, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ServiceFile{}, fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	data := struct {
+		Exe    string
+		Label  string
+		LogDir string
+	}{exe, launchdLabel, logDir}
+
+	switch runtime.GOOS {
+	case "linux":
+		content, err := render(systemdUnit, data)
+		if err != nil {
+			return ServiceFile{}, err
+		}
+		return ServiceFile{
+			Path:     filepath.Join(homeDir, ".config", "systemd", "user", "gomentum.service"),
+			Content:  content,
+			Activate: "systemctl --user daemon-reload && systemctl --user enable --now gomentum.service",
+		}, nil
+	case "darwin":
+		content, err := render(launchdPlist, data)
+		if err != nil {
+			return ServiceFile{}, err
+		}
+		path := filepath.Join(homeDir, "Library", "LaunchAgents", launchdLabel+".plist")
+		return ServiceFile{
+			Path:     path,
+			Content:  content,
+			Activate: "launchctl load -w " + path,
+		}, nil
+	default:
+		return ServiceFile{}, fmt.Errorf("service installation is not supported on %s", runtime.GOOS)
+	}
+}
+
+// InstallService writes the service definition to its standard location
+func InstallService(sf ServiceFile) error {
+	if err := os.MkdirAll(filepath.Dir(sf.Path), 0755); err != nil {
+		return fmt.Errorf("failed to create service directory: %w", err)
+	}
+	if err := os.WriteFile(sf.Path, []byte(sf.Content), 0644); err != nil {
+		return fmt.Errorf("failed to write service file: %w", err)
+	}
+	return nil
+}
+
+func render(tmpl string, data interface{}) (string, error) {
+	t, err := template.New("service").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse service template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render service template: %w", err)
+	}
+	return buf.String(), nil
+}
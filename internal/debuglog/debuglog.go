@@ -0,0 +1,180 @@
+// Package debuglog records what the agent sends to and gets from the LLM
+// in full, along with every tool call, for debugging prompts. Entries are
+// JSON lines in a file of their own that is rotated by size. The log is
+// off unless agent.debug_log.enabled is set or /debug turns it on, and
+// secrets from the config, bearer tokens and API keys are masked.
+package debuglog
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"gomentum/internal/config"
+)
+
+// Kinds of entries
+const (
+	Request  = "llm_request"
+	Response = "llm_response"
+	Tool     = "tool_call"
+)
+
+// masks match secrets that are not in the config, e.g. keys pasted into a
+// prompt. The JSON pattern keeps the key and masks the value.
+var masks = []struct {
+	re   *regexp.Regexp
+	with string
+}{
+	{regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{16,}`), "sk-REDACTED"},
+	{regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/-]{8,}=*`), "Bearer REDACTED"},
+	{regexp.MustCompile(`(?i)(\\?"[a-z_]*(?:api_key|password|secret|token)\\?"\s*:\s*\\?")[^"\\]+`), "${1}REDACTED"},
+}
+
+var (
+	mu      sync.Mutex
+	cfg     config.DebugLogConfig
+	path    string
+	secrets []string
+	on      bool
+	file    *os.File
+	size    int64
+)
+
+// Configure sets up the log from the config; secrets are masked wherever
+// they appear. It starts the log when the config enables it.
+func Configure(c config.DebugLogConfig, secretValues []string) {
+	mu.Lock()
+	defer mu.Unlock()
+	closeFile()
+	cfg, on = c, c.Enabled
+	path = c.Path
+	if path == "" {
+		if dir, err := config.DefaultDir(); err == nil {
+			path = filepath.Join(dir, "debug.log")
+		}
+	}
+	secrets = secrets[:0]
+	for _, s := range secretValues {
+		// Short values such as "1" would mask half the log
+		if len(s) >= 4 {
+			secrets = append(secrets, s)
+		}
+	}
+}
+
+// Path returns the file the log is written to
+func Path() string {
+	mu.Lock()
+	defer mu.Unlock()
+	return path
+}
+
+// Enabled reports whether entries are being written
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return on
+}
+
+// SetEnabled turns the log on or off
+func SetEnabled(enabled bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	on = enabled
+	if !on {
+		closeFile()
+	}
+}
+
+// Log writes an entry of kind with data encoded as JSON. Failures are
+// logged to the main log and otherwise ignored.
+func Log(kind string, data any) {
+	mu.Lock()
+	defer mu.Unlock()
+	if !on || path == "" {
+		return
+	}
+	entry, err := json.Marshal(struct {
+		Time time.Time `json:"time"`
+		Kind string    `json:"kind"`
+		Data any       `json:"data"`
+	}{time.Now(), kind, data})
+	if err != nil {
+		slog.Warn("Failed to encode debug log entry", "kind", kind, "error", err)
+		return
+	}
+	line := append([]byte(mask(string(entry))), '\n')
+	if err := write(line); err != nil {
+		slog.Warn("Failed to write debug log", "path", path, "error", err)
+	}
+}
+
+// mask replaces the configured secrets and anything that looks like one
+func mask(s string) string {
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, "REDACTED")
+	}
+	for _, m := range masks {
+		s = m.re.ReplaceAllString(s, m.with)
+	}
+	return s
+}
+
+// write appends line, rotating first if it would make the file too big.
+// mu must be held.
+func write(line []byte) error {
+	if file == nil {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return err
+		}
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return err
+		}
+		file, size = f, info.Size()
+	}
+	if limit := int64(cfg.MaxSize) << 20; limit > 0 && size > 0 && size+int64(len(line)) > limit {
+		if err := rotate(); err != nil {
+			return err
+		}
+		return write(line)
+	}
+	n, err := file.Write(line)
+	size += int64(n)
+	return err
+}
+
+// rotate shifts debug.log to debug.log.1, debug.log.1 to debug.log.2 and
+// so on, dropping the oldest beyond MaxFiles. mu must be held.
+func rotate() error {
+	closeFile()
+	keep := max(cfg.MaxFiles, 0)
+	if keep == 0 {
+		return os.Remove(path)
+	}
+	os.Remove(fmt.Sprintf("%s.%d", path, keep))
+	for i := keep - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", path, i), fmt.Sprintf("%s.%d", path, i+1))
+	}
+	return os.Rename(path, path+".1")
+}
+
+// closeFile closes the open file, if any. mu must be held.
+func closeFile() {
+	if file != nil {
+		file.Close()
+		file, size = nil, 0
+	}
+}
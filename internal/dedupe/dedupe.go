@@ -0,0 +1,144 @@
+// Package dedupe finds tasks that are probably the same piece of work
+// entered twice, such as by the agent and by an import: tasks with
+// similar titles that start around the same time.
+package dedupe
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"gomentum/internal/planner"
+)
+
+// DefaultWindow is how far apart the starts of two duplicates may be
+const DefaultWindow = 2 * time.Hour
+
+// Threshold is the title similarity from which two tasks around the same
+// time count as duplicates
+const Threshold = 0.75
+
+// Entry is one task of a pair
+type Entry struct {
+	ID     int            `json:"id"`
+	Title  string         `json:"title"`
+	Start  time.Time      `json:"start_time"`
+	Status planner.Status `json:"status"`
+}
+
+// Pair is two tasks that look like duplicates, the one added first first
+type Pair struct {
+	First      Entry   `json:"first"`
+	Second     Entry   `json:"second"`
+	Similarity float64 `json:"similarity"` // Of the titles, 1 when equal
+}
+
+// Find returns the pairs of duplicate tasks, most similar first. Finished
+// tasks are left out unless includeDone is set.
+func Find(tasks []planner.Task, window time.Duration, includeDone bool) []Pair {
+	var open []planner.Task
+	for _, t := range tasks {
+		if t.System() == "" && (includeDone || !t.Status.Done()) {
+			open = append(open, t)
+		}
+	}
+	sort.SliceStable(open, func(i, j int) bool { return open[i].StartTime.Before(open[j].StartTime) })
+
+	var pairs []Pair
+	for i, a := range open {
+		for _, b := range open[i+1:] {
+			if b.StartTime.Sub(a.StartTime) > window {
+				break
+			}
+			if !related(a, b) {
+				continue
+			}
+			if s := Similarity(a.Title, b.Title); s >= Threshold {
+				first, second := a, b
+				if second.ID < first.ID {
+					first, second = second, first
+				}
+				pairs = append(pairs, Pair{First: entry(first), Second: entry(second), Similarity: s})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].Similarity > pairs[j].Similarity })
+	return pairs
+}
+
+// Of returns the tasks that look like duplicates of t, most similar first
+func Of(t planner.Task, tasks []planner.Task, window time.Duration) []planner.Task {
+	type match struct {
+		task  planner.Task
+		score float64
+	}
+	var matches []match
+	for _, o := range tasks {
+		if o.ID == t.ID || o.System() != "" || o.Status.Done() || !related(t, o) {
+			continue
+		}
+		if d := t.StartTime.Sub(o.StartTime); d > window || d < -window {
+			continue
+		}
+		if s := Similarity(t.Title, o.Title); s >= Threshold {
+			matches = append(matches, match{o, s})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	out := make([]planner.Task, len(matches))
+	for i, m := range matches {
+		out[i] = m.task
+	}
+	return out
+}
+
+// Similarity compares two titles by the letter pairs they share, ignoring
+// case, punctuation and spacing: 1 for the same title, 0 for nothing in
+// common. "Team standup" and "Standup" score 0.75, "Call mom" and "Call
+// dad" 0.5.
+func Similarity(a, b string) float64 {
+	ra, rb := normalize(a), normalize(b)
+	if string(ra) == string(rb) {
+		if len(ra) == 0 {
+			return 0
+		}
+		return 1
+	}
+	if len(ra) < 2 || len(rb) < 2 {
+		return 0
+	}
+	pairs := make(map[string]int)
+	for i := 0; i+1 < len(ra); i++ {
+		pairs[string(ra[i:i+2])]++
+	}
+	shared := 0
+	for i := 0; i+1 < len(rb); i++ {
+		if p := string(rb[i : i+2]); pairs[p] > 0 {
+			pairs[p]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(ra)+len(rb)-2)
+}
+
+// related reports whether two tasks could be duplicates at all:
+// occurrences of the same recurring task are not
+func related(a, b planner.Task) bool {
+	return a.Series() == 0 || a.Series() != b.Series()
+}
+
+// normalize keeps the lower-case letters and digits of a title
+func normalize(s string) []rune {
+	var out []rune
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func entry(t planner.Task) Entry {
+	return Entry{ID: t.ID, Title: t.Title, Start: t.StartTime, Status: t.Status}
+}
@@ -0,0 +1,228 @@
+// Package embed turns text into vectors whose cosine similarity says how
+// close two texts are in meaning, for semantic search. The model is set
+// under embeddings in the config, apart from the chat model: the built-in
+// one runs offline, a local Ollama works without further settings, and
+// any OpenAI-compatible endpoint can be used instead.
+package embed
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+
+	"gomentum/internal/config"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// Providers selectable with embeddings.provider
+const (
+	ProviderLocal  = "local"
+	ProviderOllama = "ollama"
+	ProviderOpenAI = "openai"
+	ProviderOff    = "off"
+)
+
+// The defaults of the ollama provider
+const (
+	OllamaBaseURL = "http://localhost:11434/v1"
+	OllamaModel   = "nomic-embed-text"
+)
+
+// Embedder computes vectors for texts
+type Embedder interface {
+	// Embed returns one vector per text, in order
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+	// Model names the model; vectors of different models do not compare
+	Model() string
+}
+
+// New returns the embedder of the config, or nil when it is off
+func New(cfg config.EmbeddingsConfig) (Embedder, error) {
+	switch cfg.Provider {
+	case "", ProviderLocal:
+		return local{}, nil
+	case ProviderOllama:
+		if cfg.BaseURL == "" {
+			cfg.BaseURL = OllamaBaseURL
+		}
+		if cfg.Model == "" {
+			cfg.Model = OllamaModel
+		}
+		c := openai.DefaultConfig(cfg.APIKey)
+		c.BaseURL = cfg.BaseURL
+		return remote{client: openai.NewClientWithConfig(c), model: cfg.Model, provider: ProviderOllama}, nil
+	case ProviderOpenAI:
+		if cfg.Model == "" {
+			return nil, fmt.Errorf("embeddings.model is required with the %s provider", ProviderOpenAI)
+		}
+		c := openai.DefaultConfig(cfg.APIKey)
+		if cfg.BaseURL != "" {
+			c.BaseURL = cfg.BaseURL
+		}
+		return remote{client: openai.NewClientWithConfig(c), model: cfg.Model, provider: ProviderOpenAI}, nil
+	case ProviderOff:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown embeddings provider %q (use %s, %s, %s or %s)", cfg.Provider, ProviderLocal, ProviderOllama, ProviderOpenAI, ProviderOff)
+	}
+}
+
+// Cosine returns the cosine similarity of two vectors, 0 if either is
+// empty or their lengths differ
+func Cosine(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(na*nb)
+}
+
+// remote calls the /embeddings endpoint of an OpenAI-compatible API
+type remote struct {
+	client   *openai.Client
+	model    string
+	provider string
+}
+
+func (r remote) Model() string { return r.provider + ":" + r.model }
+
+func (r remote) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	resp, err := r.client.CreateEmbeddings(ctx, openai.EmbeddingRequest{Input: texts, Model: openai.EmbeddingModel(r.model)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute embeddings: %w", err)
+	}
+	if len(resp.Data) != len(texts) {
+		return nil, fmt.Errorf("embeddings endpoint returned %d vectors for %d texts", len(resp.Data), len(texts))
+	}
+	vectors := make([][]float32, len(texts))
+	for _, d := range resp.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("embeddings endpoint returned index %d for %d texts", d.Index, len(texts))
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	return vectors, nil
+}
+
+// localDims is the size of the vectors of the built-in model
+const localDims = 512
+
+// local is the built-in model. It hashes the words of a text, their first
+// letters and their letter trigrams into a vector, so "dentist" finds
+// "dental checkup" and typos still match, but unlike a trained model it
+// knows no synonyms. Chinese text is split into characters and their
+// pairs.
+type local struct{}
+
+func (local) Model() string { return ProviderLocal + ":v1" }
+
+func (local) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, t := range texts {
+		vectors[i] = hashVector(t)
+	}
+	return vectors, nil
+}
+
+// hashVector adds each feature of text at a hashed position with a hashed
+// sign, then normalizes. Whole words weigh more than their stems and
+// trigrams; stopwords are left out so they do not drown a short query.
+func hashVector(text string) []float32 {
+	v := make([]float32, localDims)
+	add := func(feature string, weight float32) {
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		sum := h.Sum64()
+		if sum>>63 == 1 {
+			weight = -weight
+		}
+		v[sum%localDims] += weight
+	}
+	for _, word := range words(text) {
+		runes := []rune(word)
+		if unicode.Is(unicode.Han, runes[0]) {
+			for i, r := range runes {
+				add(string(r), 1)
+				if i+1 < len(runes) {
+					add(string(runes[i:i+2]), 1.5)
+				}
+			}
+			continue
+		}
+		if stopwords[word] {
+			continue
+		}
+		add("w:"+word, 2)
+		if len(runes) > stemLen {
+			add("s:"+string(runes[:stemLen]), 1.5)
+		}
+		padded := []rune("^" + word + "$")
+		for i := 0; i+3 <= len(padded); i++ {
+			add(string(padded[i:i+3]), 1)
+		}
+	}
+
+	var norm float64
+	for _, x := range v {
+		norm += float64(x) * float64(x)
+	}
+	if norm > 0 {
+		scale := float32(1 / math.Sqrt(norm))
+		for i := range v {
+			v[i] *= scale
+		}
+	}
+	return v
+}
+
+// stemLen is how many leading letters of a word stand in for its stem
+const stemLen = 4
+
+// stopwords carry no meaning of their own in a search, including the
+// vague nouns of "that thing about the dentist"
+var stopwords = map[string]bool{
+	"a": true, "about": true, "an": true, "and": true, "at": true, "by": true,
+	"for": true, "from": true, "i": true, "in": true, "is": true, "it": true,
+	"me": true, "my": true, "of": true, "on": true, "or": true,
+	"something": true, "stuff": true, "that": true, "the": true,
+	"thing": true, "things": true, "this": true, "to": true, "with": true,
+}
+
+// words splits text into lower-case runs of letters and digits, keeping
+// Han characters apart from Latin ones
+func words(text string) []string {
+	var out []string
+	var cur []rune
+	han := false
+	flush := func() {
+		if len(cur) > 0 {
+			out = append(out, string(cur))
+			cur = cur[:0]
+		}
+	}
+	for _, r := range strings.ToLower(text) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if isHan := unicode.Is(unicode.Han, r); isHan != han {
+			flush()
+			han = isHan
+		}
+		cur = append(cur, r)
+	}
+	flush()
+	return out
+}
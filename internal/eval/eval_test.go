@@ -0,0 +1,221 @@
+// Package eval replays recorded agent conversations offline. A fixture
+// holds the user prompt and the scripted LLM replies (tool calls and
+// text); the agent runs against a mock OpenAI server and a throwaway
+// planner, and the resulting tool results, reply and tasks are compared
+// with the golden transcript stored in the fixture.
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"gomentum/internal/agent"
+	"gomentum/internal/config"
+	gmcp "gomentum/internal/mcp"
+	"gomentum/internal/planner"
+)
+
+// Fixture is one recorded conversation
+type Fixture struct {
+	Name   string      `json:"-"` // File name without .json
+	Prompt string      `json:"prompt"`
+	Setup  []SeedTask  `json:"setup,omitempty"` // Tasks that exist before the turn
+	Script []Reply     `json:"script"`          // LLM replies, one per request
+	Golden *Transcript `json:"golden,omitempty"`
+}
+
+// SeedTask is a task created before the conversation starts
+type SeedTask struct {
+	Title     string    `json:"title"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Fixed     bool      `json:"fixed,omitempty"`
+}
+
+// Reply is one scripted LLM response
+type Reply struct {
+	Content   string     `json:"content,omitempty"`
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+}
+
+// ToolCall is a tool the scripted LLM asks for
+type ToolCall struct {
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// Transcript is what a replay produced. The agent's own current_time call
+// is left out because its result changes on every run.
+type Transcript struct {
+	Steps []Step      `json:"steps"`
+	Reply string      `json:"reply"`
+	Tasks []TaskState `json:"tasks"`
+}
+
+// Step is one executed tool call and its result
+type Step struct {
+	Tool   string `json:"tool"`
+	Failed bool   `json:"failed,omitempty"`
+	Result string `json:"result"`
+}
+
+// TaskState is a task after the turn
+type TaskState struct {
+	ID        int            `json:"id"`
+	Title     string         `json:"title"`
+	StartTime time.Time      `json:"start_time"`
+	EndTime   time.Time      `json:"end_time"`
+	Status    planner.Status `json:"status"`
+	Flexible  bool           `json:"flexible"`
+}
+
+var update = flag.Bool("update", false, "re-record the golden transcripts in testdata")
+
+// TestFixtures replays every fixture in testdata and compares the outcome
+// with its golden transcript, or re-records it with -update
+func TestFixtures(t *testing.T) {
+	fixtures, err := load(os.DirFS("testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatal("no fixtures in testdata")
+	}
+	for _, f := range fixtures {
+		t.Run(f.Name, func(t *testing.T) {
+			got, err := run(context.Background(), f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *update {
+				f.Golden = got
+				if err := f.save("testdata"); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if diff := diff(f.Golden, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func load(fsys fs.FS) ([]Fixture, error) {
+	names, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	var fixtures []Fixture
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		var f Fixture
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		f.Name = strings.TrimSuffix(name, ".json")
+		fixtures = append(fixtures, f)
+	}
+	return fixtures, nil
+}
+
+// save writes the fixture, including its golden transcript, to dir
+func (f Fixture) save(dir string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, f.Name+".json"), append(data, '\n'), 0644)
+}
+
+// run replays the fixture and returns what the agent did
+func run(ctx context.Context, f Fixture) (*Transcript, error) {
+	p, err := planner.NewPlanner(":memory:")
+	if err != nil {
+		return nil, err
+	}
+	defer p.Close()
+	for _, s := range f.Setup {
+		t, err := p.AddTask(s.Title, "", s.StartTime, s.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		if s.Fixed {
+			t.Flexible = false
+			if err := p.UpdateTask(t); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	llm := newMockLLM(f.Script)
+	defer llm.Close()
+
+	cfg := &config.Config{
+		LLM:   config.LLMConfig{APIKey: "eval", BaseURL: llm.URL, Model: "eval"},
+		Agent: config.AgentConfig{MaxHistory: 20},
+	}
+	ms := gmcp.NewServer(p)
+	// Fixtures are recorded in UTC; the days the tools work out must not
+	// depend on the contributor's time zone
+	ms.SetClock(func() time.Time { return time.Now().UTC() })
+	ag, err := agent.NewAgent(cfg, ms, p)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := ag.Chat(ctx, f.Prompt, nil)
+	if err != nil {
+		return nil, err
+	}
+	if n := llm.used(); n != len(f.Script) {
+		return nil, fmt.Errorf("the agent made %d LLM requests, the script has %d replies", n, len(f.Script))
+	}
+
+	tr := &Transcript{Reply: resp.Text}
+	for _, a := range resp.Actions {
+		if a.Tool == "current_time" {
+			continue
+		}
+		tr.Steps = append(tr.Steps, Step{Tool: a.Tool, Failed: a.Failed, Result: strings.TrimSpace(a.Result)})
+	}
+	tasks, err := p.ListTasks()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tasks {
+		tr.Tasks = append(tr.Tasks, TaskState{
+			ID: t.ID, Title: t.Title, StartTime: t.StartTime.UTC(), EndTime: t.EndTime.UTC(),
+			Status: t.Status, Flexible: t.Flexible,
+		})
+	}
+	return tr, nil
+}
+
+// diff describes how got differs from the golden transcript, or returns
+// "" when they match
+func diff(golden, got *Transcript) string {
+	if golden == nil {
+		return "no golden transcript; run with -update to record one"
+	}
+	if reflect.DeepEqual(golden, got) {
+		return ""
+	}
+	want, _ := json.MarshalIndent(golden, "", "  ")
+	have, _ := json.MarshalIndent(got, "", "  ")
+	return fmt.Sprintf("want:\n%s\ngot:\n%s", want, have)
+}
@@ -0,0 +1,77 @@
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// mockLLM is an OpenAI-compatible chat completions endpoint that streams
+// the scripted replies of a fixture, one per request, in order
+type mockLLM struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	script   []Reply
+	requests int
+}
+
+func newMockLLM(script []Reply) *mockLLM {
+	m := &mockLLM{script: script}
+	m.Server = httptest.NewServer(http.HandlerFunc(m.handle))
+	return m
+}
+
+func (m *mockLLM) handle(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	if m.requests >= len(m.script) {
+		m.mu.Unlock()
+		http.Error(w, `{"error":{"message":"the fixture script has no more replies"}}`, http.StatusInternalServerError)
+		return
+	}
+	reply := m.script[m.requests]
+	m.requests++
+	m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	for _, chunk := range reply.chunks() {
+		data, _ := json.Marshal(chunk)
+		fmt.Fprintf(w, "data: %s\n\n", data)
+	}
+	fmt.Fprint(w, "data: [DONE]\n\n")
+}
+
+// used reports how many scripted replies were consumed
+func (m *mockLLM) used() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.requests
+}
+
+// chunks renders the reply as streaming deltas: the text first, then one
+// delta per tool call
+func (r Reply) chunks() []map[string]any {
+	var chunks []map[string]any
+	delta := func(d map[string]any) {
+		chunks = append(chunks, map[string]any{
+			"id":      "eval",
+			"object":  "chat.completion.chunk",
+			"choices": []map[string]any{{"index": 0, "delta": d}},
+		})
+	}
+	if r.Content != "" {
+		delta(map[string]any{"role": "assistant", "content": r.Content})
+	}
+	for i, tc := range r.ToolCalls {
+		args, _ := json.Marshal(tc.Arguments)
+		delta(map[string]any{"tool_calls": []map[string]any{{
+			"index":    i,
+			"id":       fmt.Sprintf("call_%d", i),
+			"type":     "function",
+			"function": map[string]any{"name": tc.Name, "arguments": string(args)},
+		}}})
+	}
+	return chunks
+}
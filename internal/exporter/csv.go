@@ -0,0 +1,54 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+
+	"gomentum/internal/planner"
+)
+
+// csvColumns are the fixed columns; custom fields follow in alphabetical
+// order, one column per field any of the tasks has
+var csvColumns = []string{"id", "title", "description", "start_time", "end_time", "status", "priority", "deadline", "flexible", "color"}
+
+// writeCSV writes one row per task with a header row. Times are RFC3339
+// so spreadsheets and scripts read them without guessing the format.
+func writeCSV(w io.Writer, tasks []planner.Task) error {
+	var keys []string
+	for _, t := range tasks {
+		for _, k := range t.FieldKeys() {
+			if !slices.Contains(keys, k) {
+				keys = append(keys, k)
+			}
+		}
+	}
+	slices.Sort(keys)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append(slices.Clone(csvColumns), keys...)); err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		var deadline string
+		if !t.Deadline.IsZero() {
+			deadline = t.Deadline.Local().Format(time.RFC3339)
+		}
+		row := []string{
+			strconv.Itoa(t.ID), t.Title, t.Description,
+			t.StartTime.Local().Format(time.RFC3339), t.EndTime.Local().Format(time.RFC3339),
+			string(t.Status), t.Priority, deadline, strconv.FormatBool(t.Flexible), t.Color,
+		}
+		for _, k := range keys {
+			v, _ := t.FieldString(k)
+			row = append(row, v)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
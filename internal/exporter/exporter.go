@@ -0,0 +1,87 @@
+// Package exporter writes tasks as markdown, iCalendar or CSV, such as
+// the result of a query or the tasks selected in the TUI.
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gomentum/internal/invite"
+	"gomentum/internal/planner"
+)
+
+// Export formats
+const (
+	Markdown = "markdown"
+	ICS      = "ics"
+	CSV      = "csv"
+)
+
+// Formats lists the export formats
+var Formats = []string{Markdown, ICS, CSV}
+
+var extensions = map[string]string{Markdown: ".md", ICS: ".ics", CSV: ".csv"}
+
+// Ext returns the file extension of a format, e.g. ".md"
+func Ext(format string) string {
+	return extensions[format]
+}
+
+// FormatOf picks the format from a file name's extension; names without
+// a known one are markdown
+func FormatOf(name string) string {
+	ext := strings.ToLower(filepath.Ext(name))
+	for format, e := range extensions {
+		if ext == e {
+			return format
+		}
+	}
+	return Markdown
+}
+
+// WithExt gives name the extension of format, replacing the extension of
+// another export format (plan.md becomes plan.csv)
+func WithExt(name, format string) string {
+	ext := filepath.Ext(name)
+	if strings.EqualFold(ext, Ext(format)) {
+		return name
+	}
+	for _, e := range extensions {
+		if strings.EqualFold(ext, e) {
+			name = strings.TrimSuffix(name, ext)
+			break
+		}
+	}
+	return name + Ext(format)
+}
+
+// ExpandHome resolves a leading ~/ to the home directory
+func ExpandHome(path string) string {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, rest)
+}
+
+// Write writes tasks to w in format
+func Write(w io.Writer, format string, tasks []planner.Task) error {
+	switch format {
+	case Markdown:
+		return planner.WriteTasksMarkdown(w, tasks)
+	case ICS:
+		_, err := w.Write(invite.Publish(tasks, time.Now()))
+		return err
+	case CSV:
+		return writeCSV(w, tasks)
+	}
+	return fmt.Errorf("%w: unknown export format %q", planner.ErrValidation, format)
+}
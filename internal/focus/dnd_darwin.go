@@ -0,0 +1,25 @@
+//go:build darwin
+
+package focus
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+
+	"gomentum/internal/config"
+)
+
+// macOS has no public API to toggle Focus, so two user-created Shortcuts
+// do it (each with a single "Set Focus" action).
+func enable(ctx context.Context, cfg config.FocusConfig) (func(context.Context) error, error) {
+	if cfg.MacOnShortcut == "" || cfg.MacOffShortcut == "" {
+		return nil, fmt.Errorf("%w: set focus.macos_on_shortcut and focus.macos_off_shortcut", ErrUnsupported)
+	}
+	if err := exec.CommandContext(ctx, "shortcuts", "run", cfg.MacOnShortcut).Run(); err != nil {
+		return nil, fmt.Errorf("failed to run shortcut %q: %w", cfg.MacOnShortcut, err)
+	}
+	return func(ctx context.Context) error {
+		return exec.CommandContext(ctx, "shortcuts", "run", cfg.MacOffShortcut).Run()
+	}, nil
+}
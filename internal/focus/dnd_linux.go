@@ -0,0 +1,34 @@
+//go:build linux
+
+package focus
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"gomentum/internal/config"
+)
+
+// GNOME hides notification banners when show-banners is false, which is
+// what its "Do Not Disturb" toggle does.
+const (
+	gnomeSchema = "org.gnome.desktop.notifications"
+	gnomeKey    = "show-banners"
+)
+
+func enable(ctx context.Context, _ config.FocusConfig) (func(context.Context) error, error) {
+	out, err := exec.CommandContext(ctx, "gsettings", "get", gnomeSchema, gnomeKey).Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w (gsettings unavailable: %v)", ErrUnsupported, err)
+	}
+	previous := strings.TrimSpace(string(out))
+
+	if err := exec.CommandContext(ctx, "gsettings", "set", gnomeSchema, gnomeKey, "false").Run(); err != nil {
+		return nil, fmt.Errorf("failed to disable notification banners: %w", err)
+	}
+	return func(ctx context.Context) error {
+		return exec.CommandContext(ctx, "gsettings", "set", gnomeSchema, gnomeKey, previous).Run()
+	}, nil
+}
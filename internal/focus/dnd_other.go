@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !windows
+
+package focus
+
+import (
+	"context"
+
+	"gomentum/internal/config"
+)
+
+func enable(context.Context, config.FocusConfig) (func(context.Context) error, error) {
+	return nil, ErrUnsupported
+}
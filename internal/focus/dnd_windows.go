@@ -0,0 +1,49 @@
+//go:build windows
+
+package focus
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"gomentum/internal/config"
+)
+
+// Windows has no public Focus Assist API. Turning off toast notifications
+// for the user ("Get notifications from apps and other senders") has the
+// same effect and is reversible.
+const toastKey = `HKCU:\Software\Microsoft\Windows\CurrentVersion\PushNotifications`
+
+func powershell(ctx context.Context, script string) (string, error) {
+	cmd := exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+func setToasts(ctx context.Context, value string) error {
+	_, err := powershell(ctx, fmt.Sprintf(
+		`New-ItemProperty -Path '%s' -Name ToastEnabled -PropertyType DWord -Value %s -Force | Out-Null`, toastKey, value))
+	return err
+}
+
+func enable(ctx context.Context, _ config.FocusConfig) (func(context.Context) error, error) {
+	previous, err := powershell(ctx, fmt.Sprintf(
+		`(Get-ItemProperty -Path '%s' -Name ToastEnabled -ErrorAction SilentlyContinue).ToastEnabled`, toastKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notification setting: %w", err)
+	}
+	if previous == "" {
+		previous = "1"
+	}
+
+	if err := setToasts(ctx, "0"); err != nil {
+		return nil, fmt.Errorf("failed to disable notifications: %w", err)
+	}
+	return func(ctx context.Context) error {
+		return setToasts(ctx, previous)
+	}, nil
+}
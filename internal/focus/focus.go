@@ -0,0 +1,51 @@
+// Package focus silences OS notifications (Do Not Disturb / Focus) for the
+// length of a deep-work session and restores the previous state afterwards.
+package focus
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"gomentum/internal/config"
+	"gomentum/internal/planner"
+)
+
+// ErrUnsupported is returned when the platform has no supported DND switch
+var ErrUnsupported = errors.New("do not disturb is not supported on this platform")
+
+// IsDeepWork reports whether a session on t should enable DND. With no
+// keywords configured, every session counts.
+func IsDeepWork(cfg config.FocusConfig, t planner.Task) bool {
+	if len(cfg.Keywords) == 0 {
+		return true
+	}
+	text := strings.ToLower(t.Title + " " + t.Description)
+	for _, k := range cfg.Keywords {
+		if k != "" && strings.Contains(text, strings.ToLower(k)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Start enables DND and returns a function that restores the previous
+// state, or nil if DND could not be enabled. Failures are logged, not
+// returned: a focus session must not fail because the desktop refused to
+// go quiet.
+func Start(ctx context.Context, cfg config.FocusConfig) (restore func()) {
+	undo, err := enable(ctx, cfg)
+	if err != nil {
+		slog.Warn("Failed to enable Do Not Disturb", "error", err)
+		return nil
+	}
+	slog.Info("Do Not Disturb enabled for focus session")
+	return func() {
+		if err := undo(context.Background()); err != nil {
+			slog.Warn("Failed to restore notification settings", "error", err)
+			return
+		}
+		slog.Info("Do Not Disturb restored")
+	}
+}
@@ -0,0 +1,121 @@
+// Package gitlog reads commit activity from local git repositories, to
+// compare a day's plan with what was actually shipped.
+package gitlog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Commit is one commit of a repository
+type Commit struct {
+	Hash       string    `json:"hash"` // Abbreviated
+	Time       time.Time `json:"time"` // Author date
+	Author     string    `json:"author"`
+	Subject    string    `json:"subject"`
+	Files      int       `json:"files"`
+	Insertions int       `json:"insertions"`
+	Deletions  int       `json:"deletions"`
+}
+
+// Repo sums up the commits of one repository
+type Repo struct {
+	Path       string   `json:"path"`
+	Branch     string   `json:"branch,omitempty"`
+	Commits    []Commit `json:"commits"`
+	Insertions int      `json:"insertions"`
+	Deletions  int      `json:"deletions"`
+	Error      string   `json:"error,omitempty"` // Set when the repository could not be read
+}
+
+// logFormat separates fields with a unit separator and starts each commit
+// with a record separator, so the --shortstat line can follow it
+const logFormat = "--pretty=format:\x1e%h\x1f%aI\x1f%an\x1f%s"
+
+var (
+	filesChanged = regexp.MustCompile(`(\d+) files? changed`)
+	insertions   = regexp.MustCompile(`(\d+) insertions?\(\+\)`)
+	deletions    = regexp.MustCompile(`(\d+) deletions?\(-\)`)
+)
+
+// Activity lists the commits authored in [from, to) on every branch of
+// the repository at path. A non-empty author keeps only commits whose
+// author name or email contains it; git's own matching applies.
+func Activity(ctx context.Context, path string, from, to time.Time, author string) Repo {
+	repo := Repo{Path: path, Commits: []Commit{}}
+	args := []string{"log", "--all", "--no-merges", "--shortstat", logFormat, "--since=" + from.Format(time.RFC3339)}
+	if author != "" {
+		args = append(args, "--author="+author)
+	}
+	out, err := git(ctx, path, args...)
+	if err != nil {
+		repo.Error = err.Error()
+		return repo
+	}
+	if branch, err := git(ctx, path, "rev-parse", "--abbrev-ref", "HEAD"); err == nil {
+		repo.Branch = strings.TrimSpace(string(branch))
+	}
+
+	for _, record := range strings.Split(string(out), "\x1e")[1:] {
+		header, stat, _ := strings.Cut(record, "\n")
+		fields := strings.Split(header, "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+		c := Commit{Hash: fields[0], Author: fields[2], Subject: fields[3]}
+		c.Time, _ = time.Parse(time.RFC3339, fields[1])
+		// --since goes by commit date; the author date is when the work
+		// was done, even if it was rebased later
+		if c.Time.Before(from) || !c.Time.Before(to) {
+			continue
+		}
+		c.Files = statNumber(filesChanged, stat)
+		c.Insertions = statNumber(insertions, stat)
+		c.Deletions = statNumber(deletions, stat)
+		repo.Commits = append(repo.Commits, c)
+		repo.Insertions += c.Insertions
+		repo.Deletions += c.Deletions
+	}
+	return repo
+}
+
+// UserEmail returns the user.email git uses in the repository at path
+func UserEmail(ctx context.Context, path string) string {
+	out, err := git(ctx, path, "config", "user.email")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func statNumber(re *regexp.Regexp, stat string) int {
+	m := re.FindStringSubmatch(stat)
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n
+}
+
+// git runs a git command in path, returning its output or the first line
+// of what it printed on failure
+func git(ctx context.Context, path string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", path}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if line, _, _ := bufio.NewReader(&stderr).ReadLine(); len(line) > 0 {
+			return nil, fmt.Errorf("git %s: %s", args[0], line)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
@@ -0,0 +1,184 @@
+// Package hooks runs user-configured shell commands around Gomentum events.
+//
+// Every event has a "pre-" and a "post-" phase, e.g. pre-focus runs when a
+// focus session starts and post-focus when it ends. A hook receives the
+// hook Context as JSON on stdin and as GOMENTUM_* environment variables.
+// Background hooks (e.g. a music player) keep running after they start and
+// are stopped when the matching post- event fires.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gomentum/internal/config"
+	"gomentum/internal/planner"
+)
+
+// Events fired by Gomentum
+const (
+	PreFocus  = "pre-focus"
+	PostFocus = "post-focus"
+)
+
+// timeout bounds foreground hooks so a hung command cannot stall Gomentum
+const timeout = 30 * time.Second
+
+// Context describes the event a hook runs for
+type Context struct {
+	Event   string        `json:"event"`
+	Time    time.Time     `json:"time"`
+	Task    *planner.Task `json:"task,omitempty"`
+	Minutes int           `json:"minutes,omitempty"` // Planned session length, 0 if open-ended
+}
+
+// env renders the context as environment variables
+func (c Context) env() []string {
+	env := []string{
+		"GOMENTUM_EVENT=" + c.Event,
+		"GOMENTUM_TIME=" + c.Time.Format(time.RFC3339),
+		"GOMENTUM_MINUTES=" + strconv.Itoa(c.Minutes),
+	}
+	if c.Task != nil {
+		env = append(env,
+			"GOMENTUM_TASK_ID="+strconv.Itoa(c.Task.ID),
+			"GOMENTUM_TASK_TITLE="+c.Task.Title,
+			"GOMENTUM_TASK_START="+c.Task.StartTime.Format(time.RFC3339),
+			"GOMENTUM_TASK_END="+c.Task.EndTime.Format(time.RFC3339),
+		)
+	}
+	return env
+}
+
+// Engine runs the hooks configured for each event
+type Engine struct {
+	hooks []config.HookConfig
+
+	mu sync.Mutex
+	// Background processes started by pre- events, keyed by event name
+	running map[string][]*exec.Cmd
+}
+
+// NewEngine creates a new hook engine
+func NewEngine(hooks []config.HookConfig) *Engine {
+	return &Engine{hooks: hooks, running: make(map[string][]*exec.Cmd)}
+}
+
+// Fire runs every hook registered for event. Foreground hooks run in order
+// and their failures are logged; they never abort the event itself.
+func (e *Engine) Fire(ctx context.Context, event string, hc Context) {
+	if e == nil {
+		return
+	}
+	hc.Event = event
+	if hc.Time.IsZero() {
+		hc.Time = time.Now()
+	}
+
+	if base, ok := strings.CutPrefix(event, "post-"); ok {
+		e.stopBackground("pre-" + base)
+	}
+
+	payload, err := json.Marshal(hc)
+	if err != nil {
+		slog.Error("Failed to encode hook context", "event", event, "error", err)
+		return
+	}
+
+	for _, h := range e.hooks {
+		if h.Event != event || h.Command == "" {
+			continue
+		}
+		if h.Background {
+			e.startBackground(event, h, hc, payload)
+			continue
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, timeout)
+		cmd := shell(runCtx, h.Command)
+		cmd.Env = append(os.Environ(), hc.env()...)
+		cmd.Stdin = bytes.NewReader(payload)
+		out, err := cmd.CombinedOutput()
+		cancel()
+		if err != nil {
+			slog.Warn("Hook failed", "event", event, "command", h.Command, "error", err, "output", strings.TrimSpace(string(out)))
+		} else {
+			slog.Info("Hook ran", "event", event, "command", h.Command)
+		}
+	}
+}
+
+func (e *Engine) startBackground(event string, h config.HookConfig, hc Context, payload []byte) {
+	cmd := shell(context.Background(), h.Command)
+	cmd.Env = append(os.Environ(), hc.env()...)
+	cmd.Stdin = bytes.NewReader(payload)
+	newGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		slog.Warn("Hook failed to start", "event", event, "command", h.Command, "error", err)
+		return
+	}
+	slog.Info("Background hook started", "event", event, "command", h.Command, "pid", cmd.Process.Pid)
+	go cmd.Wait()
+
+	e.mu.Lock()
+	e.running[event] = append(e.running[event], cmd)
+	e.mu.Unlock()
+}
+
+func (e *Engine) stopBackground(event string) {
+	e.mu.Lock()
+	cmds := e.running[event]
+	delete(e.running, event)
+	e.mu.Unlock()
+
+	for _, cmd := range cmds {
+		if err := kill(cmd); err != nil {
+			slog.Warn("Failed to stop background hook", "event", event, "error", err)
+		}
+	}
+}
+
+// Close stops all background hooks
+func (e *Engine) Close() {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	events := make([]string, 0, len(e.running))
+	for ev := range e.running {
+		events = append(events, ev)
+	}
+	e.mu.Unlock()
+	for _, ev := range events {
+		e.stopBackground(ev)
+	}
+}
+
+func shell(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+// Validate reports hooks for unknown events
+func Validate(hooks []config.HookConfig) error {
+	for _, h := range hooks {
+		switch h.Event {
+		case PreFocus, PostFocus:
+		default:
+			return fmt.Errorf("unknown hook event %q", h.Event)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+
+package hooks
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// newGroup puts background hooks in their own process group, so stopping
+// them also stops whatever the shell started (e.g. a music player)
+func newGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+func kill(cmd *exec.Cmd) error {
+	err := syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+	if err == syscall.ESRCH {
+		return nil // Already exited
+	}
+	return err
+}
@@ -0,0 +1,15 @@
+//go:build windows
+
+package hooks
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+func newGroup(*exec.Cmd) {}
+
+// kill ends the whole process tree, not just cmd.exe
+func kill(cmd *exec.Cmd) error {
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+}
@@ -0,0 +1,185 @@
+package i18n
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	// numericDate matches 2006-01-02, 1/2, 01.02 and 2006年1月2日; month
+	// comes before day
+	numericDate = regexp.MustCompile(`^(?:(\d{4})[-/.年])?(\d{1,2})[-/.月](\d{1,2})日?$`)
+	// offsetDate matches +3, -2d, +1w and the words of "in 3 days"
+	offsetDate = regexp.MustCompile(`^([+-]?)(\d+) ?(\pL*)$`)
+)
+
+// ParseDay reads a day typed by the user, such as "tomorrow", "next tue",
+// "+3", "oct 20", "2006-01-02" or, with the zh locale, "下周二", relative
+// to now. Words of the current locale and of English are understood. It
+// returns midnight of that day.
+func ParseDay(s string, now time.Time) (time.Time, bool) {
+	mu.RLock()
+	locales := []*Locale{current, fallback}
+	mu.RUnlock()
+
+	s = strings.ToLower(strings.Join(strings.Fields(s), " "))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if s == "" {
+		return time.Time{}, false
+	}
+	for _, word := range []struct {
+		key  string
+		days int
+	}{{"today", 0}, {"tomorrow", 1}, {"day_after_tomorrow", 2}, {"yesterday", -1}} {
+		if isDateWord(locales, word.key, s) {
+			return today.AddDate(0, 0, word.days), true
+		}
+	}
+
+	if m := numericDate.FindStringSubmatch(s); m != nil {
+		year := now.Year()
+		if m[1] != "" {
+			year, _ = strconv.Atoi(m[1])
+		}
+		month, _ := strconv.Atoi(m[2])
+		day, _ := strconv.Atoi(m[3])
+		return validDate(year, month, day, now.Location())
+	}
+
+	if rest, ok := trimDateWord(locales, "in", s); ok {
+		s = rest // "in 3 days" counts forward like "+3 days"
+	}
+	if m := offsetDate.FindStringSubmatch(s); m != nil && (m[1] != "" || m[3] != "") {
+		n, _ := strconv.Atoi(m[2])
+		if m[1] == "-" {
+			n = -n
+		}
+		switch {
+		case m[3] == "" || isDateWord(locales, "days", m[3]):
+			return today.AddDate(0, 0, n), true
+		case isDateWord(locales, "weeks", m[3]):
+			return today.AddDate(0, 0, 7*n), true
+		}
+	}
+
+	// "next", "last" and "this" shift by whole weeks or months
+	shift, explicit := 0, false
+	for _, mod := range []struct {
+		key   string
+		shift int
+	}{{"next", 1}, {"last", -1}, {"this", 0}} {
+		if rest, ok := trimDateWord(locales, mod.key, s); ok {
+			s, shift, explicit = rest, mod.shift, true
+			break
+		}
+	}
+	if explicit {
+		switch {
+		case isDateWord(locales, "week", s):
+			return StartOfWeek(today).AddDate(0, 0, 7*shift), true
+		case isDateWord(locales, "month", s):
+			return time.Date(today.Year(), today.Month()+time.Month(shift), 1, 0, 0, 0, 0, today.Location()), true
+		}
+	}
+	if wd, ok := weekday(locales, s); ok {
+		if !explicit {
+			// The next one, today included
+			return today.AddDate(0, 0, (int(wd)-int(today.Weekday())+7)%7), true
+		}
+		week := StartOfWeek(today).AddDate(0, 0, 7*shift)
+		return week.AddDate(0, 0, (int(wd)-int(WeekStart())+7)%7), true
+	}
+	if explicit {
+		return time.Time{}, false
+	}
+
+	// "oct 20", "20 october" or a day of this month
+	fields := strings.Fields(s)
+	switch len(fields) {
+	case 1:
+		if day, err := strconv.Atoi(fields[0]); err == nil {
+			return validDate(now.Year(), int(now.Month()), day, now.Location())
+		}
+	case 2:
+		if _, err := strconv.Atoi(fields[0]); err == nil {
+			fields[0], fields[1] = fields[1], fields[0]
+		}
+		month, ok := monthByName(locales, fields[0])
+		day, err := strconv.Atoi(fields[1])
+		if ok && err == nil {
+			return validDate(now.Year(), month, day, now.Location())
+		}
+	}
+	return time.Time{}, false
+}
+
+// validDate builds a date, rejecting days the month does not have
+func validDate(year, month, day int, loc *time.Location) (time.Time, bool) {
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, loc)
+	if month < 1 || month > 12 || t.Day() != day {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// dateWords lists the spellings of a date word in the given locales,
+// longest first so "下个" wins over "下"
+func dateWords(locales []*Locale, key string) []string {
+	var words []string
+	for _, l := range locales {
+		words = append(words, l.DateWords[key]...)
+	}
+	sort.SliceStable(words, func(i, j int) bool { return len(words[i]) > len(words[j]) })
+	return words
+}
+
+func isDateWord(locales []*Locale, key, s string) bool {
+	for _, w := range dateWords(locales, key) {
+		if s == w {
+			return true
+		}
+	}
+	return false
+}
+
+// trimDateWord removes a leading date word and the space after it, if
+// any; languages such as Chinese write "下周二" without one
+func trimDateWord(locales []*Locale, key, s string) (string, bool) {
+	for _, w := range dateWords(locales, key) {
+		if rest, ok := strings.CutPrefix(s, w); ok && rest != "" {
+			return strings.TrimPrefix(rest, " "), true
+		}
+	}
+	return s, false
+}
+
+// weekday matches a full or short weekday name. Prefixes of three
+// letters or more, like "tues", work as well.
+func weekday(locales []*Locale, s string) (time.Weekday, bool) {
+	for _, l := range locales {
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			full := strings.ToLower(pick(l.Weekdays, nil, int(d)))
+			short := strings.ToLower(pick(l.WeekdaysShort, nil, int(d)))
+			if s == full || s == short || (utf8.RuneCountInString(s) >= 3 && strings.HasPrefix(full, s)) {
+				return d, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// monthByName matches a full or short month name, returning 1 to 12
+func monthByName(locales []*Locale, s string) (int, bool) {
+	for _, l := range locales {
+		for i := 0; i < 12; i++ {
+			if s == strings.ToLower(pick(l.Months, nil, i)) || s == strings.ToLower(pick(l.MonthsShort, nil, i)) {
+				return i + 1, true
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,84 @@
+package i18n
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"gomentum/internal/config"
+)
+
+// Display settings from the config. They apply on top of the locale's
+// own layouts, which stay the default.
+var (
+	formatMu  sync.RWMutex
+	twelveH   bool
+	weekStart = time.Monday
+	layouts   = map[string]string{}
+)
+
+// Configure applies the clock, first day of the week and date layouts
+// used by every Format function in this package
+func Configure(cfg config.FormatConfig) {
+	formatMu.Lock()
+	defer formatMu.Unlock()
+
+	switch strings.ToLower(cfg.Clock) {
+	case "", "24h":
+		twelveH = false
+	case "12h":
+		twelveH = true
+	default:
+		slog.Warn("Invalid clock format, using 24h", "clock", cfg.Clock)
+		twelveH = false
+	}
+
+	weekStart = time.Monday
+	if cfg.WeekStart != "" {
+		found := false
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if strings.EqualFold(d.String(), cfg.WeekStart) {
+				weekStart, found = d, true
+			}
+		}
+		if !found {
+			slog.Warn("Invalid first day of week, using Monday", "week_start", cfg.WeekStart)
+		}
+	}
+
+	layouts = map[string]string{}
+	if cfg.Date != "" {
+		layouts["date"] = cfg.Date
+	}
+	if cfg.DateLong != "" {
+		layouts["date_long"] = cfg.DateLong
+	}
+}
+
+// WeekStart returns the configured first day of the week
+func WeekStart() time.Weekday {
+	formatMu.RLock()
+	defer formatMu.RUnlock()
+	return weekStart
+}
+
+// StartOfWeek returns midnight of the first day of the week containing t
+func StartOfWeek(t time.Time) time.Time {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return midnight.AddDate(0, 0, -((int(t.Weekday()) - int(WeekStart()) + 7) % 7))
+}
+
+func clock12() bool {
+	formatMu.RLock()
+	defer formatMu.RUnlock()
+	return twelveH
+}
+
+// override returns the configured layout replacing the locale's, if any
+func override(name string) (string, bool) {
+	formatMu.RLock()
+	defer formatMu.RUnlock()
+	layout, ok := layouts[name]
+	return layout, ok
+}
@@ -0,0 +1,237 @@
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed locales/*.yaml
+var localeFS embed.FS
+
+// DefaultLocale is used when no locale is configured or detected
+const DefaultLocale = "en"
+
+// Locale holds the messages and date vocabulary of one language
+type Locale struct {
+	Name          string            `yaml:"-"`
+	Weekdays      []string          `yaml:"weekdays"`
+	WeekdaysShort []string          `yaml:"weekdays_short"`
+	Months        []string          `yaml:"months"`
+	MonthsShort   []string          `yaml:"months_short"`
+	Meridiem      []string          `yaml:"meridiem"` // AM, PM
+	Formats       map[string]string `yaml:"formats"`
+	// Words ParseDay understands, such as "tomorrow" or "next"
+	DateWords map[string][]string `yaml:"date_words"`
+	Messages  map[string]string   `yaml:"messages"`
+}
+
+var (
+	mu       sync.RWMutex
+	current  *Locale
+	fallback *Locale
+)
+
+func init() {
+	l, err := load(DefaultLocale)
+	if err != nil {
+		panic(fmt.Sprintf("i18n: failed to load default locale: %v", err))
+	}
+	current, fallback = l, l
+}
+
+// Init selects the active locale. An empty name detects it from the
+// environment (LC_ALL, LC_MESSAGES, LANG). Unknown locales fall back to English.
+func Init(name string) error {
+	if name == "" {
+		name = Detect()
+	}
+	l, err := load(name)
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	current = l
+	mu.Unlock()
+	return nil
+}
+
+// Detect guesses the locale from the environment
+func Detect() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(env)
+		if v == "" {
+			continue
+		}
+		name := normalize(v)
+		if _, err := localeFS.ReadFile("locales/" + name + ".yaml"); err == nil {
+			return name
+		}
+		break
+	}
+	return DefaultLocale
+}
+
+// Available lists the bundled locales
+func Available() []string {
+	entries, err := localeFS.ReadDir("locales")
+	if err != nil {
+		return []string{DefaultLocale}
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".yaml"))
+	}
+	return names
+}
+
+// Current returns the name of the active locale
+func Current() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current.Name
+}
+
+// normalize maps "zh_CN.UTF-8" or "zh-Hans" to "zh"
+func normalize(name string) string {
+	name = strings.ToLower(name)
+	if i := strings.IndexAny(name, "_-.@"); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+func load(name string) (*Locale, error) {
+	name = normalize(name)
+	data, err := localeFS.ReadFile("locales/" + name + ".yaml")
+	if err != nil {
+		if name != DefaultLocale {
+			slog.Warn("Unknown locale, falling back to English", "locale", name)
+			return load(DefaultLocale)
+		}
+		return nil, err
+	}
+	var l Locale
+	if err := yaml.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("failed to parse locale %s: %w", name, err)
+	}
+	l.Name = name
+	return &l, nil
+}
+
+// T translates key, formatting it with args like fmt.Sprintf.
+// Missing keys fall back to English, then to the key itself.
+func T(key string, args ...interface{}) string {
+	mu.RLock()
+	l := current
+	mu.RUnlock()
+
+	msg, ok := l.Messages[key]
+	if !ok {
+		if msg, ok = fallback.Messages[key]; !ok {
+			msg = key
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// FormatDate renders a short date such as "Mon, Jan 2" or "1月2日 周一"
+func FormatDate(t time.Time) string {
+	return formatLayout("date", t)
+}
+
+// FormatDateLong renders a full date such as "Monday, January 2, 2006"
+func FormatDateLong(t time.Time) string {
+	return formatLayout("date_long", t)
+}
+
+// FormatTime renders a clock time on the configured 24h or 12h clock
+func FormatTime(t time.Time) string {
+	if clock12() {
+		return formatLayout("time_12h", t)
+	}
+	return formatLayout("time", t)
+}
+
+// FormatDateTime renders a short date followed by the clock time
+func FormatDateTime(t time.Time) string {
+	return FormatDate(t) + " " + FormatTime(t)
+}
+
+// FormatMonth renders a month and year such as "January 2006"
+func FormatMonth(t time.Time) string {
+	return formatLayout("month", t)
+}
+
+// WeekdayName returns the localized weekday name
+func WeekdayName(d time.Weekday) string {
+	mu.RLock()
+	l := current
+	mu.RUnlock()
+	return pick(l.Weekdays, fallback.Weekdays, int(d))
+}
+
+// WeekdayShort returns the abbreviated weekday name, e.g. "Mon"
+func WeekdayShort(d time.Weekday) string {
+	mu.RLock()
+	l := current
+	mu.RUnlock()
+	return pick(l.WeekdaysShort, fallback.WeekdaysShort, int(d))
+}
+
+func formatLayout(name string, t time.Time) string {
+	mu.RLock()
+	l := current
+	mu.RUnlock()
+
+	layout, ok := override(name)
+	if !ok {
+		if layout, ok = l.Formats[name]; !ok {
+			layout = fallback.Formats[name]
+		}
+	}
+
+	t = t.Local()
+	hour12 := t.Hour() % 12
+	if hour12 == 0 {
+		hour12 = 12
+	}
+	r := strings.NewReplacer(
+		"{yyyy}", strconv.Itoa(t.Year()),
+		"{mm}", fmt.Sprintf("%02d", int(t.Month())),
+		"{m}", strconv.Itoa(int(t.Month())),
+		"{dd}", fmt.Sprintf("%02d", t.Day()),
+		"{d}", strconv.Itoa(t.Day()),
+		"{month}", pick(l.Months, fallback.Months, int(t.Month())-1),
+		"{mon}", pick(l.MonthsShort, fallback.MonthsShort, int(t.Month())-1),
+		"{weekday}", pick(l.Weekdays, fallback.Weekdays, int(t.Weekday())),
+		"{wd}", pick(l.WeekdaysShort, fallback.WeekdaysShort, int(t.Weekday())),
+		"{HH}", fmt.Sprintf("%02d", t.Hour()),
+		"{H}", strconv.Itoa(t.Hour()),
+		"{hh}", fmt.Sprintf("%02d", hour12),
+		"{h}", strconv.Itoa(hour12),
+		"{MM}", fmt.Sprintf("%02d", t.Minute()),
+		"{ampm}", pick(l.Meridiem, fallback.Meridiem, t.Hour()/12),
+	)
+	return r.Replace(layout)
+}
+
+func pick(list, fb []string, i int) string {
+	if i >= 0 && i < len(list) {
+		return list[i]
+	}
+	if i >= 0 && i < len(fb) {
+		return fb[i]
+	}
+	return ""
+}
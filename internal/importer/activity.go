@@ -0,0 +1,59 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"gomentum/internal/planner"
+)
+
+// ActivityImporter reads the recorded activity of a time tracker
+type ActivityImporter func(r io.Reader) ([]planner.Activity, Result, error)
+
+// activityImporters maps a format name to its importer, see
+// RegisterActivity
+var activityImporters = map[string]ActivityImporter{}
+
+// RegisterActivity makes an activity importer available as format, e.g.
+// for `gomentum import <format>`
+func RegisterActivity(format string, fn ActivityImporter) {
+	activityImporters[format] = fn
+}
+
+// ActivityFormats lists the registered activity formats, sorted
+func ActivityFormats() []string {
+	formats := make([]string, 0, len(activityImporters))
+	for f := range activityImporters {
+		formats = append(formats, f)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// IsActivity reports whether format is a registered activity format
+func IsActivity(format string) bool {
+	_, ok := activityImporters[format]
+	return ok
+}
+
+// Activity reads recorded activity in format. Spans without a duration
+// are dropped.
+func Activity(format string, r io.Reader) ([]planner.Activity, Result, error) {
+	fn, ok := activityImporters[format]
+	if !ok {
+		return nil, Result{}, fmt.Errorf("unknown activity format %q", format)
+	}
+	spans, res, err := fn(r)
+	if err != nil {
+		return nil, res, err
+	}
+	kept := spans[:0]
+	for _, a := range spans {
+		if a.End.After(a.Start) {
+			a.Source = format
+			kept = append(kept, a)
+		}
+	}
+	return kept, res, nil
+}
@@ -0,0 +1,108 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"gomentum/internal/planner"
+)
+
+func init() {
+	RegisterActivity("activitywatch", ActivityWatch)
+}
+
+// awExport is the JSON ActivityWatch exports buckets as, from the web UI
+// or GET /api/0/export
+type awExport struct {
+	Buckets map[string]awBucket `json:"buckets"`
+}
+
+type awBucket struct {
+	Type   string    `json:"type"` // "currentwindow", "afkstatus", ...
+	Events []awEvent `json:"events"`
+}
+
+type awEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Duration  float64   `json:"duration"` // Seconds
+	Data      struct {
+		App    string `json:"app"`
+		Title  string `json:"title"`
+		Status string `json:"status"` // "afk" or "not-afk"
+	} `json:"data"`
+}
+
+func (e awEvent) end() time.Time {
+	return e.Timestamp.Add(time.Duration(e.Duration * float64(time.Second)))
+}
+
+// ActivityWatch reads an ActivityWatch export. Window events become
+// activity; the time the AFK watcher saw the computer idle is cut out of
+// them, since a window left open is not work.
+func ActivityWatch(r io.Reader) ([]planner.Activity, Result, error) {
+	var export awExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, Result{}, fmt.Errorf("failed to parse ActivityWatch export: %w", err)
+	}
+
+	var windows []awEvent
+	var away []span
+	for _, b := range export.Buckets {
+		switch b.Type {
+		case "currentwindow":
+			windows = append(windows, b.Events...)
+		case "afkstatus":
+			for _, e := range b.Events {
+				if e.Data.Status == "afk" {
+					away = append(away, span{e.Timestamp, e.end()})
+				}
+			}
+		}
+	}
+	if len(windows) == 0 {
+		return nil, Result{}, fmt.Errorf("no window events found; export the aw-watcher-window bucket")
+	}
+	sort.Slice(away, func(i, j int) bool { return away[i].start.Before(away[j].start) })
+
+	var spans []planner.Activity
+	for _, e := range windows {
+		for _, s := range subtract(span{e.Timestamp, e.end()}, away) {
+			spans = append(spans, planner.Activity{
+				Start: s.start.Local(),
+				End:   s.end.Local(),
+				App:   e.Data.App,
+				Title: e.Data.Title,
+			})
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].Start.Before(spans[j].Start) })
+	return spans, Result{}, nil
+}
+
+type span struct {
+	start, end time.Time
+}
+
+// subtract returns the parts of s outside the sorted spans cut
+func subtract(s span, cut []span) []span {
+	var parts []span
+	for _, c := range cut {
+		if !c.end.After(s.start) {
+			continue
+		}
+		if !c.start.Before(s.end) {
+			break
+		}
+		if c.start.After(s.start) {
+			parts = append(parts, span{s.start, c.start})
+		}
+		if !c.end.Before(s.end) {
+			return parts
+		}
+		s.start = c.end
+	}
+	return append(parts, s)
+}
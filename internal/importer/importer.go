@@ -0,0 +1,58 @@
+// Package importer converts tasks exported by other tools into Gomentum tasks.
+package importer
+
+import (
+	"fmt"
+	"time"
+
+	"gomentum/internal/planner"
+)
+
+// DefaultDuration is the length given to imported tasks that only have a
+// single point in time (e.g. a due date)
+const DefaultDuration = 30 * time.Minute
+
+// Item is a task read from a foreign format, ready to be added to the planner
+type Item struct {
+	Title       string
+	Description string
+	Start       time.Time
+	End         time.Time
+	Completed   bool
+	Priority    string   // One of the planner priorities, or empty
+	Tags        []string // Normalized tag names
+}
+
+// Result summarizes a parse
+type Result struct {
+	// Skipped lists items that could not be scheduled, with the reason
+	Skipped []string
+}
+
+// Save adds items to the planner. Imported tasks may overlap existing ones;
+// the user can reschedule them afterwards.
+func Save(p *planner.Planner, items []Item) (int, error) {
+	n := 0
+	for _, it := range items {
+		task, err := p.AddTask(it.Title, it.Description, it.Start, it.End)
+		if err != nil {
+			return n, fmt.Errorf("failed to import %q: %w", it.Title, err)
+		}
+		if it.Completed || it.Priority != "" {
+			if it.Completed {
+				task.Status = planner.StatusCompleted
+			}
+			task.Priority = it.Priority
+			if err := p.UpdateTask(task); err != nil {
+				return n, fmt.Errorf("failed to import %q: %w", it.Title, err)
+			}
+		}
+		if len(it.Tags) > 0 {
+			if err := p.SetTags(task.ID, it.Tags); err != nil {
+				return n, fmt.Errorf("failed to import %q: %w", it.Title, err)
+			}
+		}
+		n++
+	}
+	return n, nil
+}
@@ -0,0 +1,116 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"gomentum/internal/planner"
+)
+
+func init() {
+	RegisterActivity("rescuetime", RescueTime)
+}
+
+// rescueTimeLayouts are the date formats of RescueTime exports, in local
+// time
+var rescueTimeLayouts = []string{"2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+// rtAPI is the JSON of the analytic data API
+type rtAPI struct {
+	RowHeaders []string `json:"row_headers"`
+	Rows       [][]any  `json:"rows"`
+}
+
+// RescueTime reads activity exported from RescueTime, as CSV or as the
+// JSON of the analytic data API, with the interval perspective so every
+// row has a time: Date, Time Spent (seconds), Activity, Category,
+// Productivity and, if present, Document. Each row becomes a span
+// starting at its date.
+func RescueTime(r io.Reader) ([]planner.Activity, Result, error) {
+	br := bufio.NewReader(r)
+	first, err := br.Peek(1)
+	if err != nil {
+		return nil, Result{}, fmt.Errorf("failed to read RescueTime export: %w", err)
+	}
+
+	var header []string
+	var rows [][]string
+	if first[0] == '{' {
+		var api rtAPI
+		if err := json.NewDecoder(br).Decode(&api); err != nil {
+			return nil, Result{}, fmt.Errorf("failed to parse RescueTime export: %w", err)
+		}
+		header = api.RowHeaders
+		for _, row := range api.Rows {
+			cells := make([]string, len(row))
+			for i, v := range row {
+				cells[i] = fmt.Sprint(v)
+			}
+			rows = append(rows, cells)
+		}
+	} else {
+		records, err := csv.NewReader(br).ReadAll()
+		if err != nil {
+			return nil, Result{}, fmt.Errorf("failed to parse RescueTime export: %w", err)
+		}
+		if len(records) > 0 {
+			header, rows = records[0], records[1:]
+		}
+	}
+
+	col := make(map[string]int)
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if strings.HasPrefix(h, "time spent") {
+			h = "seconds"
+		}
+		col[h] = i
+	}
+	for _, name := range []string{"date", "seconds", "activity"} {
+		if _, ok := col[name]; !ok {
+			return nil, Result{}, fmt.Errorf("RescueTime export has no %q column; export with the interval perspective", name)
+		}
+	}
+	cell := func(row []string, name string) string {
+		if i, ok := col[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	var spans []planner.Activity
+	var res Result
+	for n, row := range rows {
+		start, ok := rescueTimeDate(cell(row, "date"))
+		seconds, err := strconv.ParseFloat(cell(row, "seconds"), 64)
+		if !ok || err != nil {
+			res.Skipped = append(res.Skipped, fmt.Sprintf("row %d: invalid date or time spent", n+2))
+			continue
+		}
+		productivity, _ := strconv.Atoi(cell(row, "productivity"))
+		spans = append(spans, planner.Activity{
+			Start:        start,
+			End:          start.Add(time.Duration(seconds * float64(time.Second))),
+			App:          cell(row, "activity"),
+			Title:        cell(row, "document"),
+			Category:     cell(row, "category"),
+			Productivity: max(-2, min(2, productivity)),
+		})
+	}
+	return spans, res, nil
+}
+
+func rescueTimeDate(s string) (time.Time, bool) {
+	for _, layout := range rescueTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,120 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gomentum/internal/planner"
+)
+
+// taskwarriorTime is the compact UTC format used by `task export`
+const taskwarriorTime = "20060102T150405Z"
+
+type twTask struct {
+	UUID        string   `json:"uuid"`
+	Description string   `json:"description"`
+	Status      string   `json:"status"` // pending, completed, deleted, waiting, recurring
+	Project     string   `json:"project"`
+	Priority    string   `json:"priority"` // H, M, L
+	Tags        []string `json:"tags"`
+	Due         string   `json:"due"`
+	Scheduled   string   `json:"scheduled"`
+	Annotations []struct {
+		Description string `json:"description"`
+	} `json:"annotations"`
+}
+
+var twPriorities = map[string]string{
+	"H": planner.PriorityHigh,
+	"M": planner.PriorityMedium,
+	"L": planner.PriorityLow,
+}
+
+// Taskwarrior reads the JSON array written by `task export`. Taskwarrior
+// tasks are not time blocks, so "scheduled" becomes the start and "due" the
+// end; a task with only one of them gets DefaultDuration. Priority and tags
+// carry over. Tasks with neither date, deleted tasks and recurrence
+// templates are skipped.
+func Taskwarrior(r io.Reader) ([]Item, Result, error) {
+	var tasks []twTask
+	if err := json.NewDecoder(r).Decode(&tasks); err != nil {
+		return nil, Result{}, fmt.Errorf("failed to parse Taskwarrior export: %w", err)
+	}
+
+	var items []Item
+	var res Result
+	for _, t := range tasks {
+		if t.Status == "deleted" || t.Status == "recurring" {
+			continue
+		}
+		start, end, err := twSchedule(t)
+		if err != nil {
+			res.Skipped = append(res.Skipped, fmt.Sprintf("%s: %v", t.Description, err))
+			continue
+		}
+		items = append(items, Item{
+			Title:       t.Description,
+			Description: twDescription(t),
+			Start:       start,
+			End:         end,
+			Completed:   t.Status == "completed",
+			Priority:    twPriorities[t.Priority],
+			Tags:        twTags(t.Tags),
+		})
+	}
+	return items, res, nil
+}
+
+func twSchedule(t twTask) (time.Time, time.Time, error) {
+	var start, end time.Time
+	var err error
+	if t.Scheduled != "" {
+		if start, err = time.Parse(taskwarriorTime, t.Scheduled); err != nil {
+			return start, end, fmt.Errorf("invalid scheduled date %q", t.Scheduled)
+		}
+	}
+	if t.Due != "" {
+		if end, err = time.Parse(taskwarriorTime, t.Due); err != nil {
+			return start, end, fmt.Errorf("invalid due date %q", t.Due)
+		}
+	}
+
+	switch {
+	case start.IsZero() && end.IsZero():
+		return start, end, fmt.Errorf("no due or scheduled date")
+	case start.IsZero():
+		start = end.Add(-DefaultDuration)
+	case end.IsZero() || !end.After(start):
+		end = start.Add(DefaultDuration)
+	}
+	return start.Local(), end.Local(), nil
+}
+
+// twDescription keeps the Taskwarrior metadata Gomentum has no fields for
+func twDescription(t twTask) string {
+	var lines []string
+	if t.Project != "" {
+		lines = append(lines, "Project: "+t.Project)
+	}
+	for _, a := range t.Annotations {
+		lines = append(lines, a.Description)
+	}
+	if t.UUID != "" {
+		lines = append(lines, "Taskwarrior: "+t.UUID)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// twTags normalizes Taskwarrior tags, dropping any Gomentum cannot store
+func twTags(tags []string) []string {
+	var out []string
+	for _, t := range tags {
+		if name, err := planner.NormalizeTag(t); err == nil {
+			out = append(out, name)
+		}
+	}
+	return out
+}
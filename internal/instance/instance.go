@@ -0,0 +1,263 @@
+package instance
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrRunning is returned by Acquire when another instance holds the lock
+var ErrRunning = errors.New("another Gomentum instance is already running")
+
+// ErrNotRunning is returned by Send when no instance is listening
+var ErrNotRunning = errors.New("no running Gomentum instance found")
+
+const (
+	lockFileName = "instance.lock"
+	dialTimeout  = 500 * time.Millisecond
+	ioTimeout    = 5 * time.Second
+)
+
+// Info describes the instance that owns the lock file
+type Info struct {
+	PID   int    `json:"pid"`
+	Addr  string `json:"addr"`
+	Mode  string `json:"mode"` // "tui", "daemon", ...
+	Token string `json:"token"`
+}
+
+// Request is sent from a second process to the running instance
+type Request struct {
+	Token   string `json:"token"`
+	Command string `json:"command"` // e.g. "quickadd"
+	Text    string `json:"text,omitempty"`
+}
+
+// Response is returned by the running instance
+type Response struct {
+	OK      bool   `json:"ok"`
+	Message string `json:"message,omitempty"`
+}
+
+// Handler processes a bridged request inside the running instance
+type Handler func(Request) Response
+
+// Lock is the single-instance lock held by the running process.
+// It also serves the local bridge that other processes talk to.
+type Lock struct {
+	path     string
+	info     Info
+	listener net.Listener
+
+	mu      sync.RWMutex
+	handler Handler
+}
+
+// Acquire takes the single-instance lock in dir. If another live instance
+// already holds it, ErrRunning is returned together with that instance's Info.
+// Stale lock files (left behind by a crash) are replaced.
+func Acquire(dir, mode string) (*Lock, *Info, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	path := filepath.Join(dir, lockFileName)
+
+	// Two attempts: the second one covers the race where a stale lock was
+	// removed by us and re-created by a concurrent start.
+	for attempt := 0; attempt < 2; attempt++ {
+		existing, err := readInfo(path)
+		switch {
+		case err == nil:
+			if alive(existing) {
+				return nil, existing, ErrRunning
+			}
+			slog.Info("Removing stale instance lock", "pid", existing.PID)
+			_ = os.Remove(path)
+		case !os.IsNotExist(err):
+			slog.Warn("Removing unreadable instance lock", "error", err)
+			_ = os.Remove(path)
+		}
+
+		l, err := create(path, mode)
+		if err == nil {
+			return l, &l.info, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, nil, err
+		}
+	}
+	return nil, nil, ErrRunning
+}
+
+func create(path, mode string) (*Lock, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open instance bridge: %w", err)
+	}
+
+	token, err := newToken()
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	info := Info{
+		PID:   os.Getpid(),
+		Addr:  listener.Addr().String(),
+		Mode:  mode,
+		Token: token,
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	if err := json.NewEncoder(f).Encode(info); err != nil {
+		f.Close()
+		listener.Close()
+		os.Remove(path)
+		return nil, fmt.Errorf("failed to write lock file: %w", err)
+	}
+	f.Close()
+
+	l := &Lock{
+		path:     path,
+		info:     info,
+		listener: listener,
+	}
+	go l.serve()
+	return l, nil
+}
+
+// SetHandler installs the function that answers bridged requests
+func (l *Lock) SetHandler(h Handler) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.handler = h
+}
+
+// Release closes the bridge and removes the lock file
+func (l *Lock) Release() error {
+	l.listener.Close()
+	// Only remove the file if it is still ours
+	if info, err := readInfo(l.path); err == nil && info.Token != l.info.Token {
+		return nil
+	}
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove lock file: %w", err)
+	}
+	return nil
+}
+
+func (l *Lock) serve() {
+	for {
+		conn, err := l.listener.Accept()
+		if err != nil {
+			return
+		}
+		go l.handleConn(conn)
+	}
+}
+
+func (l *Lock) handleConn(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(ioTimeout))
+
+	var req Request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		return
+	}
+
+	var resp Response
+	switch {
+	case req.Token != l.info.Token:
+		resp = Response{Message: "invalid token"}
+	case req.Command == "ping":
+		resp = Response{OK: true, Message: l.info.Mode}
+	default:
+		l.mu.RLock()
+		h := l.handler
+		l.mu.RUnlock()
+		if h == nil {
+			resp = Response{Message: "instance is not ready to accept requests"}
+		} else {
+			resp = h(req)
+		}
+	}
+
+	_ = json.NewEncoder(conn).Encode(resp)
+}
+
+// Send delivers a request to the running instance in dir
+func Send(dir string, req Request) (Response, error) {
+	info, err := readInfo(filepath.Join(dir, lockFileName))
+	if err != nil {
+		return Response{}, ErrNotRunning
+	}
+
+	conn, err := net.DialTimeout("tcp", info.Addr, dialTimeout)
+	if err != nil {
+		return Response{}, ErrNotRunning
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(ioTimeout))
+
+	req.Token = info.Token
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return Response{}, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return Response{}, fmt.Errorf("failed to read response: %w", err)
+	}
+	return resp, nil
+}
+
+// alive reports whether the instance described by info still answers
+func alive(info *Info) bool {
+	conn, err := net.DialTimeout("tcp", info.Addr, dialTimeout)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(dialTimeout))
+
+	if err := json.NewEncoder(conn).Encode(Request{Token: info.Token, Command: "ping"}); err != nil {
+		return false
+	}
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return false
+	}
+	return resp.OK
+}
+
+func readInfo(path string) (*Info, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var info Info
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse lock file: %w", err)
+	}
+	return &info, nil
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate bridge token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
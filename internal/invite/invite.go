@@ -0,0 +1,106 @@
+// Package invite renders tasks as iCalendar (RFC 5545) meeting requests,
+// so other people can be invited to time booked in Gomentum, and as a
+// calendar other apps can import.
+package invite
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gomentum/internal/planner"
+	"gomentum/internal/version"
+)
+
+// sequenceEpoch anchors SEQUENCE numbers. Counting minutes since then keeps
+// them increasing with every edit of the task and well inside 32 bits.
+var sequenceEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// UID identifies the event of a task. It stays the same across edits, so a
+// re-sent invite updates the attendee's copy instead of adding a new one.
+func UID(t planner.Task, organizer *mail.Address) string {
+	return fmt.Sprintf("gomentum-task-%d-%s", t.ID, organizer.Address)
+}
+
+// Request returns a METHOD:REQUEST calendar inviting attendees to task t
+func Request(t planner.Task, organizer *mail.Address, attendees []*mail.Address, now time.Time) []byte {
+	var b bytes.Buffer
+	line := func(s string) {
+		b.WriteString(fold(s))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//Gomentum//Gomentum " + version.Version + "//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:REQUEST")
+	line("BEGIN:VEVENT")
+	line("UID:" + UID(t, organizer))
+	line("DTSTAMP:" + utc(now))
+	line("DTSTART:" + utc(t.StartTime))
+	line("DTEND:" + utc(t.EndTime))
+	line(fmt.Sprintf("SEQUENCE:%d", sequence(t)))
+	line("SUMMARY:" + escape(t.Title))
+	if t.Description != "" {
+		line("DESCRIPTION:" + escape(t.Description))
+	}
+	line("ORGANIZER" + cn(organizer) + ":mailto:" + organizer.Address)
+	for _, a := range attendees {
+		line("ATTENDEE" + cn(a) + ";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:" + a.Address)
+	}
+	line("STATUS:CONFIRMED")
+	line("TRANSP:OPAQUE")
+	line("END:VEVENT")
+	line("END:VCALENDAR")
+	return b.Bytes()
+}
+
+func sequence(t planner.Task) int {
+	if t.UpdatedAt.Before(sequenceEpoch) {
+		return 0
+	}
+	return int(t.UpdatedAt.Sub(sequenceEpoch) / time.Minute)
+}
+
+func utc(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+func cn(a *mail.Address) string {
+	if a.Name == "" {
+		return ""
+	}
+	return `;CN="` + strings.NewReplacer(`"`, "'", "\r", "", "\n", " ").Replace(a.Name) + `"`
+}
+
+// escape quotes TEXT values (RFC 5545 section 3.3.11)
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(s)
+}
+
+// fold splits content lines longer than 75 octets without breaking UTF-8
+// sequences; continuation lines start with a space
+func fold(s string) string {
+	const limit = 75
+	if len(s) <= limit {
+		return s
+	}
+	var b strings.Builder
+	width := limit
+	for len(s) > width {
+		cut := width
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		width = limit - 1 // The leading space counts
+	}
+	b.WriteString(s)
+	return b.String()
+}
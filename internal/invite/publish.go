@@ -0,0 +1,49 @@
+package invite
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"gomentum/internal/planner"
+	"gomentum/internal/schedule"
+	"gomentum/internal/version"
+)
+
+// Publish returns a METHOD:PUBLISH calendar of the tasks that block time
+// or are done, for calendar apps that subscribe to or import a
+// schedule.ics file. Backlog tasks have no real slot and are left out.
+func Publish(tasks []planner.Task, now time.Time) []byte {
+	var b bytes.Buffer
+	line := func(s string) {
+		b.WriteString(fold(s))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//Gomentum//Gomentum " + version.Version + "//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:Gomentum")
+	for _, t := range tasks {
+		if !schedule.Busy(t) && t.Status != planner.StatusCompleted {
+			continue
+		}
+		line("BEGIN:VEVENT")
+		line(fmt.Sprintf("UID:gomentum-task-%d", t.ID))
+		line("DTSTAMP:" + utc(now))
+		line("DTSTART:" + utc(t.StartTime))
+		line("DTEND:" + utc(t.EndTime))
+		line(fmt.Sprintf("SEQUENCE:%d", sequence(t)))
+		line("SUMMARY:" + escape(t.Title))
+		if t.Description != "" {
+			line("DESCRIPTION:" + escape(t.Description))
+		}
+		line("STATUS:CONFIRMED")
+		line("TRANSP:OPAQUE")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return b.Bytes()
+}
@@ -0,0 +1,211 @@
+// Package mailer sends mail through the SMTP server from the config
+package mailer
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"gomentum/internal/config"
+	"gomentum/internal/netconf"
+)
+
+// ErrNotConfigured is returned when no SMTP server is set up
+var ErrNotConfigured = errors.New("SMTP is not configured: set smtp.host and smtp.from")
+
+// Message is a plain text mail, optionally carrying a calendar invite
+type Message struct {
+	To      []*mail.Address
+	Subject string
+	Text    string
+	// Calendar is an iCalendar METHOD:REQUEST object. It is sent both
+	// inline, so mail clients show accept/decline buttons, and as
+	// invite.ics for clients that only look at attachments.
+	Calendar []byte
+}
+
+// From parses the configured sender
+func From(cfg config.SMTPConfig) (*mail.Address, error) {
+	if cfg.Host == "" || cfg.From == "" {
+		return nil, ErrNotConfigured
+	}
+	addr, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp.from %q: %w", cfg.From, err)
+	}
+	return addr, nil
+}
+
+// Send delivers msg. Port 465 uses implicit TLS; other ports upgrade with
+// STARTTLS when the server offers it.
+func Send(cfg config.SMTPConfig, msg Message) error {
+	from, err := From(cfg)
+	if err != nil {
+		return err
+	}
+	if len(msg.To) == 0 {
+		return errors.New("no recipients")
+	}
+	data, err := build(from, msg)
+	if err != nil {
+		return err
+	}
+
+	port := cfg.Port
+	if port == 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	var to []string
+	for _, a := range msg.To {
+		to = append(to, a.Address)
+	}
+
+	// TLS trusts the CAs of network.ca_bundle too
+	var c *smtp.Client
+	if port == 465 {
+		conn, err := tls.Dial("tcp", addr, netconf.TLSConfig(cfg.Host))
+		if err != nil {
+			return fmt.Errorf("failed to connect to %s: %w", addr, err)
+		}
+		if c, err = smtp.NewClient(conn, cfg.Host); err != nil {
+			conn.Close()
+			return fmt.Errorf("failed to start SMTP session: %w", err)
+		}
+	} else {
+		if c, err = smtp.Dial(addr); err != nil {
+			return fmt.Errorf("failed to connect to %s: %w", addr, err)
+		}
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(netconf.TLSConfig(cfg.Host)); err != nil {
+				c.Close()
+				return fmt.Errorf("failed to start TLS: %w", err)
+			}
+		}
+	}
+	defer c.Close()
+	if auth != nil {
+		if err := c.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+	if err := c.Mail(from.Address); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	for _, rcpt := range to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("recipient %s rejected: %w", rcpt, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	return c.Quit()
+}
+
+// build renders the MIME message: multipart/mixed holding a
+// multipart/alternative (text + inline calendar) and the .ics attachment
+func build(from *mail.Address, msg Message) ([]byte, error) {
+	var to []string
+	for _, a := range msg.To {
+		to = append(to, a.String())
+	}
+
+	var b bytes.Buffer
+	header := func(k, v string) { fmt.Fprintf(&b, "%s: %s\r\n", k, v) }
+	header("From", from.String())
+	header("To", strings.Join(to, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+
+	if msg.Calendar == nil {
+		header("Content-Type", `text/plain; charset="utf-8"`)
+		header("Content-Transfer-Encoding", "base64")
+		b.WriteString("\r\n")
+		writeBase64(&b, []byte(msg.Text))
+		return b.Bytes(), nil
+	}
+
+	mixed := multipart.NewWriter(&b)
+	header("Content-Type", `multipart/mixed; boundary="`+mixed.Boundary()+`"`)
+	b.WriteString("\r\n")
+
+	var alt bytes.Buffer
+	altWriter := multipart.NewWriter(&alt)
+	parts := []struct {
+		header textproto.MIMEHeader
+		body   []byte
+	}{
+		{textproto.MIMEHeader{"Content-Type": {`text/plain; charset="utf-8"`}}, []byte(msg.Text)},
+		{textproto.MIMEHeader{"Content-Type": {`text/calendar; charset="utf-8"; method=REQUEST`}}, msg.Calendar},
+	}
+	for _, p := range parts {
+		p.header.Set("Content-Transfer-Encoding", "base64")
+		w, err := altWriter.CreatePart(p.header)
+		if err != nil {
+			return nil, err
+		}
+		writeBase64(w, p.body)
+	}
+	if err := altWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	w, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {`multipart/alternative; boundary="` + altWriter.Boundary() + `"`},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(alt.Bytes()); err != nil {
+		return nil, err
+	}
+
+	w, err = mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {`application/ics; name="invite.ics"`},
+		"Content-Disposition":       {`attachment; filename="invite.ics"`},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	writeBase64(w, msg.Calendar)
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// writeBase64 writes data base64 encoded in lines of 76 characters
+func writeBase64(w io.Writer, data []byte) {
+	enc := base64.StdEncoding.EncodeToString(data)
+	for len(enc) > 76 {
+		io.WriteString(w, enc[:76]+"\r\n")
+		enc = enc[76:]
+	}
+	io.WriteString(w, enc+"\r\n")
+}
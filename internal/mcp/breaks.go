@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"gomentum/internal/planner"
+	"gomentum/internal/schedule"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func scheduleBreaksTool() mcp.Tool {
+	return mcp.NewTool("schedule_breaks",
+		mcp.WithDescription("Plan rest into the schedule: a break after every long stretch of work that is followed by more work the same day, and short eye-rest micro-breaks during sessions of two hours or more, as configured under schedule.breaks. Breaks are system tasks: they get reminders but do not count against the daily capacity or in stats. Breaks planned earlier for the same days are replaced, so run it again after the day changes. Stretches too long to rest in are reported as warnings; nothing else is moved."),
+		mcp.WithString("date", mcp.Description("First day to plan, YYYY-MM-DD (default today; only the rest of today is planned)")),
+		mcp.WithNumber("days", mcp.Description("Number of days to plan (default 1)")),
+		mcp.WithBoolean("dry_run", mcp.Description("Only return the breaks that would be planned (default false)")),
+	)
+}
+
+func (s *Server) handleScheduleBreaks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	now := s.now()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if v, _ := args["date"].(string); v != "" {
+		d, err := time.ParseInLocation("2006-01-02", v, now.Location())
+		if err != nil {
+			return invalid("Invalid date: %v", err), nil
+		}
+		day = d
+	}
+	from, to := day, day.AddDate(0, 0, daysArg(request, 1))
+	if from.Before(now) {
+		from = now
+	}
+	if !to.After(from) {
+		return invalid("The days to plan are over"), nil
+	}
+	dryRun, _ := args["dry_run"].(bool)
+
+	tasks, err := s.planner.ListTasks()
+	if err != nil {
+		return failed(err, "Failed to list tasks"), nil
+	}
+	breaks, warnings := schedule.PlanBreaks(tasks, from, to)
+
+	result := map[string]interface{}{"breaks": breaks}
+	if len(warnings) > 0 {
+		result["warnings"] = warnings
+	}
+	if !dryRun {
+		replaced, ids, err := s.planner.ReplaceSystemTasks([]string{planner.SystemBreak, planner.SystemMicroBreak}, from, to, breaks)
+		if err != nil {
+			return failed(err, "Failed to save breaks"), nil
+		}
+		for i, id := range ids {
+			breaks[i].ID = id
+		}
+		result["replaced"] = replaced
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return failed(err, "Failed to marshal breaks"), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
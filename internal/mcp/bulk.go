@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func completeRangeTool() mcp.Tool {
+	return mcp.NewTool("complete_range",
+		mcp.WithDescription("Mark every unfinished task starting between two dates as completed, in one transaction. Nothing changes if any of them still waits on an open task outside the range."),
+		mcp.WithString("from", mcp.Required(), mcp.Description("First day, YYYY-MM-DD")),
+		mcp.WithString("to", mcp.Description("Last day, inclusive, YYYY-MM-DD (default: same as from)")),
+	)
+}
+
+func purgeCompletedTool() mcp.Tool {
+	return mcp.NewTool("purge_completed",
+		mcp.WithDescription("Permanently delete completed tasks that ended more than the given number of days ago. Confirm with the user first."),
+		mcp.WithNumber("older_than_days", mcp.Required(), mcp.Description("Delete finished tasks that ended more than this many days ago")),
+	)
+}
+
+func shiftTodayTool() mcp.Tool {
+	return mcp.NewTool("shift_today",
+		mcp.WithDescription("Move all of today's remaining flexible tasks (including the one running now) by the given minutes, in one transaction. Fixed tasks stay put."),
+		mcp.WithNumber("minutes", mcp.Required(), mcp.Description("Minutes to move by; negative moves earlier")),
+	)
+}
+
+func (s *Server) handleCompleteRange(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	fromStr, _ := args["from"].(string)
+	toStr, _ := args["to"].(string)
+	if toStr == "" {
+		toStr = fromStr
+	}
+	from, err := time.ParseInLocation("2006-01-02", fromStr, s.now().Location())
+	if err != nil {
+		return invalid("Invalid from date: %v", err), nil
+	}
+	to, err := time.ParseInLocation("2006-01-02", toStr, s.now().Location())
+	if err != nil {
+		return invalid("Invalid to date: %v", err), nil
+	}
+	if to.Before(from) {
+		return invalid("from must not be after to"), nil
+	}
+
+	n, err := s.planner.CompleteRange(from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return failed(err, "Failed to complete tasks"), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Marked %d tasks as completed", n)), nil
+}
+
+func (s *Server) handlePurgeCompleted(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	days, ok := args["older_than_days"].(float64)
+	if !ok || days < 0 {
+		return invalid("older_than_days must be a non-negative number"), nil
+	}
+
+	n, err := s.planner.PurgeCompleted(s.now().Add(-time.Duration(days * float64(24*time.Hour))))
+	if err != nil {
+		return failed(err, "Failed to delete tasks"), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Deleted %d completed tasks", n)), nil
+}
+
+func (s *Server) handleShiftToday(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	minutes, ok := args["minutes"].(float64)
+	if !ok || minutes == 0 {
+		return invalid("minutes must be a non-zero number"), nil
+	}
+
+	moved, fixed, err := s.planner.ShiftRemainingToday(s.now(), time.Duration(minutes)*time.Minute)
+	if err != nil {
+		return failed(err, "Failed to shift tasks"), nil
+	}
+	msg := fmt.Sprintf("Moved %d tasks by %d minutes", moved, int(minutes))
+	if fixed > 0 {
+		msg += fmt.Sprintf("; %d fixed tasks were left in place", fixed)
+	}
+	return mcp.NewToolResultText(msg), nil
+}
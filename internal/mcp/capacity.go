@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"gomentum/internal/schedule"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func checkCapacityTool() mcp.Tool {
+	return mcp.NewTool("check_capacity",
+		mcp.WithDescription("Report planned hours per workday against the daily capacity and list the over-committed days"),
+		mcp.WithNumber("days", mcp.Description("How many days ahead to check (default 7)")),
+	)
+}
+
+func suggestRebalanceTool() mcp.Tool {
+	return mcp.NewTool("suggest_rebalance",
+		mcp.WithDescription("Suggest moving tasks off over-committed days into free slots on days with spare capacity. Nothing is changed; apply the moves the user accepts with update_task."),
+		mcp.WithNumber("days", mcp.Description("How many days ahead to consider (default 7)")),
+	)
+}
+
+func daysArg(request mcp.CallToolRequest, def int) int {
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	if v, ok := args["days"].(float64); ok && v > 0 {
+		return int(v)
+	}
+	return def
+}
+
+func (s *Server) handleCheckCapacity(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	tasks, err := s.planner.ListTasks()
+	if err != nil {
+		return failed(err, "Failed to list tasks"), nil
+	}
+
+	loads := schedule.Loads(tasks, s.now(), daysArg(request, 7))
+	var warnings []string
+	for _, d := range loads {
+		if d.Over() > 0 {
+			warnings = append(warnings, d.Warning())
+		}
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"days":     loads,
+		"warnings": warnings,
+	})
+	if err != nil {
+		return failed(err, "Failed to marshal result"), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+func (s *Server) handleSuggestRebalance(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	tasks, err := s.planner.ListTasks()
+	if err != nil {
+		return failed(err, "Failed to list tasks"), nil
+	}
+	movable, err := s.movable()
+	if err != nil {
+		return failed(err, "Failed to read links"), nil
+	}
+
+	moves := schedule.Rebalance(tasks, s.now(), daysArg(request, 7), movable)
+	if len(moves) == 0 {
+		return mcp.NewToolResultText("No moves needed or possible: no over-committed day has a task that fits elsewhere."), nil
+	}
+
+	data, err := json.Marshal(moves)
+	if err != nil {
+		return failed(err, "Failed to marshal moves"), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+// capacityWarning returns the over-capacity warning for the day of t, if any
+func (s *Server) capacityWarning(t time.Time) string {
+	tasks, err := s.planner.ListTasks()
+	if err != nil {
+		return ""
+	}
+	var warnings []string
+	for _, d := range schedule.Overloaded(tasks, t, 1) {
+		warnings = append(warnings, d.Warning())
+	}
+	return strings.Join(warnings, "; ")
+}
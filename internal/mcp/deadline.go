@@ -0,0 +1,120 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gomentum/internal/planner"
+	"gomentum/internal/schedule"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// SourceDeadline links the sessions booked for a deadline to their first session
+const SourceDeadline = "deadline"
+
+func scheduleDeadlineTool() mcp.Tool {
+	return mcp.NewTool("schedule_deadline",
+		mcp.WithDescription("Book work sessions for something due at a deadline. Given the estimated effort, sessions are placed in free working time as late as possible before the deadline (backward scheduling). Fails with the missing amount if there is not enough free time; then suggest moving the deadline or other tasks."),
+		mcp.WithString("title", mcp.Required(), mcp.Description("The title of the work")),
+		mcp.WithString("description", mcp.Description("Detailed description")),
+		mcp.WithString("deadline", mcp.Required(), mcp.Description("When the work must be finished, RFC3339")),
+		mcp.WithNumber("effort_minutes", mcp.Required(), mcp.Description("Estimated total effort in minutes")),
+		mcp.WithNumber("min_session_minutes", mcp.Description("Shortest useful session (default 30)")),
+		mcp.WithNumber("max_session_minutes", mcp.Description("Longest session (default 120)")),
+		mcp.WithNumber("buffer_minutes", mcp.Description("Keep this much time free right before the deadline (default 0)")),
+	)
+}
+
+func (s *Server) handleScheduleDeadline(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return invalid("Invalid arguments format"), nil
+	}
+
+	title, _ := args["title"].(string)
+	desc, _ := args["description"].(string)
+	deadlineStr, _ := args["deadline"].(string)
+	if title == "" {
+		return invalid("title is required"), nil
+	}
+	deadline, err := time.Parse(time.RFC3339, deadlineStr)
+	if err != nil {
+		return invalid("Invalid deadline format: %v", err), nil
+	}
+	effort, _ := args["effort_minutes"].(float64)
+	if effort <= 0 {
+		return invalid("effort_minutes must be positive"), nil
+	}
+	minSession := 30 * time.Minute
+	if v, ok := args["min_session_minutes"].(float64); ok && v > 0 {
+		minSession = time.Duration(v) * time.Minute
+	}
+	maxSession := 120 * time.Minute
+	if v, ok := args["max_session_minutes"].(float64); ok && v > 0 {
+		maxSession = time.Duration(v) * time.Minute
+	}
+	buffer := time.Duration(0)
+	if v, ok := args["buffer_minutes"].(float64); ok && v > 0 {
+		buffer = time.Duration(v) * time.Minute
+	}
+
+	now := s.now()
+	latest := deadline.Add(-buffer)
+	if !latest.After(now) {
+		return invalid("The deadline (minus buffer) has already passed"), nil
+	}
+
+	tasks, err := s.planner.ListTasks()
+	if err != nil {
+		return failed(err, "Failed to list tasks"), nil
+	}
+	free := schedule.FreeSlots(tasks, now, latest, 0)
+	total := time.Duration(effort) * time.Minute
+
+	sessions, err := schedule.Backward(total, free, minSession, maxSession)
+	if err != nil {
+		return conflict("Warning: cannot fit %s of work before the deadline; only %s of free working time remains (%v). Nothing was booked.",
+			total.Round(time.Minute), schedule.Total(free).Round(time.Minute), err), nil
+	}
+
+	created, err := s.bookSessions(title, desc, deadline, sessions)
+	if err != nil {
+		return failed(err, "Failed to book sessions"), nil
+	}
+	data, err := json.Marshal(created)
+	if err != nil {
+		return failed(err, "Failed to marshal sessions"), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Booked %d sessions before the deadline: %s", len(created), data)), nil
+}
+
+// bookSessions adds one task per session, all carrying the deadline
+func (s *Server) bookSessions(title, desc string, deadline time.Time, sessions []schedule.Slot) ([]planner.Task, error) {
+	var created []planner.Task
+	for i, sess := range sessions {
+		partTitle := title
+		if len(sessions) > 1 {
+			partTitle = fmt.Sprintf("%s (%d/%d)", title, i+1, len(sessions))
+		}
+		t, err := s.planner.AddTask(partTitle, desc, sess.Start, sess.End)
+		if err != nil {
+			return created, err
+		}
+		t.Deadline = deadline
+		if err := s.planner.UpdateTask(t); err != nil {
+			return created, err
+		}
+		first := t.ID
+		if len(created) > 0 {
+			first = created[0].ID
+		}
+		if err := s.planner.Link(SourceDeadline, fmt.Sprintf("%d/%d", first, i+1), t.ID); err != nil {
+			return created, err
+		}
+		created = append(created, t)
+	}
+	return created, nil
+}
@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func linkTasksTool() mcp.Tool {
+	return mcp.NewTool("link_tasks",
+		mcp.WithDescription("Record that one task must be finished before another can be completed, e.g. 'buy the paint' before 'paint the room'. The blocked task cannot be marked done while the blocker is open. Set unlink=true to remove the dependency."),
+		mcp.WithNumber("blocker_id", mcp.Required(), mcp.Description("ID of the task that has to be done first")),
+		mcp.WithNumber("blocked_id", mcp.Required(), mcp.Description("ID of the task that waits for it")),
+		mcp.WithBoolean("unlink", mcp.Description("Remove the dependency instead of adding it")),
+	)
+}
+
+func (s *Server) handleLinkTasks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	blockerID, okA := args["blocker_id"].(float64)
+	blockedID, okB := args["blocked_id"].(float64)
+	if !okA || !okB {
+		return invalid("blocker_id and blocked_id are required"), nil
+	}
+	unlink, _ := args["unlink"].(bool)
+
+	if unlink {
+		if err := s.planner.RemoveDependency(int(blockerID), int(blockedID)); err != nil {
+			return failed(err, "Failed to unlink tasks"), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("Task %d no longer waits for task %d", int(blockedID), int(blockerID))), nil
+	}
+	if err := s.planner.AddDependency(int(blockerID), int(blockedID)); err != nil {
+		return failed(err, "Failed to link tasks"), nil
+	}
+	blocker, err := s.planner.GetTask(int(blockerID))
+	if err != nil {
+		return failed(err, "Failed to read task %d", int(blockerID)), nil
+	}
+	blocked, err := s.planner.GetTask(int(blockedID))
+	if err != nil {
+		return failed(err, "Failed to read task %d", int(blockedID)), nil
+	}
+	msg := fmt.Sprintf("'%s' (ID: %d) now waits for '%s' (ID: %d)", blocked.Title, blocked.ID, blocker.Title, blocker.ID)
+	if blocker.StartTime.After(blocked.StartTime) {
+		msg += fmt.Sprintf(". Note: the blocker starts at %s, after the blocked task; consider rescheduling.", blocker.StartTime.Format("2006-01-02 15:04"))
+	}
+	return mcp.NewToolResultText(msg), nil
+}
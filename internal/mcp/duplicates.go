@@ -0,0 +1,63 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gomentum/internal/dedupe"
+	"gomentum/internal/planner"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func findDuplicatesTool() mcp.Tool {
+	return mcp.NewTool("find_duplicates",
+		mcp.WithDescription("Find tasks that look entered twice, e.g. after an import: pairs with similar titles starting around the same time, most similar first. Nothing is changed; show the pairs to the user and fold the duplicates they confirm into the other task with merge_tasks."),
+		mcp.WithNumber("hours", mcp.Description(fmt.Sprintf("How many hours apart the starts of two duplicates may be (default %g)", dedupe.DefaultWindow.Hours()))),
+		mcp.WithBoolean("include_done", mcp.Description("Also compare finished tasks (default false)")),
+	)
+}
+
+func (s *Server) handleFindDuplicates(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	window := dedupe.DefaultWindow
+	if h, ok := args["hours"].(float64); ok && h > 0 {
+		window = time.Duration(h * float64(time.Hour))
+	}
+	includeDone, _ := args["include_done"].(bool)
+
+	tasks, err := s.planner.ListTasks()
+	if err != nil {
+		return failed(err, "Failed to list tasks"), nil
+	}
+	pairs := dedupe.Find(tasks, window, includeDone)
+	if len(pairs) == 0 {
+		return mcp.NewToolResultText("No duplicate tasks."), nil
+	}
+	data, err := json.Marshal(pairs)
+	if err != nil {
+		return failed(err, "Failed to marshal duplicates"), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("%d possible duplicates: %s", len(pairs), data)), nil
+}
+
+// duplicateNote points out tasks that look like duplicates of t, for the
+// result of add_task
+func (s *Server) duplicateNote(t planner.Task) string {
+	nearby, err := s.planner.TasksBetween(t.StartTime.Add(-dedupe.DefaultWindow), t.StartTime.Add(dedupe.DefaultWindow))
+	if err != nil {
+		return ""
+	}
+	dups := dedupe.Of(t, nearby, dedupe.DefaultWindow)
+	if len(dups) == 0 {
+		return ""
+	}
+	names := make([]string, len(dups))
+	for i, d := range dups {
+		names[i] = fmt.Sprintf("'%s' (ID: %d) at %s", d.Title, d.ID, d.StartTime.Format("2006-01-02 15:04"))
+	}
+	return ". Warning: possible duplicate of " + strings.Join(names, ", ") + "; ask the user whether both are needed and merge_tasks them if not"
+}
@@ -0,0 +1,33 @@
+package mcp
+
+import (
+	"fmt"
+
+	"gomentum/internal/planner"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// toolError is a failed tool result. The code (see planner.Code) leads the
+// text the model reads and is repeated as structured content for clients.
+func toolError(code, msg string) *mcp.CallToolResult {
+	res := mcp.NewToolResultError(fmt.Sprintf("[%s] %s", code, msg))
+	res.StructuredContent = map[string]string{"code": code, "message": msg}
+	return res
+}
+
+// invalid reports unusable arguments
+func invalid(format string, args ...interface{}) *mcp.CallToolResult {
+	return toolError(planner.CodeValidation, fmt.Sprintf(format, args...))
+}
+
+// conflict reports that the schedule cannot take the change
+func conflict(format string, args ...interface{}) *mcp.CallToolResult {
+	return toolError(planner.CodeConflict, fmt.Sprintf(format, args...))
+}
+
+// failed reports err after a description of what failed, with the code
+// taken from the kind of err
+func failed(err error, format string, args ...interface{}) *mcp.CallToolResult {
+	return toolError(planner.Code(err), fmt.Sprintf(format, args...)+": "+err.Error())
+}
@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gomentum/internal/exporter"
+	"gomentum/internal/planner"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func exportTasksTool() mcp.Tool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Export tasks to a markdown, iCalendar or CSV file in the export directory. Without ids or filter conditions every task is exported; otherwise only the matching ones, e.g. from/to for one week or fields for one client. Existing files are not replaced unless overwrite is set."),
+		mcp.WithString("filename", mcp.Description("File name inside the export directory; {date} and {time} are filled in and the format's extension is added if missing (default: plan-{date}.md)")),
+		mcp.WithString("format", mcp.Description("File format (default markdown)"), mcp.Enum(exporter.Formats...)),
+		mcp.WithBoolean("overwrite", mcp.Description("Replace an existing file instead of adding a number to the name")),
+		mcp.WithArray("ids", mcp.Description("Export only these tasks"), mcp.WithNumberItems()),
+	}
+	return mcp.NewTool("export_tasks", append(opts, filterParams()...)...)
+}
+
+// maxExportSuffix bounds the search for a free name like plan-2026-05-04-3.md
+const maxExportSuffix = 100
+
+// SetExports confines export_tasks to dir and sets the default filename
+// template. Empty values keep the defaults, "exports" and
+// "plan-{date}.md".
+func (s *Server) SetExports(dir, filename string) {
+	if dir != "" {
+		s.exportDir = dir
+	}
+	if filename != "" {
+		s.exportName = filename
+	}
+}
+
+// exportName fills in {date} and {time}, gives the name the extension of
+// format and makes sure it stays inside the export directory
+func exportName(name, format string, now time.Time) (string, error) {
+	name = strings.NewReplacer("{date}", now.Format("2006-01-02"), "{time}", now.Format("1504")).Replace(name)
+	name = exporter.WithExt(name, format)
+	name = filepath.Clean(filepath.FromSlash(name))
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("%q is outside the export directory: %w", name, planner.ErrValidation)
+	}
+	return name, nil
+}
+
+// createExport opens name inside root for writing. Unless overwrite is
+// set, an existing file is kept and a numbered name is used instead.
+func createExport(root *os.Root, name string, overwrite bool) (*os.File, string, error) {
+	if dir := filepath.Dir(name); dir != "." {
+		if err := root.MkdirAll(dir, 0o755); err != nil {
+			return nil, "", err
+		}
+	}
+	if overwrite {
+		f, err := root.Create(name)
+		return f, name, err
+	}
+	base, ext := strings.TrimSuffix(name, filepath.Ext(name)), filepath.Ext(name)
+	candidate := name
+	for i := 2; ; i++ {
+		f, err := root.OpenFile(candidate, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if !errors.Is(err, fs.ErrExist) || i > maxExportSuffix {
+			return f, candidate, err
+		}
+		candidate = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+}
+
+func (s *Server) handleExportTasks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	filename, _ := args["filename"].(string)
+	if filename == "" {
+		filename = s.exportName
+	}
+	overwrite, _ := args["overwrite"].(bool)
+	format, _ := args["format"].(string)
+	if format == "" {
+		format = exporter.Markdown
+	}
+	if exporter.Ext(format) == "" {
+		return invalid("Unknown format %q", format), nil
+	}
+
+	f, filtered, res := parseFilter(args)
+	if res != nil {
+		return res, nil
+	}
+	items, _ := args["ids"].([]interface{})
+	for _, item := range items {
+		id, ok := item.(float64)
+		if !ok {
+			return invalid("ids must be numbers"), nil
+		}
+		f.IDs = append(f.IDs, int(id))
+	}
+	var tasks []planner.Task
+	var err error
+	if filtered || len(f.IDs) > 0 {
+		tasks, err = s.planner.QueryTasks(f)
+	} else {
+		tasks, err = s.planner.ListTasks()
+	}
+	if err != nil {
+		return failed(err, "Failed to select tasks"), nil
+	}
+
+	name, err := exportName(filename, format, s.now())
+	if err != nil {
+		return failed(err, "Invalid filename"), nil
+	}
+	if err := os.MkdirAll(s.exportDir, 0o755); err != nil {
+		return failed(err, "Failed to create the export directory"), nil
+	}
+	// The root keeps symlinks from leading out of the directory as well
+	root, err := os.OpenRoot(s.exportDir)
+	if err != nil {
+		return failed(err, "Failed to open the export directory"), nil
+	}
+	defer root.Close()
+
+	file, name, err := createExport(root, name, overwrite)
+	if err != nil {
+		return failed(err, "Failed to export tasks"), nil
+	}
+	err = exporter.Write(file, format, tasks)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return failed(err, "Failed to export tasks"), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("%d tasks exported to %s", len(tasks), filepath.Join(s.exportDir, name))), nil
+}
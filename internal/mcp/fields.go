@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gomentum/internal/projects"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func setTaskFieldsTool() mcp.Tool {
+	return mcp.NewTool("set_task_fields",
+		mcp.WithDescription("Set custom fields on a task, such as client, billing code or ticket ID. Values may be strings, numbers or booleans; a null value removes the field. Other fields are kept. Setting the project field (project by default, see tag_icons.field) applies the project's configured defaults: its color and reminder unless the task has its own, and its flexibility."),
+		mcp.WithNumber("id", mcp.Required(), mcp.Description("The ID of the task")),
+		mcp.WithObject("fields", mcp.Required(), mcp.Description(`Field names mapped to values, e.g. {"client": "Acme", "ticket": "OPS-42"}`)),
+	)
+}
+
+func (s *Server) handleSetTaskFields(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return invalid("Invalid arguments format"), nil
+	}
+
+	idFloat, ok := args["id"].(float64)
+	if !ok {
+		return invalid("id is required"), nil
+	}
+	fields, ok := args["fields"].(map[string]interface{})
+	if !ok || len(fields) == 0 {
+		return invalid("fields must be a non-empty object"), nil
+	}
+
+	task, err := s.planner.GetTask(int(idFloat))
+	if err != nil {
+		return failed(err, "Task not found"), nil
+	}
+	project := projects.Of(task)
+	for k, v := range fields {
+		k = strings.TrimSpace(k)
+		if k == "" {
+			return invalid("Field names must not be empty"), nil
+		}
+		switch v.(type) {
+		case nil, string, float64, bool:
+		default:
+			return invalid("Field %q must be a string, number, boolean or null", k), nil
+		}
+		task.SetField(k, v)
+	}
+	// A new project brings its defaults
+	moved := projects.Of(task) != "" && !strings.EqualFold(projects.Of(task), project)
+	if moved {
+		projects.Apply(&task)
+	}
+	if err := s.planner.UpdateTask(task); err != nil {
+		return failed(err, "Failed to update task"), nil
+	}
+
+	data, err := json.Marshal(task)
+	if err != nil {
+		return failed(err, "Failed to marshal task"), nil
+	}
+	msg := fmt.Sprintf("Fields updated: %s", data)
+	if w := projects.Check(task); moved && w != "" {
+		msg += ". Warning: " + w
+	}
+	return mcp.NewToolResultText(msg), nil
+}
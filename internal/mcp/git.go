@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"gomentum/internal/exporter"
+	"gomentum/internal/gitlog"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func gitActivityTool() mcp.Tool {
+	return mcp.NewTool("git_activity",
+		mcp.WithDescription("Summarize the git commits of the user's configured repositories for a day: per repository the commits (time, subject, files and lines changed) and totals. Use it in end-of-day reviews to compare the tasks planned for that day (query_tasks) with the work actually shipped, and point out planned work without commits and commits without a planned task."),
+		mcp.WithString("date", mcp.Description("Day to summarize, YYYY-MM-DD (default today)")),
+		mcp.WithBoolean("all_authors", mcp.Description("Include commits by other people (default only the user's, by git user.email)")),
+	)
+}
+
+// EnableGitActivity adds the opt-in git_activity tool, reporting commits
+// of the repositories at repos
+func (s *Server) EnableGitActivity(repos []string) {
+	if len(repos) == 0 {
+		return
+	}
+	enabled := s.gitRepos != nil
+	s.gitRepos = make([]string, len(repos))
+	for i, repo := range repos {
+		s.gitRepos[i] = exporter.ExpandHome(repo)
+	}
+	if !enabled {
+		s.mcpServer.AddTool(gitActivityTool(), s.handleGitActivity)
+	}
+}
+
+func (s *Server) handleGitActivity(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if len(s.gitRepos) == 0 {
+		return invalid("git_activity is disabled; list repositories under agent.git_repos to enable it"), nil
+	}
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	now := s.now()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if v, _ := args["date"].(string); v != "" {
+		d, err := time.ParseInLocation("2006-01-02", v, now.Location())
+		if err != nil {
+			return invalid("Invalid date: %v", err), nil
+		}
+		day = d
+	}
+	allAuthors, _ := args["all_authors"].(bool)
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	repos := make([]gitlog.Repo, 0, len(s.gitRepos))
+	total := 0
+	for _, path := range s.gitRepos {
+		author := ""
+		if !allAuthors {
+			author = gitlog.UserEmail(ctx, path)
+		}
+		repo := gitlog.Activity(ctx, path, day, day.AddDate(0, 0, 1), author)
+		total += len(repo.Commits)
+		repos = append(repos, repo)
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"date":    day.Format("2006-01-02"),
+		"commits": total,
+		"repos":   repos,
+	})
+	if err != nil {
+		return failed(err, "Failed to marshal activity"), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
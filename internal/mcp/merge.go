@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gomentum/internal/planner"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func mergeTasksTool() mcp.Tool {
+	return mcp.NewTool("merge_tasks",
+		mcp.WithDescription("Merge a duplicate task into another and delete the duplicate, in one transaction. The kept task keeps its title and gains the duplicate's description, tags, fields, dependencies and links; its time runs from the earlier start to the later end, unless title, start_time or end_time are given. Nothing changes if the merged task would overlap another, unless allow_overlap is set. Ask the user which title and time to keep when the two differ. Recurring tasks cannot be merged."),
+		mcp.WithNumber("keep_id", mcp.Required(), mcp.Description("ID of the task to keep")),
+		mcp.WithNumber("merge_id", mcp.Required(), mcp.Description("ID of the duplicate to merge in and delete")),
+		mcp.WithString("title", mcp.Description("Title of the merged task (default: that of keep_id)")),
+		mcp.WithString("start_time", mcp.Description("Start of the merged task, RFC3339 or a phrase like 'tomorrow 3pm' (default: the earlier start)")),
+		mcp.WithString("end_time", mcp.Description("End of the merged task (default: the later end)")),
+		mcp.WithBoolean("allow_overlap", mcp.Description("Set to true to merge even if the merged task overlaps another")),
+	)
+}
+
+func (s *Server) handleMergeTasks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	keepID, okKeep := args["keep_id"].(float64)
+	mergeID, okMerge := args["merge_id"].(float64)
+	if !okKeep || !okMerge {
+		return invalid("keep_id and merge_id are required"), nil
+	}
+	title, _ := args["title"].(string)
+	title = strings.TrimSpace(title)
+	startStr, _ := args["start_time"].(string)
+	endStr, _ := args["end_time"].(string)
+
+	// Read the times before anything changes
+	now := s.now()
+	var start, end time.Time
+	var err error
+	if startStr != "" {
+		if start, err = parseWhen(startStr, now, time.Time{}); err != nil {
+			return invalid("Invalid start_time: %v", err), nil
+		}
+	}
+	if endStr != "" {
+		if end, err = parseWhen(endStr, now, start); err != nil {
+			return invalid("Invalid end_time: %v", err), nil
+		}
+	}
+
+	allowOverlap, _ := args["allow_overlap"].(bool)
+	task, err := s.planner.MergeTasks(int(keepID), int(mergeID), planner.MergeOptions{
+		Title: title, Start: start, End: end, AllowOverlap: allowOverlap,
+	})
+	if err != nil {
+		return failed(err, "Failed to merge tasks"), nil
+	}
+
+	data, err := json.Marshal(task)
+	if err != nil {
+		return failed(err, "Failed to marshal task"), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Merged task %d into task %d: %s", int(mergeID), task.ID, data)), nil
+}
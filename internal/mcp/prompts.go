@@ -0,0 +1,29 @@
+package mcp
+
+import (
+	"context"
+
+	"gomentum/internal/prompts"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// AddPrompts offers the prompt workflows to MCP clients, which list them
+// with prompts/list and fetch the rendered text with prompts/get
+func (s *Server) AddPrompts(list []prompts.Prompt) {
+	for _, p := range list {
+		s.mcpServer.AddPrompt(mcp.NewPrompt(p.Name,
+			mcp.WithPromptDescription(p.Description),
+			mcp.WithArgument("input", mcp.ArgumentDescription("Details to fill in, e.g. the day or the destination")),
+		), s.promptHandler(p))
+	}
+}
+
+func (s *Server) promptHandler(p prompts.Prompt) func(context.Context, mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		text := p.Render(request.Params.Arguments["input"])
+		return mcp.NewGetPromptResult(p.Description, []mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+		}), nil
+	}
+}
@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gomentum/internal/planner"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// defaultQueryLimit keeps large histories from flooding the context
+const defaultQueryLimit = 50
+
+func queryTasksTool() mcp.Tool {
+	opts := []mcp.ToolOption{
+		mcp.WithDescription("Search the task history with a filter. Translate questions like 'what did I finish for Acme in March?' into from/to, status, priority, tag, text and custom field conditions; all given conditions must match. Returns tasks ordered by start time."),
+	}
+	opts = append(opts, filterParams()...)
+	opts = append(opts, mcp.WithNumber("limit", mcp.Description(fmt.Sprintf("Maximum number of tasks (default %d)", defaultQueryLimit))))
+	return mcp.NewTool("query_tasks", opts...)
+}
+
+// filterParams are the conditions of a planner.Filter, shared by the
+// tools that select tasks
+func filterParams() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithString("from", mcp.Description("Tasks starting on or after this date (YYYY-MM-DD) or time (RFC3339)")),
+		mcp.WithString("to", mcp.Description("Tasks starting before this time (RFC3339), or on or before this date (YYYY-MM-DD)")),
+		mcp.WithArray("status", mcp.Description("Any of these statuses"), mcp.WithStringEnumItems(planner.StatusNames())),
+		mcp.WithArray("priority", mcp.Description("Any of these priorities"), mcp.WithStringEnumItems(planner.Priorities)),
+		mcp.WithArray("tags", mcp.Description("Any of these tags, such as work or errand"), mcp.WithStringItems()),
+		mcp.WithString("text", mcp.Description("Text contained in the title or description, ignoring case")),
+		mcp.WithObject("fields", mcp.Description(`Custom fields that must have these values, e.g. {"client": "Acme"}`)),
+	}
+}
+
+// parseFilter reads the filterParams conditions. It reports whether any
+// was given; the result is non-nil for invalid input.
+func parseFilter(args map[string]interface{}) (planner.Filter, bool, *mcp.CallToolResult) {
+	var f planner.Filter
+	var err error
+	if v, _ := args["from"].(string); v != "" {
+		if f.From, _, err = parseQueryTime(v); err != nil {
+			return f, false, invalid("Invalid from: %v", err)
+		}
+	}
+	if v, _ := args["to"].(string); v != "" {
+		var dateOnly bool
+		if f.To, dateOnly, err = parseQueryTime(v); err != nil {
+			return f, false, invalid("Invalid to: %v", err)
+		}
+		if dateOnly {
+			f.To = f.To.AddDate(0, 0, 1)
+		}
+	}
+	f.Statuses = stringList(args["status"])
+	f.Priorities = stringList(args["priority"])
+	f.Tags = stringList(args["tags"])
+	f.Text, _ = args["text"].(string)
+	if fields, ok := args["fields"].(map[string]interface{}); ok {
+		f.Fields = make(map[string]string, len(fields))
+		for k, v := range fields {
+			f.Fields[k] = fmt.Sprint(v)
+		}
+	}
+	given := !f.From.IsZero() || !f.To.IsZero() || len(f.Statuses) > 0 || len(f.Priorities) > 0 || len(f.Tags) > 0 || f.Text != "" || len(f.Fields) > 0
+	return f, given, nil
+}
+
+func (s *Server) handleQueryTasks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	f, _, res := parseFilter(args)
+	if res != nil {
+		return res, nil
+	}
+	f.Limit = defaultQueryLimit
+	if v, ok := args["limit"].(float64); ok && v > 0 {
+		f.Limit = int(v)
+	}
+
+	tasks, err := s.planner.QueryTasks(f)
+	if err != nil {
+		return failed(err, "Failed to query tasks"), nil
+	}
+	data, err := json.MarshalIndent(tasks, "", "  ")
+	if err != nil {
+		return failed(err, "Failed to marshal tasks"), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("%d matching tasks: %s", len(tasks), data)), nil
+}
+
+// parseQueryTime accepts RFC3339 or a local date, reporting which it was
+func parseQueryTime(v string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, false, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+	return t, true, err
+}
+
+// stringList reads a JSON array of strings, skipping other values
+func stringList(v interface{}) []string {
+	items, _ := v.([]interface{})
+	var out []string
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
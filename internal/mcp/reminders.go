@@ -0,0 +1,38 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func reminderHistoryTool() mcp.Tool {
+	return mcp.NewTool("reminder_history",
+		mcp.WithDescription("List fired reminders, latest first, with each channel (desktop, email, sound): attempts, when it was delivered, or the last error. Use it to answer whether the user was notified about a task. Kept for 30 days."),
+		mcp.WithNumber("task_id", mcp.Description("Only reminders for this task")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of reminders (default 20)")),
+	)
+}
+
+func (s *Server) handleReminderHistory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	taskID, _ := args["task_id"].(float64)
+	limit := 20
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	history, err := s.planner.ReminderHistory(int(taskID), limit)
+	if err != nil {
+		return failed(err, "Failed to read reminder history"), nil
+	}
+	if len(history) == 0 {
+		return mcp.NewToolResultText("No reminders found in the last 30 days."), nil
+	}
+	data, err := json.Marshal(history)
+	if err != nil {
+		return failed(err, "Failed to marshal reminder history"), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gomentum/internal/outlook"
+	"gomentum/internal/planner"
+	"gomentum/internal/schedule"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func bumpAndScheduleTool() mcp.Tool {
+	return mcp.NewTool("bump_and_schedule",
+		mcp.WithDescription("Schedule an important task into an occupied slot by moving lower-priority tasks out of the way. Without apply=true only the proposed moves are returned: show them to the user and call again with apply=true once they approve."),
+		mcp.WithString("title", mcp.Required(), mcp.Description("The title of the new task")),
+		mcp.WithString("description", mcp.Description("Detailed description of the task")),
+		mcp.WithString("start_time", mcp.Required(), mcp.Description("Start time in RFC3339 format")),
+		mcp.WithString("end_time", mcp.Required(), mcp.Description("End time in RFC3339 format")),
+		mcp.WithString("priority", mcp.Required(), mcp.Description("Priority of the new task: "+strings.Join(planner.Priorities, ", "))),
+		mcp.WithNumber("days", mcp.Description("How many days ahead bumped tasks may move (default 7)")),
+		mcp.WithBoolean("apply", mcp.Description("Apply the moves and add the task (default false: only propose)")),
+	)
+}
+
+// movable reports which tasks engines may move: flexible pending tasks
+// that are not mirrored from an external calendar
+func (s *Server) movable() (func(planner.Task) bool, error) {
+	mirrored, err := s.planner.Links(outlook.SourceMirror)
+	if err != nil {
+		return nil, err
+	}
+	fixed := make(map[int]bool, len(mirrored))
+	for _, id := range mirrored {
+		fixed[id] = true
+	}
+	return func(t planner.Task) bool {
+		return !fixed[t.ID] && t.Flexible && t.Status == planner.StatusPending
+	}, nil
+}
+
+func (s *Server) handleBumpAndSchedule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return invalid("Invalid arguments format"), nil
+	}
+
+	title, _ := args["title"].(string)
+	desc, _ := args["description"].(string)
+	priority, _ := args["priority"].(string)
+	startTime, err := time.Parse(time.RFC3339, fmt.Sprint(args["start_time"]))
+	if err != nil {
+		return invalid("Invalid start_time format: %v", err), nil
+	}
+	endTime, err := time.Parse(time.RFC3339, fmt.Sprint(args["end_time"]))
+	if err != nil {
+		return invalid("Invalid end_time format: %v", err), nil
+	}
+	if title == "" || !endTime.After(startTime) {
+		return invalid("title is required and end_time must be after start_time"), nil
+	}
+	if !validPriority(priority) {
+		return invalid("Invalid priority %q, use one of: %s", priority, strings.Join(planner.Priorities, ", ")), nil
+	}
+	days := 7
+	if v, ok := args["days"].(float64); ok && v > 0 {
+		days = int(v)
+	}
+	apply, _ := args["apply"].(bool)
+
+	tasks, err := s.planner.ListTasks()
+	if err != nil {
+		return failed(err, "Failed to list tasks"), nil
+	}
+	movable, err := s.movable()
+	if err != nil {
+		return failed(err, "Failed to read links"), nil
+	}
+
+	slot := schedule.Slot{Start: startTime, End: endTime}
+	moves, err := schedule.Bump(tasks, slot, priority, endTime.AddDate(0, 0, days), movable)
+	if err != nil {
+		return conflict("Cannot make room: %v", err), nil
+	}
+
+	data, err := json.Marshal(moves)
+	if err != nil {
+		return failed(err, "Failed to marshal moves"), nil
+	}
+	if !apply {
+		return mcp.NewToolResultText(fmt.Sprintf("Proposed moves (not applied, ask the user to approve): %s", data)), nil
+	}
+
+	for _, mv := range moves {
+		t, err := s.planner.GetTask(mv.TaskID)
+		if err != nil {
+			return failed(err, "Failed to move task %d", mv.TaskID), nil
+		}
+		t.StartTime, t.EndTime = mv.To.Start, mv.To.End
+		if err := s.planner.UpdateTask(t); err != nil {
+			return failed(err, "Failed to move task %d", mv.TaskID), nil
+		}
+	}
+	task, err := s.planner.AddTask(title, desc, startTime, endTime)
+	if err != nil {
+		return failed(err, "Failed to add task"), nil
+	}
+	task.Priority = priority
+	if err := s.planner.UpdateTask(task); err != nil {
+		return failed(err, "Failed to set priority"), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Task added: ID=%d, Title=%s. Applied moves: %s", task.ID, task.Title, data)), nil
+}
+
+func validPriority(p string) bool {
+	for _, v := range planner.Priorities {
+		if v == p {
+			return true
+		}
+	}
+	return false
+}
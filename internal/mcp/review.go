@@ -0,0 +1,37 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"gomentum/internal/review"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func reviewStaleTool() mcp.Tool {
+	return mcp.NewTool("review_stale",
+		mcp.WithDescription("Find pending tasks whose time has passed and that nobody has touched for a while, and propose for each whether to delete it, reschedule it into the given free slot, or move it to the backlog (status 'backlog': kept, but no longer blocking time). Nothing is changed; go through the proposals with the user and apply the accepted ones with update_task or delete_task."),
+		mcp.WithNumber("days", mcp.Description("How many days without changes make a task stale (default 14)")),
+	)
+}
+
+func (s *Server) handleReviewStale(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	tasks, err := s.planner.ListTasks()
+	if err != nil {
+		return failed(err, "Failed to list tasks"), nil
+	}
+
+	age := time.Duration(daysArg(request, 14)) * 24 * time.Hour
+	proposals := review.Triage(tasks, s.now(), age)
+	if len(proposals) == 0 {
+		return mcp.NewToolResultText("No stale tasks."), nil
+	}
+
+	data, err := json.Marshal(proposals)
+	if err != nil {
+		return failed(err, "Failed to marshal proposals"), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
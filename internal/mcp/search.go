@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"gomentum/internal/search"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func semanticSearchTool() mcp.Tool {
+	return mcp.NewTool("semantic_search",
+		mcp.WithDescription("Find tasks by meaning when the user describes a task vaguely, e.g. 'that thing about the dentist'. Returns the closest tasks, best first, each with a score up to 1. Use query_tasks instead for exact conditions such as dates, status or text."),
+		mcp.WithString("query", mcp.Required(), mcp.Description("What the task is about, in the user's words")),
+		mcp.WithNumber("limit", mcp.Description(fmt.Sprintf("Maximum number of tasks (default %d)", search.DefaultLimit))),
+		mcp.WithBoolean("include_done", mcp.Description("Also search finished tasks (default false)")),
+	)
+}
+
+// EnableSemanticSearch offers the semantic_search tool backed by s; a nil
+// searcher leaves it off
+func (s *Server) EnableSemanticSearch(searcher *search.Searcher) {
+	if searcher == nil {
+		return
+	}
+	enabled := s.searcher != nil
+	s.searcher = searcher
+	if !enabled {
+		s.mcpServer.AddTool(semanticSearchTool(), s.handleSemanticSearch)
+	}
+}
+
+func (s *Server) handleSemanticSearch(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.searcher == nil {
+		return invalid("semantic_search is disabled; set embeddings.provider to local or openai to enable it"), nil
+	}
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	query, _ := args["query"].(string)
+	limit, _ := args["limit"].(float64)
+	includeDone, _ := args["include_done"].(bool)
+
+	results, err := s.searcher.Search(ctx, query, int(limit), includeDone)
+	if err != nil {
+		return failed(err, "Failed to search tasks"), nil
+	}
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return failed(err, "Failed to marshal tasks"), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("%d matching tasks: %s", len(results), data)), nil
+}
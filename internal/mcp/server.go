@@ -4,9 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
+	"gomentum/internal/calendar"
+	"gomentum/internal/dedupe"
+	"gomentum/internal/i18n"
 	"gomentum/internal/planner"
+	"gomentum/internal/projects"
+	"gomentum/internal/search"
+	"gomentum/internal/telemetry"
+	"gomentum/internal/version"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -14,26 +22,43 @@ import (
 
 // Server wraps the MCP server and the Planner
 type Server struct {
-	mcpServer *server.MCPServer
-	planner   *planner.Planner
+	mcpServer  *server.MCPServer
+	planner    *planner.Planner
+	sqlEnabled bool             // See EnableSQL
+	gitRepos   []string         // See EnableGitActivity
+	searcher   *search.Searcher // See EnableSemanticSearch
+	// export_tasks writes inside exportDir; exportName is the default
+	// filename template, see SetExports
+	exportDir  string
+	exportName string
+	now        func() time.Time // See SetClock
 }
 
 // NewServer creates a new MCP server instance
 func NewServer(p *planner.Planner) *Server {
 	s := server.NewMCPServer(
 		"Gomentum Planner",
-		"0.1.0",
+		version.Version,
 	)
 
 	srv := &Server{
-		mcpServer: s,
-		planner:   p,
+		mcpServer:  s,
+		planner:    p,
+		exportDir:  "exports",
+		exportName: "plan-{date}.md",
+		now:        time.Now,
 	}
 
 	srv.registerTools()
 	return srv
 }
 
+// SetClock replaces time.Now for the tools, e.g. to pin the time zone
+// their days and relative dates are worked out in
+func (s *Server) SetClock(now func() time.Time) {
+	s.now = now
+}
+
 func (s *Server) registerTools() {
 	// Tool: current_time
 	s.mcpServer.AddTool(mcp.NewTool("current_time",
@@ -45,20 +70,27 @@ func (s *Server) registerTools() {
 		mcp.WithDescription("Add a new task to the schedule"),
 		mcp.WithString("title", mcp.Required(), mcp.Description("The title of the task")),
 		mcp.WithString("description", mcp.Description("Detailed description of the task")),
-		mcp.WithString("start_time", mcp.Required(), mcp.Description("Start time in RFC3339 format (e.g. 2023-10-01T14:00:00Z)")),
-		mcp.WithString("end_time", mcp.Required(), mcp.Description("End time in RFC3339 format")),
+		mcp.WithString("start_time", mcp.Required(), mcp.Description("Start time in RFC3339 format (e.g. 2023-10-01T14:00:00+08:00); phrases like 'tomorrow 3pm' are understood too")),
+		mcp.WithString("end_time", mcp.Description("End time in RFC3339 format, or a time such as '4pm' on the start day; may be left out when the project has a default duration")),
+		mcp.WithString("deadline", mcp.Description("Optional hard deadline (RFC3339), separate from end_time")),
+		mcp.WithString("priority", mcp.Description("Priority: low, medium (default), high or urgent")),
+		mcp.WithString("color", mcp.Description("Color label that sets the task apart in the list, e.g. to tell work from personal: "+strings.Join(planner.Colors, ", ")+" or #RRGGBB; empty uses the project's color")),
+		mcp.WithBoolean("flexible", mcp.Description("Whether the task may be moved automatically (default true). Set false for fixed appointments such as meetings")),
+		mcp.WithString("location", mcp.Description("Where the task takes place, e.g. office or a client's address; travel time is planned between tasks at different locations")),
+		mcp.WithString("project", mcp.Description("The task's project, stored in the configured project field. Projects may bring a default duration, reminder, color, flexibility and hours; explicit arguments win")),
+		mcp.WithString("recurrence", mcp.Description("Repeat the task: daily, weekdays, weekly, biweekly, monthly, yearly or an RRULE such as FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10. Later occurrences are added as separate tasks up to two weeks ahead")),
+		mcp.WithArray("tags", mcp.Description("Labels that categorize the task, such as work or errand; unlike the project, a task can have any number of them"), mcp.WithStringItems()),
 	), s.handleAddTask)
 
 	// Tool: list_tasks
 	s.mcpServer.AddTool(mcp.NewTool("list_tasks",
 		mcp.WithDescription("List all scheduled tasks"),
+		mcp.WithArray("priority", mcp.Description("Only tasks with any of these priorities; medium also matches tasks without one"), mcp.WithStringEnumItems(planner.Priorities)),
+		mcp.WithString("sort", mcp.Description("Order by start time (the default) or by priority, most important first"), mcp.Enum("start", "priority")),
 	), s.handleListTasks)
 
 	// Tool: export_tasks
-	s.mcpServer.AddTool(mcp.NewTool("export_tasks",
-		mcp.WithDescription("Export scheduled tasks to a markdown file"),
-		mcp.WithString("filename", mcp.Description("The filename to save to (default: plan.md)")),
-	), s.handleExportTasks)
+	s.mcpServer.AddTool(exportTasksTool(), s.handleExportTasks)
 
 	// Tool: update_task
 	s.mcpServer.AddTool(mcp.NewTool("update_task",
@@ -66,9 +98,16 @@ func (s *Server) registerTools() {
 		mcp.WithNumber("id", mcp.Required(), mcp.Description("The ID of the task to update")),
 		mcp.WithString("title", mcp.Description("The new title of the task")),
 		mcp.WithString("description", mcp.Description("The new description")),
-		mcp.WithString("start_time", mcp.Description("The new start time (RFC3339)")),
-		mcp.WithString("end_time", mcp.Description("The new end time (RFC3339)")),
-		mcp.WithString("status", mcp.Description("The new status (pending, completed, in_progress)")),
+		mcp.WithString("start_time", mcp.Description("The new start time (RFC3339, or a phrase like 'next monday 9am')")),
+		mcp.WithString("end_time", mcp.Description("The new end time (RFC3339, or a time such as '4pm' on the start day)")),
+		mcp.WithString("status", mcp.Description("The new status; backlog keeps the task without blocking time"), mcp.Enum(planner.StatusNames()...)),
+		mcp.WithString("deadline", mcp.Description("The new deadline (RFC3339), or 'none' to remove it")),
+		mcp.WithString("priority", mcp.Description("The new priority: low, medium, high or urgent")),
+		mcp.WithString("color", mcp.Description("Color label that sets the task apart in the list, e.g. to tell work from personal: "+strings.Join(planner.Colors, ", ")+" or #RRGGBB; 'none' falls back to the project's color")),
+		mcp.WithBoolean("flexible", mcp.Description("Whether the task may be moved automatically; false pins it in place")),
+		mcp.WithString("location", mcp.Description("Where the task takes place, or 'none' to remove it")),
+		mcp.WithString("recurrence", mcp.Description("A new rule for a recurring task (see add_task), or 'none' to stop it repeating. Occurrences after now that are not done are replaced; set it again after changing the task to update them")),
+		mcp.WithArray("tags", mcp.Description("The task's new labels, replacing the old ones; an empty list removes them all"), mcp.WithStringItems()),
 	), s.handleUpdateTask)
 
 	// Tool: delete_task
@@ -76,10 +115,98 @@ func (s *Server) registerTools() {
 		mcp.WithDescription("Delete a task by ID"),
 		mcp.WithNumber("id", mcp.Required(), mcp.Description("The ID of the task to delete")),
 	), s.handleDeleteTask)
+
+	// Tool: lunar_date
+	s.mcpServer.AddTool(mcp.NewTool("lunar_date",
+		mcp.WithDescription("Resolve dates in the Chinese lunar calendar. Give a festival (e.g. 'Mid-Autumn Festival', '春节') or a lunar month/day to get the Gregorian date, optionally shifted by offset_days (-1 = the day before). Give `date` instead to convert a Gregorian date to the lunar calendar."),
+		mcp.WithString("festival", mcp.Description("Festival name: "+strings.Join(calendar.FestivalNames(), ", "))),
+		mcp.WithNumber("lunar_month", mcp.Description("Lunar month (1-12)")),
+		mcp.WithNumber("lunar_day", mcp.Description("Lunar day (1-30)")),
+		mcp.WithBoolean("leap", mcp.Description("Whether lunar_month is the leap month")),
+		mcp.WithNumber("lunar_year", mcp.Description("Lunar year; defaults to the next occurrence from today")),
+		mcp.WithNumber("offset_days", mcp.Description("Days to add to the resolved date, e.g. -1 for the day before")),
+		mcp.WithString("date", mcp.Description("Gregorian date (YYYY-MM-DD) to convert to the lunar calendar")),
+	), s.handleLunarDate)
+
+	// Tool: split_task
+	s.mcpServer.AddTool(splitTaskTool(), s.handleSplitTask)
+
+	// Tool: schedule_deadline
+	s.mcpServer.AddTool(scheduleDeadlineTool(), s.handleScheduleDeadline)
+
+	// Tools: check_capacity, suggest_rebalance
+	s.mcpServer.AddTool(checkCapacityTool(), s.handleCheckCapacity)
+	s.mcpServer.AddTool(suggestRebalanceTool(), s.handleSuggestRebalance)
+
+	// Tool: bump_and_schedule
+	s.mcpServer.AddTool(bumpAndScheduleTool(), s.handleBumpAndSchedule)
+
+	// Tool: review_stale
+	s.mcpServer.AddTool(reviewStaleTool(), s.handleReviewStale)
+
+	// Tools: find_duplicates, merge_tasks
+	s.mcpServer.AddTool(findDuplicatesTool(), s.handleFindDuplicates)
+	s.mcpServer.AddTool(mergeTasksTool(), s.handleMergeTasks)
+
+	// Tool: set_task_fields
+	s.mcpServer.AddTool(setTaskFieldsTool(), s.handleSetTaskFields)
+
+	// Tool: get_stats
+	s.mcpServer.AddTool(getStatsTool(), s.handleGetStats)
+
+	// Tool: query_tasks
+	s.mcpServer.AddTool(queryTasksTool(), s.handleQueryTasks)
+
+	// Tool: complete_range
+	s.mcpServer.AddTool(completeRangeTool(), s.handleCompleteRange)
+
+	// Tool: purge_completed
+	s.mcpServer.AddTool(purgeCompletedTool(), s.handlePurgeCompleted)
+
+	// Tool: shift_today
+	s.mcpServer.AddTool(shiftTodayTool(), s.handleShiftToday)
+
+	// Tool: shift_tasks
+	s.mcpServer.AddTool(shiftTasksTool(), s.handleShiftTasks)
+
+	// Tool: swap_tasks
+	s.mcpServer.AddTool(swapTasksTool(), s.handleSwapTasks)
+
+	// Tool: link_tasks
+	s.mcpServer.AddTool(linkTasksTool(), s.handleLinkTasks)
+
+	// Tool: propose_changes
+	s.mcpServer.AddTool(proposeChangesTool(), s.handleProposeChanges)
+
+	// Tool: apply_workflow
+	s.mcpServer.AddTool(applyWorkflowTool(), s.handleApplyWorkflow)
+
+	// Tool: discard_workflow
+	s.mcpServer.AddTool(discardWorkflowTool(), s.handleDiscardWorkflow)
+
+	// Tool: list_workflows
+	s.mcpServer.AddTool(listWorkflowsTool(), s.handleListWorkflows)
+
+	// Tool: reminder_history
+	s.mcpServer.AddTool(reminderHistoryTool(), s.handleReminderHistory)
+
+	// Tool: revise_workflow
+	s.mcpServer.AddTool(reviseWorkflowTool(), s.handleReviseWorkflow)
+
+	// Tool: schedule_breaks
+	s.mcpServer.AddTool(scheduleBreaksTool(), s.handleScheduleBreaks)
+
+	// Tool: plan_travel
+	s.mcpServer.AddTool(planTravelTool(), s.handlePlanTravel)
+
+	// Tools: save_smart_list, list_smart_lists, query_smart_list
+	s.mcpServer.AddTool(saveSmartListTool(), s.handleSaveSmartList)
+	s.mcpServer.AddTool(listSmartListsTool(), s.handleListSmartLists)
+	s.mcpServer.AddTool(querySmartListTool(), s.handleQuerySmartList)
 }
 
 func (s *Server) handleCurrentTime(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	now := time.Now()
+	now := s.now()
 	payload := fmt.Sprintf(`{"local_time":"%s"}`, now.Format(time.RFC3339))
 	return mcp.NewToolResultText(payload), nil
 }
@@ -87,7 +214,7 @@ func (s *Server) handleCurrentTime(ctx context.Context, request mcp.CallToolRequ
 func (s *Server) handleAddTask(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args, ok := request.Params.Arguments.(map[string]interface{})
 	if !ok {
-		return mcp.NewToolResultError("Invalid arguments format"), nil
+		return invalid("Invalid arguments format"), nil
 	}
 
 	title, _ := args["title"].(string)
@@ -95,82 +222,180 @@ func (s *Server) handleAddTask(ctx context.Context, request mcp.CallToolRequest)
 	startStr, _ := args["start_time"].(string)
 	endStr, _ := args["end_time"].(string)
 
-	startTime, err := time.Parse(time.RFC3339, startStr)
+	now := s.now()
+	startTime, err := parseWhen(startStr, now, time.Time{})
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Invalid start_time format: %v", err)), nil
+		return invalid("Invalid start_time: %v", err), nil
 	}
 
-	endTime, err := time.Parse(time.RFC3339, endStr)
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Invalid end_time format: %v", err)), nil
+	project, _ := args["project"].(string)
+	project = strings.TrimSpace(project)
+	var endTime time.Time
+	if d, ok := projects.Lookup(project); endStr == "" && ok && d.Duration > 0 {
+		endTime = startTime.Add(d.Duration)
+	} else if endStr == "" {
+		return invalid("end_time is required unless the project has a default duration"), nil
+	} else if endTime, err = parseWhen(endStr, now, startTime); err != nil {
+		return invalid("Invalid end_time: %v", err), nil
 	}
 
 	// Check for overlap
 	allowOverlap, _ := args["allow_overlap"].(bool)
 	if !allowOverlap {
-		conflict, err := s.planner.CheckOverlap(startTime, endTime, 0)
+		clash, err := s.planner.CheckOverlap(startTime, endTime, 0)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to check overlap: %v", err)), nil
+			return failed(err, "Failed to check overlap"), nil
+		}
+		if clash != nil && dedupe.Similarity(title, clash.Title) >= dedupe.Threshold {
+			return conflict("'%s' (ID: %d) from %s to %s looks like the same task, so it is probably scheduled already. Ask the user before adding it again with allow_overlap=true.",
+				clash.Title, clash.ID, i18n.FormatTime(clash.StartTime), i18n.FormatTime(clash.EndTime)), nil
 		}
-		if conflict != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Time conflict with existing task: '%s' (ID: %d) from %s to %s. Set allow_overlap=true to force.",
-				conflict.Title, conflict.ID, conflict.StartTime.Format("15:04"), conflict.EndTime.Format("15:04"))), nil
+		if clash != nil {
+			return conflict("Time conflict with existing task: '%s' (ID: %d) from %s to %s. Set allow_overlap=true to force, or use bump_and_schedule if the new task is more important.",
+				clash.Title, clash.ID, i18n.FormatTime(clash.StartTime), i18n.FormatTime(clash.EndTime)), nil
 		}
 	}
 
-	task, err := s.planner.AddTask(title, desc, startTime, endTime)
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to add task: %v", err)), nil
+	priority, _ := args["priority"].(string)
+	if priority != "" && !validPriority(priority) {
+		return invalid("Invalid priority %q, use one of: %s", priority, strings.Join(planner.Priorities, ", ")), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Task added: ID=%d, Title=%s", task.ID, task.Title)), nil
-}
+	color, _ := args["color"].(string)
+	if !planner.ValidColor(color) {
+		return invalid("Invalid color %q, use one of: %s or #RRGGBB", color, strings.Join(planner.Colors, ", ")), nil
+	}
 
-func (s *Server) handleListTasks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	tasks, err := s.planner.ListTasks()
+	var deadline time.Time
+	if deadlineStr, _ := args["deadline"].(string); deadlineStr != "" {
+		deadline, err = parseWhen(deadlineStr, now, time.Time{})
+		if err != nil {
+			return invalid("Invalid deadline: %v", err), nil
+		}
+	}
+
+	rule, _ := args["recurrence"].(string)
+	recurrence, err := planner.NormalizeRecurrence(rule)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to list tasks: %v", err)), nil
+		return failed(err, "Invalid recurrence"), nil
+	}
+	labels := stringList(args["tags"])
+	for _, l := range labels {
+		if _, err := planner.NormalizeTag(l); err != nil {
+			return failed(err, "Invalid tag"), nil
+		}
 	}
 
-	data, err := json.MarshalIndent(tasks, "", "  ")
+	var task planner.Task
+	if recurrence != "" {
+		task, err = s.planner.AddRecurringTask(title, desc, startTime, endTime, recurrence)
+	} else {
+		task, err = s.planner.AddTask(title, desc, startTime, endTime)
+	}
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal tasks: %v", err)), nil
+		return failed(err, "Failed to add task"), nil
+	}
+	msg := fmt.Sprintf("Task added: ID=%d, Title=%s", task.ID, task.Title)
+	if recurrence != "" {
+		msg += ", repeating " + recurrence
+	}
+	if len(labels) > 0 {
+		// Before anything lists tasks, so occurrences get the tags too
+		if err := s.planner.SetTags(task.ID, labels); err != nil {
+			return failed(err, "Failed to tag task"), nil
+		}
+		if task, err = s.planner.GetTask(task.ID); err != nil {
+			return failed(err, "Failed to find task"), nil
+		}
+		msg += ", tagged " + strings.Join(task.Tags, ", ")
+	}
+	flexible, flexibleSet := args["flexible"].(bool)
+	if !flexibleSet {
+		flexible = true
+	}
+	location, _ := args["location"].(string)
+	location = strings.TrimSpace(location)
+	if !deadline.IsZero() || priority != "" || color != "" || !flexible || location != "" || project != "" {
+		task.Deadline, task.Priority, task.Color, task.Flexible = deadline, priority, color, flexible
+		if location != "" {
+			task.SetField(planner.FieldLocation, location)
+		}
+		if project != "" {
+			task.SetField(projects.Field(), project)
+			projects.Apply(&task)
+			if flexibleSet {
+				task.Flexible = flexible
+			}
+		}
+		if err := s.planner.UpdateTask(task); err != nil {
+			return failed(err, "Failed to update task"), nil
+		}
+		if !deadline.IsZero() && endTime.After(deadline) {
+			msg += ". Warning: it ends after its deadline"
+		}
+		if w := projects.Check(task); w != "" {
+			msg += ". Warning: " + w
+		}
+	}
+	if w := s.capacityWarning(startTime); w != "" {
+		msg += ". Warning: " + w + " (call suggest_rebalance to fix)"
 	}
+	if task.Location() != "" {
+		msg += s.travelNote(task.StartTime)
+	}
+	msg += s.duplicateNote(task)
 
-	return mcp.NewToolResultText(string(data)), nil
+	return mcp.NewToolResultText(msg), nil
 }
 
-func (s *Server) handleExportTasks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) handleListTasks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args, _ := request.Params.Arguments.(map[string]interface{})
-	filename, _ := args["filename"].(string)
-	if filename == "" {
-		filename = "plan.md"
+	f := planner.Filter{Priorities: stringList(args["priority"])}
+	for _, p := range f.Priorities {
+		if !validPriority(p) {
+			return invalid("Invalid priority %q, use one of: %s", p, strings.Join(planner.Priorities, ", ")), nil
+		}
+	}
+	switch sort, _ := args["sort"].(string); sort {
+	case "", "start":
+	case "priority":
+		f.ByPriority = true
+	default:
+		return invalid("Invalid sort %q, use start or priority", sort), nil
 	}
 
-	if err := s.planner.ExportToMarkdown(filename); err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to export tasks: %v", err)), nil
+	tasks, err := s.planner.QueryTasks(f)
+	if err != nil {
+		return failed(err, "Failed to list tasks"), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Tasks exported to %s", filename)), nil
+	data, err := json.MarshalIndent(tasks, "", "  ")
+	if err != nil {
+		return failed(err, "Failed to marshal tasks"), nil
+	}
+
+	return mcp.NewToolResultText(string(data)), nil
 }
 
 func (s *Server) handleUpdateTask(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args, ok := request.Params.Arguments.(map[string]interface{})
 	if !ok {
-		return mcp.NewToolResultError("Invalid arguments format"), nil
+		return invalid("Invalid arguments format"), nil
 	}
 
 	idFloat, ok := args["id"].(float64)
 	if !ok {
-		return mcp.NewToolResultError("Task ID is required and must be a number"), nil
+		return invalid("Task ID is required and must be a number"), nil
 	}
 	id := int(idFloat)
 
 	// Get existing task
 	task, err := s.planner.GetTask(id)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to find task: %v", err)), nil
+		return failed(err, "Failed to find task"), nil
 	}
+	// Fields is a map, so keep what travel planning needs before it changes
+	located, day := task.Location() != "", task.StartTime
 
 	// Update fields if provided
 	if title, ok := args["title"].(string); ok && title != "" {
@@ -180,56 +405,252 @@ func (s *Server) handleUpdateTask(ctx context.Context, request mcp.CallToolReque
 		task.Description = desc
 	}
 	if status, ok := args["status"].(string); ok && status != "" {
-		task.Status = status
+		if !planner.Status(status).Valid() {
+			return invalid("Invalid status %q, use one of: %s", status, strings.Join(planner.StatusNames(), ", ")), nil
+		}
+		task.Status = planner.Status(status)
 	}
+	now := s.now()
 	if startStr, ok := args["start_time"].(string); ok && startStr != "" {
-		if t, err := time.Parse(time.RFC3339, startStr); err == nil {
-			task.StartTime = t
+		t, err := parseWhen(startStr, now, time.Time{})
+		if err != nil {
+			return invalid("Invalid start_time: %v", err), nil
 		}
+		task.StartTime = t
 	}
 	if endStr, ok := args["end_time"].(string); ok && endStr != "" {
-		if t, err := time.Parse(time.RFC3339, endStr); err == nil {
-			task.EndTime = t
+		t, err := parseWhen(endStr, now, task.StartTime)
+		if err != nil {
+			return invalid("Invalid end_time: %v", err), nil
+		}
+		task.EndTime = t
+	}
+	if priority, ok := args["priority"].(string); ok && priority != "" {
+		if !validPriority(priority) {
+			return invalid("Invalid priority %q, use one of: %s", priority, strings.Join(planner.Priorities, ", ")), nil
+		}
+		task.Priority = priority
+	}
+	if color, ok := args["color"].(string); ok && color != "" {
+		if color == "none" {
+			color = ""
+		}
+		if !planner.ValidColor(color) {
+			return invalid("Invalid color %q, use one of: %s or #RRGGBB", color, strings.Join(planner.Colors, ", ")), nil
+		}
+		task.Color = color
+	}
+	if flexible, ok := args["flexible"].(bool); ok {
+		task.Flexible = flexible
+	}
+	if location, ok := args["location"].(string); ok && strings.TrimSpace(location) != "" {
+		if location = strings.TrimSpace(location); location == "none" {
+			task.SetField(planner.FieldLocation, nil)
+		} else {
+			task.SetField(planner.FieldLocation, location)
+		}
+	}
+	if deadlineStr, ok := args["deadline"].(string); ok && deadlineStr != "" {
+		if deadlineStr == "none" {
+			task.Deadline = time.Time{}
+		} else if t, err := parseWhen(deadlineStr, now, time.Time{}); err != nil {
+			return invalid("Invalid deadline: %v", err), nil
+		} else {
+			task.Deadline = t
+		}
+	}
+	rule, _ := args["recurrence"].(string)
+	setRule := strings.TrimSpace(rule) != ""
+	recurrence, err := planner.NormalizeRecurrence(rule)
+	if err != nil {
+		return failed(err, "Invalid recurrence"), nil
+	}
+	if setRule {
+		if series := task.Series(); series != 0 && series != task.ID {
+			return invalid("Task %d is an occurrence of the recurring task %d; change the recurrence there", task.ID, series), nil
+		}
+	}
+	_, setTags := args["tags"].([]interface{})
+	labels := stringList(args["tags"])
+	for _, l := range labels {
+		if _, err := planner.NormalizeTag(l); err != nil {
+			return failed(err, "Invalid tag"), nil
 		}
 	}
 
 	// Check for overlap
 	allowOverlap, _ := args["allow_overlap"].(bool)
 	if !allowOverlap {
-		conflict, err := s.planner.CheckOverlap(task.StartTime, task.EndTime, task.ID)
+		clash, err := s.planner.CheckOverlap(task.StartTime, task.EndTime, task.ID)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to check overlap: %v", err)), nil
+			return failed(err, "Failed to check overlap"), nil
 		}
-		if conflict != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Time conflict with existing task: '%s' (ID: %d) from %s to %s. Set allow_overlap=true to force.",
-				conflict.Title, conflict.ID, conflict.StartTime.Format("15:04"), conflict.EndTime.Format("15:04"))), nil
+		if clash != nil {
+			return conflict("Time conflict with existing task: '%s' (ID: %d) from %s to %s. Set allow_overlap=true to force.",
+				clash.Title, clash.ID, i18n.FormatTime(clash.StartTime), i18n.FormatTime(clash.EndTime)), nil
 		}
 	}
 
 	if err := s.planner.UpdateTask(task); err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to update task: %v", err)), nil
+		return failed(err, "Failed to update task"), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Task %d updated successfully", id)), nil
+	msg := fmt.Sprintf("Task %d updated successfully", id)
+	if setTags {
+		if err := s.planner.SetTags(id, labels); err != nil {
+			return failed(err, "Failed to tag task"), nil
+		}
+	}
+	if setRule {
+		removed, err := s.planner.SetRecurrence(id, recurrence)
+		if err != nil {
+			return failed(err, "Failed to change the recurrence"), nil
+		}
+		if recurrence == "" {
+			msg += fmt.Sprintf(", no longer repeating (%d upcoming occurrences removed)", removed)
+		} else {
+			msg += fmt.Sprintf(", now repeating %s (%d upcoming occurrences replaced)", recurrence, removed)
+		}
+	}
+	if w := projects.Check(task); w != "" {
+		msg += ". Warning: " + w
+	}
+	if located || task.Location() != "" {
+		msg += s.travelNote(day, task.StartTime)
+	}
+	return mcp.NewToolResultText(msg), nil
 }
 
 func (s *Server) handleDeleteTask(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args, ok := request.Params.Arguments.(map[string]interface{})
 	if !ok {
-		return mcp.NewToolResultError("Invalid arguments format"), nil
+		return invalid("Invalid arguments format"), nil
 	}
 
 	idFloat, ok := args["id"].(float64)
 	if !ok {
-		return mcp.NewToolResultError("Task ID is required and must be a number"), nil
+		return invalid("Task ID is required and must be a number"), nil
 	}
 	id := int(idFloat)
 
+	task, err := s.planner.GetTask(id)
+	if err != nil {
+		return failed(err, "Failed to delete task"), nil
+	}
 	if err := s.planner.DeleteTask(id); err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to delete task: %v", err)), nil
+		return failed(err, "Failed to delete task"), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Task %d deleted successfully", id)), nil
+	msg := fmt.Sprintf("Task %d deleted successfully", id)
+	if task.Location() != "" {
+		msg += s.travelNote(task.StartTime)
+	}
+	return mcp.NewToolResultText(msg), nil
+}
+
+func (s *Server) handleLunarDate(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	now := s.now()
+
+	// Gregorian -> lunar
+	if dateStr, _ := args["date"].(string); dateStr != "" {
+		d, err := time.ParseInLocation("2006-01-02", dateStr, now.Location())
+		if err != nil {
+			if d, err = time.Parse(time.RFC3339, dateStr); err != nil {
+				return invalid("Invalid date format (want YYYY-MM-DD): %v", err), nil
+			}
+		}
+		return lunarResult(d)
+	}
+
+	offset, _ := args["offset_days"].(float64)
+	year, _ := args["lunar_year"].(float64)
+
+	var (
+		resolved time.Time
+		err      error
+	)
+	if name, _ := args["festival"].(string); name != "" {
+		f, ok := calendar.FindFestival(name)
+		if !ok {
+			return invalid("Unknown festival %q. Known festivals: %s", name, strings.Join(calendar.FestivalNames(), ", ")), nil
+		}
+		if year > 0 {
+			resolved, err = f.Date(int(year), now.Location())
+		} else {
+			resolved, err = f.Next(now)
+		}
+	} else {
+		month, _ := args["lunar_month"].(float64)
+		day, _ := args["lunar_day"].(float64)
+		leap, _ := args["leap"].(bool)
+		if month == 0 || day == 0 {
+			return invalid("Provide either festival, lunar_month and lunar_day, or date"), nil
+		}
+		resolved, err = nextLunar(now, int(year), int(month), int(day), leap)
+	}
+	if err != nil {
+		return invalid("Failed to resolve lunar date: %v", err), nil
+	}
+
+	return lunarResult(resolved.AddDate(0, 0, int(offset)))
+}
+
+// nextLunar resolves a lunar month/day in the given year, or the next
+// occurrence on or after today when year is 0.
+func nextLunar(now time.Time, year, month, day int, leap bool) (time.Time, error) {
+	if year > 0 {
+		return calendar.LunarDate{Year: year, Month: month, Day: day, Leap: leap}.ToGregorian(now.Location())
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	current, err := calendar.ToLunar(today)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var lastErr error
+	for y := current.Year; y <= current.Year+2; y++ {
+		d, err := calendar.LunarDate{Year: y, Month: month, Day: day, Leap: leap}.ToGregorian(now.Location())
+		if err != nil {
+			// e.g. a leap month that does not exist this year
+			lastErr = err
+			continue
+		}
+		if !d.Before(today) {
+			return d, nil
+		}
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no upcoming occurrence found")
+	}
+	return time.Time{}, lastErr
+}
+
+func lunarResult(d time.Time) (*mcp.CallToolResult, error) {
+	ld, err := calendar.ToLunar(d)
+	if err != nil {
+		return invalid("%v", err), nil
+	}
+	out := map[string]interface{}{
+		"date":          d.Format("2006-01-02"),
+		"weekday":       d.Weekday().String(),
+		"lunar":         ld.String(),
+		"lunar_chinese": ld.Chinese(),
+		"lunar_year":    ld.Year,
+	}
+	if f, ok := calendar.FestivalOn(d); ok {
+		out["festival"] = f.Name
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return failed(err, "Failed to marshal result"), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+// MCPServer returns the underlying protocol server, used to expose the
+// tools over SSE or stdio transports.
+func (s *Server) MCPServer() *server.MCPServer {
+	return s.mcpServer
 }
 
 // GetTools returns the list of tool definitions (helper for the Agent)
@@ -244,7 +665,7 @@ func (s *Server) GetTools() []mcp.Tool {
 	// Since mark3labs/mcp-go server might not expose a simple "GetTools" list for local consumption easily without reflection or private access,
 	// we will manually reconstruct the definitions for the Agent to consume.
 
-	return []mcp.Tool{
+	tools := []mcp.Tool{
 		mcp.NewTool("current_time",
 			mcp.WithDescription("Return the current local time in RFC3339 format with timezone offset"),
 		),
@@ -252,32 +673,94 @@ func (s *Server) GetTools() []mcp.Tool {
 			mcp.WithDescription("Add a new task to the schedule"),
 			mcp.WithString("title", mcp.Required(), mcp.Description("The title of the task")),
 			mcp.WithString("description", mcp.Description("Detailed description of the task")),
-			mcp.WithString("start_time", mcp.Required(), mcp.Description("Start time in RFC3339 format (e.g. 2023-10-01T14:00:00Z)")),
-			mcp.WithString("end_time", mcp.Required(), mcp.Description("End time in RFC3339 format")),
+			mcp.WithString("start_time", mcp.Required(), mcp.Description("Start time in RFC3339 format (e.g. 2023-10-01T14:00:00+08:00); phrases like 'tomorrow 3pm' are understood too")),
+			mcp.WithString("end_time", mcp.Description("End time in RFC3339 format, or a time such as '4pm' on the start day; may be left out when the project has a default duration")),
+			mcp.WithString("deadline", mcp.Description("Optional hard deadline (RFC3339), separate from end_time")),
+			mcp.WithString("priority", mcp.Description("Priority: low, medium (default), high or urgent")),
+			mcp.WithString("color", mcp.Description("Color label that sets the task apart in the list, e.g. to tell work from personal: "+strings.Join(planner.Colors, ", ")+" or #RRGGBB; empty uses the project's color")),
+			mcp.WithBoolean("flexible", mcp.Description("Whether the task may be moved automatically (default true). Set false for fixed appointments such as meetings")),
+			mcp.WithString("location", mcp.Description("Where the task takes place, e.g. office or a client's address; travel time is planned between tasks at different locations")),
+			mcp.WithString("project", mcp.Description("The task's project, stored in the configured project field. Projects may bring a default duration, reminder, color, flexibility and hours; explicit arguments win")),
+			mcp.WithString("recurrence", mcp.Description("Repeat the task: daily, weekdays, weekly, biweekly, monthly, yearly or an RRULE such as FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10. Later occurrences are added as separate tasks up to two weeks ahead")),
+			mcp.WithArray("tags", mcp.Description("Labels that categorize the task, such as work or errand; unlike the project, a task can have any number of them"), mcp.WithStringItems()),
 			mcp.WithBoolean("allow_overlap", mcp.Description("Set to true to allow scheduling even if there is a conflict")),
 		),
 		mcp.NewTool("list_tasks",
 			mcp.WithDescription("List all scheduled tasks"),
+			mcp.WithArray("priority", mcp.Description("Only tasks with any of these priorities; medium also matches tasks without one"), mcp.WithStringEnumItems(planner.Priorities)),
+			mcp.WithString("sort", mcp.Description("Order by start time (the default) or by priority, most important first"), mcp.Enum("start", "priority")),
 		),
-		mcp.NewTool("export_tasks",
-			mcp.WithDescription("Export scheduled tasks to a markdown file"),
-			mcp.WithString("filename", mcp.Description("The filename to save to (default: plan.md)")),
-		),
+		exportTasksTool(),
 		mcp.NewTool("update_task",
 			mcp.WithDescription("Update an existing task"),
 			mcp.WithNumber("id", mcp.Required(), mcp.Description("The ID of the task to update")),
 			mcp.WithString("title", mcp.Description("The new title of the task")),
 			mcp.WithString("description", mcp.Description("The new description")),
-			mcp.WithString("start_time", mcp.Description("The new start time (RFC3339)")),
-			mcp.WithString("end_time", mcp.Description("The new end time (RFC3339)")),
-			mcp.WithString("status", mcp.Description("The new status (pending, completed, in_progress)")),
+			mcp.WithString("start_time", mcp.Description("The new start time (RFC3339, or a phrase like 'next monday 9am')")),
+			mcp.WithString("end_time", mcp.Description("The new end time (RFC3339, or a time such as '4pm' on the start day)")),
+			mcp.WithString("status", mcp.Description("The new status; backlog keeps the task without blocking time"), mcp.Enum(planner.StatusNames()...)),
+			mcp.WithString("deadline", mcp.Description("The new deadline (RFC3339), or 'none' to remove it")),
+			mcp.WithString("priority", mcp.Description("The new priority: low, medium, high or urgent")),
+			mcp.WithString("color", mcp.Description("Color label that sets the task apart in the list, e.g. to tell work from personal: "+strings.Join(planner.Colors, ", ")+" or #RRGGBB; 'none' falls back to the project's color")),
+			mcp.WithBoolean("flexible", mcp.Description("Whether the task may be moved automatically; false pins it in place")),
+			mcp.WithString("location", mcp.Description("Where the task takes place, or 'none' to remove it")),
+			mcp.WithString("recurrence", mcp.Description("A new rule for a recurring task (see add_task), or 'none' to stop it repeating. Occurrences after now that are not done are replaced; set it again after changing the task to update them")),
+			mcp.WithArray("tags", mcp.Description("The task's new labels, replacing the old ones; an empty list removes them all"), mcp.WithStringItems()),
 			mcp.WithBoolean("allow_overlap", mcp.Description("Set to true to allow scheduling even if there is a conflict")),
 		),
 		mcp.NewTool("delete_task",
 			mcp.WithDescription("Delete a task by ID"),
 			mcp.WithNumber("id", mcp.Required(), mcp.Description("The ID of the task to delete")),
 		),
+		mcp.NewTool("lunar_date",
+			mcp.WithDescription("Resolve dates in the Chinese lunar calendar. Give a festival (e.g. 'Mid-Autumn Festival', '春节') or a lunar month/day to get the Gregorian date, optionally shifted by offset_days (-1 = the day before). Give `date` instead to convert a Gregorian date to the lunar calendar."),
+			mcp.WithString("festival", mcp.Description("Festival name: "+strings.Join(calendar.FestivalNames(), ", "))),
+			mcp.WithNumber("lunar_month", mcp.Description("Lunar month (1-12)")),
+			mcp.WithNumber("lunar_day", mcp.Description("Lunar day (1-30)")),
+			mcp.WithBoolean("leap", mcp.Description("Whether lunar_month is the leap month")),
+			mcp.WithNumber("lunar_year", mcp.Description("Lunar year; defaults to the next occurrence from today")),
+			mcp.WithNumber("offset_days", mcp.Description("Days to add to the resolved date, e.g. -1 for the day before")),
+			mcp.WithString("date", mcp.Description("Gregorian date (YYYY-MM-DD) to convert to the lunar calendar")),
+		),
+		splitTaskTool(),
+		scheduleDeadlineTool(),
+		checkCapacityTool(),
+		suggestRebalanceTool(),
+		bumpAndScheduleTool(),
+		reviewStaleTool(),
+		findDuplicatesTool(),
+		mergeTasksTool(),
+		setTaskFieldsTool(),
+		getStatsTool(),
+		queryTasksTool(),
+		completeRangeTool(),
+		purgeCompletedTool(),
+		shiftTodayTool(),
+		shiftTasksTool(),
+		swapTasksTool(),
+		linkTasksTool(),
+		proposeChangesTool(),
+		applyWorkflowTool(),
+		discardWorkflowTool(),
+		listWorkflowsTool(),
+		reminderHistoryTool(),
+		reviseWorkflowTool(),
+		scheduleBreaksTool(),
+		planTravelTool(),
+		saveSmartListTool(),
+		listSmartListsTool(),
+		querySmartListTool(),
+	}
+	if s.sqlEnabled {
+		tools = append(tools, sqlQueryTool())
+	}
+	if len(s.gitRepos) > 0 {
+		tools = append(tools, gitActivityTool())
+	}
+	if s.searcher != nil {
+		tools = append(tools, semanticSearchTool())
 	}
+	return tools
 }
 
 // CallTool directly calls a tool (helper for the Agent)
@@ -293,6 +776,7 @@ func (s *Server) CallTool(ctx context.Context, name string, args map[string]inte
 	// We need to route this to the handler.
 	// The mcpServer handles JSON-RPC, but we want to call the handler directly.
 	// Let's switch on name for now since we are bridging locally.
+	telemetry.Count("tool." + name)
 
 	switch name {
 	case "current_time":
@@ -307,6 +791,70 @@ func (s *Server) CallTool(ctx context.Context, name string, args map[string]inte
 		return s.handleUpdateTask(ctx, req)
 	case "delete_task":
 		return s.handleDeleteTask(ctx, req)
+	case "lunar_date":
+		return s.handleLunarDate(ctx, req)
+	case "split_task":
+		return s.handleSplitTask(ctx, req)
+	case "schedule_deadline":
+		return s.handleScheduleDeadline(ctx, req)
+	case "check_capacity":
+		return s.handleCheckCapacity(ctx, req)
+	case "suggest_rebalance":
+		return s.handleSuggestRebalance(ctx, req)
+	case "bump_and_schedule":
+		return s.handleBumpAndSchedule(ctx, req)
+	case "review_stale":
+		return s.handleReviewStale(ctx, req)
+	case "find_duplicates":
+		return s.handleFindDuplicates(ctx, req)
+	case "merge_tasks":
+		return s.handleMergeTasks(ctx, req)
+	case "set_task_fields":
+		return s.handleSetTaskFields(ctx, req)
+	case "get_stats":
+		return s.handleGetStats(ctx, req)
+	case "query_tasks":
+		return s.handleQueryTasks(ctx, req)
+	case "complete_range":
+		return s.handleCompleteRange(ctx, req)
+	case "purge_completed":
+		return s.handlePurgeCompleted(ctx, req)
+	case "shift_today":
+		return s.handleShiftToday(ctx, req)
+	case "shift_tasks":
+		return s.handleShiftTasks(ctx, req)
+	case "swap_tasks":
+		return s.handleSwapTasks(ctx, req)
+	case "link_tasks":
+		return s.handleLinkTasks(ctx, req)
+	case "propose_changes":
+		return s.handleProposeChanges(ctx, req)
+	case "apply_workflow":
+		return s.handleApplyWorkflow(ctx, req)
+	case "discard_workflow":
+		return s.handleDiscardWorkflow(ctx, req)
+	case "list_workflows":
+		return s.handleListWorkflows(ctx, req)
+	case "reminder_history":
+		return s.handleReminderHistory(ctx, req)
+	case "revise_workflow":
+		return s.handleReviseWorkflow(ctx, req)
+	case "schedule_breaks":
+		return s.handleScheduleBreaks(ctx, req)
+	case "plan_travel":
+		return s.handlePlanTravel(ctx, req)
+	case "save_smart_list":
+		return s.handleSaveSmartList(ctx, req)
+	case "list_smart_lists":
+		return s.handleListSmartLists(ctx, req)
+	case "query_smart_list":
+		return s.handleQuerySmartList(ctx, req)
+	case "sql_query":
+		return s.handleSQLQuery(ctx, req)
+	case "git_activity":
+		return s.handleGitActivity(ctx, req)
+	case "semantic_search":
+		return s.handleSemanticSearch(ctx, req)
 	default:
 		return nil, fmt.Errorf("tool not found: %s", name)
 	}
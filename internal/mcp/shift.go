@@ -0,0 +1,133 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gomentum/internal/outlook"
+	"gomentum/internal/planner"
+	"gomentum/internal/schedule"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func shiftTasksTool() mcp.Tool {
+	return mcp.NewTool("shift_tasks",
+		mcp.WithDescription("Move a set of tasks by the same number of minutes in one transaction, e.g. 'push everything back 30 minutes'. Pick the set with ids, or with a custom field (such as a project) for all its upcoming tasks; without either, the rest of today is moved. Returns the moved tasks and any new time conflicts."),
+		mcp.WithNumber("minutes", mcp.Required(), mcp.Description("Minutes to move by; negative moves earlier")),
+		mcp.WithArray("ids", mcp.Description("IDs of the tasks to move"), mcp.WithNumberItems()),
+		mcp.WithString("field", mcp.Description("Move upcoming tasks whose custom field has the given value, e.g. project")),
+		mcp.WithString("value", mcp.Description("Value of the custom field")),
+		mcp.WithBoolean("include_fixed", mcp.Description("Also move fixed tasks such as meetings when selecting by rest of today or field (default false)")),
+	)
+}
+
+// shiftConflict is a moved task that now overlaps a task that stayed put
+type shiftConflict struct {
+	TaskID    int    `json:"task_id"`
+	WithID    int    `json:"with_id"`
+	WithTitle string `json:"with_title"`
+}
+
+func (s *Server) handleShiftTasks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	minutes, ok := args["minutes"].(float64)
+	if !ok || minutes == 0 {
+		return invalid("minutes must be a non-zero number"), nil
+	}
+	field, _ := args["field"].(string)
+	value, _ := args["value"].(string)
+	includeFixed, _ := args["include_fixed"].(bool)
+
+	var ids []int
+	if items, _ := args["ids"].([]interface{}); len(items) > 0 {
+		for _, item := range items {
+			id, ok := item.(float64)
+			if !ok {
+				return invalid("ids must be numbers"), nil
+			}
+			ids = append(ids, int(id))
+		}
+	} else {
+		now := s.now()
+		f := planner.Filter{}
+		if field != "" {
+			f.Fields = map[string]string{field: value}
+		} else {
+			f.To = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+		}
+		tasks, err := s.planner.QueryTasks(f)
+		if err != nil {
+			return failed(err, "Failed to select tasks"), nil
+		}
+		// Mirrored calendar events would be moved back by the next sync
+		mirrored, err := s.planner.Links(outlook.SourceMirror)
+		if err != nil {
+			return failed(err, "Failed to read links"), nil
+		}
+		fixed := make(map[int]bool, len(mirrored))
+		for _, id := range mirrored {
+			fixed[id] = true
+		}
+		for _, t := range tasks {
+			if !t.Status.Active() || !t.EndTime.After(now) || fixed[t.ID] {
+				continue
+			}
+			if !t.Flexible && !includeFixed {
+				continue
+			}
+			ids = append(ids, t.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return mcp.NewToolResultText("No tasks to move"), nil
+	}
+
+	if err := s.planner.ShiftTasks(ids, time.Duration(minutes)*time.Minute); err != nil {
+		return failed(err, "Failed to shift tasks"), nil
+	}
+
+	conflicts, err := s.shiftConflicts(ids)
+	if err != nil {
+		return failed(err, "Failed to check conflicts"), nil
+	}
+	moved, _ := json.Marshal(ids)
+	msg := fmt.Sprintf("Moved %d tasks by %d minutes: %s", len(ids), int(minutes), moved)
+	if len(conflicts) > 0 {
+		data, _ := json.Marshal(conflicts)
+		msg += fmt.Sprintf(". New time conflicts, tell the user: %s", data)
+	}
+	return mcp.NewToolResultText(msg), nil
+}
+
+// shiftConflicts finds busy tasks that the moved ones now overlap. Tasks
+// moved together keep their relative times, so only tasks that stayed
+// put can newly clash.
+func (s *Server) shiftConflicts(ids []int) ([]shiftConflict, error) {
+	tasks, err := s.planner.ListTasks()
+	if err != nil {
+		return nil, err
+	}
+	moved := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		moved[id] = true
+	}
+
+	var conflicts []shiftConflict
+	for _, t := range tasks {
+		if !moved[t.ID] || !schedule.Busy(t) {
+			continue
+		}
+		for _, o := range tasks {
+			if moved[o.ID] || !schedule.Busy(o) {
+				continue
+			}
+			if t.StartTime.Before(o.EndTime) && o.StartTime.Before(t.EndTime) {
+				conflicts = append(conflicts, shiftConflict{TaskID: t.ID, WithID: o.ID, WithTitle: o.Title})
+			}
+		}
+	}
+	return conflicts, nil
+}
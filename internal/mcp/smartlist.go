@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"gomentum/internal/planner"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// queryHelp explains the filter language of planner.ParseQuery to the agent
+const queryHelp = `Terms separated by spaces, all of which must hold: #work,errand (any of these tags), status:pending,in_progress, priority:high,urgent, is:overdue, date:today|tomorrow|yesterday|week|next-week|last-week|month|YYYY-MM-DD|YYYY-MM-DD..YYYY-MM-DD, field:value for custom fields such as project:acme, and plain or "quoted" words searched in titles and descriptions. Example: is:overdue priority:high,urgent`
+
+func saveSmartListTool() mcp.Tool {
+	return mcp.NewTool("save_smart_list",
+		mcp.WithDescription("Save a smart list: a named query, like a saved search in email, that the user can pick in the task list and query_smart_list runs. Relative dates are resolved each time the list is opened. Saving under an existing name replaces its query."),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the list, e.g. 'Overdue & urgent'")),
+		mcp.WithString("query", mcp.Description("The query. "+queryHelp)),
+		mcp.WithBoolean("delete", mcp.Description("Delete the list instead (default false)")),
+	)
+}
+
+func listSmartListsTool() mcp.Tool {
+	return mcp.NewTool("list_smart_lists",
+		mcp.WithDescription("List the saved smart lists with their queries and how many tasks each holds now"),
+	)
+}
+
+func querySmartListTool() mcp.Tool {
+	return mcp.NewTool("query_smart_list",
+		mcp.WithDescription("Return the tasks of a saved smart list, ordered by start time"),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Name of the list")),
+		mcp.WithNumber("limit", mcp.Description(fmt.Sprintf("Maximum number of tasks (default %d)", defaultQueryLimit))),
+	)
+}
+
+func (s *Server) handleSaveSmartList(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	name, _ := args["name"].(string)
+	if del, _ := args["delete"].(bool); del {
+		if err := s.planner.DeleteSmartList(name); err != nil {
+			return failed(err, "Failed to delete smart list"), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("Smart list %q deleted", name)), nil
+	}
+	query, _ := args["query"].(string)
+	l, err := s.planner.SaveSmartList(name, query)
+	if err != nil {
+		return failed(err, "Failed to save smart list"), nil
+	}
+	tasks, err := s.smartListTasks(l, 0)
+	if err != nil {
+		return failed(err, "Failed to query smart list"), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Smart list %q saved, %d tasks match now", l.Name, len(tasks))), nil
+}
+
+func (s *Server) handleListSmartLists(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	lists, err := s.planner.SmartLists()
+	if err != nil {
+		return failed(err, "Failed to list smart lists"), nil
+	}
+	type listCount struct {
+		planner.SmartList
+		Tasks int `json:"tasks"`
+	}
+	counts := make([]listCount, 0, len(lists))
+	for _, l := range lists {
+		tasks, err := s.smartListTasks(l, 0)
+		if err != nil {
+			return failed(err, "Failed to query smart list %q", l.Name), nil
+		}
+		counts = append(counts, listCount{SmartList: l, Tasks: len(tasks)})
+	}
+	data, err := json.Marshal(counts)
+	if err != nil {
+		return failed(err, "Failed to marshal smart lists"), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+func (s *Server) handleQuerySmartList(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	name, _ := args["name"].(string)
+	limit := defaultQueryLimit
+	if v, ok := args["limit"].(float64); ok && v > 0 {
+		limit = int(v)
+	}
+	l, err := s.planner.GetSmartList(name)
+	if err != nil {
+		return failed(err, "Failed to find smart list"), nil
+	}
+	tasks, err := s.smartListTasks(l, limit)
+	if err != nil {
+		return failed(err, "Failed to query smart list"), nil
+	}
+	data, err := json.MarshalIndent(tasks, "", "  ")
+	if err != nil {
+		return failed(err, "Failed to marshal tasks"), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("%d tasks in %q (%s): %s", len(tasks), l.Name, l.Query, data)), nil
+}
+
+// smartListTasks runs the query of l, at most limit tasks if limit > 0
+func (s *Server) smartListTasks(l planner.SmartList, limit int) ([]planner.Task, error) {
+	f, err := planner.ParseQuery(l.Query, s.now())
+	if err != nil {
+		return nil, err
+	}
+	f.Limit = limit
+	return s.planner.QueryTasks(f)
+}
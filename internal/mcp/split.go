@@ -0,0 +1,139 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gomentum/internal/planner"
+	"gomentum/internal/schedule"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// SourceSplit links the sessions of a split task to the original task ID
+const SourceSplit = "split"
+
+func splitTaskTool() mcp.Tool {
+	return mcp.NewTool("split_task",
+		mcp.WithDescription("Split a task that is longer than any free slot into several sessions across days, keeping its total duration. Sessions are placed in free working time starting now; the original task becomes the first session."),
+		mcp.WithNumber("id", mcp.Required(), mcp.Description("The ID of the task to split")),
+		mcp.WithNumber("min_session_minutes", mcp.Description("Shortest useful session (default 30)")),
+		mcp.WithNumber("max_session_minutes", mcp.Description("Longest session, e.g. 90 to avoid marathon blocks (default: no limit)")),
+		mcp.WithNumber("days", mcp.Description("How many days ahead to search (default 14)")),
+		mcp.WithBoolean("force", mcp.Description("Split even if the task fits into a single free slot")),
+	)
+}
+
+func (s *Server) handleSplitTask(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := request.Params.Arguments.(map[string]interface{})
+	if !ok {
+		return invalid("Invalid arguments format"), nil
+	}
+
+	idFloat, ok := args["id"].(float64)
+	if !ok {
+		return invalid("Task ID is required and must be a number"), nil
+	}
+	task, err := s.planner.GetTask(int(idFloat))
+	if err != nil {
+		return failed(err, "Failed to find task"), nil
+	}
+
+	minSession := 30 * time.Minute
+	if v, ok := args["min_session_minutes"].(float64); ok && v > 0 {
+		minSession = time.Duration(v) * time.Minute
+	}
+	var maxSession time.Duration
+	if v, ok := args["max_session_minutes"].(float64); ok && v > 0 {
+		maxSession = time.Duration(v) * time.Minute
+	}
+	days := 14
+	if v, ok := args["days"].(float64); ok && v > 0 {
+		days = int(v)
+	}
+	force, _ := args["force"].(bool)
+	if !task.Flexible {
+		return invalid("Task %d is fixed; ask the user before making it flexible with update_task", task.ID), nil
+	}
+
+	total := task.EndTime.Sub(task.StartTime)
+	if total <= 0 {
+		return invalid("Task has no duration to split"), nil
+	}
+
+	tasks, err := s.planner.ListTasks()
+	if err != nil {
+		return failed(err, "Failed to list tasks"), nil
+	}
+	now := s.now()
+	free := schedule.FreeSlots(tasks, now, now.AddDate(0, 0, days), task.ID)
+
+	if largest := schedule.Largest(free); !force && largest.Duration() >= total && (maxSession == 0 || total <= maxSession) {
+		return invalid("Task fits into the free slot %s - %s; move it there instead or set force=true",
+			largest.Start.Format(time.RFC3339), largest.End.Format(time.RFC3339)), nil
+	}
+
+	sessions, err := schedule.Split(total, free, minSession, maxSession)
+	if err != nil {
+		return conflict("Cannot split task within %d days: %v", days, err), nil
+	}
+	if len(sessions) < 2 && !force {
+		return invalid("Task does not need to be split"), nil
+	}
+
+	created, err := s.applySplit(task, sessions)
+	if err != nil {
+		return failed(err, "Failed to split task"), nil
+	}
+
+	data, err := json.Marshal(created)
+	if err != nil {
+		return failed(err, "Failed to marshal sessions"), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Task %d split into %d sessions: %s", task.ID, len(created), data)), nil
+}
+
+// applySplit turns task into the first session and adds the others,
+// linking each one back to the original task ID.
+func (s *Server) applySplit(task planner.Task, sessions []schedule.Slot) ([]planner.Task, error) {
+	n := len(sessions)
+	title, desc := task.Title, task.Description
+	var created []planner.Task
+
+	for i, sess := range sessions {
+		partTitle := fmt.Sprintf("%s (%d/%d)", title, i+1, n)
+		partDesc := fmt.Sprintf("Session %d of %d, split from task #%d", i+1, n, task.ID)
+		if desc != "" {
+			partDesc = desc + "\n" + partDesc
+		}
+
+		var part planner.Task
+		if i == 0 {
+			part = task
+			part.Title, part.Description = partTitle, partDesc
+			part.StartTime, part.EndTime = sess.Start, sess.End
+			if err := s.planner.UpdateTask(part); err != nil {
+				return created, err
+			}
+		} else {
+			var err error
+			part, err = s.planner.AddTask(partTitle, partDesc, sess.Start, sess.End)
+			if err != nil {
+				return created, err
+			}
+			if !task.Deadline.IsZero() {
+				part.Deadline = task.Deadline
+				if err := s.planner.UpdateTask(part); err != nil {
+					return created, err
+				}
+			}
+		}
+		if err := s.planner.Link(SourceSplit, fmt.Sprintf("%d/%d", task.ID, i+1), part.ID); err != nil {
+			return created, err
+		}
+		created = append(created, part)
+	}
+	return created, nil
+}
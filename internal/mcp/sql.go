@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+
+	"gomentum/internal/planner"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// sqlToolMaxRows keeps query results small enough for the model's context
+const sqlToolMaxRows = 200
+
+func sqlQueryTool() mcp.Tool {
+	return mcp.NewTool("sql_query",
+		mcp.WithDescription("Run a read-only SQL SELECT against the SQLite database for ad-hoc analysis the other tools cannot answer. Tables: tasks (id, title, description, start_time, end_time, status, reminded, deadline, priority, flexible, updated_at, fields as JSON, color), external_links, shares, reminder_deliveries, chat_history, activity (source, start_time, end_time, app, title, category, productivity: imported screen time), smart_lists (name, query, created_at). Writes are rejected."),
+		mcp.WithString("query", mcp.Required(), mcp.Description("A single SELECT or WITH ... SELECT statement")),
+	)
+}
+
+// EnableSQL adds the opt-in sql_query tool
+func (s *Server) EnableSQL() {
+	if s.sqlEnabled {
+		return
+	}
+	s.sqlEnabled = true
+	s.mcpServer.AddTool(sqlQueryTool(), s.handleSQLQuery)
+}
+
+func (s *Server) handleSQLQuery(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if !s.sqlEnabled {
+		return invalid("sql_query is disabled; set agent.sql_tool to true to enable it"), nil
+	}
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	query, _ := args["query"].(string)
+	if query == "" {
+		return invalid("query is required"), nil
+	}
+
+	res, err := s.planner.ReadOnlyQuery(ctx, query, sqlToolMaxRows)
+	if err != nil {
+		return toolError(planner.Code(err), err.Error()), nil
+	}
+	data, err := json.Marshal(res)
+	if err != nil {
+		return failed(err, "Failed to marshal result"), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
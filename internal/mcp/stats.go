@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"gomentum/internal/stats"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func getStatsTool() mcp.Tool {
+	return mcp.NewTool("get_stats",
+		mcp.WithDescription("Compute statistics for a past period from the task data: completion rate, missed tasks, planned hours, busiest day and average overrun (how late completed tasks were marked done). If screen time was imported from ActivityWatch or RescueTime, 'focus' contrasts planned deep-work blocks (flexible tasks of an hour or more) with the recorded activity: how much of the blocks went to focused work, the least focused blocks and the top distractions. Use it to answer questions like 'how productive was last week?' instead of guessing."),
+		mcp.WithString("from", mcp.Description("First day of the period, YYYY-MM-DD (default 7 days ago)")),
+		mcp.WithString("to", mcp.Description("Last day of the period, inclusive, YYYY-MM-DD (default today)")),
+	)
+}
+
+func (s *Server) handleGetStats(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	now := s.now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	from, to := today.AddDate(0, 0, -7), today
+	for key, dst := range map[string]*time.Time{"from": &from, "to": &to} {
+		v, _ := args[key].(string)
+		if v == "" {
+			continue
+		}
+		d, err := time.ParseInLocation("2006-01-02", v, now.Location())
+		if err != nil {
+			return invalid("Invalid %s date: %v", key, err), nil
+		}
+		*dst = d
+	}
+	to = to.AddDate(0, 0, 1)
+	if !to.After(from) {
+		return invalid("from must not be after to"), nil
+	}
+
+	tasks, err := s.planner.ListTasks()
+	if err != nil {
+		return failed(err, "Failed to list tasks"), nil
+	}
+	activity, err := s.planner.ActivityBetween(from, to)
+	if err != nil {
+		return failed(err, "Failed to list activity"), nil
+	}
+	report := stats.Compute(tasks, from, to, now)
+	report.Focus = stats.ComputeFocus(tasks, activity, from, to)
+	data, err := json.Marshal(report)
+	if err != nil {
+		return failed(err, "Failed to marshal stats"), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
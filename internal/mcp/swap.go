@@ -0,0 +1,40 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func swapTasksTool() mcp.Tool {
+	return mcp.NewTool("swap_tasks",
+		mcp.WithDescription("Exchange the start times of two tasks in one transaction. Each task keeps its duration. Fixed tasks are refused, and so is a swap that would overlap another task."),
+		mcp.WithNumber("id_a", mcp.Required(), mcp.Description("ID of the first task")),
+		mcp.WithNumber("id_b", mcp.Required(), mcp.Description("ID of the second task")),
+	)
+}
+
+func (s *Server) handleSwapTasks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	idA, okA := args["id_a"].(float64)
+	idB, okB := args["id_b"].(float64)
+	if !okA || !okB {
+		return invalid("id_a and id_b are required"), nil
+	}
+
+	if err := s.planner.SwapTasks(int(idA), int(idB)); err != nil {
+		return failed(err, "Failed to swap tasks"), nil
+	}
+	a, err := s.planner.GetTask(int(idA))
+	if err != nil {
+		return failed(err, "Failed to read task %d", int(idA)), nil
+	}
+	b, err := s.planner.GetTask(int(idB))
+	if err != nil {
+		return failed(err, "Failed to read task %d", int(idB)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Swapped: '%s' now %s-%s, '%s' now %s-%s",
+		a.Title, a.StartTime.Format("15:04"), a.EndTime.Format("15:04"),
+		b.Title, b.StartTime.Format("15:04"), b.EndTime.Format("15:04"))), nil
+}
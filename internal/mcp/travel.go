@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"gomentum/internal/planner"
+	"gomentum/internal/schedule"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func planTravelTool() mcp.Tool {
+	return mcp.NewTool("plan_travel",
+		mcp.WithDescription("Plan travel time between consecutive tasks at different locations (the location field of add_task and update_task), using the routes and default under schedule.travel. A travel buffer ends as each task starts; where the gap before it is too short the buffer fills the gap and a warning tells how much time is missing. Buffers are system tasks: they get reminders but do not count against the daily capacity or in stats. Buffers planned earlier for the same days are replaced; nothing else is moved. When schedule.travel.buffers is on, this already happens whenever a located task changes."),
+		mcp.WithString("date", mcp.Description("First day to plan, YYYY-MM-DD (default today; only the rest of today is planned)")),
+		mcp.WithNumber("days", mcp.Description("Number of days to plan (default 1)")),
+		mcp.WithBoolean("dry_run", mcp.Description("Only return the buffers and warnings (default false)")),
+	)
+}
+
+func (s *Server) handlePlanTravel(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	if !schedule.TravelConfigured() {
+		return invalid("No travel times are configured; set schedule.travel.default or routes"), nil
+	}
+	now := s.now()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if v, _ := args["date"].(string); v != "" {
+		d, err := time.ParseInLocation("2006-01-02", v, now.Location())
+		if err != nil {
+			return invalid("Invalid date: %v", err), nil
+		}
+		day = d
+	}
+	from, to := day, day.AddDate(0, 0, daysArg(request, 1))
+	if from.Before(now) {
+		from = now
+	}
+	if !to.After(from) {
+		return invalid("The days to plan are over"), nil
+	}
+	dryRun, _ := args["dry_run"].(bool)
+
+	tasks, err := s.planner.ListTasks()
+	if err != nil {
+		return failed(err, "Failed to list tasks"), nil
+	}
+	buffers, warnings := schedule.PlanTravel(tasks, from, to)
+
+	result := map[string]interface{}{"travel": buffers}
+	if len(warnings) > 0 {
+		result["warnings"] = warnings
+	}
+	if !dryRun {
+		replaced, ids, err := s.planner.ReplaceSystemTasks([]string{planner.SystemTravel}, from, to, buffers)
+		if err != nil {
+			return failed(err, "Failed to save travel"), nil
+		}
+		for i, id := range ids {
+			buffers[i].ID = id
+		}
+		result["replaced"] = replaced
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return failed(err, "Failed to marshal travel"), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+// travelNote replans the travel buffers of the days a located task was
+// added to, moved between or removed from, and returns the trips that no
+// longer fit as a warning to append to the tool's message. Past time is
+// left alone, and failures only cost the note.
+func (s *Server) travelNote(days ...time.Time) string {
+	if !schedule.TravelConfigured() {
+		return ""
+	}
+	tasks, err := s.planner.ListTasks()
+	if err != nil {
+		return ""
+	}
+	now := s.now()
+	seen := make(map[time.Time]bool)
+	var warnings []string
+	for _, d := range days {
+		if d.IsZero() {
+			continue
+		}
+		d = d.Local()
+		from := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, d.Location())
+		if seen[from] {
+			continue
+		}
+		seen[from] = true
+		to := from.AddDate(0, 0, 1)
+		if from.Before(now) {
+			from = now
+		}
+		if !to.After(from) {
+			continue
+		}
+		buffers, w := schedule.PlanTravel(tasks, from, to)
+		warnings = append(warnings, w...)
+		if schedule.TravelBuffers() {
+			s.planner.ReplaceSystemTasks([]string{planner.SystemTravel}, from, to, buffers)
+		}
+	}
+	if len(warnings) == 0 {
+		return ""
+	}
+	return ". Warning: " + strings.Join(warnings, "; ")
+}
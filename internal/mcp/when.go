@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gomentum/internal/i18n"
+)
+
+// Models are asked for RFC 3339 times, but weaker ones send phrases such
+// as "tomorrow 3pm". parseWhen accepts both so those calls still succeed.
+
+var (
+	// clockTime matches "3pm", "3:30 pm", "15:00" and "at 9", with an
+	// optional "at" in front
+	clockTime = regexp.MustCompile(`(?i)(?:^|\s)(?:at\s+)?(\d{1,2})(?::(\d{2}))?\s*(am|pm)(?:\s|$)|(?:^|\s)(?:at\s+)?(\d{1,2}):(\d{2})(?:\s|$)|(?:^|\s)(?:at\s+)(\d{1,2})(?:\s|$)`)
+	// namedTime matches "noon" and "midnight", not "afternoon"
+	namedTime = regexp.MustCompile(`(?i)(?:^|\s)(?:at\s+)?(noon|midnight)(?:\s|$)`)
+	// fromNow matches "in 2 hours" and "in 30 minutes"
+	fromNow = regexp.MustCompile(`(?i)^in\s+(\d+)\s*(m|mins?|minutes?|h|hrs?|hours?)$`)
+)
+
+// localLayouts are the RFC 3339 variants without an offset, read in the
+// local time zone
+var localLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"}
+
+// parseWhen reads a time given as RFC 3339, as a local date and time, or
+// as a phrase like "tomorrow 3pm", "next monday at 9:30", "in 2 hours" or
+// "noon". A phrase without a day falls on the day of ref, so an end time
+// of "4pm" follows a start of "tomorrow 3pm"; a zero ref means today.
+func parseWhen(s string, now, ref time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range localLayouts {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	phrase := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	if m := fromNow.FindStringSubmatch(phrase); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		unit := time.Minute
+		if strings.HasPrefix(m[2], "h") {
+			unit = time.Hour
+		}
+		return now.Add(time.Duration(n) * unit).Truncate(time.Minute), nil
+	}
+
+	hour, minute, rest, ok := splitClock(phrase)
+	if !ok {
+		return time.Time{}, fmt.Errorf("%q has no time of day; use RFC 3339 (e.g. 2006-01-02T15:04:05-07:00) or a phrase like 'tomorrow 3pm'", s)
+	}
+	day := ref
+	if ref.IsZero() {
+		day = now
+	}
+	if rest != "" {
+		if day, ok = i18n.ParseDay(rest, now); !ok {
+			return time.Time{}, fmt.Errorf("cannot read the day %q; use RFC 3339 (e.g. 2006-01-02T15:04:05-07:00) or a phrase like 'tomorrow 3pm'", rest)
+		}
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, now.Location()), nil
+}
+
+// splitClock takes the time of day out of a phrase and returns it with
+// the words left, which name the day
+func splitClock(phrase string) (hour, minute int, rest string, ok bool) {
+	if loc := namedTime.FindStringSubmatchIndex(phrase); loc != nil {
+		if phrase[loc[2]:loc[3]] == "noon" {
+			hour = 12
+		}
+		rest = strings.TrimSpace(phrase[:loc[0]] + " " + phrase[loc[1]:])
+		return hour, 0, strings.Join(strings.Fields(rest), " "), true
+	}
+
+	loc := clockTime.FindStringSubmatchIndex(phrase)
+	if loc == nil {
+		return 0, 0, "", false
+	}
+	group := func(i int) string {
+		if loc[2*i] < 0 {
+			return ""
+		}
+		return phrase[loc[2*i]:loc[2*i+1]]
+	}
+	h, m, meridiem := group(1), group(2), group(3)
+	if h == "" {
+		h, m = group(4), group(5)
+	}
+	if h == "" {
+		h = group(6)
+	}
+	hour, _ = strconv.Atoi(h)
+	if m != "" {
+		minute, _ = strconv.Atoi(m)
+	}
+	switch {
+	case meridiem != "" && (hour < 1 || hour > 12):
+		return 0, 0, "", false
+	case meridiem == "pm" && hour != 12:
+		hour += 12
+	case meridiem == "am" && hour == 12:
+		hour = 0
+	}
+	if hour > 23 || minute > 59 {
+		return 0, 0, "", false
+	}
+	rest = strings.TrimSpace(phrase[:loc[0]] + " " + phrase[loc[1]:])
+	return hour, minute, strings.Join(strings.Fields(rest), " "), true
+}
@@ -0,0 +1,175 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gomentum/internal/planner"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func proposeChangesTool() mcp.Tool {
+	return mcp.NewTool("propose_changes",
+		mcp.WithDescription("Save a multi-step plan as a workflow without applying it. Gather the tasks first, then propose every change at once, show the plan to the user and call apply_workflow after they confirm. Nothing changes until then, and an interrupted workflow can be resumed later."),
+		mcp.WithString("name", mcp.Required(), mcp.Description("Short name of the plan, e.g. 'Deep work Friday'")),
+		mcp.WithArray("changes", mcp.Required(), mcp.Description(`Changes in order. Each is an object with "op": "add" (title, description, start_time, end_time), "move" (task_id, start_time, end_time), "status" (task_id, status) or "delete" (task_id). Times are RFC3339.`),
+			mcp.Items(map[string]any{"type": "object"})),
+	)
+}
+
+func reviseWorkflowTool() mcp.Tool {
+	return mcp.NewTool("revise_workflow",
+		mcp.WithDescription("Change one item of a proposed workflow when the user tweaks the plan by item number, e.g. 'swap items 2 and 3' or 'drop the last one'. Items are numbered from 1 as in the workflow outline. Returns the revised outline; nothing is applied until apply_workflow."),
+		mcp.WithNumber("id", mcp.Description("Workflow ID; defaults to the plan proposed last today")),
+		mcp.WithString("action", mcp.Required(), mcp.Enum("swap", "remove", "replace", "insert"),
+			mcp.Description("swap exchanges the time slots of item and with; remove drops item; replace puts change in place of item; insert adds change before item, or at the end without item")),
+		mcp.WithNumber("item", mcp.Description("Item number")),
+		mcp.WithNumber("with", mcp.Description("Second item number for swap")),
+		mcp.WithObject("change", mcp.Description(`The new change for replace and insert, in the same form as in propose_changes`)),
+	)
+}
+
+func applyWorkflowTool() mcp.Tool {
+	return mcp.NewTool("apply_workflow",
+		mcp.WithDescription("Apply a proposed workflow after the user confirmed it. All changes are applied in one transaction: either all of them or none."),
+		mcp.WithNumber("id", mcp.Required(), mcp.Description("Workflow ID")),
+	)
+}
+
+func discardWorkflowTool() mcp.Tool {
+	return mcp.NewTool("discard_workflow",
+		mcp.WithDescription("Drop a proposed workflow the user rejected"),
+		mcp.WithNumber("id", mcp.Required(), mcp.Description("Workflow ID")),
+	)
+}
+
+func listWorkflowsTool() mcp.Tool {
+	return mcp.NewTool("list_workflows",
+		mcp.WithDescription("List workflows still waiting for confirmation, e.g. to resume one after an interruption"),
+	)
+}
+
+func (s *Server) handleProposeChanges(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	name, _ := args["name"].(string)
+	if name == "" {
+		return invalid("name is required"), nil
+	}
+	// Round-trip through JSON to read the change objects into planner.Change
+	data, err := json.Marshal(args["changes"])
+	if err != nil {
+		return invalid("Invalid changes: %v", err), nil
+	}
+	var changes []planner.Change
+	if err := json.Unmarshal(data, &changes); err != nil {
+		return invalid("Invalid changes: %v", err), nil
+	}
+
+	w, err := s.planner.ProposeWorkflow(name, changes)
+	if err != nil {
+		return failed(err, "Failed to save workflow"), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Saved workflow %d '%s' with %d changes:\n%sNothing is applied yet: show the plan to the user with these item numbers and call apply_workflow with id=%d once they confirm, or discard_workflow if they do not.",
+		w.ID, w.Name, len(w.Changes), w.Outline(), w.ID)), nil
+}
+
+func (s *Server) handleReviseWorkflow(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	action, _ := args["action"].(string)
+	item, _ := args["item"].(float64)
+	with, _ := args["with"].(float64)
+
+	var w planner.Workflow
+	var err error
+	if id, ok := args["id"].(float64); ok {
+		w, err = s.planner.GetWorkflow(int(id))
+	} else {
+		now := s.now()
+		w, err = s.planner.LatestWorkflow(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()))
+	}
+	if err != nil {
+		return failed(err, "No plan to revise"), nil
+	}
+
+	var change planner.Change
+	if action == "replace" || action == "insert" {
+		data, err := json.Marshal(args["change"])
+		if err != nil {
+			return invalid("Invalid change: %v", err), nil
+		}
+		if err := json.Unmarshal(data, &change); err != nil || change.Op == "" {
+			return invalid("change is required for %s", action), nil
+		}
+	}
+
+	switch action {
+	case "swap":
+		err = w.SwapItems(int(item), int(with))
+	case "remove":
+		err = w.RemoveItem(int(item))
+	case "replace":
+		err = w.ReplaceItem(int(item), change)
+	case "insert":
+		err = w.InsertItem(int(item), change)
+	default:
+		return invalid("action must be swap, remove, replace or insert"), nil
+	}
+	if err != nil {
+		return failed(err, "Failed to %s item", action), nil
+	}
+
+	revised, err := s.planner.ReviseWorkflow(w)
+	if err != nil {
+		return failed(err, "Failed to revise workflow %d", w.ID), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Workflow %d '%s' now reads:\n%sNothing is applied yet.", revised.ID, revised.Name, revised.Outline())), nil
+}
+
+func (s *Server) handleApplyWorkflow(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	id, ok := args["id"].(float64)
+	if !ok {
+		return invalid("id is required"), nil
+	}
+
+	added, err := s.planner.ApplyWorkflow(int(id))
+	if err != nil {
+		return failed(err, "Workflow %d was not applied, nothing changed", int(id)), nil
+	}
+	msg := fmt.Sprintf("Workflow %d applied", int(id))
+	if len(added) > 0 {
+		ids, _ := json.Marshal(added)
+		msg += fmt.Sprintf("; added task IDs %s", ids)
+	}
+	return mcp.NewToolResultText(msg), nil
+}
+
+func (s *Server) handleDiscardWorkflow(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	id, ok := args["id"].(float64)
+	if !ok {
+		return invalid("id is required"), nil
+	}
+	if err := s.planner.DiscardWorkflow(int(id)); err != nil {
+		return failed(err, "Failed to discard workflow %d", int(id)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Workflow %d discarded", int(id))), nil
+}
+
+func (s *Server) handleListWorkflows(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	list, err := s.planner.ListWorkflows(planner.WorkflowProposed)
+	if err != nil {
+		return failed(err, "Failed to list workflows"), nil
+	}
+	if len(list) == 0 {
+		return mcp.NewToolResultText("No workflows are waiting for confirmation"), nil
+	}
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return failed(err, "Failed to marshal workflows"), nil
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
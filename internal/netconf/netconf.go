@@ -0,0 +1,130 @@
+// Package netconf applies the network section of the config. The LLM
+// client, update checks, telemetry, Outlook and webhooks all go through
+// http.DefaultTransport, which Configure points at the proxy and the
+// extra certificate authorities; mail takes its TLS settings from
+// TLSConfig.
+package netconf
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gomentum/internal/config"
+)
+
+var (
+	mu      sync.RWMutex
+	rootCAs *x509.CertPool // nil trusts the system CAs only
+)
+
+// Configure routes HTTP clients through cfg.Proxy and trusts the CAs of
+// cfg.CABundle next to the system ones. Without a proxy, the HTTP_PROXY,
+// HTTPS_PROXY and NO_PROXY variables apply as usual.
+func Configure(cfg config.NetworkConfig) error {
+	t, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return errors.New("the default HTTP transport was replaced")
+	}
+	proxy, err := proxyFunc(cfg)
+	if err != nil {
+		return err
+	}
+	pool, err := loadCAs(cfg.CABundle)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	t.Proxy = proxy
+	rootCAs = pool
+	if pool != nil {
+		t.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+	return nil
+}
+
+// TLSConfig returns the TLS settings for a connection to serverName that
+// does not go through net/http, such as SMTP
+func TLSConfig(serverName string) *tls.Config {
+	mu.RLock()
+	defer mu.RUnlock()
+	return &tls.Config{ServerName: serverName, RootCAs: rootCAs}
+}
+
+// proxyFunc returns the proxy of each request: none for hosts in
+// cfg.NoProxy and loopback addresses, cfg.Proxy for the rest
+func proxyFunc(cfg config.NetworkConfig) (func(*http.Request) (*url.URL, error), error) {
+	if cfg.Proxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	u, err := url.Parse(cfg.Proxy)
+	if err != nil || u.Host == "" {
+		// The URL may hold a password, so it stays out of the message
+		return nil, fmt.Errorf("invalid network.proxy: expected a URL such as http://proxy:3128")
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("network.proxy must be an http, https, socks5 or socks5h URL, not %q", u.Scheme)
+	}
+	return func(r *http.Request) (*url.URL, error) {
+		if bypass(r.URL.Hostname(), cfg.NoProxy) {
+			return nil, nil
+		}
+		return u, nil
+	}, nil
+}
+
+// bypass reports whether host is reached directly: it is localhost, a
+// loopback address, or in noProxy, where "example.com" also covers its
+// subdomains and "*" covers every host
+func bypass(host string, noProxy []string) bool {
+	host = strings.ToLower(host)
+	if host == "localhost" {
+		return true
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return true
+	}
+	for _, h := range noProxy {
+		h = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(h), "."))
+		if h == "*" || host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadCAs reads a PEM bundle into a pool on top of the system CAs
+func loadCAs(path string) (*x509.CertPool, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read network.ca_bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("network.ca_bundle %s holds no PEM certificates", path)
+	}
+	return pool, nil
+}
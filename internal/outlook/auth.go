@@ -0,0 +1,209 @@
+// Package outlook syncs with Outlook calendars through Microsoft Graph.
+// Events are mirrored as busy blocks, and Gomentum tasks can optionally be
+// pushed back as calendar entries.
+package outlook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gomentum/internal/config"
+)
+
+// ErrNotLoggedIn is returned when no token has been stored yet
+var ErrNotLoggedIn = errors.New("not signed in to Outlook, run `gomentum outlook login`")
+
+const (
+	tokenFileName = "outlook_token.json"
+	scopes        = "offline_access Calendars.ReadWrite"
+)
+
+// token is the OAuth token cached in the config directory
+type token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+// Auth obtains and refreshes Microsoft identity platform tokens
+type Auth struct {
+	cfg  config.OutlookConfig
+	path string
+	http *http.Client
+}
+
+// NewAuth creates a new Auth storing its token in dir
+func NewAuth(cfg config.OutlookConfig, dir string) *Auth {
+	return &Auth{
+		cfg:  cfg,
+		path: filepath.Join(dir, tokenFileName),
+		http: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (a *Auth) endpoint(name string) string {
+	tenant := a.cfg.Tenant
+	if tenant == "" {
+		tenant = "common"
+	}
+	return fmt.Sprintf("https://login.microsoftonline.com/%s/oauth2/v2.0/%s", url.PathEscape(tenant), name)
+}
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	Error        string `json:"error"`
+	Description  string `json:"error_description"`
+}
+
+func (a *Auth) post(ctx context.Context, endpoint string, form url.Values, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := a.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to contact Microsoft login: %w", err)
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode login response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	return nil
+}
+
+// Login runs the OAuth device code flow. show receives the message telling
+// the user which code to enter where.
+func (a *Auth) Login(ctx context.Context, show func(message string)) error {
+	if a.cfg.ClientID == "" {
+		return fmt.Errorf("outlook.client_id is not configured")
+	}
+
+	var code struct {
+		DeviceCode string `json:"device_code"`
+		Message    string `json:"message"`
+		Interval   int    `json:"interval"`
+		ExpiresIn  int    `json:"expires_in"`
+		Error      string `json:"error"`
+		Desc       string `json:"error_description"`
+	}
+	err := a.post(ctx, a.endpoint("devicecode"), url.Values{
+		"client_id": {a.cfg.ClientID},
+		"scope":     {scopes},
+	}, &code)
+	if err != nil {
+		return err
+	}
+	if code.Error != "" {
+		return fmt.Errorf("device code request failed: %s", code.Desc)
+	}
+	show(code.Message)
+
+	interval := time.Duration(code.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	deadline := time.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+
+		var tr tokenResponse
+		err := a.post(ctx, a.endpoint("token"), url.Values{
+			"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+			"client_id":   {a.cfg.ClientID},
+			"device_code": {code.DeviceCode},
+		}, &tr)
+		if err != nil {
+			return err
+		}
+		switch tr.Error {
+		case "":
+			return a.save(tr)
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		default:
+			return fmt.Errorf("sign-in failed: %s", tr.Description)
+		}
+	}
+	return fmt.Errorf("sign-in timed out")
+}
+
+// Logout removes the stored token
+func (a *Auth) Logout() error {
+	if err := os.Remove(a.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove token: %w", err)
+	}
+	return nil
+}
+
+// AccessToken returns a valid access token, refreshing it when needed
+func (a *Auth) AccessToken(ctx context.Context) (string, error) {
+	data, err := os.ReadFile(a.path)
+	if os.IsNotExist(err) {
+		return "", ErrNotLoggedIn
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read token: %w", err)
+	}
+	var t token
+	if err := json.Unmarshal(data, &t); err != nil {
+		return "", fmt.Errorf("failed to parse token: %w", err)
+	}
+	if time.Now().Add(time.Minute).Before(t.Expiry) {
+		return t.AccessToken, nil
+	}
+
+	var tr tokenResponse
+	err = a.post(ctx, a.endpoint("token"), url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {a.cfg.ClientID},
+		"refresh_token": {t.RefreshToken},
+		"scope":         {scopes},
+	}, &tr)
+	if err != nil {
+		return "", err
+	}
+	if tr.Error != "" {
+		return "", fmt.Errorf("failed to refresh token (%s), sign in again: %w", tr.Description, ErrNotLoggedIn)
+	}
+	if tr.RefreshToken == "" {
+		tr.RefreshToken = t.RefreshToken
+	}
+	if err := a.save(tr); err != nil {
+		return "", err
+	}
+	return tr.AccessToken, nil
+}
+
+func (a *Auth) save(tr tokenResponse) error {
+	t := token{
+		AccessToken:  tr.AccessToken,
+		RefreshToken: tr.RefreshToken,
+		Expiry:       time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second),
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(a.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package outlook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const graphURL = "https://graph.microsoft.com/v1.0"
+
+// graphTime is the dateTime layout Graph uses inside dateTimeTimeZone
+const graphTime = "2006-01-02T15:04:05.9999999"
+
+// Event is a calendar event
+type Event struct {
+	ID        string
+	Subject   string
+	Start     time.Time
+	End       time.Time
+	ShowAs    string // free, tentative, busy, oof, workingElsewhere
+	Cancelled bool
+}
+
+type dateTimeTimeZone struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+func toGraph(t time.Time) dateTimeTimeZone {
+	return dateTimeTimeZone{DateTime: t.UTC().Format(graphTime), TimeZone: "UTC"}
+}
+
+type graphEvent struct {
+	ID          string           `json:"id,omitempty"`
+	Subject     string           `json:"subject"`
+	Start       dateTimeTimeZone `json:"start"`
+	End         dateTimeTimeZone `json:"end"`
+	ShowAs      string           `json:"showAs,omitempty"`
+	IsCancelled bool             `json:"isCancelled,omitempty"`
+	Body        *struct {
+		ContentType string `json:"contentType"`
+		Content     string `json:"content"`
+	} `json:"body,omitempty"`
+}
+
+// Client calls the Graph calendar API
+type Client struct {
+	auth *Auth
+	http *http.Client
+}
+
+// NewClient creates a new Graph client
+func NewClient(auth *Auth) *Client {
+	return &Client{auth: auth, http: &http.Client{Timeout: 30 * time.Second}}
+}
+
+func (c *Client) do(ctx context.Context, method, u string, body, v interface{}) error {
+	tok, err := c.auth.AccessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+tok)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Prefer", `outlook.timezone="UTC"`)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to contact Microsoft Graph: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("Microsoft Graph returned %s: %s", resp.Status, msg)
+	}
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode Graph response: %w", err)
+	}
+	return nil
+}
+
+// Events returns the events of the default calendar between start and end
+func (c *Client) Events(ctx context.Context, start, end time.Time) ([]Event, error) {
+	q := url.Values{
+		"startDateTime": {start.UTC().Format(time.RFC3339)},
+		"endDateTime":   {end.UTC().Format(time.RFC3339)},
+		"$select":       {"id,subject,start,end,showAs,isCancelled"},
+		"$top":          {"100"},
+	}
+	next := graphURL + "/me/calendarView?" + q.Encode()
+
+	var events []Event
+	for next != "" {
+		var page struct {
+			Value    []graphEvent `json:"value"`
+			NextLink string       `json:"@odata.nextLink"`
+		}
+		if err := c.do(ctx, http.MethodGet, next, nil, &page); err != nil {
+			return nil, err
+		}
+		for _, ge := range page.Value {
+			s, err1 := time.Parse(graphTime, ge.Start.DateTime)
+			e, err2 := time.Parse(graphTime, ge.End.DateTime)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			events = append(events, Event{
+				ID:        ge.ID,
+				Subject:   ge.Subject,
+				Start:     s.Local(),
+				End:       e.Local(),
+				ShowAs:    ge.ShowAs,
+				Cancelled: ge.IsCancelled,
+			})
+		}
+		next = page.NextLink
+	}
+	return events, nil
+}
+
+func newGraphEvent(subject, description string, start, end time.Time) graphEvent {
+	ge := graphEvent{Subject: subject, Start: toGraph(start), End: toGraph(end)}
+	if description != "" {
+		ge.Body = &struct {
+			ContentType string `json:"contentType"`
+			Content     string `json:"content"`
+		}{"text", description}
+	}
+	return ge
+}
+
+// CreateEvent adds an event to the default calendar and returns its ID
+func (c *Client) CreateEvent(ctx context.Context, subject, description string, start, end time.Time) (string, error) {
+	var created graphEvent
+	if err := c.do(ctx, http.MethodPost, graphURL+"/me/events", newGraphEvent(subject, description, start, end), &created); err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// UpdateEvent changes the subject and times of an event
+func (c *Client) UpdateEvent(ctx context.Context, id, subject, description string, start, end time.Time) error {
+	return c.do(ctx, http.MethodPatch, graphURL+"/me/events/"+url.PathEscape(id), newGraphEvent(subject, description, start, end), nil)
+}
@@ -0,0 +1,189 @@
+package outlook
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"gomentum/internal/config"
+	"gomentum/internal/planner"
+)
+
+// Link sources used in the planner's external_links table
+const (
+	SourceMirror = "outlook"      // Outlook event -> busy block task
+	SourcePushed = "outlook-push" // Gomentum task -> Outlook event
+)
+
+const busyPrefix = "[Outlook] "
+
+// SyncResult counts what a sync changed
+type SyncResult struct {
+	Added, Updated, Removed, Pushed int
+}
+
+// Sync mirrors upcoming Outlook events as busy blocks and, if enabled,
+// pushes Gomentum tasks in the same window to Outlook.
+func Sync(ctx context.Context, c *Client, p *planner.Planner, cfg config.OutlookConfig) (SyncResult, error) {
+	var res SyncResult
+	days := cfg.Days
+	if days <= 0 {
+		days = 14
+	}
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	to := from.AddDate(0, 0, days)
+
+	events, err := c.Events(ctx, from, to)
+	if err != nil {
+		return res, err
+	}
+	mirrored, err := p.Links(SourceMirror)
+	if err != nil {
+		return res, err
+	}
+	pushed, err := p.Links(SourcePushed)
+	if err != nil {
+		return res, err
+	}
+
+	seen := make(map[string]bool)
+	for _, ev := range events {
+		if _, ours := pushed[ev.ID]; ours || ev.Cancelled || ev.ShowAs == "free" {
+			continue
+		}
+		seen[ev.ID] = true
+		title := busyPrefix + ev.Subject
+
+		if id, ok := mirrored[ev.ID]; ok {
+			t, err := p.GetTask(id)
+			if err == nil {
+				if t.Title == title && t.StartTime.Equal(ev.Start) && t.EndTime.Equal(ev.End) {
+					continue
+				}
+				t.Title, t.StartTime, t.EndTime = title, ev.Start, ev.End
+				if err := p.UpdateTask(t); err != nil {
+					return res, err
+				}
+				// Outlook sends its own reminders for these
+				if err := p.MarkAsReminded(t.ID); err != nil {
+					return res, err
+				}
+				res.Updated++
+				continue
+			}
+			// The busy block was deleted locally; recreate it
+		}
+
+		t, err := p.AddTask(title, "Busy block mirrored from Outlook", ev.Start, ev.End)
+		if err != nil {
+			return res, err
+		}
+		// Outlook owns the time of a busy block
+		t.Flexible = false
+		if err := p.UpdateTask(t); err != nil {
+			return res, err
+		}
+		if err := p.MarkAsReminded(t.ID); err != nil {
+			return res, err
+		}
+		if err := p.Link(SourceMirror, ev.ID, t.ID); err != nil {
+			return res, err
+		}
+		res.Added++
+	}
+
+	// Events that disappeared from the window were deleted or moved away
+	for extID, taskID := range mirrored {
+		if seen[extID] {
+			continue
+		}
+		t, err := p.GetTask(taskID)
+		if err == nil && (t.StartTime.Before(from) || !t.StartTime.Before(to)) {
+			continue
+		}
+		if err == nil {
+			if err := p.DeleteTask(taskID); err != nil {
+				return res, err
+			}
+			res.Removed++
+		}
+		if err := p.Unlink(SourceMirror, extID); err != nil {
+			return res, err
+		}
+	}
+
+	if cfg.PushTasks {
+		n, err := push(ctx, c, p, mirrored, pushed, from, to)
+		res.Pushed = n
+		if err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// push creates Outlook events for pending tasks in the window and keeps
+// previously pushed events in step with their tasks.
+func push(ctx context.Context, c *Client, p *planner.Planner, mirrored, pushed map[string]int, from, to time.Time) (int, error) {
+	tasks, err := p.ListTasks()
+	if err != nil {
+		return 0, err
+	}
+
+	isMirror := make(map[int]bool, len(mirrored))
+	for _, id := range mirrored {
+		isMirror[id] = true
+	}
+	eventFor := make(map[int]string, len(pushed))
+	for extID, id := range pushed {
+		eventFor[id] = extID
+	}
+
+	n := 0
+	for _, t := range tasks {
+		if isMirror[t.ID] || !t.Status.Active() || t.StartTime.Before(from) || !t.StartTime.Before(to) {
+			continue
+		}
+		if extID, ok := eventFor[t.ID]; ok {
+			if err := c.UpdateEvent(ctx, extID, t.Title, t.Description, t.StartTime, t.EndTime); err != nil {
+				slog.Warn("Failed to update Outlook event", "task", t.ID, "error", err)
+			}
+			continue
+		}
+		extID, err := c.CreateEvent(ctx, t.Title, t.Description, t.StartTime, t.EndTime)
+		if err != nil {
+			return n, err
+		}
+		if err := p.Link(SourcePushed, extID, t.ID); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// Run syncs periodically until ctx is cancelled
+func Run(ctx context.Context, cfg config.OutlookConfig, dir string, p *planner.Planner) {
+	interval := cfg.SyncInterval
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+	c := NewClient(NewAuth(cfg, dir))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		res, err := Sync(ctx, c, p, cfg)
+		if err != nil {
+			slog.Error("Outlook sync failed", "error", err)
+		} else {
+			slog.Info("Outlook sync finished", "added", res.Added, "updated", res.Updated, "removed", res.Removed, "pushed", res.Pushed)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package planner
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Activity is a span of recorded computer use, imported from a time
+// tracker such as ActivityWatch or RescueTime. Stats compare it with the
+// plan; it never changes tasks.
+type Activity struct {
+	Source   string    `json:"source"` // Importer, e.g. "activitywatch"
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	App      string    `json:"app"`
+	Title    string    `json:"title,omitempty"`    // Window title or site
+	Category string    `json:"category,omitempty"` // As the tracker classified it
+	// Productivity is the tracker's own rating from -2 (very distracting)
+	// to 2 (very productive); 0 if it has none
+	Productivity int `json:"productivity,omitempty"`
+}
+
+func createActivityTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS activity (
+		source TEXT NOT NULL,
+		start_time DATETIME NOT NULL,
+		end_time DATETIME NOT NULL,
+		app TEXT NOT NULL,
+		title TEXT NOT NULL DEFAULT '',
+		category TEXT NOT NULL DEFAULT '',
+		productivity INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (source, start_time, app, title)
+	);
+	`
+	if _, err := db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create activity table: %w", err)
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_activity_span ON activity(start_time, end_time)`); err != nil {
+		return fmt.Errorf("failed to create index: %w", err)
+	}
+	return nil
+}
+
+// AddActivity stores recorded activity in one transaction. Spans already
+// imported from the same source are skipped, so an export can be imported
+// again after it grew. It returns how many spans were new.
+func (p *Planner) AddActivity(spans []Activity) (int, error) {
+	n := 0
+	err := p.inTx(func(tx *sql.Tx) error {
+		stmt, err := tx.Prepare(`INSERT OR IGNORE INTO activity (source, start_time, end_time, app, title, category, productivity)
+		                         VALUES (?, ?, ?, ?, ?, ?, ?)`)
+		if err != nil {
+			return fmt.Errorf("failed to prepare insert: %w", err)
+		}
+		defer stmt.Close()
+
+		for _, a := range spans {
+			res, err := stmt.Exec(a.Source, a.Start, a.End, a.App, a.Title, a.Category, a.Productivity)
+			if err != nil {
+				return fmt.Errorf("failed to insert activity: %w", err)
+			}
+			if added, _ := res.RowsAffected(); added > 0 {
+				n++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// ActivityBetween returns the recorded activity overlapping [from, to),
+// oldest first
+func (p *Planner) ActivityBetween(from, to time.Time) ([]Activity, error) {
+	rows, err := p.db.Query(`SELECT source, start_time, end_time, app, title, category, productivity FROM activity
+	                         WHERE start_time < ? AND end_time > ? ORDER BY start_time ASC`, to, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query activity: %w", err)
+	}
+	defer rows.Close()
+
+	var spans []Activity
+	for rows.Next() {
+		var a Activity
+		if err := rows.Scan(&a.Source, &a.Start, &a.End, &a.App, &a.Title, &a.Category, &a.Productivity); err != nil {
+			return nil, fmt.Errorf("failed to scan activity: %w", err)
+		}
+		spans = append(spans, a)
+	}
+	return spans, rows.Err()
+}
@@ -0,0 +1,354 @@
+package planner
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Bulk operations change many tasks in one transaction: either all of
+// them change or none do.
+
+// AddTasks inserts many tasks in one transaction and returns their IDs.
+// Unlike AddTask it keeps the given status, reminder state, priority,
+// deadline, fields, color, flexibility and tags.
+func (p *Planner) AddTasks(tasks []Task) ([]int, error) {
+	var ids []int
+	err := p.inTx(func(tx *sql.Tx) error {
+		var err error
+		ids, err = insertTasks(tx, tasks)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// insertTasks inserts tasks in tx the way AddTasks does
+func insertTasks(tx *sql.Tx, tasks []Task) ([]int, error) {
+	stmt, err := tx.Prepare(`INSERT INTO tasks (title, description, start_time, end_time, status, reminded, deadline, priority, flexible, updated_at, fields, color, recurrence)
+	                         VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	ids := make([]int, 0, len(tasks))
+	now := time.Now()
+	for _, t := range tasks {
+		if t.Status == "" {
+			t.Status = StatusPending
+		}
+		fields, err := encodeFields(t.Fields)
+		if err != nil {
+			return nil, err
+		}
+		res, err := stmt.Exec(t.Title, t.Description, t.StartTime, t.EndTime, t.Status, t.Reminded, nullTime(t.Deadline), t.Priority, t.Flexible, now, fields, t.Color, t.Recurrence)
+		if err != nil {
+			return nil, fmt.Errorf("failed to insert task: %w", err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		if len(t.Tags) > 0 {
+			tags, err := normalizeTags(t.Tags)
+			if err != nil {
+				return nil, err
+			}
+			if err := insertTags(tx, int(id), tags); err != nil {
+				return nil, err
+			}
+		}
+		ids = append(ids, int(id))
+	}
+	return ids, nil
+}
+
+// CompleteRange marks every unfinished task starting in [from, to) as
+// completed and returns how many changed. If any of them still waits on
+// an open task outside the range, nothing changes and ErrConflict is
+// returned.
+func (p *Planner) CompleteRange(from, to time.Time) (int, error) {
+	done := doneStatuses()
+	var n int
+	err := p.inTx(func(tx *sql.Tx) error {
+		args := []any{from, to}
+		for _, s := range done {
+			args = append(args, s)
+		}
+		rows, err := tx.Query(`SELECT id FROM tasks WHERE start_time >= ? AND start_time < ? AND status NOT IN (`+placeholders(len(done))+`)`, args...)
+		if err != nil {
+			return fmt.Errorf("failed to list tasks: %w", err)
+		}
+		var ids []int
+		for rows.Next() {
+			var id int
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan task: %w", err)
+			}
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to list tasks: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		if err := checkBlockers(tx, ids); err != nil {
+			return err
+		}
+
+		args = []any{StatusCompleted, time.Now()}
+		for _, id := range ids {
+			args = append(args, id)
+		}
+		res, err := tx.Exec(`UPDATE tasks SET status = ?, updated_at = ?, reminded = 0 WHERE id IN (`+placeholders(len(ids))+`)`, args...)
+		if err != nil {
+			return fmt.Errorf("failed to complete tasks: %w", err)
+		}
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		n = int(rowsAffected)
+		return nil
+	})
+	return n, err
+}
+
+// PurgeCompleted deletes finished tasks that ended before cutoff, along
+// with their external links, and returns how many were deleted
+func (p *Planner) PurgeCompleted(cutoff time.Time) (int, error) {
+	done := doneStatuses()
+	where := `status IN (` + placeholders(len(done)) + `) AND end_time < ?`
+	var args []any
+	for _, s := range done {
+		args = append(args, s)
+	}
+	args = append(args, cutoff)
+
+	var n int64
+	err := p.inTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM external_links WHERE task_id IN (SELECT id FROM tasks WHERE `+where+`)`, args...); err != nil {
+			return fmt.Errorf("failed to delete links: %w", err)
+		}
+		res, err := tx.Exec(`DELETE FROM tasks WHERE `+where, args...)
+		if err != nil {
+			return fmt.Errorf("failed to delete tasks: %w", err)
+		}
+		n, err = res.RowsAffected()
+		return err
+	})
+	return int(n), err
+}
+
+// ShiftTasks moves the given tasks by delta, keeping their durations
+func (p *Planner) ShiftTasks(ids []int, delta time.Duration) error {
+	return p.inTx(func(tx *sql.Tx) error {
+		now := time.Now()
+		for _, id := range ids {
+			var start, end time.Time
+			if err := tx.QueryRow(`SELECT start_time, end_time FROM tasks WHERE id = ?`, id).Scan(&start, &end); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return fmt.Errorf("task with ID %d %w", id, ErrNotFound)
+				}
+				return fmt.Errorf("failed to read task %d: %w", id, err)
+			}
+			if _, err := tx.Exec(`UPDATE tasks SET start_time = ?, end_time = ?, updated_at = ?, reminded = 0 WHERE id = ?`,
+				start.Add(delta), end.Add(delta), now, id); err != nil {
+				return fmt.Errorf("failed to move task %d: %w", id, err)
+			}
+		}
+		return nil
+	})
+}
+
+// SetTasksStatus gives the given tasks the same status, such as
+// completing a selection at once. Finishing a task whose open blockers
+// are not in the selection is a conflict.
+func (p *Planner) SetTasksStatus(ids []int, status Status) error {
+	if !status.Valid() {
+		return fmt.Errorf("%w: unknown status %q", ErrValidation, status)
+	}
+	return p.inTx(func(tx *sql.Tx) error {
+		if status.Done() {
+			if err := checkBlockers(tx, ids); err != nil {
+				return err
+			}
+		}
+		now := time.Now()
+		for _, id := range ids {
+			if err := execTask(tx, id, `UPDATE tasks SET status = ?, updated_at = ?, reminded = 0 WHERE id = ?`, status, now, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetTasksField sets a custom field, such as the project, on the given
+// tasks; a nil value removes it
+func (p *Planner) SetTasksField(ids []int, key string, value any) error {
+	return p.inTx(func(tx *sql.Tx) error {
+		now := time.Now()
+		for _, id := range ids {
+			var raw string
+			if err := tx.QueryRow(`SELECT fields FROM tasks WHERE id = ?`, id).Scan(&raw); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return fmt.Errorf("task with ID %d %w", id, ErrNotFound)
+				}
+				return fmt.Errorf("failed to read task %d: %w", id, err)
+			}
+			var t Task
+			var err error
+			if t.Fields, err = decodeFields(raw); err != nil {
+				return err
+			}
+			t.SetField(key, value)
+			fields, err := encodeFields(t.Fields)
+			if err != nil {
+				return err
+			}
+			if err := execTask(tx, id, `UPDATE tasks SET fields = ?, updated_at = ? WHERE id = ?`, fields, now, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteTasks deletes the given tasks along with their external links
+func (p *Planner) DeleteTasks(ids []int) error {
+	return p.inTx(func(tx *sql.Tx) error {
+		for _, id := range ids {
+			if _, err := tx.Exec(`DELETE FROM external_links WHERE task_id = ?`, id); err != nil {
+				return fmt.Errorf("failed to delete links: %w", err)
+			}
+			if err := execTask(tx, id, `DELETE FROM tasks WHERE id = ?`, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// execTask runs a statement changing the task id in tx, failing with
+// ErrNotFound if no such task exists
+func execTask(tx *sql.Tx, id int, query string, args ...any) error {
+	res, err := tx.Exec(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to change task %d: %w", id, err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("task with ID %d %w", id, ErrNotFound)
+	}
+	return nil
+}
+
+// SwapTasks exchanges the start times of two tasks. Each task keeps its
+// duration, so the new slots are checked against the rest of the plan;
+// fixed tasks are not moved and an overlap is a conflict.
+func (p *Planner) SwapTasks(idA, idB int) error {
+	if idA == idB {
+		return fmt.Errorf("cannot swap task %d with itself: %w", idA, ErrValidation)
+	}
+	return p.inTx(func(tx *sql.Tx) error {
+		ids := [2]int{idA, idB}
+		var starts, ends [2]time.Time
+		for i, id := range ids {
+			var flexible bool
+			if err := tx.QueryRow(`SELECT start_time, end_time, flexible FROM tasks WHERE id = ?`, id).Scan(&starts[i], &ends[i], &flexible); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return fmt.Errorf("task with ID %d %w", id, ErrNotFound)
+				}
+				return fmt.Errorf("failed to read task %d: %w", id, err)
+			}
+			if !flexible {
+				return fmt.Errorf("task %d is fixed and cannot be moved: %w", id, ErrConflict)
+			}
+		}
+		newStarts := [2]time.Time{starts[1], starts[0]}
+		var newEnds [2]time.Time
+		for i, start := range newStarts {
+			newEnds[i] = start.Add(ends[i].Sub(starts[i]))
+		}
+		if newStarts[0].Before(newEnds[1]) && newStarts[1].Before(newEnds[0]) {
+			return fmt.Errorf("tasks %d and %d would overlap after the swap: %w", idA, idB, ErrConflict)
+		}
+
+		now := time.Now()
+		for i, start := range newStarts {
+			end := newEnds[i]
+			var other int
+			var title string
+			err := tx.QueryRow(`SELECT id, title FROM tasks
+				WHERE id NOT IN (?, ?) AND start_time < ? AND end_time > ?
+				AND (fields = '' OR json_extract(fields, '$.`+FieldSystem+`') IS NOT '`+SystemMicroBreak+`')
+				LIMIT 1`, idA, idB, end, start).Scan(&other, &title)
+			if err == nil {
+				return fmt.Errorf("task %d would overlap task %d '%s': %w", ids[i], other, title, ErrConflict)
+			}
+			if !errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("failed to check overlap: %w", err)
+			}
+			if _, err := tx.Exec(`UPDATE tasks SET start_time = ?, end_time = ?, updated_at = ?, reminded = 0 WHERE id = ?`,
+				start, end, now, ids[i]); err != nil {
+				return fmt.Errorf("failed to move task %d: %w", ids[i], err)
+			}
+		}
+		return nil
+	})
+}
+
+// ShiftRemainingToday moves the day's unfinished flexible tasks that have
+// not ended by now, including a running one. Fixed tasks stay put; the
+// counts of moved and skipped fixed tasks are returned.
+func (p *Planner) ShiftRemainingToday(now time.Time, delta time.Duration) (int, int, error) {
+	tasks, err := p.ListTasks()
+	if err != nil {
+		return 0, 0, err
+	}
+	endOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+
+	var ids []int
+	fixed := 0
+	for _, t := range tasks {
+		if !t.Status.Active() || !t.EndTime.After(now) || !t.StartTime.Before(endOfDay) {
+			continue
+		}
+		if !t.Flexible {
+			fixed++
+			continue
+		}
+		ids = append(ids, t.ID)
+	}
+	if err := p.ShiftTasks(ids, delta); err != nil {
+		return 0, 0, err
+	}
+	return len(ids), fixed, nil
+}
+
+// inTx runs fn in a transaction, committing only if it succeeds
+func (p *Planner) inTx(fn func(tx *sql.Tx) error) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
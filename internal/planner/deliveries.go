@@ -0,0 +1,147 @@
+package planner
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Deliveries record each channel (desktop, email, ...) a reminder went out
+// on. They are keyed by the task and its start time, so a restart does
+// not repeat a reminder that was already sent, while a rescheduled task
+// is reminded again.
+
+// Delivery is the state of one reminder on one channel
+type Delivery struct {
+	TaskID      int       `json:"task_id"`
+	Start       time.Time `json:"start"`
+	Channel     string    `json:"channel"`
+	Attempts    int       `json:"attempts"`
+	AttemptedAt time.Time `json:"attempted_at"`          // Last attempt
+	DeliveredAt time.Time `json:"delivered_at,omitzero"` // Zero until an attempt succeeds
+	LastError   string    `json:"last_error,omitempty"`
+	NextAttempt time.Time `json:"next_attempt,omitzero"` // Earliest retry after a failure
+}
+
+func createDeliveriesTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS reminder_deliveries (
+		task_id INTEGER NOT NULL,
+		start_time DATETIME NOT NULL,
+		channel TEXT NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		attempted_at DATETIME,
+		delivered_at DATETIME,
+		last_error TEXT NOT NULL DEFAULT '',
+		next_attempt DATETIME,
+		PRIMARY KEY (task_id, start_time, channel)
+	);
+	`
+	if _, err := db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create reminder_deliveries table: %w", err)
+	}
+	_, _ = db.Exec(`ALTER TABLE reminder_deliveries ADD COLUMN attempted_at DATETIME`)
+	return nil
+}
+
+// Deliveries returns the channel -> delivery state of the reminder for a
+// task starting at start
+func (p *Planner) Deliveries(taskID int, start time.Time) (map[string]Delivery, error) {
+	rows, err := p.db.Query(`SELECT channel, attempts, attempted_at, delivered_at, last_error, next_attempt FROM reminder_deliveries
+	                         WHERE task_id = ? AND start_time = ?`, taskID, start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make(map[string]Delivery)
+	for rows.Next() {
+		d := Delivery{TaskID: taskID, Start: start}
+		var attempted, delivered, next sql.NullTime
+		if err := rows.Scan(&d.Channel, &d.Attempts, &attempted, &delivered, &d.LastError, &next); err != nil {
+			return nil, fmt.Errorf("failed to scan delivery: %w", err)
+		}
+		d.AttemptedAt = attempted.Time
+		d.DeliveredAt = delivered.Time
+		d.NextAttempt = next.Time
+		deliveries[d.Channel] = d
+	}
+	return deliveries, nil
+}
+
+// RecordDelivery saves the state of a reminder on one channel
+func (p *Planner) RecordDelivery(d Delivery) error {
+	query := `INSERT OR REPLACE INTO reminder_deliveries (task_id, start_time, channel, attempts, attempted_at, delivered_at, last_error, next_attempt)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	if _, err := p.db.Exec(query, d.TaskID, d.Start, d.Channel, d.Attempts, nullTime(d.AttemptedAt), nullTime(d.DeliveredAt), d.LastError, nullTime(d.NextAttempt)); err != nil {
+		return fmt.Errorf("failed to save delivery: %w", err)
+	}
+	return nil
+}
+
+// PurgeDeliveries forgets reminders for tasks that started before t
+func (p *Planner) PurgeDeliveries(before time.Time) error {
+	if _, err := p.db.Exec(`DELETE FROM reminder_deliveries WHERE start_time < ?`, before); err != nil {
+		return fmt.Errorf("failed to purge deliveries: %w", err)
+	}
+	return nil
+}
+
+// Reminder is one fired reminder with its delivery on each channel
+type Reminder struct {
+	TaskID     int        `json:"task_id"`
+	Title      string     `json:"title"` // Empty if the task was deleted since
+	Start      time.Time  `json:"start"`
+	Deliveries []Delivery `json:"deliveries"`
+}
+
+// Delivered reports whether the reminder reached at least one channel
+func (r Reminder) Delivered() bool {
+	for _, d := range r.Deliveries {
+		if !d.DeliveredAt.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// ReminderHistory returns up to limit reminders, latest first. A taskID
+// other than 0 limits it to that task.
+func (p *Planner) ReminderHistory(taskID, limit int) ([]Reminder, error) {
+	query := `SELECT d.task_id, COALESCE(t.title, ''), d.start_time, d.channel, d.attempts, d.attempted_at, d.delivered_at, d.last_error, d.next_attempt
+	          FROM reminder_deliveries d LEFT JOIN tasks t ON t.id = d.task_id`
+	var args []any
+	if taskID != 0 {
+		query += ` WHERE d.task_id = ?`
+		args = append(args, taskID)
+	}
+	query += ` ORDER BY d.start_time DESC, d.task_id, d.channel`
+	rows, err := p.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reminder history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []Reminder
+	for rows.Next() {
+		var d Delivery
+		var title string
+		var attempted, delivered, next sql.NullTime
+		if err := rows.Scan(&d.TaskID, &title, &d.Start, &d.Channel, &d.Attempts, &attempted, &delivered, &d.LastError, &next); err != nil {
+			return nil, fmt.Errorf("failed to scan delivery: %w", err)
+		}
+		d.AttemptedAt = attempted.Time
+		d.DeliveredAt = delivered.Time
+		d.NextAttempt = next.Time
+
+		if n := len(history); n == 0 || history[n-1].TaskID != d.TaskID || !history[n-1].Start.Equal(d.Start) {
+			if limit > 0 && n == limit {
+				break
+			}
+			history = append(history, Reminder{TaskID: d.TaskID, Title: title, Start: d.Start})
+		}
+		last := &history[len(history)-1]
+		last.Deliveries = append(last.Deliveries, d)
+	}
+	return history, nil
+}
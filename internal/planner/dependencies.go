@@ -0,0 +1,185 @@
+package planner
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A dependency says one task blocks another: the blocked task cannot be
+// finished while the blocker is still open.
+
+func createDependenciesTable(db *sql.DB) error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS task_dependencies (
+			blocker_id INTEGER NOT NULL,
+			blocked_id INTEGER NOT NULL,
+			PRIMARY KEY (blocker_id, blocked_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_dependencies_blocked ON task_dependencies(blocked_id)`,
+		`CREATE TRIGGER IF NOT EXISTS task_dependencies_cleanup AFTER DELETE ON tasks BEGIN
+			DELETE FROM task_dependencies WHERE blocker_id = OLD.id OR blocked_id = OLD.id;
+		END`,
+	}
+	for _, q := range queries {
+		if _, err := db.Exec(q); err != nil {
+			return fmt.Errorf("failed to create task_dependencies table: %w", err)
+		}
+	}
+	return nil
+}
+
+// blockedByColumn reads the blockers of a task as one comma-separated column
+const blockedByColumn = `(SELECT group_concat(blocker_id) FROM task_dependencies WHERE blocked_id = tasks.id)`
+
+// splitIDs reads blockedByColumn in order
+func splitIDs(s sql.NullString) []int {
+	if !s.Valid || s.String == "" {
+		return nil
+	}
+	var ids []int
+	for _, f := range strings.Split(s.String, ",") {
+		if id, err := strconv.Atoi(f); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// AddDependency records that blockerID blocks blockedID. A dependency that
+// would make a task wait on itself, directly or through others, is a
+// conflict.
+func (p *Planner) AddDependency(blockerID, blockedID int) error {
+	if blockerID == blockedID {
+		return fmt.Errorf("%w: task %d cannot block itself", ErrValidation, blockerID)
+	}
+	return p.inTx(func(tx *sql.Tx) error {
+		for _, id := range []int{blockerID, blockedID} {
+			if err := taskExists(tx, id); err != nil {
+				return err
+			}
+		}
+		// Walk what blockerID waits on; finding blockedID closes a loop
+		var cycle bool
+		err := tx.QueryRow(`
+			WITH RECURSIVE waits(id) AS (
+				SELECT blocker_id FROM task_dependencies WHERE blocked_id = ?
+				UNION
+				SELECT d.blocker_id FROM task_dependencies d JOIN waits ON d.blocked_id = waits.id
+			)
+			SELECT EXISTS (SELECT 1 FROM waits WHERE id = ?)`, blockerID, blockedID).Scan(&cycle)
+		if err != nil {
+			return fmt.Errorf("failed to check dependencies: %w", err)
+		}
+		if cycle {
+			return fmt.Errorf("task %d already waits on task %d, directly or through other tasks: %w", blockerID, blockedID, ErrConflict)
+		}
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO task_dependencies (blocker_id, blocked_id) VALUES (?, ?)`, blockerID, blockedID); err != nil {
+			return fmt.Errorf("failed to save dependency: %w", err)
+		}
+		return nil
+	})
+}
+
+// RemoveDependency drops the dependency of blockedID on blockerID
+func (p *Planner) RemoveDependency(blockerID, blockedID int) error {
+	res, err := p.db.Exec(`DELETE FROM task_dependencies WHERE blocker_id = ? AND blocked_id = ?`, blockerID, blockedID)
+	if err != nil {
+		return fmt.Errorf("failed to remove dependency: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("dependency of task %d on task %d %w", blockedID, blockerID, ErrNotFound)
+	}
+	return nil
+}
+
+// OpenBlockers returns the unfinished tasks that block a task, ordered by
+// start time
+func (p *Planner) OpenBlockers(taskID int) ([]Task, error) {
+	done := doneStatuses()
+	query := `SELECT ` + taskColumns + ` FROM tasks
+	          WHERE id IN (SELECT blocker_id FROM task_dependencies WHERE blocked_id = ?)
+	          AND status NOT IN (` + placeholders(len(done)) + `) ORDER BY start_time`
+	args := []any{taskID}
+	for _, s := range done {
+		args = append(args, s)
+	}
+	rows, err := p.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blockers: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []Task
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+// checkBlockers fails with ErrConflict if a task in ids that is not yet
+// finished has an open blocker outside ids. Blockers finished in the same
+// change do not count.
+func checkBlockers(q querier, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	done := doneStatuses()
+	query := `SELECT d.blocked_id, d.blocker_id FROM task_dependencies d
+	          JOIN tasks b ON b.id = d.blocker_id
+	          JOIN tasks t ON t.id = d.blocked_id
+	          WHERE d.blocked_id IN (` + placeholders(len(ids)) + `)
+	          AND d.blocker_id NOT IN (` + placeholders(len(ids)) + `)
+	          AND b.status NOT IN (` + placeholders(len(done)) + `)
+	          AND t.status NOT IN (` + placeholders(len(done)) + `)
+	          LIMIT 1`
+	var args []any
+	for range 2 {
+		for _, id := range ids {
+			args = append(args, id)
+		}
+	}
+	for range 2 {
+		for _, s := range done {
+			args = append(args, s)
+		}
+	}
+	var blocked, blocker int
+	err := q.QueryRow(query, args...).Scan(&blocked, &blocker)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check blockers: %w", err)
+	}
+	return fmt.Errorf("task %d is blocked by open task %d: %w", blocked, blocker, ErrConflict)
+}
+
+// querier is implemented by *sql.DB and *sql.Tx
+type querier interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// taskExists fails with ErrNotFound if there is no task with id
+func taskExists(tx *sql.Tx, id int) error {
+	var n int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM tasks WHERE id = ?`, id).Scan(&n); err != nil {
+		return fmt.Errorf("failed to read task %d: %w", id, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("task with ID %d %w", id, ErrNotFound)
+	}
+	return nil
+}
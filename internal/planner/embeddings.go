@@ -0,0 +1,91 @@
+package planner
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Task embeddings are the vectors semantic search compares, kept so only
+// new and edited tasks need to be embedded again. Each row records the
+// model and a digest of the text it was computed from.
+
+func createEmbeddingsTable(db *sql.DB) error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS task_embeddings (
+			task_id INTEGER PRIMARY KEY,
+			model TEXT NOT NULL,
+			digest TEXT NOT NULL,
+			vector BLOB NOT NULL
+		)`,
+		`CREATE TRIGGER IF NOT EXISTS task_embeddings_cleanup AFTER DELETE ON tasks BEGIN
+			DELETE FROM task_embeddings WHERE task_id = OLD.id;
+		END`,
+	}
+	for _, q := range queries {
+		if _, err := db.Exec(q); err != nil {
+			return fmt.Errorf("failed to create task_embeddings table: %w", err)
+		}
+	}
+	return nil
+}
+
+// Embedding is the stored vector of a task
+type Embedding struct {
+	TaskID int
+	Digest string // Of the text the vector was computed from
+	Vector []float32
+}
+
+// Embeddings returns the stored vectors computed by model, by task ID
+func (p *Planner) Embeddings(model string) (map[int]Embedding, error) {
+	rows, err := p.db.Query(`SELECT task_id, digest, vector FROM task_embeddings WHERE model = ?`, model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[int]Embedding)
+	for rows.Next() {
+		var e Embedding
+		var blob []byte
+		if err := rows.Scan(&e.TaskID, &e.Digest, &blob); err != nil {
+			return nil, fmt.Errorf("failed to scan embedding: %w", err)
+		}
+		e.Vector = decodeVector(blob)
+		out[e.TaskID] = e
+	}
+	return out, nil
+}
+
+// SaveEmbeddings stores vectors computed by model, replacing those the
+// tasks had
+func (p *Planner) SaveEmbeddings(model string, embeddings []Embedding) error {
+	return p.inTx(func(tx *sql.Tx) error {
+		for _, e := range embeddings {
+			if _, err := tx.Exec(`INSERT OR REPLACE INTO task_embeddings (task_id, model, digest, vector) VALUES (?, ?, ?, ?)`,
+				e.TaskID, model, e.Digest, encodeVector(e.Vector)); err != nil {
+				return fmt.Errorf("failed to save embedding: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// encodeVector stores a vector as little-endian float32s
+func encodeVector(v []float32) []byte {
+	b := make([]byte, 4*len(v))
+	for i, x := range v {
+		binary.LittleEndian.PutUint32(b[4*i:], math.Float32bits(x))
+	}
+	return b
+}
+
+func decodeVector(b []byte) []float32 {
+	v := make([]float32, len(b)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:]))
+	}
+	return v
+}
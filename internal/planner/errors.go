@@ -0,0 +1,33 @@
+package planner
+
+import "errors"
+
+// Error kinds. Errors returned by the Planner wrap one of them where it
+// applies, so callers can use errors.Is instead of matching messages.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrValidation = errors.New("invalid input")
+)
+
+// Error codes for clients such as MCP tool results and HTTP responses
+const (
+	CodeNotFound   = "not_found"
+	CodeConflict   = "conflict"
+	CodeValidation = "validation"
+	CodeInternal   = "internal"
+)
+
+// Code returns the error code of err's kind, or CodeInternal
+func Code(err error) string {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return CodeNotFound
+	case errors.Is(err, ErrConflict):
+		return CodeConflict
+	case errors.Is(err, ErrValidation):
+		return CodeValidation
+	default:
+		return CodeInternal
+	}
+}
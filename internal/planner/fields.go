@@ -0,0 +1,130 @@
+package planner
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Custom fields are free-form key/values users attach to tasks, such as a
+// client, billing code or ticket ID. They are stored as a JSON object.
+
+// FieldLocation holds where a task takes place, e.g. "office". Travel
+// time is planned between tasks at different locations.
+const FieldLocation = "location"
+
+// Location returns where the task takes place, or "" if it does not say
+func (t Task) Location() string {
+	loc, _ := t.FieldString(FieldLocation)
+	return loc
+}
+
+// FieldURL holds a link the task is about, e.g. a page captured from
+// the browser
+const FieldURL = "url"
+
+// FieldRemindBefore holds how many minutes before its start a task is
+// reminded of, e.g. from the defaults of its tag
+const FieldRemindBefore = "remind_before"
+
+// RemindAt returns when the task is due for its reminder
+func (t Task) RemindAt() time.Time {
+	if m, ok := t.FieldNumber(FieldRemindBefore); ok && m > 0 {
+		return t.StartTime.Add(-time.Duration(m * float64(time.Minute)))
+	}
+	return t.StartTime
+}
+
+// FieldString returns a field as text. Numbers and booleans are formatted.
+func (t Task) FieldString(key string) (string, bool) {
+	v, ok := t.Fields[key]
+	if !ok || v == nil {
+		return "", false
+	}
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		return string(data), true
+	}
+}
+
+// FieldNumber returns a numeric field; numeric strings are parsed
+func (t Task) FieldNumber(key string) (float64, bool) {
+	switch v := t.Fields[key].(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// FieldBool returns a boolean field; "true"/"false" strings are parsed
+func (t Task) FieldBool(key string) (bool, bool) {
+	switch v := t.Fields[key].(type) {
+	case bool:
+		return v, true
+	case string:
+		b, err := strconv.ParseBool(v)
+		return b, err == nil
+	}
+	return false, false
+}
+
+// SetField sets a field; a nil value removes it
+func (t *Task) SetField(key string, value any) {
+	if value == nil {
+		delete(t.Fields, key)
+		return
+	}
+	if t.Fields == nil {
+		t.Fields = make(map[string]any)
+	}
+	t.Fields[key] = value
+}
+
+// FieldKeys returns the field names in alphabetical order
+func (t Task) FieldKeys() []string {
+	keys := make([]string, 0, len(t.Fields))
+	for k := range t.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func encodeFields(fields map[string]any) (string, error) {
+	if len(fields) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode custom fields: %w", err)
+	}
+	return string(data), nil
+}
+
+func decodeFields(raw string) (map[string]any, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode custom fields: %w", err)
+	}
+	return fields, nil
+}
@@ -0,0 +1,66 @@
+package planner
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// External links tie tasks to items in other systems (e.g. Outlook events),
+// so repeated syncs update the same task instead of creating duplicates.
+
+func createLinksTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS external_links (
+		source TEXT NOT NULL,
+		external_id TEXT NOT NULL,
+		task_id INTEGER NOT NULL,
+		PRIMARY KEY (source, external_id)
+	);
+	`
+	if _, err := db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create external_links table: %w", err)
+	}
+	// Deleting tasks removes their links by task ID
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_links_task ON external_links(task_id)`); err != nil {
+		return fmt.Errorf("failed to create index: %w", err)
+	}
+	return nil
+}
+
+// Links returns external ID -> task ID for a source
+func (p *Planner) Links(source string) (map[string]int, error) {
+	rows, err := p.db.Query(`SELECT external_id, task_id FROM external_links WHERE source = ?`, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query links: %w", err)
+	}
+	defer rows.Close()
+
+	links := make(map[string]int)
+	for rows.Next() {
+		var extID string
+		var taskID int
+		if err := rows.Scan(&extID, &taskID); err != nil {
+			return nil, fmt.Errorf("failed to scan link: %w", err)
+		}
+		links[extID] = taskID
+	}
+	return links, nil
+}
+
+// Link records that taskID mirrors externalID in source
+func (p *Planner) Link(source, externalID string, taskID int) error {
+	query := `INSERT OR REPLACE INTO external_links (source, external_id, task_id) VALUES (?, ?, ?)`
+	if _, err := p.db.Exec(query, source, externalID, taskID); err != nil {
+		return fmt.Errorf("failed to save link: %w", err)
+	}
+	return nil
+}
+
+// Unlink forgets the link for externalID in source
+func (p *Planner) Unlink(source, externalID string) error {
+	query := `DELETE FROM external_links WHERE source = ? AND external_id = ?`
+	if _, err := p.db.Exec(query, source, externalID); err != nil {
+		return fmt.Errorf("failed to remove link: %w", err)
+	}
+	return nil
+}
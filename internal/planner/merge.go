@@ -0,0 +1,167 @@
+package planner
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+)
+
+// MergeTasks folds task dropID into keepID, typically a duplicate left by
+// an import, and deletes it, in one transaction. The kept task keeps its
+// title and gains the rest: see merged. Its tags, dependencies and
+// external links are moved over, so a later sync updates the kept task
+// instead of adding the duplicate again. Recurring tasks cannot be
+// merged.
+//
+// The title and times in o replace those of the merged task, and the
+// result must not overlap another task unless o.AllowOverlap is set.
+func (p *Planner) MergeTasks(keepID, dropID int, o MergeOptions) (Task, error) {
+	if keepID == dropID {
+		return Task{}, fmt.Errorf("cannot merge task %d with itself: %w", keepID, ErrValidation)
+	}
+	var result Task
+	err := p.inTx(func(tx *sql.Tx) error {
+		var tasks [2]Task
+		for i, id := range [2]int{keepID, dropID} {
+			t, err := scanTask(tx.QueryRow(`SELECT `+taskColumns+` FROM tasks WHERE id = ?`, id))
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("task with ID %d %w", id, ErrNotFound)
+			}
+			if err != nil {
+				return err
+			}
+			if t.Series() != 0 {
+				return fmt.Errorf("%w: task %d is recurring and cannot be merged", ErrValidation, id)
+			}
+			tasks[i] = t
+		}
+		keep, drop := tasks[0], tasks[1]
+		m := merged(keep, drop)
+		if o.Title != "" {
+			m.Title = o.Title
+		}
+		if !o.Start.IsZero() {
+			// Without a new end the task keeps its length
+			if o.End.IsZero() {
+				m.EndTime = o.Start.Add(m.EndTime.Sub(m.StartTime))
+			}
+			m.StartTime = o.Start
+		}
+		if !o.End.IsZero() {
+			m.EndTime = o.End
+		}
+		if !m.EndTime.After(m.StartTime) {
+			return fmt.Errorf("%w: the merged task must end after it starts", ErrValidation)
+		}
+
+		fields, err := encodeFields(m.Fields)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE tasks SET title = ?, description = ?, start_time = ?, end_time = ?, status = ?, deadline = ?, priority = ?, flexible = ?, updated_at = ?, fields = ?, color = ?, reminded = 0 WHERE id = ?`,
+			m.Title, m.Description, m.StartTime, m.EndTime, m.Status, nullTime(m.Deadline), m.Priority, m.Flexible, time.Now(), fields, m.Color, keepID); err != nil {
+			return fmt.Errorf("failed to update task: %w", err)
+		}
+		if err := insertTags(tx, keepID, drop.Tags); err != nil {
+			return err
+		}
+		// Links between the two tasks would make the kept one wait on itself
+		queries := []string{
+			`DELETE FROM task_dependencies WHERE (blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)`,
+			`INSERT OR IGNORE INTO task_dependencies (blocker_id, blocked_id) SELECT ?, blocked_id FROM task_dependencies WHERE blocker_id = ?`,
+			`INSERT OR IGNORE INTO task_dependencies (blocker_id, blocked_id) SELECT blocker_id, ? FROM task_dependencies WHERE blocked_id = ?`,
+			`UPDATE external_links SET task_id = ? WHERE task_id = ?`,
+		}
+		args := [][]any{{keepID, dropID, dropID, keepID}, {keepID, dropID}, {keepID, dropID}, {keepID, dropID}}
+		for i, q := range queries {
+			if _, err := tx.Exec(q, args[i]...); err != nil {
+				return fmt.Errorf("failed to move links: %w", err)
+			}
+		}
+		if _, err := tx.Exec(`DELETE FROM tasks WHERE id = ?`, dropID); err != nil {
+			return fmt.Errorf("failed to delete task: %w", err)
+		}
+
+		// A chain through other tasks can still close a loop
+		var cycle bool
+		err = tx.QueryRow(`
+			WITH RECURSIVE waits(id) AS (
+				SELECT blocker_id FROM task_dependencies WHERE blocked_id = ?
+				UNION
+				SELECT d.blocker_id FROM task_dependencies d JOIN waits ON d.blocked_id = waits.id
+			)
+			SELECT EXISTS (SELECT 1 FROM waits WHERE id = ?)`, keepID, keepID).Scan(&cycle)
+		if err != nil {
+			return fmt.Errorf("failed to check dependencies: %w", err)
+		}
+		if cycle {
+			return fmt.Errorf("task %d and task %d wait on each other through other tasks: %w", keepID, dropID, ErrConflict)
+		}
+
+		if !o.AllowOverlap {
+			clash, err := scanTask(tx.Stmt(p.stmt.overlap).QueryRow(keepID, m.EndTime, m.StartTime, keepID))
+			if err == nil {
+				return fmt.Errorf("task %d would overlap task %d '%s': %w", keepID, clash.ID, clash.Title, ErrConflict)
+			}
+			if !errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("failed to check overlap: %w", err)
+			}
+		}
+
+		result, err = scanTask(tx.QueryRow(`SELECT `+taskColumns+` FROM tasks WHERE id = ?`, keepID))
+		return err
+	})
+	return result, err
+}
+
+// MergeOptions changes the task MergeTasks keeps. Zero values keep what
+// merged chose.
+type MergeOptions struct {
+	Title        string
+	Start, End   time.Time
+	AllowOverlap bool
+}
+
+// merged combines two tasks into keep: the descriptions are joined, the
+// time runs from the earlier start to the later end, the earlier deadline
+// and higher priority win, and the task is fixed if either was. Fields
+// and the color of keep win over those of drop. It stays open while
+// either task is.
+func merged(keep, drop Task) Task {
+	m := keep
+	switch d := strings.TrimSpace(drop.Description); {
+	case d == "" || strings.Contains(m.Description, d):
+	case strings.TrimSpace(m.Description) == "":
+		m.Description = drop.Description
+	default:
+		m.Description = strings.TrimRight(m.Description, "\n") + "\n\n" + drop.Description
+	}
+	if drop.StartTime.Before(m.StartTime) {
+		m.StartTime = drop.StartTime
+	}
+	if drop.EndTime.After(m.EndTime) {
+		m.EndTime = drop.EndTime
+	}
+	if !drop.Deadline.IsZero() && (m.Deadline.IsZero() || drop.Deadline.Before(m.Deadline)) {
+		m.Deadline = drop.Deadline
+	}
+	if PriorityRank(drop.Priority) > PriorityRank(m.Priority) {
+		m.Priority = drop.Priority
+	}
+	if m.Color == "" {
+		m.Color = drop.Color
+	}
+	m.Flexible = keep.Flexible && drop.Flexible
+	if m.Status.Done() && !drop.Status.Done() {
+		m.Status = drop.Status
+	}
+	if len(drop.Fields) > 0 {
+		fields := maps.Clone(drop.Fields)
+		maps.Copy(fields, keep.Fields)
+		m.Fields = fields
+	}
+	return m
+}
@@ -2,10 +2,17 @@ package planner
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"regexp"
+	"slices"
 	"time"
 
+	"gomentum/internal/calendar"
+	"gomentum/internal/i18n"
+
 	_ "github.com/glebarez/go-sqlite"
 )
 
@@ -16,8 +23,115 @@ type Task struct {
 	Description string    `json:"description"`
 	StartTime   time.Time `json:"start_time"`
 	EndTime     time.Time `json:"end_time"`
-	Status      string    `json:"status"` // "pending", "completed", "in_progress"
+	Status      Status    `json:"status"` // See Statuses
 	Reminded    bool      `json:"reminded"`
+	// Deadline is when the work must be done by; zero if there is none.
+	// Unlike EndTime it does not move when the task is rescheduled.
+	Deadline time.Time `json:"deadline,omitzero"`
+	Priority string    `json:"priority,omitempty"` // low, medium, high, urgent; empty means medium
+	// Color is a label such as "blue" that sets the task apart in the list,
+	// independent of its status; empty uses the color of its project
+	Color string `json:"color,omitempty"`
+	// Flexible tasks may be moved by the scheduling engines. Fixed ones
+	// (appointments, meetings) stay where they are.
+	Flexible bool `json:"flexible"`
+	// Recurrence is the rule the task repeats by as an RRULE, such as
+	// "FREQ=WEEKLY;BYDAY=MO"; empty if it does not recur. See ParseRule.
+	Recurrence string `json:"recurrence,omitempty"`
+	// UpdatedAt is when the task was last added or edited; reminders do
+	// not count. Used to find stale tasks.
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
+	// Fields holds custom key/values such as a client or ticket ID
+	Fields map[string]any `json:"fields,omitempty"`
+	// Tags are labels such as "work" or "errand", lower case and sorted.
+	// They are read with the task but changed with AddTag, RemoveTag and
+	// SetTags; UpdateTask leaves them alone.
+	Tags []string `json:"tags,omitempty"`
+	// BlockedBy lists the IDs of the tasks that must be finished before
+	// this one; see AddDependency
+	BlockedBy []int `json:"blocked_by,omitempty"`
+}
+
+// Task priorities, lowest first
+const (
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
+)
+
+// Priorities lists the valid priorities, lowest first
+var Priorities = []string{PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent}
+
+// Colors lists the named color labels; "#RRGGBB" values are accepted too
+var Colors = []string{"red", "orange", "yellow", "green", "blue", "purple", "pink", "gray"}
+
+// colorHex is how the named labels are drawn
+var colorHex = map[string]string{
+	"red": "#E5484D", "orange": "#F76B15", "yellow": "#FFC53D", "green": "#30A46C",
+	"blue": "#0090FF", "purple": "#8E4EC6", "pink": "#D6409F", "gray": "#8B8D98",
+}
+
+var hexColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// ColorHex returns the "#RRGGBB" value of a color label, or "" for none
+func ColorHex(c string) string {
+	if hexColor.MatchString(c) {
+		return c
+	}
+	return colorHex[c]
+}
+
+// ValidColor reports whether c is a named color or a hex value. The empty
+// label is valid and means no color.
+func ValidColor(c string) bool {
+	return c == "" || slices.Contains(Colors, c) || hexColor.MatchString(c)
+}
+
+// PriorityRank orders priorities; unknown or empty values rank as medium
+func PriorityRank(p string) int {
+	for i, v := range Priorities {
+		if v == p {
+			return i
+		}
+	}
+	return 1
+}
+
+// taskColumns lists the columns read by scanTask, in order
+const taskColumns = `id, title, description, start_time, end_time, status, reminded, deadline, priority, flexible, updated_at, fields, color, recurrence, ` + tagsColumn + `, ` + blockedByColumn
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTask(row rowScanner) (Task, error) {
+	var t Task
+	var deadline, updated sql.NullTime
+	var fields string
+	var tags, blockedBy sql.NullString
+	if err := row.Scan(&t.ID, &t.Title, &t.Description, &t.StartTime, &t.EndTime, &t.Status, &t.Reminded, &deadline, &t.Priority, &t.Flexible, &updated, &fields, &t.Color, &t.Recurrence, &tags, &blockedBy); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Task{}, err
+		}
+		return Task{}, fmt.Errorf("failed to scan task: %w", err)
+	}
+	t.Deadline = deadline.Time
+	t.UpdatedAt = updated.Time
+	f, err := decodeFields(fields)
+	if err != nil {
+		return Task{}, err
+	}
+	t.Fields = f
+	t.Tags = splitTags(tags)
+	t.BlockedBy = splitIDs(blockedBy)
+	return t, nil
+}
+
+// nullTime stores the zero time as NULL
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
 }
 
 // ChatMessage represents a stored chat message
@@ -30,15 +144,21 @@ type ChatMessage struct {
 
 // Planner manages a list of tasks using SQLite
 type Planner struct {
-	db *sql.DB
+	db   *sql.DB
+	stmt statements
 }
 
-// NewPlanner creates a new Planner instance
+// NewPlanner creates a new Planner instance. dbPath may be ":memory:"
+// for a throwaway database.
 func NewPlanner(dbPath string) (*Planner, error) {
 	db, err := sql.Open("sqlite", dbPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	// Each connection to ":memory:" gets a database of its own
+	if dbPath == ":memory:" {
+		db.SetMaxOpenConns(1)
+	}
 
 	// Create tasks table if not exists
 	queryTasks := `
@@ -71,14 +191,81 @@ func NewPlanner(dbPath string) (*Planner, error) {
 
 	// Try to add reminded column if it doesn't exist (migration for existing db)
 	_, _ = db.Exec(`ALTER TABLE tasks ADD COLUMN reminded BOOLEAN DEFAULT 0`)
+	_, _ = db.Exec(`ALTER TABLE tasks ADD COLUMN deadline DATETIME`)
+	_, _ = db.Exec(`ALTER TABLE tasks ADD COLUMN priority TEXT NOT NULL DEFAULT ''`)
+	_, _ = db.Exec(`ALTER TABLE tasks ADD COLUMN flexible BOOLEAN NOT NULL DEFAULT 1`)
+	if _, err := db.Exec(`ALTER TABLE tasks ADD COLUMN updated_at DATETIME`); err == nil {
+		// Existing tasks start their staleness clock now
+		_, _ = db.Exec(`UPDATE tasks SET updated_at = ?`, time.Now())
+	}
+	_, _ = db.Exec(`ALTER TABLE tasks ADD COLUMN fields TEXT NOT NULL DEFAULT ''`)
+	_, _ = db.Exec(`ALTER TABLE tasks ADD COLUMN color TEXT NOT NULL DEFAULT ''`)
+	_, _ = db.Exec(`ALTER TABLE tasks ADD COLUMN recurrence TEXT NOT NULL DEFAULT ''`)
+	_, _ = db.Exec(`ALTER TABLE tasks ADD COLUMN recurrence_through DATETIME`)
+
+	// Nearly every query filters or sorts by start time; with the end time
+	// overlap checks never read table rows. idx_tasks_due covers the
+	// reminder poll, which runs every few seconds, and idx_tasks_recurring
+	// the check for occurrences to add, which runs with it.
+	indexes := []string{
+		`CREATE INDEX IF NOT EXISTS idx_tasks_span ON tasks(start_time, end_time)`,
+		`CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(status, start_time)`,
+		`CREATE INDEX IF NOT EXISTS idx_tasks_due ON tasks(reminded, start_time, status)`,
+		`CREATE INDEX IF NOT EXISTS idx_tasks_recurring ON tasks(recurrence_through) WHERE recurrence != ''`,
+	}
+	for _, q := range indexes {
+		if _, err := db.Exec(q); err != nil {
+			return nil, fmt.Errorf("failed to create index: %w", err)
+		}
+	}
 
-	return &Planner{db: db}, nil
+	if err := createLinksTable(db); err != nil {
+		return nil, err
+	}
+	if err := createSharesTable(db); err != nil {
+		return nil, err
+	}
+	if err := createWorkflowsTable(db); err != nil {
+		return nil, err
+	}
+	if err := createDeliveriesTable(db); err != nil {
+		return nil, err
+	}
+	if err := createActivityTable(db); err != nil {
+		return nil, err
+	}
+	if err := createSmartListsTable(db); err != nil {
+		return nil, err
+	}
+	if err := createAgentUsageTable(db); err != nil {
+		return nil, err
+	}
+	if err := createTagsTable(db); err != nil {
+		return nil, err
+	}
+	if err := createDependenciesTable(db); err != nil {
+		return nil, err
+	}
+	if err := createEmbeddingsTable(db); err != nil {
+		return nil, err
+	}
+
+	stmt, err := prepareStatements(db)
+	if err != nil {
+		return nil, err
+	}
+	return &Planner{db: db, stmt: stmt}, nil
 }
 
-// AddTask adds a new task to the planner
+// AddTask adds a new, flexible task to the planner
 func (p *Planner) AddTask(title, description string, start, end time.Time) (Task, error) {
-	query := `INSERT INTO tasks (title, description, start_time, end_time, status, reminded) VALUES (?, ?, ?, ?, ?, 0)`
-	res, err := p.db.Exec(query, title, description, start, end, "pending")
+	return p.addTask(title, description, start, end, "")
+}
+
+func (p *Planner) addTask(title, description string, start, end time.Time, recurrence string) (Task, error) {
+	now := time.Now()
+	query := `INSERT INTO tasks (title, description, start_time, end_time, status, reminded, updated_at, recurrence) VALUES (?, ?, ?, ?, ?, 0, ?, ?)`
+	res, err := p.db.Exec(query, title, description, start, end, StatusPending, now, recurrence)
 	if err != nil {
 		return Task{}, fmt.Errorf("failed to insert task: %w", err)
 	}
@@ -94,14 +281,21 @@ func (p *Planner) AddTask(title, description string, start, end time.Time) (Task
 		Description: description,
 		StartTime:   start,
 		EndTime:     end,
-		Status:      "pending",
+		Status:      StatusPending,
 		Reminded:    false,
+		Flexible:    true,
+		Recurrence:  recurrence,
+		UpdatedAt:   now,
 	}, nil
 }
 
-// ListTasks returns all tasks
+// ListTasks returns all tasks, with the occurrences of recurring tasks
+// over the next two weeks
 func (p *Planner) ListTasks() ([]Task, error) {
-	query := `SELECT id, title, description, start_time, end_time, status, reminded FROM tasks ORDER BY start_time ASC`
+	if err := p.materialize(time.Time{}); err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + taskColumns + ` FROM tasks ORDER BY start_time ASC`
 	rows, err := p.db.Query(query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tasks: %w", err)
@@ -110,9 +304,9 @@ func (p *Planner) ListTasks() ([]Task, error) {
 
 	var tasks []Task
 	for rows.Next() {
-		var t Task
-		if err := rows.Scan(&t.ID, &t.Title, &t.Description, &t.StartTime, &t.EndTime, &t.Status, &t.Reminded); err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, err
 		}
 		tasks = append(tasks, t)
 	}
@@ -123,14 +317,37 @@ func (p *Planner) ListTasks() ([]Task, error) {
 func (p *Planner) GetUpcomingTasks(d time.Duration) ([]Task, error) {
 	now := time.Now()
 	target := now.Add(d)
+	if err := p.materialize(target); err != nil {
+		return nil, err
+	}
 
 	// We check for tasks that are due (start_time <= target) and haven't been reminded yet.
 	// We don't strictly enforce start_time > now to catch tasks that might have been missed
 	// if the poller was slow or the app was restarted.
-	query := `SELECT id, title, description, start_time, end_time, status, reminded FROM tasks 
-	          WHERE start_time <= ? AND reminded = 0 AND status != 'completed'`
+	args := []any{target}
+	for _, s := range inactiveStatuses() {
+		args = append(args, s)
+	}
+	ids, err := p.stmt.due.Query(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query upcoming tasks: %w", err)
+	}
+	args = args[:0]
+	for ids.Next() {
+		var id int
+		if err := ids.Scan(&id); err != nil {
+			ids.Close()
+			return nil, fmt.Errorf("failed to scan task id: %w", err)
+		}
+		args = append(args, id)
+	}
+	ids.Close()
+	if len(args) == 0 {
+		return nil, nil
+	}
 
-	rows, err := p.db.Query(query, target)
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id IN (` + placeholders(len(args)) + `) ORDER BY start_time ASC`
+	rows, err := p.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query upcoming tasks: %w", err)
 	}
@@ -138,9 +355,9 @@ func (p *Planner) GetUpcomingTasks(d time.Duration) ([]Task, error) {
 
 	var tasks []Task
 	for rows.Next() {
-		var t Task
-		if err := rows.Scan(&t.ID, &t.Title, &t.Description, &t.StartTime, &t.EndTime, &t.Status, &t.Reminded); err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, err
 		}
 		tasks = append(tasks, t)
 	}
@@ -149,22 +366,19 @@ func (p *Planner) GetUpcomingTasks(d time.Duration) ([]Task, error) {
 
 // MarkAsReminded marks a task as reminded
 func (p *Planner) MarkAsReminded(id int) error {
-	query := `UPDATE tasks SET reminded = 1 WHERE id = ?`
-	_, err := p.db.Exec(query, id)
+	_, err := p.stmt.reminded.Exec(id)
 	return err
 }
 
 // CheckOverlap checks if the given time range overlaps with any existing task.
-// Returns the conflicting task if found. excludeID is used when updating a task to ignore itself.
+// Returns the conflicting task if found. excludeID is used when updating a task to ignore itself
+// and the system tasks planned for it, such as its travel buffers.
 func (p *Planner) CheckOverlap(start, end time.Time, excludeID int) (*Task, error) {
-	query := `SELECT id, title, description, start_time, end_time, status, reminded FROM tasks 
-	          WHERE id != ? AND start_time < ? AND end_time > ?`
-
-	row := p.db.QueryRow(query, excludeID, end, start)
+	row := p.stmt.overlap.QueryRow(excludeID, end, start, excludeID)
 
-	var t Task
-	if err := row.Scan(&t.ID, &t.Title, &t.Description, &t.StartTime, &t.EndTime, &t.Status, &t.Reminded); err != nil {
-		if err == sql.ErrNoRows {
+	t, err := scanTask(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("database error: %w", err)
@@ -174,23 +388,32 @@ func (p *Planner) CheckOverlap(start, end time.Time, excludeID int) (*Task, erro
 
 // GetTask finds a task by ID
 func (p *Planner) GetTask(id int) (Task, error) {
-	query := `SELECT id, title, description, start_time, end_time, status, reminded FROM tasks WHERE id = ?`
-	row := p.db.QueryRow(query, id)
+	row := p.stmt.get.QueryRow(id)
 
-	var t Task
-	if err := row.Scan(&t.ID, &t.Title, &t.Description, &t.StartTime, &t.EndTime, &t.Status, &t.Reminded); err != nil {
-		if err == sql.ErrNoRows {
-			return Task{}, fmt.Errorf("task with ID %d not found", id)
+	t, err := scanTask(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Task{}, fmt.Errorf("task with ID %d %w", id, ErrNotFound)
 		}
-		return Task{}, fmt.Errorf("failed to scan task: %w", err)
+		return Task{}, err
 	}
 	return t, nil
 }
 
-// UpdateTask updates an existing task and resets the reminder status
+// UpdateTask updates an existing task and resets the reminder status.
+// Finishing a task that has open blockers is a conflict.
 func (p *Planner) UpdateTask(t Task) error {
-	query := `UPDATE tasks SET title = ?, description = ?, start_time = ?, end_time = ?, status = ?, reminded = 0 WHERE id = ?`
-	res, err := p.db.Exec(query, t.Title, t.Description, t.StartTime, t.EndTime, t.Status, t.ID)
+	fields, err := encodeFields(t.Fields)
+	if err != nil {
+		return err
+	}
+	if t.Status.Done() {
+		if err := checkBlockers(p.db, []int{t.ID}); err != nil {
+			return err
+		}
+	}
+	query := `UPDATE tasks SET title = ?, description = ?, start_time = ?, end_time = ?, status = ?, deadline = ?, priority = ?, flexible = ?, updated_at = ?, fields = ?, color = ?, recurrence = ?, reminded = 0 WHERE id = ?`
+	res, err := p.db.Exec(query, t.Title, t.Description, t.StartTime, t.EndTime, t.Status, nullTime(t.Deadline), t.Priority, t.Flexible, time.Now(), fields, t.Color, t.Recurrence, t.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update task: %w", err)
 	}
@@ -199,7 +422,7 @@ func (p *Planner) UpdateTask(t Task) error {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("task with ID %d not found", t.ID)
+		return fmt.Errorf("task with ID %d %w", t.ID, ErrNotFound)
 	}
 	return nil
 }
@@ -216,34 +439,51 @@ func (p *Planner) DeleteTask(id int) error {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("task with ID %d not found", id)
+		return fmt.Errorf("task with ID %d %w", id, ErrNotFound)
 	}
 	return nil
 }
 
 // ExportToMarkdown exports all tasks to a markdown file
 func (p *Planner) ExportToMarkdown(filename string) error {
-	tasks, err := p.ListTasks()
+	f, err := os.Create(filename)
 	if err != nil {
 		return err
 	}
+	defer f.Close()
+	return p.WriteMarkdown(f)
+}
 
-	f, err := os.Create(filename)
+// WriteMarkdown writes all tasks to f as markdown
+func (p *Planner) WriteMarkdown(f io.Writer) error {
+	tasks, err := p.ListTasks()
 	if err != nil {
 		return err
 	}
-	defer f.Close()
+	return WriteTasksMarkdown(f, tasks)
+}
 
-	fmt.Fprintf(f, "# Gomentum Plan\n\n")
-	fmt.Fprintf(f, "Generated at: %s\n\n", time.Now().Format(time.RFC1123))
+// WriteTasksMarkdown writes the given tasks to f as markdown, such as the
+// result of a query
+func WriteTasksMarkdown(f io.Writer, tasks []Task) error {
+	fmt.Fprintf(f, "# %s\n\n", i18n.T("export.title"))
+	fmt.Fprintf(f, "%s\n\n", i18n.T("export.generated_at", i18n.FormatDateTime(time.Now())))
 
 	for _, t := range tasks {
 		fmt.Fprintf(f, "## %s\n", t.Title)
-		fmt.Fprintf(f, "- **ID**: %d\n", t.ID)
-		fmt.Fprintf(f, "- **Time**: %s - %s\n", t.StartTime.Local().Format("15:04"), t.EndTime.Local().Format("15:04"))
-		fmt.Fprintf(f, "- **Status**: %s\n", t.Status)
+		fmt.Fprintf(f, "- **%s**: %d\n", i18n.T("export.id"), t.ID)
+		fmt.Fprintf(f, "- **%s**: %s %s - %s\n", i18n.T("export.time"),
+			calendar.Annotate(i18n.FormatDate(t.StartTime), t.StartTime), i18n.FormatTime(t.StartTime), i18n.FormatTime(t.EndTime))
+		fmt.Fprintf(f, "- **%s**: %s\n", i18n.T("export.status"), t.Status)
+		if !t.Deadline.IsZero() {
+			fmt.Fprintf(f, "- **%s**: %s\n", i18n.T("export.deadline"), i18n.FormatDateTime(t.Deadline))
+		}
 		if t.Description != "" {
-			fmt.Fprintf(f, "- **Description**: %s\n", t.Description)
+			fmt.Fprintf(f, "- **%s**: %s\n", i18n.T("export.description"), t.Description)
+		}
+		for _, k := range t.FieldKeys() {
+			v, _ := t.FieldString(k)
+			fmt.Fprintf(f, "- **%s**: %s\n", k, v)
 		}
 		fmt.Fprintln(f)
 	}
@@ -287,6 +527,24 @@ func (p *Planner) GetRecentMessages(limit int) ([]ChatMessage, error) {
 	return messages, nil
 }
 
+// LastMessageID returns the ID of the newest stored chat message, or 0
+func (p *Planner) LastMessageID() (int, error) {
+	var id int
+	if err := p.db.QueryRow(`SELECT COALESCE(MAX(id), 0) FROM chat_history`).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to query chat history: %w", err)
+	}
+	return id, nil
+}
+
+// DeleteMessagesAfter removes the chat messages stored after the one with
+// the given ID
+func (p *Planner) DeleteMessagesAfter(id int) error {
+	if _, err := p.db.Exec(`DELETE FROM chat_history WHERE id > ?`, id); err != nil {
+		return fmt.Errorf("failed to delete chat messages: %w", err)
+	}
+	return nil
+}
+
 // ClearHistory clears the chat history
 func (p *Planner) ClearHistory() error {
 	_, err := p.db.Exec(`DELETE FROM chat_history`)
@@ -295,5 +553,6 @@ func (p *Planner) ClearHistory() error {
 
 // Close closes the database connection
 func (p *Planner) Close() error {
+	p.stmt.close()
 	return p.db.Close()
 }
@@ -0,0 +1,176 @@
+package planner
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Filter selects tasks for QueryTasks. Zero fields match every task and
+// the conditions that are set must all hold.
+type Filter struct {
+	From       time.Time         // Tasks starting at or after From
+	To         time.Time         // Tasks starting before To
+	Statuses   []string          // Any of these statuses
+	Priorities []string          // Any of these priorities; "medium" also matches unset
+	Text       string            // Substring of the title or description, ignoring case
+	Fields     map[string]string // Custom fields equal to these values
+	Tags       []string          // Any of these tags, ignoring case
+	IDs        []int             // Only these tasks
+	Limit      int               // At most this many tasks; 0 means no limit
+	ByPriority bool              // Highest priority first, then by start time
+	// Overdue selects unfinished tasks whose deadline, or else end, is
+	// before this time
+	Overdue time.Time
+}
+
+// priorityOrder sorts urgent tasks first and low ones last; unset and
+// unknown priorities sort as medium, as in PriorityRank
+const priorityOrder = `CASE priority WHEN 'urgent' THEN 0 WHEN 'high' THEN 1 WHEN 'low' THEN 3 ELSE 2 END`
+
+// QueryTasks returns the tasks matching f ordered by start time, or by
+// priority first with f.ByPriority. Every value is passed as a query
+// parameter, never spliced into the SQL.
+func (p *Planner) QueryTasks(f Filter) ([]Task, error) {
+	if err := p.materialize(f.To); err != nil {
+		return nil, err
+	}
+	where, args, err := f.compile()
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + taskColumns + ` FROM tasks`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+	if f.ByPriority {
+		query += ` ORDER BY ` + priorityOrder + `, start_time ASC`
+	} else {
+		query += ` ORDER BY start_time ASC`
+	}
+	if f.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, f.Limit)
+	}
+
+	rows, err := p.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []Task
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+// TasksBetween returns the tasks that overlap from to to, such as the
+// tasks of one day, ordered by start time. Unlike a Filter on start
+// times, it includes tasks that began earlier and are still running.
+func (p *Planner) TasksBetween(from, to time.Time) ([]Task, error) {
+	if err := p.materialize(to); err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE start_time < ? AND (start_time >= ? OR end_time > ?) ORDER BY start_time ASC`
+	rows, err := p.db.Query(query, to, from, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []Task
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+func (f Filter) compile() ([]string, []any, error) {
+	var where []string
+	var args []any
+
+	if !f.From.IsZero() {
+		where = append(where, `start_time >= ?`)
+		args = append(args, f.From)
+	}
+	if !f.To.IsZero() {
+		where = append(where, `start_time < ?`)
+		args = append(args, f.To)
+	}
+	if len(f.IDs) > 0 {
+		where = append(where, `id IN (`+placeholders(len(f.IDs))+`)`)
+		for _, id := range f.IDs {
+			args = append(args, id)
+		}
+	}
+	if !f.Overdue.IsZero() {
+		inactive := inactiveStatuses()
+		where = append(where, `status NOT IN (`+placeholders(len(inactive))+`) AND COALESCE(deadline, end_time) < ?`)
+		for _, s := range inactive {
+			args = append(args, s)
+		}
+		args = append(args, f.Overdue)
+	}
+	if len(f.Statuses) > 0 {
+		where = append(where, `status IN (`+placeholders(len(f.Statuses))+`)`)
+		for _, s := range f.Statuses {
+			args = append(args, s)
+		}
+	}
+	if len(f.Priorities) > 0 {
+		cond := `priority IN (` + placeholders(len(f.Priorities)) + `)`
+		if slices.Contains(f.Priorities, PriorityMedium) {
+			cond = `(` + cond + ` OR priority = '')`
+		}
+		for _, p := range f.Priorities {
+			args = append(args, p)
+		}
+		where = append(where, cond)
+	}
+	if len(f.Tags) > 0 {
+		where = append(where, `id IN (SELECT task_id FROM task_tags JOIN tags ON tags.id = task_tags.tag_id WHERE tags.name IN (`+placeholders(len(f.Tags))+`))`)
+		for _, tag := range f.Tags {
+			args = append(args, strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		}
+	}
+	if f.Text != "" {
+		where = append(where, `(title LIKE ? ESCAPE '\' OR description LIKE ? ESCAPE '\')`)
+		like := "%" + escapeLike(f.Text) + "%"
+		args = append(args, like, like)
+	}
+	keys := make([]string, 0, len(f.Fields))
+	for k := range f.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if strings.ContainsAny(k, `"\`) {
+			return nil, nil, fmt.Errorf("%w: field name %q", ErrValidation, k)
+		}
+		// Tasks without fields store an empty string, which is not JSON
+		where = append(where, `CAST(CASE WHEN fields = '' THEN NULL ELSE json_extract(fields, ?) END AS TEXT) = ?`)
+		args = append(args, `$."`+k+`"`, f.Fields[k])
+	}
+	return where, args, nil
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// escapeLike makes % and _ match literally in a LIKE pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,405 @@
+package planner
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A recurring task is the first occurrence of a series and carries the
+// rule. The following occurrences are materialized as ordinary tasks a
+// while ahead, so reminders, overlap checks and every view see them
+// without knowing about rules. Each occurrence holds the ID of the first
+// task in its series field, and the first task records how far its
+// series has been materialized, so an occurrence that was deleted is not
+// brought back.
+
+// FieldSeries holds the ID of the recurring task an occurrence belongs to
+const FieldSeries = "series"
+
+// recurrenceHorizon is how far ahead occurrences are materialized when
+// tasks are listed
+const recurrenceHorizon = 14 * 24 * time.Hour
+
+// maxRecurrenceHorizon bounds how far a query for a later range expands
+// the series
+const maxRecurrenceHorizon = 366 * 24 * time.Hour
+
+// Recurrence frequencies
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+	FreqYearly  = "YEARLY"
+)
+
+// Rule is a recurrence rule, a subset of iCalendar's RRULE
+type Rule struct {
+	Freq     string
+	Interval int            // Every Interval days, weeks, months or years
+	ByDay    []time.Weekday // Weekly only: the days of the week
+	Count    int            // Occurrences in all, counting the first; 0 is unlimited
+	Until    time.Time      // No occurrence starts after this day; zero is unlimited
+}
+
+// recurrenceNames are the simple rules and the RRULEs they stand for
+var recurrenceNames = map[string]string{
+	"daily":    "FREQ=DAILY",
+	"weekdays": "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR",
+	"weekly":   "FREQ=WEEKLY",
+	"biweekly": "FREQ=WEEKLY;INTERVAL=2",
+	"monthly":  "FREQ=MONTHLY",
+	"yearly":   "FREQ=YEARLY",
+}
+
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// ParseRule reads daily, weekdays, weekly, biweekly, monthly, yearly or an
+// RRULE such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10" with FREQ,
+// INTERVAL, BYDAY (weekly only), COUNT and UNTIL (YYYYMMDD)
+func ParseRule(s string) (Rule, error) {
+	s = strings.TrimSpace(s)
+	if rrule, ok := recurrenceNames[strings.ToLower(s)]; ok {
+		s = rrule
+	}
+	s = strings.TrimPrefix(strings.ToUpper(s), "RRULE:")
+	if s == "" {
+		return Rule{}, fmt.Errorf("%w: recurrence is empty", ErrValidation)
+	}
+
+	r := Rule{Interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return Rule{}, fmt.Errorf("%w: invalid recurrence part %q", ErrValidation, part)
+		}
+		switch key {
+		case "FREQ":
+			if !slices.Contains([]string{FreqDaily, FreqWeekly, FreqMonthly, FreqYearly}, value) {
+				return Rule{}, fmt.Errorf("%w: unsupported recurrence frequency %q, use DAILY, WEEKLY, MONTHLY or YEARLY", ErrValidation, value)
+			}
+			r.Freq = value
+		case "INTERVAL", "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return Rule{}, fmt.Errorf("%w: %s must be a positive number", ErrValidation, key)
+			}
+			if key == "INTERVAL" {
+				r.Interval = n
+			} else {
+				r.Count = n
+			}
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				day := slices.Index(weekdayCodes, code)
+				if day < 0 {
+					return Rule{}, fmt.Errorf("%w: unknown BYDAY day %q, use MO, TU, WE, TH, FR, SA or SU", ErrValidation, code)
+				}
+				if !slices.Contains(r.ByDay, time.Weekday(day)) {
+					r.ByDay = append(r.ByDay, time.Weekday(day))
+				}
+			}
+		case "UNTIL":
+			until, err := time.ParseInLocation("20060102", value[:min(len(value), 8)], time.Local)
+			if err != nil {
+				return Rule{}, fmt.Errorf("%w: UNTIL must be a date such as 20261231", ErrValidation)
+			}
+			r.Until = until
+		default:
+			return Rule{}, fmt.Errorf("%w: unsupported recurrence part %s", ErrValidation, key)
+		}
+	}
+	if r.Freq == "" {
+		return Rule{}, fmt.Errorf("%w: recurrence needs a FREQ", ErrValidation)
+	}
+	if len(r.ByDay) > 0 && r.Freq != FreqWeekly {
+		return Rule{}, fmt.Errorf("%w: BYDAY only works with FREQ=WEEKLY", ErrValidation)
+	}
+	// Days in the order of a week starting on Monday, as occurrences are
+	// generated week by week
+	slices.SortFunc(r.ByDay, func(a, b time.Weekday) int { return mondayIndex(a) - mondayIndex(b) })
+	return r, nil
+}
+
+func mondayIndex(d time.Weekday) int {
+	return (int(d) + 6) % 7
+}
+
+// String renders the rule as an RRULE
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			codes[i] = weekdayCodes[d]
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Occurrences calls fn with the start of each occurrence of a series
+// whose first occurrence starts at first, in order and beginning with
+// first, until fn returns false or the series ends. Occurrences keep the
+// local wall-clock time of first across daylight saving changes; months
+// and years without the day of first are skipped.
+func (r Rule) Occurrences(first time.Time, fn func(time.Time) bool) {
+	first = first.Local()
+	y, m, d := first.Date()
+	hour, minute, sec := first.Clock()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, hour, minute, sec, first.Nanosecond(), time.Local)
+	}
+	var until time.Time
+	if !r.Until.IsZero() {
+		until = r.Until.AddDate(0, 0, 1)
+	}
+
+	n := 0
+	emit := func(t time.Time) bool {
+		if (!until.IsZero() && !t.Before(until)) || (r.Count > 0 && n >= r.Count) {
+			return false
+		}
+		n++
+		return fn(t)
+	}
+
+	// A rule that can never match would otherwise loop forever
+	const maxSteps = 100000
+	for step := 0; step < maxSteps; step++ {
+		k := step * r.Interval
+		switch r.Freq {
+		case FreqDaily:
+			if !emit(at(y, m, d+k)) {
+				return
+			}
+		case FreqWeekly:
+			if len(r.ByDay) == 0 {
+				if !emit(at(y, m, d+7*k)) {
+					return
+				}
+				continue
+			}
+			monday := d - mondayIndex(first.Weekday()) + 7*k
+			for _, wd := range r.ByDay {
+				t := at(y, m, monday+mondayIndex(wd))
+				if t.Before(first) {
+					continue
+				}
+				if !emit(t) {
+					return
+				}
+			}
+		case FreqMonthly:
+			t := at(y, m+time.Month(k), d)
+			if t.Day() == d && !emit(t) {
+				return
+			}
+		case FreqYearly:
+			t := at(y+k, m, d)
+			if t.Day() == d && !emit(t) {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+// NormalizeRecurrence checks a rule and returns it as an RRULE; "" and
+// "none" mean the task does not recur and give ""
+func NormalizeRecurrence(s string) (string, error) {
+	if s = strings.TrimSpace(s); s == "" || strings.EqualFold(s, "none") {
+		return "", nil
+	}
+	r, err := ParseRule(s)
+	if err != nil {
+		return "", err
+	}
+	return r.String(), nil
+}
+
+// Series returns the recurring task t belongs to: its own ID if it
+// carries the rule, the ID in its series field if it is a later
+// occurrence, or 0
+func (t Task) Series() int {
+	if t.Recurrence != "" {
+		return t.ID
+	}
+	if id, ok := t.FieldNumber(FieldSeries); ok {
+		return int(id)
+	}
+	return 0
+}
+
+// AddRecurringTask adds a task like AddTask that repeats by rule (see
+// ParseRule). Its next occurrences appear as soon as tasks are listed.
+func (p *Planner) AddRecurringTask(title, description string, start, end time.Time, rule string) (Task, error) {
+	recurrence, err := NormalizeRecurrence(rule)
+	if err != nil {
+		return Task{}, err
+	}
+	return p.addTask(title, description, start, end, recurrence)
+}
+
+// SetRecurrence gives the task a new rule, or stops it recurring with ""
+// or "none". Occurrences of the old rule that start after now and are not
+// done are deleted, and the new rule is materialized from now on. It
+// returns how many occurrences were deleted.
+func (p *Planner) SetRecurrence(id int, rule string) (int, error) {
+	recurrence, err := NormalizeRecurrence(rule)
+	if err != nil {
+		return 0, err
+	}
+	t, err := p.GetTask(id)
+	if err != nil {
+		return 0, err
+	}
+	if t.Series() != 0 && t.Series() != t.ID {
+		return 0, fmt.Errorf("%w: task %d is an occurrence of the recurring task %d; change the rule there", ErrValidation, id, t.Series())
+	}
+
+	now := time.Now()
+	through := now
+	if t.StartTime.After(through) {
+		through = t.StartTime
+	}
+	var removed int64
+	err = p.inTx(func(tx *sql.Tx) error {
+		args := []any{id, now}
+		for _, s := range doneStatuses() {
+			args = append(args, s)
+		}
+		res, err := tx.Exec(`DELETE FROM tasks WHERE fields != '' AND json_extract(fields, '$.`+FieldSeries+`') = ?
+			AND start_time > ? AND status NOT IN (`+placeholders(len(doneStatuses()))+`)`, args...)
+		if err != nil {
+			return fmt.Errorf("failed to delete occurrences: %w", err)
+		}
+		if removed, err = res.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		_, err = tx.Exec(`UPDATE tasks SET recurrence = ?, recurrence_through = ?, updated_at = ? WHERE id = ?`, recurrence, through, now, id)
+		if err != nil {
+			return fmt.Errorf("failed to update recurrence: %w", err)
+		}
+		return nil
+	})
+	return int(removed), err
+}
+
+// materialize adds the occurrences of every recurring task that start up
+// to until, or at least over the next two weeks, and have not been added
+// yet. Each series is claimed by moving
+// its recurrence_through mark in the same transaction as the inserts, so
+// two processes listing tasks at once cannot both add them.
+func (p *Planner) materialize(until time.Time) error {
+	now := time.Now()
+	if horizon := now.Add(recurrenceHorizon); until.Before(horizon) {
+		until = horizon
+	}
+	if limit := now.Add(maxRecurrenceHorizon); until.After(limit) {
+		until = limit
+	}
+	rows, err := p.db.Query(`SELECT id, recurrence_through FROM tasks
+		WHERE recurrence != '' AND (recurrence_through IS NULL OR recurrence_through < ?)`, until)
+	if err != nil {
+		return fmt.Errorf("failed to query recurring tasks: %w", err)
+	}
+	type pending struct {
+		id      int
+		through sql.NullTime
+	}
+	var series []pending
+	for rows.Next() {
+		var s pending
+		if err := rows.Scan(&s.id, &s.through); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan recurring task: %w", err)
+		}
+		series = append(series, s)
+	}
+	rows.Close()
+
+	for _, s := range series {
+		t, err := p.GetTask(s.id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		rule, err := ParseRule(t.Recurrence)
+		if err != nil {
+			slog.Warn("Invalid recurrence, skipping it", "task", t.ID, "recurrence", t.Recurrence, "error", err)
+			continue
+		}
+		// Occurrences up to the first task itself, or up to the last mark,
+		// exist already
+		after := t.StartTime
+		if s.through.Valid && s.through.Time.After(after) {
+			after = s.through.Time
+		}
+
+		duration := t.EndTime.Sub(t.StartTime)
+		var occurrences []Task
+		rule.Occurrences(t.StartTime, func(start time.Time) bool {
+			if start.After(until) {
+				return false
+			}
+			if start.After(after) {
+				occurrences = append(occurrences, occurrence(t, start, duration))
+			}
+			return true
+		})
+
+		err = p.inTx(func(tx *sql.Tx) error {
+			res, err := tx.Exec(`UPDATE tasks SET recurrence_through = ? WHERE id = ? AND recurrence_through IS ?`, until, t.ID, s.through)
+			if err != nil {
+				return fmt.Errorf("failed to mark recurring task: %w", err)
+			}
+			if n, err := res.RowsAffected(); err != nil || n == 0 {
+				return err // Another process got here first
+			}
+			_, err = insertTasks(tx, occurrences)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// occurrence is the copy of the recurring task t that starts at start
+func occurrence(t Task, start time.Time, duration time.Duration) Task {
+	o := Task{
+		Title:       t.Title,
+		Description: t.Description,
+		StartTime:   start,
+		EndTime:     start.Add(duration),
+		Status:      StatusPending,
+		Priority:    t.Priority,
+		Color:       t.Color,
+		Flexible:    t.Flexible,
+		Tags:        t.Tags,
+	}
+	for k, v := range t.Fields {
+		o.SetField(k, v)
+	}
+	o.SetField(FieldSeries, t.ID)
+	return o
+}
@@ -0,0 +1,114 @@
+package planner
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Shares are tokens that grant read-only access to the plan of a time
+// range through the HTTP server, until they expire or are revoked.
+
+// Share is a read-only link to part of the plan
+type Share struct {
+	Token     string    `json:"token"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Details   bool      `json:"details"` // Show task titles instead of plain busy blocks
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Expired reports whether the share can no longer be used at now
+func (s Share) Expired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+func createSharesTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS shares (
+		token TEXT PRIMARY KEY,
+		range_start DATETIME NOT NULL,
+		range_end DATETIME NOT NULL,
+		details BOOLEAN NOT NULL DEFAULT 0,
+		expires_at DATETIME NOT NULL,
+		created_at DATETIME NOT NULL
+	);
+	`
+	if _, err := db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create shares table: %w", err)
+	}
+	return nil
+}
+
+// CreateShare issues a new token for the plan between from and to
+func (p *Planner) CreateShare(from, to time.Time, details bool, expires time.Time) (Share, error) {
+	buf := make([]byte, 18)
+	if _, err := rand.Read(buf); err != nil {
+		return Share{}, fmt.Errorf("failed to generate token: %w", err)
+	}
+	s := Share{
+		Token:     base64.RawURLEncoding.EncodeToString(buf),
+		From:      from,
+		To:        to,
+		Details:   details,
+		ExpiresAt: expires,
+		CreatedAt: time.Now(),
+	}
+	query := `INSERT INTO shares (token, range_start, range_end, details, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	if _, err := p.db.Exec(query, s.Token, s.From, s.To, s.Details, s.ExpiresAt, s.CreatedAt); err != nil {
+		return Share{}, fmt.Errorf("failed to save share: %w", err)
+	}
+	return s, nil
+}
+
+// GetShare finds a share by token, expired or not
+func (p *Planner) GetShare(token string) (Share, error) {
+	row := p.db.QueryRow(`SELECT token, range_start, range_end, details, expires_at, created_at FROM shares WHERE token = ?`, token)
+	var s Share
+	if err := row.Scan(&s.Token, &s.From, &s.To, &s.Details, &s.ExpiresAt, &s.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Share{}, fmt.Errorf("share %w", ErrNotFound)
+		}
+		return Share{}, fmt.Errorf("failed to scan share: %w", err)
+	}
+	return s, nil
+}
+
+// ListShares returns every share, newest first
+func (p *Planner) ListShares() ([]Share, error) {
+	rows, err := p.db.Query(`SELECT token, range_start, range_end, details, expires_at, created_at FROM shares ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query shares: %w", err)
+	}
+	defer rows.Close()
+
+	var shares []Share
+	for rows.Next() {
+		var s Share
+		if err := rows.Scan(&s.Token, &s.From, &s.To, &s.Details, &s.ExpiresAt, &s.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan share: %w", err)
+		}
+		shares = append(shares, s)
+	}
+	return shares, nil
+}
+
+// RevokeShare deletes a share so its link stops working
+func (p *Planner) RevokeShare(token string) error {
+	res, err := p.db.Exec(`DELETE FROM shares WHERE token = ?`, token)
+	if err != nil {
+		return fmt.Errorf("failed to revoke share: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("share %s %w", token, ErrNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package planner
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gomentum/internal/i18n"
+)
+
+// Smart lists are saved searches: a name for a query in the filter
+// language of ParseQuery, run again whenever the list is opened so
+// relative dates such as "this week" stay current.
+
+// SmartList is a saved query
+type SmartList struct {
+	Name      string    `json:"name"`
+	Query     string    `json:"query"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func createSmartListsTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS smart_lists (
+		name TEXT PRIMARY KEY COLLATE NOCASE,
+		query TEXT NOT NULL,
+		created_at DATETIME NOT NULL
+	);
+	`
+	if _, err := db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create smart_lists table: %w", err)
+	}
+	return nil
+}
+
+// SaveSmartList stores a query under a name, replacing the list of that
+// name if there is one. The query is checked with ParseQuery first.
+func (p *Planner) SaveSmartList(name, query string) (SmartList, error) {
+	name, query = strings.TrimSpace(name), strings.TrimSpace(query)
+	if name == "" {
+		return SmartList{}, fmt.Errorf("%w: smart list name is empty", ErrValidation)
+	}
+	if query == "" {
+		return SmartList{}, fmt.Errorf("%w: smart list query is empty", ErrValidation)
+	}
+	if _, err := ParseQuery(query, time.Now()); err != nil {
+		return SmartList{}, err
+	}
+	_, err := p.db.Exec(`INSERT INTO smart_lists (name, query, created_at) VALUES (?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET query = excluded.query`, name, query, time.Now())
+	if err != nil {
+		return SmartList{}, fmt.Errorf("failed to save smart list: %w", err)
+	}
+	return p.GetSmartList(name)
+}
+
+// GetSmartList finds a smart list by name, ignoring case
+func (p *Planner) GetSmartList(name string) (SmartList, error) {
+	row := p.db.QueryRow(`SELECT name, query, created_at FROM smart_lists WHERE name = ?`, strings.TrimSpace(name))
+	var l SmartList
+	if err := row.Scan(&l.Name, &l.Query, &l.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return SmartList{}, fmt.Errorf("smart list %q %w", name, ErrNotFound)
+		}
+		return SmartList{}, fmt.Errorf("failed to scan smart list: %w", err)
+	}
+	return l, nil
+}
+
+// SmartLists returns every smart list, oldest first
+func (p *Planner) SmartLists() ([]SmartList, error) {
+	rows, err := p.db.Query(`SELECT name, query, created_at FROM smart_lists ORDER BY created_at ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query smart lists: %w", err)
+	}
+	defer rows.Close()
+
+	var lists []SmartList
+	for rows.Next() {
+		var l SmartList
+		if err := rows.Scan(&l.Name, &l.Query, &l.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan smart list: %w", err)
+		}
+		lists = append(lists, l)
+	}
+	return lists, nil
+}
+
+// DeleteSmartList removes a smart list; its tasks are untouched
+func (p *Planner) DeleteSmartList(name string) error {
+	res, err := p.db.Exec(`DELETE FROM smart_lists WHERE name = ?`, strings.TrimSpace(name))
+	if err != nil {
+		return fmt.Errorf("failed to delete smart list: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("smart list %q %w", name, ErrNotFound)
+	}
+	return nil
+}
+
+// ParseQuery turns a filter query into a Filter, resolving relative dates
+// against now. Terms are separated by spaces and must all hold:
+//
+//	#work,errand           any of these tags, from the tags table
+//	status:pending,waiting any of these statuses
+//	priority:high,urgent   any of these priorities
+//	is:overdue             unfinished and past the deadline, or else the end
+//	date:week              starting today, tomorrow, yesterday, this week,
+//	                       next-week, last-week, this month, on a YYYY-MM-DD
+//	                       or in a YYYY-MM-DD..YYYY-MM-DD range
+//	project:acme           any other custom field equals a value
+//	"weekly report"        other words are text in the title or description
+//
+// A lone "&" or "and" is skipped, so "is:overdue & priority:high" reads
+// naturally.
+func ParseQuery(query string, now time.Time) (Filter, error) {
+	var f Filter
+	var text []string
+	setField := func(k, v string) {
+		if f.Fields == nil {
+			f.Fields = make(map[string]string)
+		}
+		f.Fields[k] = v
+	}
+	for _, term := range queryTerms(query) {
+		if term.quoted {
+			text = append(text, term.text)
+			continue
+		}
+		t := term.text
+		if t == "&" || strings.EqualFold(t, "and") {
+			continue
+		}
+		if list, ok := strings.CutPrefix(t, "#"); ok {
+			if f.Tags != nil {
+				return Filter{}, fmt.Errorf("%w: more than one #tag term; use #a,b for any of several tags", ErrValidation)
+			}
+			for _, tag := range strings.Split(list, ",") {
+				name, err := NormalizeTag(tag)
+				if err != nil {
+					return Filter{}, err
+				}
+				f.Tags = append(f.Tags, name)
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(t, ":")
+		if !ok || key == "" {
+			text = append(text, t)
+			continue
+		}
+		if value == "" {
+			return Filter{}, fmt.Errorf("%w: %q has no value", ErrValidation, t)
+		}
+		switch strings.ToLower(key) {
+		case "status":
+			for _, s := range strings.Split(value, ",") {
+				if !slices.Contains(StatusNames(), s) {
+					return Filter{}, fmt.Errorf("%w: unknown status %q, use one of %s", ErrValidation, s, strings.Join(StatusNames(), ", "))
+				}
+				f.Statuses = append(f.Statuses, s)
+			}
+		case "priority":
+			for _, p := range strings.Split(strings.ToLower(value), ",") {
+				if !slices.Contains(Priorities, p) {
+					return Filter{}, fmt.Errorf("%w: unknown priority %q, use one of %s", ErrValidation, p, strings.Join(Priorities, ", "))
+				}
+				f.Priorities = append(f.Priorities, p)
+			}
+		case "is":
+			if !strings.EqualFold(value, "overdue") {
+				return Filter{}, fmt.Errorf("%w: unknown is:%s, only is:overdue", ErrValidation, value)
+			}
+			f.Overdue = now
+		case "date":
+			from, to, err := queryDates(strings.ToLower(value), now)
+			if err != nil {
+				return Filter{}, err
+			}
+			f.From, f.To = from, to
+		default:
+			setField(key, value)
+		}
+	}
+	f.Text = strings.Join(text, " ")
+	return f, nil
+}
+
+type queryTerm struct {
+	text   string
+	quoted bool
+}
+
+// queryTerms splits a query at spaces, keeping "quoted phrases" whole
+func queryTerms(query string) []queryTerm {
+	var terms []queryTerm
+	for rest := strings.TrimSpace(query); rest != ""; rest = strings.TrimSpace(rest) {
+		if phrase, ok := strings.CutPrefix(rest, `"`); ok {
+			text, after, _ := strings.Cut(phrase, `"`)
+			if text != "" {
+				terms = append(terms, queryTerm{text: text, quoted: true})
+			}
+			rest = after
+			continue
+		}
+		end := strings.IndexAny(rest, " \t\n")
+		if end < 0 {
+			end = len(rest)
+		}
+		terms = append(terms, queryTerm{text: rest[:end]})
+		rest = rest[end:]
+	}
+	return terms
+}
+
+// queryDates resolves the value of a date: term to the start times it
+// selects
+func queryDates(v string, now time.Time) (time.Time, time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	week := i18n.StartOfWeek(today)
+	switch v {
+	case "today":
+		return today, today.AddDate(0, 0, 1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), today.AddDate(0, 0, 2), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), today, nil
+	case "week":
+		return week, week.AddDate(0, 0, 7), nil
+	case "next-week":
+		return week.AddDate(0, 0, 7), week.AddDate(0, 0, 14), nil
+	case "last-week":
+		return week.AddDate(0, 0, -7), week, nil
+	case "month":
+		first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		return first, first.AddDate(0, 1, 0), nil
+	}
+	first, last, isRange := strings.Cut(v, "..")
+	if !isRange {
+		last = first
+	}
+	from, err := time.ParseInLocation("2006-01-02", first, now.Location())
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: unknown date %q", ErrValidation, v)
+	}
+	to, err := time.ParseInLocation("2006-01-02", last, now.Location())
+	if err != nil || to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: unknown date %q", ErrValidation, v)
+	}
+	return from, to.AddDate(0, 0, 1), nil
+}
@@ -0,0 +1,117 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrNotReadOnly is returned for anything but a single SELECT statement
+var ErrNotReadOnly = fmt.Errorf("%w: only a single SELECT (or WITH ... SELECT) statement is allowed", ErrValidation)
+
+// QueryResult is the outcome of a read-only query, with every value
+// rendered as text
+type QueryResult struct {
+	Columns   []string   `json:"columns"`
+	Rows      [][]string `json:"rows"`
+	Truncated bool       `json:"truncated,omitempty"` // More rows than the limit matched
+}
+
+// ReadOnlyQuery runs an ad-hoc SELECT against the database and returns
+// at most maxRows rows. Besides checking the statement, the connection
+// is switched to query_only so writes fail inside SQLite itself.
+func (p *Planner) ReadOnlyQuery(ctx context.Context, query string, maxRows int) (QueryResult, error) {
+	query = strings.TrimSpace(query)
+	query = strings.TrimSpace(strings.TrimRight(query, ";"))
+	fields := strings.Fields(query)
+	if len(fields) == 0 || strings.Contains(query, ";") {
+		return QueryResult{}, ErrNotReadOnly
+	}
+	if verb := strings.ToUpper(fields[0]); verb != "SELECT" && verb != "WITH" {
+		return QueryResult{}, ErrNotReadOnly
+	}
+
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return QueryResult{}, fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, `PRAGMA query_only = ON`); err != nil {
+		return QueryResult{}, fmt.Errorf("failed to enable read-only mode: %w", err)
+	}
+	// The connection goes back to the pool; later writes must work again
+	defer func() { _, _ = conn.ExecContext(context.Background(), `PRAGMA query_only = OFF`) }()
+
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return QueryResult{}, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var res QueryResult
+	if res.Columns, err = rows.Columns(); err != nil {
+		return QueryResult{}, fmt.Errorf("failed to read columns: %w", err)
+	}
+	values := make([]any, len(res.Columns))
+	ptrs := make([]any, len(values))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	for rows.Next() {
+		if maxRows > 0 && len(res.Rows) == maxRows {
+			res.Truncated = true
+			break
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return QueryResult{}, fmt.Errorf("failed to scan row: %w", err)
+		}
+		row := make([]string, len(values))
+		for i, v := range values {
+			row[i] = formatValue(v)
+		}
+		res.Rows = append(res.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return QueryResult{}, fmt.Errorf("query failed: %w", err)
+	}
+	return res, nil
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Local().Format("2006-01-02 15:04:05")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Schema returns the SQLite version and the CREATE statements of every
+// table, without any data
+func (p *Planner) Schema() (string, error) {
+	var version string
+	if err := p.db.QueryRow(`SELECT sqlite_version()`).Scan(&version); err != nil {
+		return "", fmt.Errorf("failed to read sqlite version: %w", err)
+	}
+	rows, err := p.db.Query(`SELECT sql FROM sqlite_master WHERE type IN ('table', 'index') AND sql IS NOT NULL ORDER BY name`)
+	if err != nil {
+		return "", fmt.Errorf("failed to read schema: %w", err)
+	}
+	defer rows.Close()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- SQLite %s\n", version)
+	for rows.Next() {
+		var stmt string
+		if err := rows.Scan(&stmt); err != nil {
+			return "", fmt.Errorf("failed to read schema: %w", err)
+		}
+		b.WriteString(stmt + ";\n\n")
+	}
+	return b.String(), rows.Err()
+}
@@ -0,0 +1,129 @@
+package planner
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+
+	"gomentum/internal/config"
+)
+
+// Status is the state of a task
+type Status string
+
+// Built-in statuses
+const (
+	StatusPending    Status = "pending"
+	StatusInProgress Status = "in_progress"
+	StatusCompleted  Status = "completed"
+	StatusBacklog    Status = "backlog" // Kept, but no longer blocks time or triggers reminders
+)
+
+// StatusDef describes a status. Custom statuses come from the config.
+type StatusDef struct {
+	Name   Status `json:"name"`
+	Color  string `json:"color,omitempty"` // TUI color, e.g. "#D7875F"; empty keeps the default
+	Done   bool   `json:"done"`            // Counts as finished, like completed
+	Custom bool   `json:"custom"`
+}
+
+var builtinStatuses = []StatusDef{
+	{Name: StatusPending},
+	{Name: StatusInProgress},
+	{Name: StatusCompleted, Done: true},
+	{Name: StatusBacklog},
+}
+
+var (
+	statusMu sync.RWMutex
+	statuses = builtinStatuses
+)
+
+// ConfigureStatuses adds the user-defined statuses to the built-in ones.
+// Invalid or duplicate names are skipped with a warning.
+func ConfigureStatuses(custom []config.StatusConfig) {
+	defs := append([]StatusDef(nil), builtinStatuses...)
+	seen := make(map[Status]bool)
+	for _, d := range defs {
+		seen[d.Name] = true
+	}
+	for _, c := range custom {
+		name := Status(strings.ToLower(strings.TrimSpace(c.Name)))
+		if name == "" || strings.ContainsAny(string(name), " \t,") || seen[name] {
+			slog.Warn("Skipping invalid or duplicate custom status", "name", c.Name)
+			continue
+		}
+		seen[name] = true
+		defs = append(defs, StatusDef{Name: name, Color: c.Color, Done: c.Done, Custom: true})
+	}
+
+	statusMu.Lock()
+	statuses = defs
+	statusMu.Unlock()
+}
+
+// Statuses returns every known status, built-in ones first
+func Statuses() []StatusDef {
+	statusMu.RLock()
+	defer statusMu.RUnlock()
+	return statuses
+}
+
+// StatusNames returns the names of every known status
+func StatusNames() []string {
+	var names []string
+	for _, d := range Statuses() {
+		names = append(names, string(d.Name))
+	}
+	return names
+}
+
+// Def returns the definition of s; unknown statuses behave like pending
+func (s Status) Def() (StatusDef, bool) {
+	for _, d := range Statuses() {
+		if d.Name == s {
+			return d, true
+		}
+	}
+	return StatusDef{Name: s}, false
+}
+
+// Valid reports whether s is a built-in or configured status
+func (s Status) Valid() bool {
+	_, ok := s.Def()
+	return ok
+}
+
+// Done reports whether s counts as finished
+func (s Status) Done() bool {
+	d, _ := s.Def()
+	return d.Done
+}
+
+// Active reports whether a task with status s still needs doing: it is
+// neither done nor parked in the backlog
+func (s Status) Active() bool {
+	return !s.Done() && s != StatusBacklog
+}
+
+// inactiveStatuses lists the statuses that are not Active
+func inactiveStatuses() []string {
+	var names []string
+	for _, d := range Statuses() {
+		if !d.Name.Active() {
+			names = append(names, string(d.Name))
+		}
+	}
+	return names
+}
+
+// doneStatuses lists the statuses that count as finished
+func doneStatuses() []string {
+	var names []string
+	for _, d := range Statuses() {
+		if d.Done {
+			names = append(names, string(d.Name))
+		}
+	}
+	return names
+}
@@ -0,0 +1,54 @@
+package planner
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Queries run on every reminder poll or edit are prepared once per planner
+type statements struct {
+	due      *sql.Stmt // IDs of unreminded, active tasks starting by a time
+	overlap  *sql.Stmt
+	get      *sql.Stmt
+	reminded *sql.Stmt
+}
+
+func prepareStatements(db *sql.DB) (statements, error) {
+	var s statements
+	inactive := inactiveStatuses()
+	queries := []struct {
+		stmt  **sql.Stmt
+		query string
+	}{
+		// Only reads idx_tasks_due, so the poll never touches table rows
+		// when nothing is due
+		{&s.due, `SELECT id FROM tasks
+		          WHERE reminded = 0 AND start_time <= ? AND status NOT IN (` + placeholders(len(inactive)) + `)`},
+		// Micro-breaks sit inside work on purpose and never clash, and the
+		// system tasks planned for the excluded task are planned again
+		// after it moves
+		{&s.overlap, `SELECT ` + taskColumns + ` FROM tasks
+		              WHERE id != ? AND start_time < ? AND end_time > ?
+		              AND (fields = '' OR (json_extract(fields, '$.` + FieldSystem + `') IS NOT '` + SystemMicroBreak + `'
+		                                   AND json_extract(fields, '$.` + FieldSystemFor + `') IS NOT ?))`},
+		{&s.get, `SELECT ` + taskColumns + ` FROM tasks WHERE id = ?`},
+		{&s.reminded, `UPDATE tasks SET reminded = 1 WHERE id = ?`},
+	}
+	for _, q := range queries {
+		stmt, err := db.Prepare(q.query)
+		if err != nil {
+			s.close()
+			return statements{}, fmt.Errorf("failed to prepare statement: %w", err)
+		}
+		*q.stmt = stmt
+	}
+	return s, nil
+}
+
+func (s statements) close() {
+	for _, stmt := range []*sql.Stmt{s.due, s.overlap, s.get, s.reminded} {
+		if stmt != nil {
+			stmt.Close()
+		}
+	}
+}
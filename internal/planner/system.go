@@ -0,0 +1,66 @@
+package planner
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// System tasks are planned by Gomentum itself, such as the breaks
+// schedule_breaks inserts and travel buffers. Their kind is kept in the
+// "system" field; they do not count as planned work and are replaced
+// rather than edited when planned again.
+
+// FieldSystem is the field holding the kind of a system task
+const FieldSystem = "system"
+
+// FieldSystemFor holds the ID of the task a system task was planned for,
+// such as the one a travel buffer leads to
+const FieldSystemFor = "system_for"
+
+// Kinds of system tasks
+const (
+	SystemBreak      = "break"
+	SystemMicroBreak = "micro_break"
+	SystemTravel     = "travel"
+)
+
+// System returns the kind of a system task, or "" for the user's own
+func (t Task) System() string {
+	kind, _ := t.FieldString(FieldSystem)
+	return kind
+}
+
+// ReplaceSystemTasks deletes the system tasks of the given kinds starting
+// in [from, to) and adds tasks in their place, in one transaction. It
+// returns how many were deleted and the IDs of the added tasks.
+func (p *Planner) ReplaceSystemTasks(kinds []string, from, to time.Time, tasks []Task) (int, []int, error) {
+	var removed int
+	var ids []int
+	err := p.inTx(func(tx *sql.Tx) error {
+		match := `start_time >= ? AND start_time < ? AND fields != '' AND json_extract(fields, '$.` + FieldSystem + `') IN (` + placeholders(len(kinds)) + `)`
+		args := []any{from, to}
+		for _, k := range kinds {
+			args = append(args, k)
+		}
+		if _, err := tx.Exec(`DELETE FROM external_links WHERE task_id IN (SELECT id FROM tasks WHERE `+match+`)`, args...); err != nil {
+			return fmt.Errorf("failed to delete links: %w", err)
+		}
+		res, err := tx.Exec(`DELETE FROM tasks WHERE `+match, args...)
+		if err != nil {
+			return fmt.Errorf("failed to delete system tasks: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		removed = int(n)
+
+		ids, err = insertTasks(tx, tasks)
+		return err
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return removed, ids, nil
+}
@@ -0,0 +1,179 @@
+package planner
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Tags are free-form labels such as "work" or "errand", and a task can
+// have any number of them. They are unrelated to the project field of
+// the projects package, the one value that brings defaults.
+
+func createTagsTable(db *sql.DB) error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS tags (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE COLLATE NOCASE
+		)`,
+		`CREATE TABLE IF NOT EXISTS task_tags (
+			task_id INTEGER NOT NULL,
+			tag_id INTEGER NOT NULL,
+			PRIMARY KEY (task_id, tag_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_task_tags_tag ON task_tags(tag_id)`,
+		// Tasks are deleted in many places; the trigger covers all of them
+		`CREATE TRIGGER IF NOT EXISTS task_tags_cleanup AFTER DELETE ON tasks BEGIN
+			DELETE FROM task_tags WHERE task_id = OLD.id;
+		END`,
+	}
+	for _, q := range queries {
+		if _, err := db.Exec(q); err != nil {
+			return fmt.Errorf("failed to create tags table: %w", err)
+		}
+	}
+	return nil
+}
+
+// tagsColumn reads the tags of a task as one comma-separated column
+const tagsColumn = `(SELECT group_concat(tags.name) FROM task_tags JOIN tags ON tags.id = task_tags.tag_id WHERE task_tags.task_id = tasks.id)`
+
+// splitTags reads tagsColumn in order
+func splitTags(s sql.NullString) []string {
+	if !s.Valid || s.String == "" {
+		return nil
+	}
+	tags := strings.Split(s.String, ",")
+	slices.Sort(tags)
+	return tags
+}
+
+// NormalizeTag trims a tag, drops a leading # and lowers its case. Tags
+// cannot contain commas.
+func NormalizeTag(name string) (string, error) {
+	name = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "#"))
+	if name == "" {
+		return "", fmt.Errorf("%w: tag is empty", ErrValidation)
+	}
+	if strings.Contains(name, ",") {
+		return "", fmt.Errorf("%w: tag %q contains a comma", ErrValidation, name)
+	}
+	return name, nil
+}
+
+// normalizeTags normalizes names and drops duplicates
+func normalizeTags(names []string) ([]string, error) {
+	var tags []string
+	for _, n := range names {
+		tag, err := NormalizeTag(n)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	slices.Sort(tags)
+	return tags, nil
+}
+
+// AddTag adds a tag to a task; a tag the task already has is kept
+func (p *Planner) AddTag(taskID int, name string) error {
+	tag, err := NormalizeTag(name)
+	if err != nil {
+		return err
+	}
+	return p.inTx(func(tx *sql.Tx) error {
+		if err := touchTask(tx, taskID); err != nil {
+			return err
+		}
+		return insertTags(tx, taskID, []string{tag})
+	})
+}
+
+// RemoveTag takes a tag off a task
+func (p *Planner) RemoveTag(taskID int, name string) error {
+	tag, err := NormalizeTag(name)
+	if err != nil {
+		return err
+	}
+	return p.inTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(`DELETE FROM task_tags WHERE task_id = ? AND tag_id = (SELECT id FROM tags WHERE name = ?)`, taskID, tag)
+		if err != nil {
+			return fmt.Errorf("failed to remove tag: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if n == 0 {
+			return fmt.Errorf("tag %q on task %d %w", tag, taskID, ErrNotFound)
+		}
+		return touchTask(tx, taskID)
+	})
+}
+
+// SetTags replaces the tags of a task; no names removes them all
+func (p *Planner) SetTags(taskID int, names []string) error {
+	tags, err := normalizeTags(names)
+	if err != nil {
+		return err
+	}
+	return p.inTx(func(tx *sql.Tx) error {
+		if err := touchTask(tx, taskID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`DELETE FROM task_tags WHERE task_id = ?`, taskID); err != nil {
+			return fmt.Errorf("failed to clear tags: %w", err)
+		}
+		return insertTags(tx, taskID, tags)
+	})
+}
+
+// ListByTag returns the tasks with a tag, ordered by start time
+func (p *Planner) ListByTag(name string) ([]Task, error) {
+	tag, err := NormalizeTag(name)
+	if err != nil {
+		return nil, err
+	}
+	return p.QueryTasks(Filter{Tags: []string{tag}})
+}
+
+// Tags returns every tag that is on at least one task, in order
+func (p *Planner) Tags() ([]string, error) {
+	rows, err := p.db.Query(`SELECT name FROM tags WHERE id IN (SELECT tag_id FROM task_tags) ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, name)
+	}
+	return tags, nil
+}
+
+// touchTask marks a task as edited, failing if it does not exist
+func touchTask(tx *sql.Tx, taskID int) error {
+	return execTask(tx, taskID, `UPDATE tasks SET updated_at = ? WHERE id = ?`, time.Now(), taskID)
+}
+
+// insertTags adds normalized tags to a task in tx, creating new tags
+func insertTags(tx *sql.Tx, taskID int, tags []string) error {
+	for _, tag := range tags {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO tags (name) VALUES (?)`, tag); err != nil {
+			return fmt.Errorf("failed to save tag: %w", err)
+		}
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO task_tags (task_id, tag_id) SELECT ?, id FROM tags WHERE name = ?`, taskID, tag); err != nil {
+			return fmt.Errorf("failed to tag task: %w", err)
+		}
+	}
+	return nil
+}
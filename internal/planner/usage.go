@@ -0,0 +1,80 @@
+package planner
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Agent usage is counted per local day, so budgets hold across restarts
+// and across the processes sharing the database.
+
+// AgentUsage is what the agent spent: tool calls, tokens and the
+// estimated cost of those tokens
+type AgentUsage struct {
+	ToolCalls        int     `json:"tool_calls"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	Cost             float64 `json:"cost"`
+}
+
+// Tokens is the sum of prompt and completion tokens
+func (u AgentUsage) Tokens() int {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// Add returns the sum of u and v
+func (u AgentUsage) Add(v AgentUsage) AgentUsage {
+	return AgentUsage{
+		ToolCalls:        u.ToolCalls + v.ToolCalls,
+		PromptTokens:     u.PromptTokens + v.PromptTokens,
+		CompletionTokens: u.CompletionTokens + v.CompletionTokens,
+		Cost:             u.Cost + v.Cost,
+	}
+}
+
+func createAgentUsageTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS agent_usage (
+		day TEXT PRIMARY KEY,
+		tool_calls INTEGER NOT NULL DEFAULT 0,
+		prompt_tokens INTEGER NOT NULL DEFAULT 0,
+		completion_tokens INTEGER NOT NULL DEFAULT 0,
+		cost REAL NOT NULL DEFAULT 0
+	);
+	`
+	if _, err := db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create agent_usage table: %w", err)
+	}
+	return nil
+}
+
+// RecordAgentUsage adds u to the usage of the local day containing at
+func (p *Planner) RecordAgentUsage(at time.Time, u AgentUsage) error {
+	_, err := p.db.Exec(`INSERT INTO agent_usage (day, tool_calls, prompt_tokens, completion_tokens, cost) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(day) DO UPDATE SET tool_calls = tool_calls + excluded.tool_calls,
+			prompt_tokens = prompt_tokens + excluded.prompt_tokens,
+			completion_tokens = completion_tokens + excluded.completion_tokens,
+			cost = cost + excluded.cost`,
+		usageDay(at), u.ToolCalls, u.PromptTokens, u.CompletionTokens, u.Cost)
+	if err != nil {
+		return fmt.Errorf("failed to record agent usage: %w", err)
+	}
+	return nil
+}
+
+// AgentUsageOn returns the usage of the local day containing at
+func (p *Planner) AgentUsageOn(at time.Time) (AgentUsage, error) {
+	var u AgentUsage
+	err := p.db.QueryRow(`SELECT tool_calls, prompt_tokens, completion_tokens, cost FROM agent_usage WHERE day = ?`, usageDay(at)).
+		Scan(&u.ToolCalls, &u.PromptTokens, &u.CompletionTokens, &u.Cost)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return AgentUsage{}, fmt.Errorf("failed to read agent usage: %w", err)
+	}
+	return u, nil
+}
+
+func usageDay(t time.Time) string {
+	return t.Local().Format("2006-01-02")
+}
@@ -0,0 +1,51 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Watch calls changed whenever a change is committed to the database by
+// another connection: another process such as the daemon serving MCP
+// clients over SSE, a CLI command, or this process's own writes. It polls
+// SQLite's data_version every interval until ctx is done.
+func (p *Planner) Watch(ctx context.Context, interval time.Duration, changed func()) error {
+	// data_version is per connection, so the same one must be asked each time
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open watch connection: %w", err)
+	}
+	defer conn.Close()
+
+	version := func() (int64, error) {
+		var v int64
+		err := conn.QueryRowContext(ctx, `PRAGMA data_version`).Scan(&v)
+		return v, err
+	}
+	last, err := version()
+	if err != nil {
+		return fmt.Errorf("failed to read data version: %w", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			v, err := version()
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("failed to read data version: %w", err)
+			}
+			if v != last {
+				last = v
+				changed()
+			}
+		}
+	}
+}
@@ -0,0 +1,431 @@
+package planner
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Workflows are multi-step plans: the agent gathers tasks and proposes a
+// set of changes, which is saved as a checkpoint until the user confirms
+// it. Applying runs every change and the state update in one transaction,
+// so a crash or cancellation at any point leaves the plan either waiting
+// or fully applied, never half done. Until then the user can tweak the
+// plan by item number ("swap items 2 and 3"), which revises it in place.
+
+// WorkflowState is how far a workflow has got
+type WorkflowState string
+
+const (
+	WorkflowProposed  WorkflowState = "proposed"
+	WorkflowApplied   WorkflowState = "applied"
+	WorkflowDiscarded WorkflowState = "discarded"
+)
+
+// Change operations
+const (
+	ChangeAdd    = "add"    // New task with Title, StartTime and EndTime
+	ChangeMove   = "move"   // TaskID to StartTime and EndTime
+	ChangeStatus = "status" // TaskID to Status
+	ChangeDelete = "delete" // TaskID
+)
+
+// Change is one step of a workflow
+type Change struct {
+	Op          string    `json:"op"`
+	TaskID      int       `json:"task_id,omitempty"`
+	Title       string    `json:"title,omitempty"`
+	Description string    `json:"description,omitempty"`
+	StartTime   time.Time `json:"start_time,omitzero"`
+	EndTime     time.Time `json:"end_time,omitzero"`
+	Status      Status    `json:"status,omitempty"`
+}
+
+// Validate checks that the change has what its operation needs
+func (c Change) Validate() error {
+	switch c.Op {
+	case ChangeAdd, ChangeMove:
+		if c.Op == ChangeAdd && c.Title == "" {
+			return fmt.Errorf("add needs a title: %w", ErrValidation)
+		}
+		if c.Op == ChangeMove && c.TaskID == 0 {
+			return fmt.Errorf("move needs a task_id: %w", ErrValidation)
+		}
+		if !c.EndTime.After(c.StartTime) {
+			return fmt.Errorf("%s needs an end_time after start_time: %w", c.Op, ErrValidation)
+		}
+	case ChangeStatus:
+		if c.TaskID == 0 || !c.Status.Valid() {
+			return fmt.Errorf("status needs a task_id and a known status: %w", ErrValidation)
+		}
+	case ChangeDelete:
+		if c.TaskID == 0 {
+			return fmt.Errorf("delete needs a task_id: %w", ErrValidation)
+		}
+	default:
+		return fmt.Errorf("unknown operation %q: %w", c.Op, ErrValidation)
+	}
+	return nil
+}
+
+// String describes the change on one line, e.g. "move #12 to 2026-05-04 09:00-10:00"
+func (c Change) String() string {
+	slot := c.StartTime.Local().Format("2006-01-02 15:04") + "-" + c.EndTime.Local().Format("15:04")
+	switch c.Op {
+	case ChangeAdd:
+		return fmt.Sprintf("add %q at %s", c.Title, slot)
+	case ChangeMove:
+		return fmt.Sprintf("move #%d to %s", c.TaskID, slot)
+	case ChangeStatus:
+		return fmt.Sprintf("set #%d to %s", c.TaskID, c.Status)
+	}
+	return fmt.Sprintf("%s #%d", c.Op, c.TaskID)
+}
+
+// Workflow is a saved plan of changes
+type Workflow struct {
+	ID        int           `json:"id"`
+	Name      string        `json:"name"`
+	State     WorkflowState `json:"state"`
+	Changes   []Change      `json:"changes"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// Outline numbers the changes one per line. Items are referred to by
+// these numbers, starting at 1.
+func (w Workflow) Outline() string {
+	var b strings.Builder
+	for i, c := range w.Changes {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, c)
+	}
+	return b.String()
+}
+
+// SwapItems exchanges the start times of items i and j the way SwapTasks
+// does, and their places in the list so it keeps reading in order. Both
+// must add or move a task.
+func (w *Workflow) SwapItems(i, j int) error {
+	a, err := w.item(i)
+	if err != nil {
+		return err
+	}
+	b, err := w.item(j)
+	if err != nil {
+		return err
+	}
+	if i == j {
+		return fmt.Errorf("cannot swap item %d with itself: %w", i, ErrValidation)
+	}
+	for n, c := range map[int]*Change{i: a, j: b} {
+		if c.Op != ChangeAdd && c.Op != ChangeMove {
+			return fmt.Errorf("item %d has no time slot to swap: %w", n, ErrValidation)
+		}
+	}
+	startA, startB := b.StartTime, a.StartTime
+	endA, endB := startA.Add(a.EndTime.Sub(a.StartTime)), startB.Add(b.EndTime.Sub(b.StartTime))
+	if startA.Before(endB) && startB.Before(endA) {
+		return fmt.Errorf("items %d and %d would overlap after the swap: %w", i, j, ErrConflict)
+	}
+	a.StartTime, a.EndTime = startA, endA
+	b.StartTime, b.EndTime = startB, endB
+	*a, *b = *b, *a
+	return nil
+}
+
+// ReplaceItem puts c in place of item i
+func (w *Workflow) ReplaceItem(i int, c Change) error {
+	item, err := w.item(i)
+	if err != nil {
+		return err
+	}
+	*item = c
+	return nil
+}
+
+// RemoveItem drops item i; the later items move up by one
+func (w *Workflow) RemoveItem(i int) error {
+	if _, err := w.item(i); err != nil {
+		return err
+	}
+	w.Changes = append(w.Changes[:i-1], w.Changes[i:]...)
+	return nil
+}
+
+// InsertItem adds c before item i, or at the end for i = 0
+func (w *Workflow) InsertItem(i int, c Change) error {
+	if i == 0 {
+		w.Changes = append(w.Changes, c)
+		return nil
+	}
+	if _, err := w.item(i); err != nil {
+		return err
+	}
+	w.Changes = slices.Insert(w.Changes, i-1, c)
+	return nil
+}
+
+func (w *Workflow) item(i int) (*Change, error) {
+	if i < 1 || i > len(w.Changes) {
+		return nil, fmt.Errorf("workflow %d has no item %d: %w", w.ID, i, ErrValidation)
+	}
+	return &w.Changes[i-1], nil
+}
+
+func createWorkflowsTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS workflows (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		state TEXT NOT NULL,
+		changes TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL
+	);
+	`
+	if _, err := db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create workflows table: %w", err)
+	}
+	return nil
+}
+
+// ProposeWorkflow saves changes as a workflow waiting for confirmation
+func (p *Planner) ProposeWorkflow(name string, changes []Change) (Workflow, error) {
+	if len(changes) == 0 {
+		return Workflow{}, fmt.Errorf("a workflow needs at least one change: %w", ErrValidation)
+	}
+	for i, c := range changes {
+		if err := c.Validate(); err != nil {
+			return Workflow{}, fmt.Errorf("change %d: %w", i+1, err)
+		}
+	}
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return Workflow{}, fmt.Errorf("failed to encode changes: %w", err)
+	}
+
+	now := time.Now()
+	res, err := p.db.Exec(`INSERT INTO workflows (name, state, changes, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+		name, WorkflowProposed, string(data), now, now)
+	if err != nil {
+		return Workflow{}, fmt.Errorf("failed to save workflow: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return Workflow{}, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	return Workflow{ID: int(id), Name: name, State: WorkflowProposed, Changes: changes, CreatedAt: now, UpdatedAt: now}, nil
+}
+
+// ReviseWorkflow saves new changes for a workflow still waiting for
+// confirmation
+func (p *Planner) ReviseWorkflow(w Workflow) (Workflow, error) {
+	if len(w.Changes) == 0 {
+		return Workflow{}, fmt.Errorf("a workflow needs at least one change: %w", ErrValidation)
+	}
+	for i, c := range w.Changes {
+		if err := c.Validate(); err != nil {
+			return Workflow{}, fmt.Errorf("change %d: %w", i+1, err)
+		}
+	}
+	data, err := json.Marshal(w.Changes)
+	if err != nil {
+		return Workflow{}, fmt.Errorf("failed to encode changes: %w", err)
+	}
+
+	now := time.Now()
+	res, err := p.db.Exec(`UPDATE workflows SET changes = ?, updated_at = ? WHERE id = ? AND state = ?`,
+		string(data), now, w.ID, WorkflowProposed)
+	if err != nil {
+		return Workflow{}, fmt.Errorf("failed to save workflow: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return Workflow{}, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		if _, err := p.GetWorkflow(w.ID); err != nil {
+			return Workflow{}, err
+		}
+		return Workflow{}, fmt.Errorf("workflow %d is not waiting for confirmation: %w", w.ID, ErrConflict)
+	}
+	return p.GetWorkflow(w.ID)
+}
+
+// LatestWorkflow returns the workflow waiting for confirmation that was
+// proposed or revised last, if that happened after since
+func (p *Planner) LatestWorkflow(since time.Time) (Workflow, error) {
+	row := p.db.QueryRow(`SELECT `+workflowColumns+` FROM workflows WHERE state = ? AND updated_at >= ? ORDER BY updated_at DESC, id DESC LIMIT 1`,
+		WorkflowProposed, since)
+	w, err := scanWorkflow(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Workflow{}, fmt.Errorf("workflow %w", ErrNotFound)
+	}
+	return w, err
+}
+
+// GetWorkflow finds a workflow by ID
+func (p *Planner) GetWorkflow(id int) (Workflow, error) {
+	return getWorkflow(p.db.QueryRow(`SELECT `+workflowColumns+` FROM workflows WHERE id = ?`, id), id)
+}
+
+// ListWorkflows returns the workflows in a state, or all of them for an
+// empty state, newest first
+func (p *Planner) ListWorkflows(state WorkflowState) ([]Workflow, error) {
+	query := `SELECT ` + workflowColumns + ` FROM workflows`
+	var args []any
+	if state != "" {
+		query += ` WHERE state = ?`
+		args = append(args, state)
+	}
+	rows, err := p.db.Query(query+` ORDER BY id DESC`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query workflows: %w", err)
+	}
+	defer rows.Close()
+
+	var list []Workflow
+	for rows.Next() {
+		w, err := scanWorkflow(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, w)
+	}
+	return list, nil
+}
+
+// ApplyWorkflow runs a proposed workflow's changes and marks it applied,
+// all in one transaction. It returns the IDs of the tasks it added.
+func (p *Planner) ApplyWorkflow(id int) ([]int, error) {
+	var added []int
+	err := p.inTx(func(tx *sql.Tx) error {
+		w, err := getWorkflow(tx.QueryRow(`SELECT `+workflowColumns+` FROM workflows WHERE id = ?`, id), id)
+		if err != nil {
+			return err
+		}
+		if w.State != WorkflowProposed {
+			return fmt.Errorf("workflow %d is already %s: %w", id, w.State, ErrConflict)
+		}
+
+		// Tasks completed together may wait on each other, but not on
+		// open tasks outside the workflow
+		var completing []int
+		for _, c := range w.Changes {
+			if c.Op == ChangeStatus && c.Status.Done() {
+				completing = append(completing, c.TaskID)
+			}
+		}
+		if err := checkBlockers(tx, completing); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for i, c := range w.Changes {
+			newID, err := applyChange(tx, c, now)
+			if err != nil {
+				return fmt.Errorf("change %d (%s): %w", i+1, c.Op, err)
+			}
+			if newID != 0 {
+				added = append(added, newID)
+			}
+		}
+		_, err = tx.Exec(`UPDATE workflows SET state = ?, updated_at = ? WHERE id = ?`, WorkflowApplied, now, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// DiscardWorkflow drops a proposed workflow without applying it
+func (p *Planner) DiscardWorkflow(id int) error {
+	res, err := p.db.Exec(`UPDATE workflows SET state = ?, updated_at = ? WHERE id = ? AND state = ?`,
+		WorkflowDiscarded, time.Now(), id, WorkflowProposed)
+	if err != nil {
+		return fmt.Errorf("failed to discard workflow: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		if _, err := p.GetWorkflow(id); err != nil {
+			return err
+		}
+		return fmt.Errorf("workflow %d is not waiting for confirmation: %w", id, ErrConflict)
+	}
+	return nil
+}
+
+// applyChange runs one change inside tx and returns the ID of an added task
+func applyChange(tx *sql.Tx, c Change, now time.Time) (int, error) {
+	if err := c.Validate(); err != nil {
+		return 0, err
+	}
+	var (
+		res sql.Result
+		err error
+	)
+	switch c.Op {
+	case ChangeAdd:
+		res, err = tx.Exec(`INSERT INTO tasks (title, description, start_time, end_time, status, reminded, updated_at) VALUES (?, ?, ?, ?, ?, 0, ?)`,
+			c.Title, c.Description, c.StartTime, c.EndTime, StatusPending, now)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert task: %w", err)
+		}
+		id, err := res.LastInsertId()
+		return int(id), err
+	case ChangeMove:
+		res, err = tx.Exec(`UPDATE tasks SET start_time = ?, end_time = ?, updated_at = ?, reminded = 0 WHERE id = ?`,
+			c.StartTime, c.EndTime, now, c.TaskID)
+	case ChangeStatus:
+		res, err = tx.Exec(`UPDATE tasks SET status = ?, updated_at = ? WHERE id = ?`, c.Status, now, c.TaskID)
+	case ChangeDelete:
+		if _, err := tx.Exec(`DELETE FROM external_links WHERE task_id = ?`, c.TaskID); err != nil {
+			return 0, fmt.Errorf("failed to delete links: %w", err)
+		}
+		res, err = tx.Exec(`DELETE FROM tasks WHERE id = ?`, c.TaskID)
+	}
+	if err != nil {
+		return 0, err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return 0, fmt.Errorf("task with ID %d %w", c.TaskID, ErrNotFound)
+	}
+	return 0, nil
+}
+
+const workflowColumns = `id, name, state, changes, created_at, updated_at`
+
+func getWorkflow(row *sql.Row, id int) (Workflow, error) {
+	w, err := scanWorkflow(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Workflow{}, fmt.Errorf("workflow %d %w", id, ErrNotFound)
+	}
+	return w, err
+}
+
+func scanWorkflow(row rowScanner) (Workflow, error) {
+	var w Workflow
+	var changes string
+	if err := row.Scan(&w.ID, &w.Name, &w.State, &changes, &w.CreatedAt, &w.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Workflow{}, err
+		}
+		return Workflow{}, fmt.Errorf("failed to scan workflow: %w", err)
+	}
+	if err := json.Unmarshal([]byte(changes), &w.Changes); err != nil {
+		return Workflow{}, fmt.Errorf("failed to decode changes: %w", err)
+	}
+	return w, nil
+}
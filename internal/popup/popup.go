@@ -0,0 +1,49 @@
+// Package popup asks for a single line of text, in the terminal when
+// there is one and otherwise in a native dialog, so a global hotkey can
+// capture a task without opening the TUI.
+package popup
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/gen2brain/beeep"
+)
+
+// ErrUnavailable means there is neither a terminal nor a dialog tool to
+// ask with
+var ErrUnavailable = errors.New("no terminal or dialog available")
+
+// Input asks for one line. An empty answer means the user cancelled.
+func Input(title, prompt string) (string, error) {
+	if Terminal() {
+		fmt.Fprintf(os.Stderr, "%s ", prompt)
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", err
+		}
+		return strings.TrimSpace(line), nil
+	}
+	return Dialog(title, prompt)
+}
+
+// Terminal reports whether stdin is an interactive terminal, as when the
+// hotkey opens a terminal window rather than running the command directly
+func Terminal() bool {
+	return term.IsTerminal(os.Stdin.Fd())
+}
+
+// Notify tells the outcome where the question was asked: on stderr in a
+// terminal, as a desktop notification otherwise
+func Notify(title, msg string) {
+	if Terminal() {
+		fmt.Fprintln(os.Stderr, msg)
+		return
+	}
+	_ = beeep.Notify(title, msg, "")
+}
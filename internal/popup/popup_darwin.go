@@ -0,0 +1,29 @@
+//go:build darwin
+
+package popup
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// Dialog shows a native single-line input dialog through AppleScript
+func Dialog(title, prompt string) (string, error) {
+	script := `display dialog ` + asQuote(prompt) + ` default answer "" with title ` + asQuote(title) + `
+return text returned of result`
+	out, err := exec.Command("osascript", "-e", script).Output()
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		// Cancel makes osascript fail with error -128
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func asQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
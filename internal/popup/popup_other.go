@@ -0,0 +1,32 @@
+//go:build !darwin && !windows
+
+package popup
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// Dialog shows a single-line input dialog with zenity (GNOME and most
+// desktops) or kdialog (KDE), whichever is installed
+func Dialog(title, prompt string) (string, error) {
+	var cmd *exec.Cmd
+	if path, err := exec.LookPath("zenity"); err == nil {
+		cmd = exec.Command(path, "--entry", "--title", title, "--text", prompt)
+	} else if path, err := exec.LookPath("kdialog"); err == nil {
+		cmd = exec.Command(path, "--title", title, "--inputbox", prompt)
+	} else {
+		return "", ErrUnavailable
+	}
+	out, err := cmd.Output()
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		// Both exit with 1 when the dialog is cancelled
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
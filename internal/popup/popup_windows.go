@@ -0,0 +1,28 @@
+//go:build windows
+
+package popup
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// Dialog shows a native single-line input dialog via PowerShell
+func Dialog(title, prompt string) (string, error) {
+	script := fmt.Sprintf(
+		"Add-Type -AssemblyName Microsoft.VisualBasic; [Microsoft.VisualBasic.Interaction]::InputBox('%s', '%s')",
+		psQuote(prompt), psQuote(title))
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func psQuote(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
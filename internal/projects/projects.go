@@ -0,0 +1,157 @@
+// Package projects applies the per-project defaults of the config to
+// tasks. A task's project is the value of its project field, the custom
+// field tag_icons looks up (project by default); it is unrelated to the
+// free-form tags of the tags table. The defaults are process-wide, set
+// once from the config.
+package projects
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"gomentum/internal/config"
+	"gomentum/internal/i18n"
+	"gomentum/internal/planner"
+)
+
+// Default is the defaults of one project, with its hours parsed
+type Default struct {
+	config.ProjectConfig
+	hours      bool
+	start, end int // Minutes after midnight
+}
+
+var (
+	mu       sync.RWMutex
+	field    = "project"
+	defaults = map[string]Default{} // Lower-case project -> defaults
+)
+
+// Configure sets the project field and the defaults per project. Invalid
+// colors and hours are logged and left out.
+func Configure(projectField string, cfg map[string]config.ProjectConfig) {
+	mu.Lock()
+	defer mu.Unlock()
+	if projectField = strings.TrimSpace(projectField); projectField != "" {
+		field = projectField
+	}
+	defaults = make(map[string]Default, len(cfg))
+	for project, c := range cfg {
+		d := Default{ProjectConfig: c}
+		if !planner.ValidColor(c.Color) {
+			slog.Warn("Invalid project color, ignoring it", "project", project, "color", c.Color)
+			d.Color = ""
+		}
+		if c.Hours != "" {
+			start, end, err := parseHours(c.Hours)
+			if err != nil {
+				slog.Warn("Invalid project hours, ignoring them", "project", project, "error", err)
+			} else {
+				d.hours, d.start, d.end = true, start, end
+			}
+		}
+		d.Duration, d.RemindBefore = max(d.Duration, 0), max(d.RemindBefore, 0)
+		defaults[strings.ToLower(strings.TrimSpace(project))] = d
+	}
+}
+
+// parseHours parses "HH:MM-HH:MM" into minutes after midnight
+func parseHours(s string) (int, int, error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("hours %q are not HH:MM-HH:MM", s)
+	}
+	start, err := time.Parse("15:04", strings.TrimSpace(from))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid start in hours %q: %w", s, err)
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(to))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid end in hours %q: %w", s, err)
+	}
+	if !end.After(start) {
+		return 0, 0, fmt.Errorf("hours %q end before they start", s)
+	}
+	return start.Hour()*60 + start.Minute(), end.Hour()*60 + end.Minute(), nil
+}
+
+// Field returns the custom field that holds a task's project
+func Field() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return field
+}
+
+// Of returns the project of t, or "" if it has none
+func Of(t planner.Task) string {
+	project, _ := t.FieldString(Field())
+	return strings.TrimSpace(project)
+}
+
+// Lookup returns the defaults of a project
+func Lookup(project string) (Default, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	d, ok := defaults[strings.ToLower(strings.TrimSpace(project))]
+	return d, ok
+}
+
+// MaxRemindBefore is the earliest any project has its tasks reminded, so
+// the reminder poll knows how far ahead to look
+func MaxRemindBefore() time.Duration {
+	mu.RLock()
+	defer mu.RUnlock()
+	var longest time.Duration
+	for _, d := range defaults {
+		longest = max(longest, d.RemindBefore)
+	}
+	return longest
+}
+
+// Apply gives t the defaults of its project: the color and reminder
+// offset unless it has its own, and the flexible flag if the project sets
+// one. It reports whether anything changed. The duration only applies to
+// tasks added without an end time, which the caller handles.
+func Apply(t *planner.Task) bool {
+	d, ok := Lookup(Of(*t))
+	if !ok {
+		return false
+	}
+	changed := false
+	if t.Color == "" && d.Color != "" {
+		t.Color, changed = d.Color, true
+	}
+	if _, set := t.Fields[planner.FieldRemindBefore]; !set && d.RemindBefore > 0 {
+		t.SetField(planner.FieldRemindBefore, d.RemindBefore.Minutes())
+		changed = true
+	}
+	if d.Flexible != nil && t.Flexible != *d.Flexible {
+		t.Flexible, changed = *d.Flexible, true
+	}
+	return changed
+}
+
+// Hours returns the part of the day containing day that tasks in the
+// project of t are confined to
+func Hours(t planner.Task, day time.Time) (start, end time.Time, ok bool) {
+	d, found := Lookup(Of(t))
+	if !found || !d.hours {
+		return time.Time{}, time.Time{}, false
+	}
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	return midnight.Add(time.Duration(d.start) * time.Minute), midnight.Add(time.Duration(d.end) * time.Minute), true
+}
+
+// Check warns when t lies outside the hours of its project, and returns
+// "" when it fits or its project has no hours
+func Check(t planner.Task) string {
+	start, end, ok := Hours(t, t.StartTime.Local())
+	if !ok || t.StartTime.IsZero() || (!t.StartTime.Before(start) && !t.EndTime.After(end)) {
+		return ""
+	}
+	d, _ := Lookup(Of(t))
+	return i18n.T("projects.outside_hours", t.Title, Of(t), d.Hours)
+}
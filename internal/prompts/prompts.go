@@ -0,0 +1,156 @@
+// Package prompts manages reusable prompt workflows. Each workflow is a
+// markdown file under ~/.gomentum/prompts whose name is the command that
+// runs it, e.g. triage-inbox.md runs as /triage-inbox in the TUI and is
+// offered to MCP clients as the prompt "triage-inbox".
+//
+// A file may start with a front matter block holding its description:
+//
+//	---
+//	description: Go through overdue tasks
+//	---
+//	Help me triage ... {{input}}
+//
+// {{input}} is replaced by the text typed after the command, and
+// {{input|fallback}} uses the fallback when nothing was typed.
+package prompts
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed defaults/*.md
+var defaultsFS embed.FS
+
+// Prompt is one reusable workflow
+type Prompt struct {
+	Name        string
+	Description string
+	Text        string
+}
+
+var placeholder = regexp.MustCompile(`\{\{input(?:\|([^}]*))?\}\}`)
+
+// Render fills in the text typed after the command. Without a
+// placeholder the input is appended as a new paragraph.
+func (p Prompt) Render(input string) string {
+	input = strings.TrimSpace(input)
+	if !placeholder.MatchString(p.Text) {
+		if input == "" {
+			return p.Text
+		}
+		return p.Text + "\n\n" + input
+	}
+	text := placeholder.ReplaceAllStringFunc(p.Text, func(m string) string {
+		if input != "" {
+			return input
+		}
+		return placeholder.FindStringSubmatch(m)[1]
+	})
+	return strings.TrimSpace(text)
+}
+
+// Dir returns the prompts directory inside the config directory
+func Dir(configDir string) string {
+	return filepath.Join(configDir, "prompts")
+}
+
+// Load reads every prompt in dir, sorted by name. The first time, when
+// dir does not exist yet, the bundled prompts are copied there so they
+// can be edited; deleted ones are not brought back.
+func Load(dir string) ([]Prompt, error) {
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		if err := install(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var list []Prompt
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read prompt: %w", err)
+		}
+		p, err := parse(strings.TrimSuffix(filepath.Base(f), ".md"), string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+		list = append(list, p)
+	}
+	return list, nil
+}
+
+// Find looks up a prompt by name
+func Find(list []Prompt, name string) (Prompt, bool) {
+	for _, p := range list {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Prompt{}, false
+}
+
+// Names lists the prompt names
+func Names(list []Prompt) []string {
+	names := make([]string, len(list))
+	for i, p := range list {
+		names[i] = p.Name
+	}
+	return names
+}
+
+func install(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create prompts directory: %w", err)
+	}
+	entries, err := defaultsFS.ReadDir("defaults")
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		data, err := defaultsFS.ReadFile("defaults/" + e.Name())
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, e.Name()), data, 0644); err != nil {
+			return fmt.Errorf("failed to install prompt: %w", err)
+		}
+	}
+	return nil
+}
+
+func parse(name, data string) (Prompt, error) {
+	p := Prompt{Name: name}
+	body := strings.ReplaceAll(data, "\r\n", "\n")
+	if rest, ok := strings.CutPrefix(body, "---\n"); ok {
+		front, text, found := strings.Cut(rest, "\n---\n")
+		if !found {
+			return p, errors.New("front matter is not closed with ---")
+		}
+		var meta struct {
+			Description string `yaml:"description"`
+		}
+		if err := yaml.Unmarshal([]byte(front), &meta); err != nil {
+			return p, fmt.Errorf("invalid front matter: %w", err)
+		}
+		p.Description = meta.Description
+		body = text
+	}
+	p.Text = strings.TrimSpace(body)
+	return p, nil
+}
@@ -0,0 +1,241 @@
+// Package redact keeps personal data away from a remote LLM. A Redactor
+// replaces emails, phone numbers and other configured patterns with
+// placeholders such as [EMAIL_1] and names with their aliases, and puts
+// the originals back into what the model writes. Placeholders stay the
+// same for the life of the Redactor, so the model can refer to them
+// across turns.
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"gomentum/internal/config"
+)
+
+// Builtin maps the names usable in agent.redact.mask to their patterns.
+// Phone numbers need a leading + or separators (or the shape of a Chinese
+// mobile number) so that dates, times and task IDs are left alone.
+var Builtin = map[string]string{
+	"email": `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`,
+	"phone": `\+\d[\d\s().-]{6,}\d|\(?\b\d{3}\)?[\s.-]?\d{3}[\s.-]\d{4}\b|\b1[3-9]\d{9}\b`,
+}
+
+type pattern struct {
+	kind string // Placeholder prefix, e.g. EMAIL
+	re   *regexp.Regexp
+}
+
+type alias struct {
+	real, alias string
+	re          *regexp.Regexp
+}
+
+// Redactor masks and restores personal data. It is safe for concurrent
+// use.
+type Redactor struct {
+	patterns []pattern
+	aliases  []alias // Longest name first, so it wins over names it contains
+
+	mu       sync.Mutex
+	values   map[string]string // Placeholder -> original
+	assigned map[string]string // Kind and original -> placeholder
+	counts   map[string]int    // Placeholders per kind
+}
+
+// New returns a Redactor for cfg, or nil when redaction is disabled
+func New(cfg config.RedactConfig) (*Redactor, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	r := &Redactor{
+		values:   map[string]string{},
+		assigned: map[string]string{},
+		counts:   map[string]int{},
+	}
+	for _, m := range cfg.Mask {
+		m = strings.TrimSpace(m)
+		if m == "" {
+			continue
+		}
+		expr, kind := m, "PII"
+		if b, ok := Builtin[strings.ToLower(m)]; ok {
+			expr, kind = b, strings.ToUpper(m)
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid agent.redact.mask pattern %q: %v", m, err)
+		}
+		r.patterns = append(r.patterns, pattern{kind: kind, re: re})
+	}
+	for real, as := range cfg.Aliases {
+		real, as = strings.TrimSpace(real), strings.TrimSpace(as)
+		if real == "" || as == "" {
+			return nil, fmt.Errorf("agent.redact.aliases needs a name and an alias, got %q: %q", real, as)
+		}
+		r.aliases = append(r.aliases, alias{real: real, alias: as, re: wordPattern(real)})
+	}
+	sort.Slice(r.aliases, func(i, j int) bool {
+		if len(r.aliases[i].real) != len(r.aliases[j].real) {
+			return len(r.aliases[i].real) > len(r.aliases[j].real)
+		}
+		return r.aliases[i].real < r.aliases[j].real
+	})
+	return r, nil
+}
+
+// wordPattern matches name regardless of case, as a whole word where it
+// starts or ends with a letter or digit. Names in scripts without spaces,
+// such as Chinese, match anywhere.
+func wordPattern(name string) *regexp.Regexp {
+	expr := regexp.QuoteMeta(name)
+	first, _ := utf8.DecodeRuneInString(name)
+	last, _ := utf8.DecodeLastRuneInString(name)
+	if first < utf8.RuneSelf && (unicode.IsLetter(first) || unicode.IsDigit(first)) {
+		expr = `\b` + expr
+	}
+	if last < utf8.RuneSelf && (unicode.IsLetter(last) || unicode.IsDigit(last)) {
+		expr += `\b`
+	}
+	return regexp.MustCompile(`(?i)` + expr)
+}
+
+// Remote reports whether llm sends data off this machine. The fake
+// provider and models served on a loopback address do not.
+func Remote(llm config.LLMConfig) bool {
+	if llm.Provider == "fake" {
+		return false
+	}
+	u, err := url.Parse(llm.BaseURL)
+	if err != nil || u.Hostname() == "" {
+		return true
+	}
+	if strings.EqualFold(u.Hostname(), "localhost") {
+		return false
+	}
+	ip := net.ParseIP(u.Hostname())
+	return ip == nil || !ip.IsLoopback()
+}
+
+// Redact replaces the names with their aliases and the masked patterns
+// with placeholders
+func (r *Redactor) Redact(s string) string {
+	if s == "" {
+		return s
+	}
+	for _, a := range r.aliases {
+		s = a.re.ReplaceAllLiteralString(s, a.alias)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.patterns {
+		s = p.re.ReplaceAllStringFunc(s, func(v string) string {
+			return r.placeholder(p.kind, v)
+		})
+	}
+	return s
+}
+
+// placeholder returns the placeholder of v, assigning the next one of its
+// kind on first sight. r.mu must be held.
+func (r *Redactor) placeholder(kind, v string) string {
+	key := kind + "\x00" + v
+	if ph, ok := r.assigned[key]; ok {
+		return ph
+	}
+	r.counts[kind]++
+	ph := fmt.Sprintf("[%s_%d]", kind, r.counts[kind])
+	r.assigned[key] = ph
+	r.values[ph] = v
+	return ph
+}
+
+// Restore puts the originals back in place of placeholders and aliases
+func (r *Redactor) Restore(s string) string {
+	return r.replacer(false).Replace(s)
+}
+
+// RestoreJSON is Restore for the JSON arguments of a tool call, with the
+// originals escaped as JSON string content
+func (r *Redactor) RestoreJSON(s string) string {
+	return r.replacer(true).Replace(s)
+}
+
+func (r *Redactor) replacer(escape bool) *strings.Replacer {
+	var pairs []string
+	add := func(from, to string) {
+		if escape {
+			b, _ := json.Marshal(to)
+			to = string(b[1 : len(b)-1])
+		}
+		pairs = append(pairs, from, to)
+	}
+	for _, a := range r.aliases {
+		add(a.alias, a.real)
+	}
+	r.mu.Lock()
+	for ph, v := range r.values {
+		add(ph, v)
+	}
+	r.mu.Unlock()
+	return strings.NewReplacer(pairs...)
+}
+
+// tokens lists what Restore replaces
+func (r *Redactor) tokens() []string {
+	var tokens []string
+	for _, a := range r.aliases {
+		tokens = append(tokens, a.alias)
+	}
+	r.mu.Lock()
+	for ph := range r.values {
+		tokens = append(tokens, ph)
+	}
+	r.mu.Unlock()
+	return tokens
+}
+
+// Stream restores streamed text on its way to emit. A chunk that ends in
+// the beginning of a placeholder or alias is held back until the next one
+// shows whether it is; flush emits whatever is left at the end.
+func (r *Redactor) Stream(emit func(string)) (write func(string), flush func()) {
+	var pending string
+	write = func(chunk string) {
+		pending += chunk
+		hold := partialSuffix(pending, r.tokens())
+		if out := r.Restore(pending[:len(pending)-hold]); out != "" {
+			emit(out)
+		}
+		pending = pending[len(pending)-hold:]
+	}
+	flush = func() {
+		if pending != "" {
+			emit(r.Restore(pending))
+			pending = ""
+		}
+	}
+	return write, flush
+}
+
+// partialSuffix returns the length of the longest end of s that is the
+// start, but not the whole, of one of tokens
+func partialSuffix(s string, tokens []string) int {
+	longest := 0
+	for _, t := range tokens {
+		for n := min(len(s), len(t)-1); n > longest; n-- {
+			if strings.HasPrefix(t, s[len(s)-n:]) {
+				longest = n
+				break
+			}
+		}
+	}
+	return longest
+}
@@ -0,0 +1,139 @@
+package reminder
+
+import (
+	"context"
+	"log/slog"
+	"net/mail"
+	"slices"
+	"time"
+
+	"gomentum/internal/config"
+	"gomentum/internal/i18n"
+	"gomentum/internal/mailer"
+	"gomentum/internal/planner"
+
+	"github.com/gen2brain/beeep"
+)
+
+// Reminder channels, as recorded in planner.Delivery
+const (
+	ChannelDesktop = "desktop"
+	ChannelEmail   = "email"
+	ChannelSound   = "sound"
+)
+
+// A failed channel is tried MaxAttempts times in all, waiting retryDelay
+// after the first failure and twice as long after each further one
+const (
+	MaxAttempts = 5
+	retryDelay  = 30 * time.Second
+)
+
+// channel sends a reminder one way
+type channel struct {
+	name string
+	send func(context.Context, planner.Task) error
+}
+
+// channels lists the ways a reminder for t goes out: every configured
+// channel, narrowed down by the first matching route
+func channels(cfg *config.Config, t planner.Task) []channel {
+	var chs []channel
+	if cfg.Reminders.Desktop {
+		chs = append(chs, channel{ChannelDesktop, notifyDesktop})
+	}
+	if len(cfg.Reminders.Email) > 0 {
+		chs = append(chs, channel{ChannelEmail, func(_ context.Context, t planner.Task) error {
+			return sendEmail(cfg.SMTP, cfg.Reminders.Email, t)
+		}})
+	}
+	if file := SoundFile(cfg.Reminders.Sound, t.Priority); file != "" {
+		// Playback runs in the background, so only starting it is tracked
+		chs = append(chs, channel{ChannelSound, func(ctx context.Context, _ planner.Task) error {
+			go func() {
+				if err := PlaySound(ctx, cfg.Reminders.Sound, file); err != nil {
+					slog.Error("Reminder sound failed", "file", file, "error", err)
+				}
+			}()
+			return nil
+		}})
+	}
+	if allowed, ok := route(cfg.Reminders.Routes, t); ok {
+		chs = slices.DeleteFunc(chs, func(ch channel) bool { return !slices.Contains(allowed, ch.name) })
+	}
+	return chs
+}
+
+// deliver sends the reminder for t on every channel it has not gone out
+// on yet. The task is marked reminded once every channel succeeded or
+// used up its attempts; until then it comes back on each poll and
+// failed channels are retried when their delay has passed.
+func deliver(ctx context.Context, p *planner.Planner, cfg *config.Config, t planner.Task, now time.Time, onFire func(planner.Task)) {
+	sent, err := p.Deliveries(t.ID, t.StartTime)
+	if err != nil {
+		slog.Error("Failed to load reminder deliveries", "task", t.ID, "error", err)
+		return
+	}
+	if len(sent) == 0 && onFire != nil {
+		onFire(t)
+	}
+
+	pending := false
+	for _, ch := range channels(cfg, t) {
+		d, ok := sent[ch.name]
+		if !ok {
+			d = planner.Delivery{TaskID: t.ID, Start: t.StartTime, Channel: ch.name}
+		}
+		if !d.DeliveredAt.IsZero() || d.Attempts >= MaxAttempts {
+			continue
+		}
+		if now.Before(d.NextAttempt) {
+			pending = true
+			continue
+		}
+
+		d.Attempts++
+		d.AttemptedAt = now
+		if err := ch.send(ctx, t); err != nil {
+			d.LastError = err.Error()
+			d.NextAttempt = now.Add(retryDelay << (d.Attempts - 1))
+			if d.Attempts < MaxAttempts {
+				pending = true
+				slog.Warn("Reminder failed, will retry", "task", t.ID, "channel", ch.name, "attempt", d.Attempts, "error", err)
+			} else {
+				slog.Error("Reminder failed, giving up", "task", t.ID, "channel", ch.name, "attempts", d.Attempts, "error", err)
+			}
+		} else {
+			d.DeliveredAt = now
+			d.LastError = ""
+		}
+		if err := p.RecordDelivery(d); err != nil {
+			slog.Error("Failed to record reminder delivery", "task", t.ID, "channel", ch.name, "error", err)
+		}
+	}
+
+	if !pending {
+		_ = p.MarkAsReminded(t.ID)
+	}
+}
+
+func notifyDesktop(_ context.Context, t planner.Task) error {
+	msg := i18n.T("notify.reminder_body", i18n.FormatTime(t.StartTime), t.Description)
+	return beeep.Notify(i18n.T("notify.reminder_title"), msg, "")
+}
+
+func sendEmail(smtp config.SMTPConfig, to []string, t planner.Task) error {
+	var addrs []*mail.Address
+	for _, a := range to {
+		addr, err := mail.ParseAddress(a)
+		if err != nil {
+			return err
+		}
+		addrs = append(addrs, addr)
+	}
+	return mailer.Send(smtp, mailer.Message{
+		To:      addrs,
+		Subject: i18n.T("notify.reminder_title") + ": " + t.Title,
+		Text:    i18n.T("notify.reminder_body", i18n.FormatTime(t.StartTime), t.Description),
+	})
+}
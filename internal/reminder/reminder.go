@@ -0,0 +1,52 @@
+package reminder
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"gomentum/internal/config"
+	"gomentum/internal/planner"
+	"gomentum/internal/projects"
+)
+
+// keepDeliveries is how long delivery records outlive their reminder
+const keepDeliveries = 30 * 24 * time.Hour
+
+// Run polls the planner for due tasks and sends reminders on the
+// configured channels until ctx is cancelled. onFire, if not nil, is
+// called once for every reminder so the UI can announce it as well.
+func Run(ctx context.Context, p *planner.Planner, cfg *config.Config, onFire func(planner.Task)) {
+	checkRoutes(cfg)
+	if err := p.PurgeDeliveries(time.Now().Add(-keepDeliveries)); err != nil {
+		slog.Warn("Failed to purge old reminder deliveries", "error", err)
+	}
+
+	// Check every 10 seconds for better responsiveness
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Find tasks that are due now (or past due). Tasks are reminded at
+		// their start unless their project asks for earlier, so look ahead
+		// as far as the earliest project and skip what is not due yet.
+		tasks, err := p.GetUpcomingTasks(projects.MaxRemindBefore())
+		if err != nil {
+			continue
+		}
+
+		now := time.Now()
+		for _, t := range tasks {
+			if t.RemindAt().After(now) {
+				continue
+			}
+			deliver(ctx, p, cfg, t, now, onFire)
+		}
+	}
+}
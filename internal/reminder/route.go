@@ -0,0 +1,60 @@
+package reminder
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+
+	"gomentum/internal/config"
+	"gomentum/internal/planner"
+)
+
+// channelNames lists every channel a route can name
+var channelNames = []string{ChannelDesktop, ChannelEmail, ChannelSound}
+
+// route returns the channels the first matching route allows for t, and
+// false if no route matches
+func route(routes []config.RouteConfig, t planner.Task) ([]string, bool) {
+	for _, r := range routes {
+		if matches(r, t) {
+			return r.Channels, true
+		}
+	}
+	return nil, false
+}
+
+func matches(r config.RouteConfig, t planner.Task) bool {
+	if len(r.Priorities) > 0 {
+		priority := t.Priority
+		if priority == "" {
+			priority = planner.PriorityMedium
+		}
+		if !slices.Contains(r.Priorities, priority) {
+			return false
+		}
+	}
+	if r.Field != "" {
+		key, want, _ := strings.Cut(r.Field, "=")
+		got, ok := t.FieldString(strings.TrimSpace(key))
+		if !ok || !strings.EqualFold(got, strings.TrimSpace(want)) {
+			return false
+		}
+	}
+	return !r.Deadline || !t.Deadline.IsZero()
+}
+
+// checkRoutes warns about routes naming channels that do not exist or are
+// not set up, since their reminders would quietly go nowhere
+func checkRoutes(cfg *config.Config) {
+	for i, r := range cfg.Reminders.Routes {
+		for _, ch := range r.Channels {
+			switch {
+			case !slices.Contains(channelNames, ch):
+				slog.Warn("Unknown reminder channel in route", "route", i+1, "channel", ch, "known", channelNames)
+			case ch == ChannelDesktop && !cfg.Reminders.Desktop,
+				ch == ChannelEmail && len(cfg.Reminders.Email) == 0:
+				slog.Warn("Reminder route uses a channel that is turned off", "route", i+1, "channel", ch)
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gomentum/internal/config"
+	"gomentum/internal/planner"
+)
+
+// ErrNoPlayer is returned when no audio player command is available
+var ErrNoPlayer = errors.New("no audio player found; set reminders.sound.player")
+
+// maxSound stops players that hang, e.g. on a file that never ends
+const maxSound = 30 * time.Second
+
+// SoundFile returns the audio file for a reminder of the given priority,
+// or "" when sounds are muted or none is configured
+func SoundFile(cfg config.SoundConfig, priority string) string {
+	if cfg.Mute || cfg.Volume <= 0 {
+		return ""
+	}
+	if priority == "" {
+		priority = planner.PriorityMedium
+	}
+	file, ok := cfg.Files[priority]
+	if !ok {
+		file = cfg.Files["default"]
+	}
+	if rest, ok := strings.CutPrefix(file, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			file = filepath.Join(home, rest)
+		}
+	}
+	return file
+}
+
+// PlaySound plays file at the configured volume and waits until it ends
+func PlaySound(ctx context.Context, cfg config.SoundConfig, file string) error {
+	if _, err := os.Stat(file); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, maxSound)
+	defer cancel()
+
+	if fields := strings.Fields(cfg.Player); len(fields) > 0 {
+		return exec.CommandContext(ctx, fields[0], append(fields[1:], file)...).Run()
+	}
+	cmd, err := playerCommand(ctx, file, min(cfg.Volume, 100))
+	if err != nil {
+		return err
+	}
+	return cmd.Run()
+}
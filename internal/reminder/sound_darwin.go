@@ -0,0 +1,15 @@
+//go:build darwin
+
+package reminder
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+)
+
+// afplay ships with macOS; its volume is 0 to 1
+func playerCommand(ctx context.Context, file string, volume int) (*exec.Cmd, error) {
+	v := strconv.FormatFloat(float64(volume)/100, 'f', 2, 64)
+	return exec.CommandContext(ctx, "afplay", "-v", v, file), nil
+}
@@ -0,0 +1,29 @@
+//go:build linux
+
+package reminder
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+)
+
+// playerCommand uses the first player found: PulseAudio, PipeWire, FFmpeg
+// and finally ALSA, which has no volume option
+func playerCommand(ctx context.Context, file string, volume int) (*exec.Cmd, error) {
+	players := []struct {
+		name string
+		args []string
+	}{
+		{"paplay", []string{"--volume=" + strconv.Itoa(65536*volume/100), file}},
+		{"pw-play", []string{"--volume=" + strconv.FormatFloat(float64(volume)/100, 'f', 2, 64), file}},
+		{"ffplay", []string{"-nodisp", "-autoexit", "-loglevel", "quiet", "-volume", strconv.Itoa(volume), file}},
+		{"aplay", []string{"-q", file}},
+	}
+	for _, p := range players {
+		if path, err := exec.LookPath(p.name); err == nil {
+			return exec.CommandContext(ctx, path, p.args...), nil
+		}
+	}
+	return nil, ErrNoPlayer
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !windows
+
+package reminder
+
+import (
+	"context"
+	"os/exec"
+)
+
+func playerCommand(context.Context, string, int) (*exec.Cmd, error) {
+	return nil, ErrNoPlayer
+}
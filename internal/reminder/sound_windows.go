@@ -0,0 +1,24 @@
+//go:build windows
+
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// playerCommand uses the WPF media player through PowerShell, which plays
+// WAV, MP3 and WMA and supports a volume
+func playerCommand(ctx context.Context, file string, volume int) (*exec.Cmd, error) {
+	script := fmt.Sprintf(`Add-Type -AssemblyName PresentationCore
+$p = New-Object System.Windows.Media.MediaPlayer
+$p.Volume = %.2f
+$p.Open([uri]'%s')
+while (-not $p.NaturalDuration.HasTimeSpan) { Start-Sleep -Milliseconds 100 }
+$p.Play()
+Start-Sleep -Milliseconds ([int]$p.NaturalDuration.TimeSpan.TotalMilliseconds + 200)`,
+		float64(volume)/100, strings.ReplaceAll(file, "'", "''"))
+	return exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", script), nil
+}
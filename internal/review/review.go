@@ -0,0 +1,172 @@
+// Package review finds stale tasks (pending work nobody has touched for a
+// while) and proposes what to do with each of them.
+package review
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"gomentum/internal/config"
+	"gomentum/internal/i18n"
+	"gomentum/internal/planner"
+	"gomentum/internal/schedule"
+
+	"github.com/gen2brain/beeep"
+)
+
+// Triage actions
+const (
+	ActionDelete     = "delete"
+	ActionReschedule = "reschedule"
+	ActionBacklog    = "backlog" // Keep the task but stop it from blocking time
+)
+
+// horizon is how far ahead a stale task may be rescheduled
+const horizon = 14 * 24 * time.Hour
+
+// Proposal is the suggested fate of one stale task
+type Proposal struct {
+	TaskID    int            `json:"task_id"`
+	Title     string         `json:"title"`
+	Untouched int            `json:"untouched_days"`
+	Action    string         `json:"action"`
+	To        *schedule.Slot `json:"to,omitempty"` // Set for reschedule
+	Reason    string         `json:"reason"`
+}
+
+// Stale returns the pending tasks whose time has passed and that have not
+// been edited for at least age, oldest first
+func Stale(tasks []planner.Task, now time.Time, age time.Duration) []planner.Task {
+	var stale []planner.Task
+	for _, t := range tasks {
+		if t.Status != planner.StatusPending || t.UpdatedAt.IsZero() || !t.EndTime.Before(now) {
+			continue
+		}
+		if now.Sub(t.UpdatedAt) >= age {
+			stale = append(stale, t)
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool { return stale[i].UpdatedAt.Before(stale[j].UpdatedAt) })
+	return stale
+}
+
+// Triage proposes an action for every stale task. Tasks untouched for three
+// times age are proposed for deletion, as are fixed tasks, whose time
+// cannot be moved. Flexible tasks move to the next free slot of the same
+// length, or to the backlog if there is none. Nothing is changed.
+func Triage(tasks []planner.Task, now time.Time, age time.Duration) []Proposal {
+	plan := append([]planner.Task(nil), tasks...)
+
+	var proposals []Proposal
+	for _, t := range Stale(tasks, now, age) {
+		untouched := now.Sub(t.UpdatedAt)
+		p := Proposal{TaskID: t.ID, Title: t.Title, Untouched: int(untouched.Hours() / 24)}
+
+		switch {
+		case untouched >= 3*age:
+			p.Action = ActionDelete
+			p.Reason = fmt.Sprintf("untouched for %d days, probably no longer relevant", p.Untouched)
+		case !t.Flexible:
+			p.Action = ActionDelete
+			p.Reason = "fixed appointment that is over"
+		default:
+			if slot, ok := nextSlot(plan, t, now); ok {
+				p.Action = ActionReschedule
+				p.To = &slot
+				p.Reason = "missed its slot; the next free one fits"
+				// Later proposals must not reuse the slot
+				moved := t
+				moved.ID = -1
+				moved.StartTime, moved.EndTime = slot.Start, slot.End
+				plan = append(plan, moved)
+			} else {
+				p.Action = ActionBacklog
+				p.Reason = fmt.Sprintf("no free slot within %d days", int(horizon.Hours()/24))
+			}
+		}
+		proposals = append(proposals, p)
+	}
+	return proposals
+}
+
+// nextSlot finds the earliest free slot after now that fits t
+func nextSlot(tasks []planner.Task, t planner.Task, now time.Time) (schedule.Slot, bool) {
+	length := t.EndTime.Sub(t.StartTime)
+	for _, s := range schedule.FreeSlots(tasks, now, now.Add(horizon), t.ID) {
+		if s.Duration() >= length {
+			return schedule.Slot{Start: s.Start, End: s.Start.Add(length)}, true
+		}
+	}
+	return schedule.Slot{}, false
+}
+
+// Run notifies about stale tasks once a week at the configured weekday and
+// time until ctx is cancelled. The user then asks the agent to triage them.
+func Run(ctx context.Context, cfg config.ReviewConfig, p *planner.Planner) {
+	age := time.Duration(cfg.StaleDays) * 24 * time.Hour
+	if age <= 0 {
+		age = 14 * 24 * time.Hour
+	}
+	weekday, clock, err := parseWhen(cfg.Weekday, cfg.Time)
+	if err != nil {
+		slog.Warn("Invalid review schedule, using Monday 09:00", "error", err)
+		weekday, clock = time.Monday, 9*time.Hour
+	}
+
+	for {
+		next := nextRun(time.Now(), weekday, clock)
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		tasks, err := p.ListTasks()
+		if err != nil {
+			slog.Error("Stale task review failed", "error", err)
+			continue
+		}
+		stale := Stale(tasks, time.Now(), age)
+		slog.Info("Stale task review finished", "stale", len(stale))
+		if len(stale) == 0 {
+			continue
+		}
+		if err := beeep.Notify(i18n.T("notify.review_title"), i18n.T("notify.review_body", len(stale)), ""); err != nil {
+			slog.Error("System notification failed", "error", err)
+		}
+	}
+}
+
+func parseWhen(day, clock string) (time.Weekday, time.Duration, error) {
+	weekday := time.Weekday(-1)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(d.String(), day) {
+			weekday = d
+		}
+	}
+	if weekday < 0 {
+		return 0, 0, fmt.Errorf("invalid weekday %q", day)
+	}
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time of day %q: %w", clock, err)
+	}
+	return weekday, time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// nextRun returns the first weekday at clock strictly after now
+func nextRun(now time.Time, weekday time.Weekday, clock time.Duration) time.Time {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	days := (int(weekday) - int(now.Weekday()) + 7) % 7
+	next := midnight.AddDate(0, 0, days).Add(clock)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 7)
+	}
+	return next
+}
@@ -0,0 +1,333 @@
+// Package rpc implements a line-delimited JSON-RPC 2.0 protocol over stdio
+// so editor plugins can drive Gomentum without scraping TUI output.
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"gomentum/internal/agent"
+	"gomentum/internal/config"
+	"gomentum/internal/hooks"
+	"gomentum/internal/planner"
+)
+
+// Standard JSON-RPC 2.0 error codes
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// Request is a single JSON-RPC request. Requests without an ID are
+// notifications and get no response.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is written for every request that carries an ID
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Notification is pushed to the client unprompted, e.g. when a timer ends
+type Notification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// Error is a JSON-RPC error object
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	// Data carries the planner error code, e.g. {"code": "not_found"}
+	Data interface{} `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func errorf(code int, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// plannerError maps a planner error to invalid params or an internal
+// error, keeping its kind in Data
+func plannerError(err error) *Error {
+	code := planner.Code(err)
+	e := errorf(CodeInternalError, "%v", err)
+	if code != planner.CodeInternal {
+		e.Code = CodeInvalidParams
+	}
+	e.Data = map[string]string{"code": code}
+	return e
+}
+
+// AgentFactory builds the agent on first use, so clients that never call
+// "chat" work without an LLM configuration.
+type AgentFactory func() (agent.Agent, error)
+
+// Server answers requests read from an input stream
+type Server struct {
+	planner  *planner.Planner
+	focus    config.FocusConfig
+	hooks    *hooks.Engine
+	newAgent AgentFactory
+	agent    agent.Agent
+
+	outMu sync.Mutex
+	enc   *json.Encoder
+
+	timerMu sync.Mutex
+	timer   *timer
+}
+
+// NewServer creates a new stdio RPC server. newAgent may be nil, in which
+// case "chat" is unavailable.
+func NewServer(p *planner.Planner, focus config.FocusConfig, h *hooks.Engine, newAgent AgentFactory) *Server {
+	return &Server{planner: p, focus: focus, hooks: h, newAgent: newAgent}
+}
+
+// Serve reads one request per line from r and writes responses to w until
+// r is exhausted or ctx is cancelled.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.enc = json.NewEncoder(w)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			break
+		}
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req Request
+		if err := json.Unmarshal(line, &req); err != nil {
+			s.write(Response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: errorf(CodeParseError, "parse error: %v", err)})
+			continue
+		}
+		if req.JSONRPC != "2.0" || req.Method == "" {
+			s.write(Response{JSONRPC: "2.0", ID: idOrNull(req.ID), Error: errorf(CodeInvalidRequest, "invalid request")})
+			continue
+		}
+
+		result, rpcErr := s.dispatch(ctx, req)
+		if len(req.ID) == 0 {
+			continue
+		}
+		resp := Response{JSONRPC: "2.0", ID: req.ID, Result: result}
+		if rpcErr != nil {
+			resp.Result = nil
+			resp.Error = rpcErr
+		} else if result == nil {
+			resp.Result = struct{}{}
+		}
+		s.write(resp)
+	}
+
+	s.stopTimer()
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read request: %w", err)
+	}
+	return nil
+}
+
+func (s *Server) write(v interface{}) {
+	s.outMu.Lock()
+	defer s.outMu.Unlock()
+	if err := s.enc.Encode(v); err != nil {
+		slog.Error("Failed to write RPC message", "error", err)
+	}
+}
+
+func (s *Server) notify(method string, params interface{}) {
+	s.write(Notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func idOrNull(id json.RawMessage) json.RawMessage {
+	if len(id) == 0 {
+		return json.RawMessage("null")
+	}
+	return id
+}
+
+func decodeParams(raw json.RawMessage, v interface{}) *Error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return errorf(CodeInvalidParams, "invalid params: %v", err)
+	}
+	return nil
+}
+
+func (s *Server) dispatch(ctx context.Context, req Request) (interface{}, *Error) {
+	switch req.Method {
+	case "ping":
+		return "pong", nil
+	case "tasks.list":
+		return s.listTasks()
+	case "tasks.today":
+		return s.todayTasks()
+	case "tasks.add":
+		return s.addTask(req.Params)
+	case "tasks.complete":
+		return s.completeTask(req.Params)
+	case "timer.start":
+		return s.startTimer(req.Params)
+	case "timer.stop":
+		return s.stopTimerRPC()
+	case "timer.status":
+		return s.timerStatus(), nil
+	case "chat":
+		return s.chat(ctx, req.Params)
+	default:
+		return nil, errorf(CodeMethodNotFound, "method not found: %s", req.Method)
+	}
+}
+
+func (s *Server) listTasks() (interface{}, *Error) {
+	tasks, err := s.planner.ListTasks()
+	if err != nil {
+		return nil, errorf(CodeInternalError, "%v", err)
+	}
+	if tasks == nil {
+		tasks = []planner.Task{}
+	}
+	return tasks, nil
+}
+
+// todayTasks returns the tasks overlapping the current local day
+func (s *Server) todayTasks() (interface{}, *Error) {
+	tasks, err := s.planner.ListTasks()
+	if err != nil {
+		return nil, errorf(CodeInternalError, "%v", err)
+	}
+	now := time.Now()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	dayEnd := dayStart.AddDate(0, 0, 1)
+
+	today := []planner.Task{}
+	for _, t := range tasks {
+		if t.StartTime.Before(dayEnd) && t.EndTime.After(dayStart) {
+			today = append(today, t)
+		}
+	}
+	return today, nil
+}
+
+type addParams struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	StartTime   time.Time `json:"start_time"`
+	EndTime     time.Time `json:"end_time"`
+	// Duration in minutes, used when end_time is omitted
+	Duration     int  `json:"duration"`
+	AllowOverlap bool `json:"allow_overlap"`
+}
+
+func (s *Server) addTask(raw json.RawMessage) (interface{}, *Error) {
+	var params addParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	if params.Title == "" || params.StartTime.IsZero() {
+		return nil, errorf(CodeInvalidParams, "title and start_time are required")
+	}
+	if params.EndTime.IsZero() {
+		if params.Duration <= 0 {
+			return nil, errorf(CodeInvalidParams, "end_time or duration is required")
+		}
+		params.EndTime = params.StartTime.Add(time.Duration(params.Duration) * time.Minute)
+	}
+	if !params.EndTime.After(params.StartTime) {
+		return nil, errorf(CodeInvalidParams, "end_time must be after start_time")
+	}
+
+	if !params.AllowOverlap {
+		conflict, err := s.planner.CheckOverlap(params.StartTime, params.EndTime, 0)
+		if err != nil {
+			return nil, errorf(CodeInternalError, "%v", err)
+		}
+		if conflict != nil {
+			e := errorf(CodeInvalidParams, "time conflict with task %d (%s)", conflict.ID, conflict.Title)
+			e.Data = map[string]string{"code": planner.CodeConflict}
+			return nil, e
+		}
+	}
+
+	task, err := s.planner.AddTask(params.Title, params.Description, params.StartTime, params.EndTime)
+	if err != nil {
+		return nil, errorf(CodeInternalError, "%v", err)
+	}
+	return task, nil
+}
+
+type idParams struct {
+	ID int `json:"id"`
+}
+
+func (s *Server) completeTask(raw json.RawMessage) (interface{}, *Error) {
+	var params idParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	task, err := s.planner.GetTask(params.ID)
+	if err != nil {
+		return nil, plannerError(err)
+	}
+	task.Status = planner.StatusCompleted
+	if err := s.planner.UpdateTask(task); err != nil {
+		return nil, errorf(CodeInternalError, "%v", err)
+	}
+	return task, nil
+}
+
+type chatParams struct {
+	Message string `json:"message"`
+}
+
+func (s *Server) chat(ctx context.Context, raw json.RawMessage) (interface{}, *Error) {
+	var params chatParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	if params.Message == "" {
+		return nil, errorf(CodeInvalidParams, "message is required")
+	}
+	if s.agent == nil {
+		if s.newAgent == nil {
+			return nil, errorf(CodeInternalError, "chat is not available")
+		}
+		ag, err := s.newAgent()
+		if err != nil {
+			return nil, errorf(CodeInternalError, "failed to initialize agent: %v", err)
+		}
+		s.agent = ag
+	}
+
+	resp, err := s.agent.Chat(ctx, params.Message, nil)
+	if err != nil {
+		return nil, errorf(CodeInternalError, "%v", err)
+	}
+	return map[string]interface{}{"reply": resp.Text, "actions": resp.Actions}, nil
+}
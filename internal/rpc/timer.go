@@ -0,0 +1,141 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"gomentum/internal/focus"
+	"gomentum/internal/hooks"
+	"gomentum/internal/planner"
+)
+
+// timer tracks focused work on a task for the lifetime of the RPC session
+type timer struct {
+	Task    planner.Task
+	Started time.Time
+	Minutes int // Planned length; 0 means open-ended
+
+	done    *time.Timer
+	restore func() // Undoes Do Not Disturb, if it was enabled
+}
+
+// TimerStatus is returned by the timer.* methods
+type TimerStatus struct {
+	Running bool    `json:"running"`
+	TaskID  int     `json:"task_id,omitempty"`
+	Title   string  `json:"title,omitempty"`
+	Started string  `json:"started,omitempty"`
+	Elapsed float64 `json:"elapsed_minutes"`
+	Minutes int     `json:"minutes,omitempty"`
+	DND     bool    `json:"dnd,omitempty"`
+}
+
+func (t *timer) status() TimerStatus {
+	if t == nil {
+		return TimerStatus{}
+	}
+	return TimerStatus{
+		Running: true,
+		TaskID:  t.Task.ID,
+		Title:   t.Task.Title,
+		Started: t.Started.Format(time.RFC3339),
+		Elapsed: time.Since(t.Started).Minutes(),
+		Minutes: t.Minutes,
+		DND:     t.restore != nil,
+	}
+}
+
+type timerParams struct {
+	ID      int `json:"id"`
+	Minutes int `json:"minutes"`
+	// DeepWork forces Do Not Disturb on or off, overriding focus.keywords
+	DeepWork *bool `json:"deep_work"`
+}
+
+// startTimer marks the task as in progress and starts timing it. When
+// minutes is set, a "timer.finished" notification is sent once it elapses
+// and the session ends.
+func (s *Server) startTimer(raw json.RawMessage) (interface{}, *Error) {
+	var params timerParams
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+	task, err := s.planner.GetTask(params.ID)
+	if err != nil {
+		return nil, plannerError(err)
+	}
+	if !task.Status.Done() {
+		task.Status = planner.StatusInProgress
+		if err := s.planner.UpdateTask(task); err != nil {
+			return nil, errorf(CodeInternalError, "%v", err)
+		}
+	}
+
+	s.stopTimer()
+
+	s.timerMu.Lock()
+	defer s.timerMu.Unlock()
+	s.hooks.Fire(context.Background(), hooks.PreFocus, hooks.Context{Task: &task, Minutes: params.Minutes})
+
+	t := &timer{Task: task, Started: time.Now(), Minutes: params.Minutes}
+	deepWork := focus.IsDeepWork(s.focus, task)
+	if params.DeepWork != nil {
+		deepWork = *params.DeepWork
+	}
+	if s.focus.DND && deepWork {
+		t.restore = focus.Start(context.Background(), s.focus)
+	}
+	if params.Minutes > 0 {
+		t.done = time.AfterFunc(time.Duration(params.Minutes)*time.Minute, func() {
+			if st := s.finishTimer(t); st.Running {
+				st.Running = false
+				s.notify("timer.finished", st)
+			}
+		})
+	}
+	s.timer = t
+	return t.status(), nil
+}
+
+func (s *Server) stopTimerRPC() (interface{}, *Error) {
+	st := s.stopTimer()
+	if !st.Running {
+		return nil, errorf(CodeInvalidRequest, "no timer is running")
+	}
+	st.Running = false
+	return st, nil
+}
+
+// stopTimer cancels the running timer and returns its final status
+func (s *Server) stopTimer() TimerStatus {
+	s.timerMu.Lock()
+	t := s.timer
+	s.timerMu.Unlock()
+	return s.finishTimer(t)
+}
+
+// finishTimer ends t if it is still the running timer
+func (s *Server) finishTimer(t *timer) TimerStatus {
+	s.timerMu.Lock()
+	defer s.timerMu.Unlock()
+	if t == nil || s.timer != t {
+		return TimerStatus{}
+	}
+	if t.done != nil {
+		t.done.Stop()
+	}
+	st := t.status()
+	if t.restore != nil {
+		t.restore()
+	}
+	s.hooks.Fire(context.Background(), hooks.PostFocus, hooks.Context{Task: &t.Task, Minutes: t.Minutes})
+	s.timer = nil
+	return st
+}
+
+func (s *Server) timerStatus() TimerStatus {
+	s.timerMu.Lock()
+	defer s.timerMu.Unlock()
+	return s.timer.status()
+}
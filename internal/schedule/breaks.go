@@ -0,0 +1,112 @@
+package schedule
+
+import (
+	"sort"
+	"time"
+
+	"gomentum/internal/config"
+	"gomentum/internal/i18n"
+	"gomentum/internal/planner"
+)
+
+// microSession is how long work must run without a real break before
+// eye-rest micro-breaks are planned inside it
+const microSession = 2 * time.Hour
+
+// breaks is the break policy, set by Configure
+var breaks = config.BreaksConfig{
+	After:       90 * time.Minute,
+	Length:      15 * time.Minute,
+	MicroEvery:  30 * time.Minute,
+	MicroLength: 2 * time.Minute,
+}
+
+func configureBreaks(cfg config.BreaksConfig) {
+	if cfg.After > 0 {
+		breaks.After = cfg.After
+	}
+	if cfg.Length > 0 {
+		breaks.Length = cfg.Length
+	}
+	breaks.MicroEvery = max(cfg.MicroEvery, 0)
+	if cfg.MicroLength > 0 {
+		breaks.MicroLength = cfg.MicroLength
+	}
+}
+
+// Work reports whether a task counts as planned work against the daily
+// capacity: it blocks its time and is not a system task such as a break
+func Work(t planner.Task) bool {
+	return Busy(t) && t.System() == ""
+}
+
+// workRuns merges the work among tasks into runs, stretches without a
+// gap long enough to rest in, oldest first
+func workRuns(tasks []planner.Task) []Slot {
+	var work []Slot
+	for _, t := range tasks {
+		if Work(t) && t.EndTime.After(t.StartTime) {
+			work = append(work, Slot{Start: t.StartTime, End: t.EndTime})
+		}
+	}
+	sort.Slice(work, func(i, j int) bool { return work[i].Start.Before(work[j].Start) })
+
+	var runs []Slot
+	for _, w := range work {
+		if n := len(runs); n > 0 && w.Start.Sub(runs[n-1].End) < breaks.Length {
+			if w.End.After(runs[n-1].End) {
+				runs[n-1].End = w.End
+			}
+			continue
+		}
+		runs = append(runs, w)
+	}
+	return runs
+}
+
+// PlanBreaks proposes the breaks for the work starting in [from, to): a
+// break after every run of work at least breaks.After long that is
+// followed by more work the same day, and eye-rest micro-breaks during
+// runs of two hours or more. Breaks starting before from are left out.
+// Runs too long to rest in, twice breaks.After or more, are returned as
+// warnings, since nothing is moved to make room.
+func PlanBreaks(tasks []planner.Task, from, to time.Time) ([]planner.Task, []string) {
+	var planned []planner.Task
+	var warnings []string
+	add := func(kind, title string, start time.Time, length time.Duration) {
+		if start.Before(from) || !start.Before(to) {
+			return
+		}
+		t := planner.Task{
+			Title:     title,
+			StartTime: start,
+			EndTime:   start.Add(length),
+			Status:    planner.StatusPending,
+			Flexible:  true,
+		}
+		t.SetField(planner.FieldSystem, kind)
+		planned = append(planned, t)
+	}
+
+	runs := workRuns(tasks)
+	for i, r := range runs {
+		if r.End.Before(from) || !r.Start.Before(to) {
+			continue
+		}
+		length := r.Duration()
+		next := i+1 < len(runs) && dayKey(runs[i+1].Start) == dayKey(r.End)
+		if length >= breaks.After && next {
+			add(planner.SystemBreak, i18n.T("breaks.break"), r.End, breaks.Length)
+		}
+		if length >= 2*breaks.After && !r.Start.Before(from) {
+			warnings = append(warnings, i18n.T("breaks.no_room", i18n.FormatDateTime(r.Start), FormatHours(length)))
+		}
+		if breaks.MicroEvery > 0 && length >= microSession {
+			for at := r.Start.Add(breaks.MicroEvery); at.Add(breaks.MicroLength).Before(r.End); at = at.Add(breaks.MicroEvery) {
+				add(planner.SystemMicroBreak, i18n.T("breaks.micro"), at, breaks.MicroLength)
+			}
+		}
+	}
+	sort.SliceStable(planned, func(i, j int) bool { return planned[i].StartTime.Before(planned[j].StartTime) })
+	return planned, warnings
+}
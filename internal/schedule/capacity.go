@@ -0,0 +1,167 @@
+package schedule
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"gomentum/internal/i18n"
+	"gomentum/internal/planner"
+)
+
+// capacity is how much planned work fits into one day
+var capacity = 7 * time.Hour
+
+// DailyCapacity returns how much planned work fits into one day
+func DailyCapacity() time.Duration {
+	return capacity
+}
+
+// DayLoad is the planned work on one day
+type DayLoad struct {
+	Date     time.Time     `json:"date"`
+	Planned  time.Duration `json:"-"`
+	Capacity time.Duration `json:"-"`
+	// Hours mirror the durations for JSON consumers
+	PlannedHours  float64 `json:"planned_hours"`
+	CapacityHours float64 `json:"capacity_hours"`
+}
+
+// Over returns how far the day exceeds its capacity (0 if it does not)
+func (d DayLoad) Over() time.Duration {
+	if d.Planned > d.Capacity {
+		return d.Planned - d.Capacity
+	}
+	return 0
+}
+
+// Warning describes an overloaded day, e.g. "Wednesday is 3h over capacity"
+func (d DayLoad) Warning() string {
+	return i18n.T("capacity.over", i18n.WeekdayName(d.Date.Weekday()), FormatHours(d.Over()))
+}
+
+// FormatHours renders a duration as hours with at most one decimal, e.g. "2.5h"
+func FormatHours(d time.Duration) string {
+	h := math.Round(d.Hours()*10) / 10
+	return strconv.FormatFloat(h, 'f', -1, 64) + "h"
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// dayKey identifies the local calendar day of t. Times read from the
+// database may carry a different *Location, so time.Time is no map key.
+func dayKey(t time.Time) string {
+	return t.Local().Format("2006-01-02")
+}
+
+// Loads returns the planned work per workday for days days starting with
+// the day of from. Tasks count towards the day they start on; breaks and
+// other system tasks do not count.
+func Loads(tasks []planner.Task, from time.Time, days int) []DayLoad {
+	first := startOfDay(from)
+	byDay := make(map[string]time.Duration)
+	for _, t := range tasks {
+		if Work(t) {
+			byDay[dayKey(t.StartTime)] += t.EndTime.Sub(t.StartTime)
+		}
+	}
+
+	var loads []DayLoad
+	for i := 0; i < days; i++ {
+		day := first.AddDate(0, 0, i)
+		if !IsWorkday(day) {
+			continue
+		}
+		planned := byDay[dayKey(day)]
+		loads = append(loads, DayLoad{
+			Date:          day,
+			Planned:       planned,
+			Capacity:      capacity,
+			PlannedHours:  math.Round(planned.Hours()*10) / 10,
+			CapacityHours: capacity.Hours(),
+		})
+	}
+	return loads
+}
+
+// Overloaded returns the days within the horizon that exceed capacity
+func Overloaded(tasks []planner.Task, from time.Time, days int) []DayLoad {
+	var over []DayLoad
+	for _, d := range Loads(tasks, from, days) {
+		if d.Over() > 0 {
+			over = append(over, d)
+		}
+	}
+	return over
+}
+
+// Move is a suggested new time for a task
+type Move struct {
+	TaskID int       `json:"task_id"`
+	Title  string    `json:"title"`
+	From   time.Time `json:"from"`
+	To     Slot      `json:"to"`
+}
+
+// Rebalance suggests moves that bring overloaded days back under capacity.
+// Only flexible tasks for which movable returns true are considered; the
+// latest tasks of a day move first, to the earliest day with both spare
+// capacity and a free slot within the hours of their tag. Nothing is
+// changed.
+func Rebalance(tasks []planner.Task, from time.Time, days int, movable func(planner.Task) bool) []Move {
+	tasks = append([]planner.Task(nil), tasks...)
+	horizon := startOfDay(from).AddDate(0, 0, days)
+
+	var moves []Move
+	for _, day := range Overloaded(tasks, from, days) {
+		over := day.Over()
+
+		// Candidates on this day, latest first
+		var candidates []int
+		for i, t := range tasks {
+			if dayKey(t.StartTime) == dayKey(day.Date) && Work(t) && t.StartTime.After(from) && t.Flexible && movable(t) {
+				candidates = append(candidates, i)
+			}
+		}
+		sort.Slice(candidates, func(a, b int) bool {
+			return tasks[candidates[a]].StartTime.After(tasks[candidates[b]].StartTime)
+		})
+
+		for _, i := range candidates {
+			if over <= 0 {
+				break
+			}
+			t := tasks[i]
+			length := t.EndTime.Sub(t.StartTime)
+			slot, ok := findSlot(tasks, t, day.Date, from, horizon)
+			if !ok {
+				continue
+			}
+			moves = append(moves, Move{TaskID: t.ID, Title: t.Title, From: t.StartTime, To: slot})
+			tasks[i].StartTime, tasks[i].EndTime = slot.Start, slot.End
+			over -= length
+		}
+	}
+	return moves
+}
+
+// findSlot finds the earliest slot for t on a day other than skip that
+// stays within capacity and the hours of its tag
+func findSlot(tasks []planner.Task, t planner.Task, skip, from, to time.Time) (Slot, bool) {
+	length := t.EndTime.Sub(t.StartTime)
+	loads := make(map[string]time.Duration)
+	for _, d := range Loads(tasks, from, int(to.Sub(startOfDay(from)).Hours()/24)+1) {
+		loads[dayKey(d.Date)] = d.Planned
+	}
+	for _, s := range within(t, FreeSlots(tasks, from, to, 0)) {
+		day := dayKey(s.Start)
+		if day == dayKey(skip) || s.Duration() < length || loads[day]+length > capacity {
+			continue
+		}
+		return Slot{Start: s.Start, End: s.Start.Add(length)}, true
+	}
+	return Slot{}, false
+}
@@ -0,0 +1,85 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gomentum/internal/planner"
+)
+
+// Bump makes room for new work of the given priority in slot. Every task
+// overlapping slot must be flexible, movable and of lower priority; each is
+// re-placed into the earliest free slot of the same length after its
+// original start, within horizon and the hours of its tag. The returned moves are a proposal; nothing is changed.
+func Bump(tasks []planner.Task, slot Slot, priority string, horizon time.Time, movable func(planner.Task) bool) ([]Move, error) {
+	rank := planner.PriorityRank(priority)
+
+	var bumped []planner.Task
+	var blockers []string
+	for _, t := range tasks {
+		if !Busy(t) || !t.StartTime.Before(slot.End) || !t.EndTime.After(slot.Start) {
+			continue
+		}
+		if !t.Flexible {
+			blockers = append(blockers, fmt.Sprintf("%q (ID %d, fixed)", t.Title, t.ID))
+			continue
+		}
+		if !movable(t) || planner.PriorityRank(t.Priority) >= rank {
+			blockers = append(blockers, fmt.Sprintf("%q (ID %d, priority %s)", t.Title, t.ID, priorityName(t.Priority)))
+			continue
+		}
+		bumped = append(bumped, t)
+	}
+	if len(blockers) > 0 {
+		return nil, fmt.Errorf("the slot is taken by tasks that cannot be bumped: %s", strings.Join(blockers, ", "))
+	}
+	sort.Slice(bumped, func(i, j int) bool { return bumped[i].StartTime.Before(bumped[j].StartTime) })
+
+	// Working copy: bumped tasks leave, the new work arrives
+	var plan []planner.Task
+	isBumped := make(map[int]bool, len(bumped))
+	for _, t := range bumped {
+		isBumped[t.ID] = true
+	}
+	for _, t := range tasks {
+		if !isBumped[t.ID] {
+			plan = append(plan, t)
+		}
+	}
+	plan = append(plan, planner.Task{ID: -1, StartTime: slot.Start, EndTime: slot.End, Status: planner.StatusPending})
+
+	var moves []Move
+	for _, t := range bumped {
+		length := t.EndTime.Sub(t.StartTime)
+		from := t.StartTime
+		if slot.End.After(from) {
+			from = slot.End
+		}
+		placed := false
+		for _, free := range within(t, FreeSlots(plan, from, horizon, 0)) {
+			if free.Duration() < length {
+				continue
+			}
+			to := Slot{Start: free.Start, End: free.Start.Add(length)}
+			moves = append(moves, Move{TaskID: t.ID, Title: t.Title, From: t.StartTime, To: to})
+			moved := t
+			moved.StartTime, moved.EndTime = to.Start, to.End
+			plan = append(plan, moved)
+			placed = true
+			break
+		}
+		if !placed {
+			return nil, fmt.Errorf("no free slot left for %q (ID %d) before %s", t.Title, t.ID, horizon.Format(time.RFC3339))
+		}
+	}
+	return moves, nil
+}
+
+func priorityName(p string) string {
+	if p == "" {
+		return planner.PriorityMedium
+	}
+	return p
+}
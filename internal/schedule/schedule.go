@@ -0,0 +1,241 @@
+// Package schedule computes free time within working hours and fits work
+// into it. The working hours are process-wide, set once from the config.
+package schedule
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"gomentum/internal/config"
+	"gomentum/internal/planner"
+	"gomentum/internal/projects"
+)
+
+// Slot is a span of time
+type Slot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Duration returns the length of the slot
+func (s Slot) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// dayStart and dayEnd are minutes after midnight
+var (
+	dayStart = 9 * 60
+	dayEnd   = 18 * 60
+	weekends = false
+)
+
+// Configure sets the working hours used by every function in this package
+func Configure(cfg config.ScheduleConfig) {
+	start, err1 := parseClock(cfg.DayStart)
+	end, err2 := parseClock(cfg.DayEnd)
+	if err1 != nil || err2 != nil || end <= start {
+		slog.Warn("Invalid working hours, using 09:00-18:00", "day_start", cfg.DayStart, "day_end", cfg.DayEnd)
+		start, end = 9*60, 18*60
+	}
+	dayStart, dayEnd, weekends = start, end, cfg.Weekends
+	configureBreaks(cfg.Breaks)
+	configureTravel(cfg.Travel)
+	if cfg.DailyCapacity > 0 {
+		capacity = time.Duration(cfg.DailyCapacity * float64(time.Hour))
+	}
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q: %w", s, err)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// IsWorkday reports whether work is scheduled on the day of t
+func IsWorkday(t time.Time) bool {
+	if weekends {
+		return true
+	}
+	wd := t.Weekday()
+	return wd != time.Saturday && wd != time.Sunday
+}
+
+// WorkingHours returns the working window of the day containing t
+func WorkingHours(t time.Time) Slot {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return Slot{
+		Start: midnight.Add(time.Duration(dayStart) * time.Minute),
+		End:   midnight.Add(time.Duration(dayEnd) * time.Minute),
+	}
+}
+
+// Windows returns the working hours between from and to
+func Windows(from, to time.Time) []Slot {
+	var windows []Slot
+	first := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	for day := first; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !IsWorkday(day) {
+			continue
+		}
+		w := WorkingHours(day)
+		if w.Start.Before(from) {
+			w.Start = from
+		}
+		if w.End.After(to) {
+			w.End = to
+		}
+		if w.End.After(w.Start) {
+			windows = append(windows, w)
+		}
+	}
+	return windows
+}
+
+// Busy reports whether a task blocks its time. Done and backlog
+// tasks do not.
+func Busy(t planner.Task) bool {
+	return t.Status.Active()
+}
+
+// FreeSlots returns the working time between from and to not taken by
+// tasks. The task with ID exclude (e.g. the one being moved) is ignored.
+func FreeSlots(tasks []planner.Task, from, to time.Time, exclude int) []Slot {
+	var busy []Slot
+	for _, t := range tasks {
+		if t.ID == exclude || !Busy(t) || !t.StartTime.Before(to) || !t.EndTime.After(from) {
+			continue
+		}
+		busy = append(busy, Slot{Start: t.StartTime, End: t.EndTime})
+	}
+	sort.Slice(busy, func(i, j int) bool { return busy[i].Start.Before(busy[j].Start) })
+
+	var free []Slot
+	for _, w := range Windows(from, to) {
+		cursor := w.Start
+		for _, b := range busy {
+			if !b.End.After(cursor) || !b.Start.Before(w.End) {
+				continue
+			}
+			if b.Start.After(cursor) {
+				free = append(free, Slot{Start: cursor, End: b.Start})
+			}
+			cursor = b.End
+		}
+		if w.End.After(cursor) {
+			free = append(free, Slot{Start: cursor, End: w.End})
+		}
+	}
+	return free
+}
+
+// within narrows slots, which each lie in one day, to the hours the
+// project of t confines it to
+func within(t planner.Task, slots []Slot) []Slot {
+	var fit []Slot
+	for _, s := range slots {
+		start, end, ok := projects.Hours(t, s.Start)
+		if !ok {
+			return slots
+		}
+		if s.Start.After(start) {
+			start = s.Start
+		}
+		if s.End.Before(end) {
+			end = s.End
+		}
+		if end.After(start) {
+			fit = append(fit, Slot{Start: start, End: end})
+		}
+	}
+	return fit
+}
+
+// Largest returns the longest slot, or a zero Slot if there are none
+func Largest(slots []Slot) Slot {
+	var best Slot
+	for _, s := range slots {
+		if s.Duration() > best.Duration() {
+			best = s
+		}
+	}
+	return best
+}
+
+// Split fills slots in order with sessions adding up to total. Sessions
+// are at least minChunk long (except a shorter final remainder) and at
+// most maxChunk if maxChunk > 0.
+func Split(total time.Duration, slots []Slot, minChunk, maxChunk time.Duration) ([]Slot, error) {
+	var sessions []Slot
+	remaining := total
+	for _, s := range slots {
+		if remaining <= 0 {
+			break
+		}
+		for cursor := s.Start; remaining > 0; {
+			length := s.End.Sub(cursor)
+			if maxChunk > 0 && length > maxChunk {
+				length = maxChunk
+			}
+			if length > remaining {
+				length = remaining
+			}
+			if length <= 0 || (length < minChunk && length < remaining) {
+				break
+			}
+			sessions = append(sessions, Slot{Start: cursor, End: cursor.Add(length)})
+			remaining -= length
+			cursor = cursor.Add(length)
+		}
+	}
+	if remaining > 0 {
+		return nil, fmt.Errorf("not enough free time: %s left unscheduled", remaining.Round(time.Minute))
+	}
+	return sessions, nil
+}
+
+// Backward books sessions adding up to total as late as possible in slots,
+// i.e. working back from a deadline. Sessions are returned in chronological
+// order. If the slots cannot hold total, the error says how much is missing.
+func Backward(total time.Duration, slots []Slot, minChunk, maxChunk time.Duration) ([]Slot, error) {
+	var sessions []Slot
+	remaining := total
+	for i := len(slots) - 1; i >= 0 && remaining > 0; i-- {
+		s := slots[i]
+		for cursor := s.End; remaining > 0; {
+			length := cursor.Sub(s.Start)
+			if maxChunk > 0 && length > maxChunk {
+				length = maxChunk
+			}
+			if length > remaining {
+				length = remaining
+			}
+			if length <= 0 || (length < minChunk && length < remaining) {
+				break
+			}
+			sessions = append(sessions, Slot{Start: cursor.Add(-length), End: cursor})
+			remaining -= length
+			cursor = cursor.Add(-length)
+		}
+	}
+	if remaining > 0 {
+		return nil, fmt.Errorf("not enough free time: %s of %s missing", remaining.Round(time.Minute), total.Round(time.Minute))
+	}
+
+	for i, j := 0, len(sessions)-1; i < j; i, j = i+1, j-1 {
+		sessions[i], sessions[j] = sessions[j], sessions[i]
+	}
+	return sessions, nil
+}
+
+// Total returns the combined length of slots
+func Total(slots []Slot) time.Duration {
+	var d time.Duration
+	for _, s := range slots {
+		d += s.Duration()
+	}
+	return d
+}
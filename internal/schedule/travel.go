@@ -0,0 +1,107 @@
+package schedule
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"gomentum/internal/config"
+	"gomentum/internal/i18n"
+	"gomentum/internal/planner"
+)
+
+// travel is the travel time policy, set by Configure. Route locations are
+// lower-cased.
+var travel config.TravelConfig
+
+func configureTravel(cfg config.TravelConfig) {
+	travel = config.TravelConfig{Default: max(cfg.Default, 0), Buffers: cfg.Buffers}
+	for _, r := range cfg.Routes {
+		r.From, r.To = normalizeLocation(r.From), normalizeLocation(r.To)
+		if r.From != "" && r.To != "" && r.Time > 0 {
+			travel.Routes = append(travel.Routes, r)
+		}
+	}
+}
+
+func normalizeLocation(loc string) string {
+	return strings.ToLower(strings.TrimSpace(loc))
+}
+
+// TravelConfigured reports whether any travel time is known
+func TravelConfigured() bool {
+	return travel.Default > 0 || len(travel.Routes) > 0
+}
+
+// TravelBuffers reports whether travel tasks are inserted automatically
+func TravelBuffers() bool {
+	return travel.Buffers && TravelConfigured()
+}
+
+// TravelTime estimates how long it takes to get from one location to
+// another: the configured route in either direction, else the default.
+// The same place, or an unknown one, takes no time.
+func TravelTime(from, to string) time.Duration {
+	from, to = normalizeLocation(from), normalizeLocation(to)
+	if from == "" || to == "" || from == to {
+		return 0
+	}
+	for _, r := range travel.Routes {
+		if (r.From == from && r.To == to) || (r.From == to && r.To == from) {
+			return r.Time
+		}
+	}
+	return travel.Default
+}
+
+// PlanTravel proposes travel buffers for the located tasks starting in
+// [from, to): one ending as each task starts, as long as the trip from the
+// previous located task of the same day. Where the gap between the two is
+// shorter, the buffer fills it and a warning tells how much time is
+// missing. Nothing is moved.
+func PlanTravel(tasks []planner.Task, from, to time.Time) ([]planner.Task, []string) {
+	var located []planner.Task
+	for _, t := range tasks {
+		if Work(t) && t.Location() != "" {
+			located = append(located, t)
+		}
+	}
+	sort.Slice(located, func(i, j int) bool { return located[i].StartTime.Before(located[j].StartTime) })
+
+	var buffers []planner.Task
+	var warnings []string
+	for i := 1; i < len(located); i++ {
+		prev, next := located[i-1], located[i]
+		if next.StartTime.Before(from) || !next.StartTime.Before(to) || dayKey(prev.EndTime) != dayKey(next.StartTime) {
+			continue
+		}
+		need := TravelTime(prev.Location(), next.Location())
+		if need == 0 {
+			continue
+		}
+		start := next.StartTime.Add(-need)
+		if gap := next.StartTime.Sub(prev.EndTime); gap < need {
+			warnings = append(warnings, i18n.T("travel.short", i18n.FormatDateTime(next.StartTime),
+				formatMinutes(max(gap, 0)), prev.Location(), next.Location(), formatMinutes(need)))
+			start = prev.EndTime
+		}
+		if !next.StartTime.After(start) {
+			continue
+		}
+		b := planner.Task{
+			Title:     i18n.T("travel.title", prev.Location(), next.Location()),
+			StartTime: start,
+			EndTime:   next.StartTime,
+			Status:    planner.StatusPending,
+			Flexible:  true,
+		}
+		b.SetField(planner.FieldSystem, planner.SystemTravel)
+		b.SetField(planner.FieldSystemFor, next.ID)
+		buffers = append(buffers, b)
+	}
+	return buffers, warnings
+}
+
+func formatMinutes(d time.Duration) string {
+	return i18n.T("travel.minutes", int(d.Round(time.Minute).Minutes()))
+}
@@ -0,0 +1,230 @@
+// Package search finds tasks by meaning rather than by exact words, e.g.
+// "that thing about the dentist". Tasks are embedded with the model of
+// the embeddings config and compared with the query by cosine similarity.
+// Vectors are cached in the database, so only new and edited tasks are
+// embedded again. A remote embeddings endpoint gets tasks and queries
+// masked by agent.redact, like a remote chat model.
+package search
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gomentum/internal/config"
+	"gomentum/internal/embed"
+	"gomentum/internal/planner"
+	"gomentum/internal/redact"
+)
+
+// DefaultLimit is how many results Search returns unless told otherwise
+const DefaultLimit = 10
+
+// batchSize bounds the texts sent to the embedder at once
+const batchSize = 64
+
+// Results scoring below minScore, or below relScore times the best one,
+// are left out: an embedding always finds a nearest task, related or not
+const (
+	minScore = 0.15
+	relScore = 0.6
+)
+
+// Result is a task found by Search
+type Result struct {
+	planner.Task
+	Score float64 `json:"score"` // Cosine similarity to the query, at most 1
+}
+
+// Searcher runs semantic searches over the tasks of a planner
+type Searcher struct {
+	planner  *planner.Planner
+	embedder embed.Embedder
+	redactor *redact.Redactor // Masks texts sent to a remote endpoint, if set
+}
+
+// New creates a searcher for the embeddings config, or returns nil when
+// semantic search is off. rc is the redaction applied when the endpoint
+// is remote.
+func New(cfg config.EmbeddingsConfig, rc config.RedactConfig, p *planner.Planner) (*Searcher, error) {
+	e, err := embed.New(cfg)
+	if err != nil || e == nil {
+		return nil, err
+	}
+	s := &Searcher{planner: p, embedder: e}
+	if remote(cfg) {
+		if s.redactor, err = redact.New(rc); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// remote reports whether the embeddings provider of cfg is reached over
+// the network rather than on this machine
+func remote(cfg config.EmbeddingsConfig) bool {
+	switch cfg.Provider {
+	case embed.ProviderOllama:
+		return cfg.BaseURL != "" && redact.Remote(config.LLMConfig{BaseURL: cfg.BaseURL})
+	case embed.ProviderOpenAI:
+		return redact.Remote(config.LLMConfig{BaseURL: cfg.BaseURL})
+	}
+	return false
+}
+
+// text is what is sent to the embedder for s, masked for a remote one
+func (s *Searcher) text(t string) string {
+	if s.redactor == nil {
+		return t
+	}
+	return s.redactor.Redact(t)
+}
+
+// Search returns the tasks closest in meaning to query, best first.
+// Finished tasks are left out unless includeDone is set, and a repeating
+// task is only found as its next occurrence.
+func (s *Searcher) Search(ctx context.Context, query string, limit int, includeDone bool) ([]Result, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("%w: query is empty", planner.ErrValidation)
+	}
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	tasks, err := s.planner.ListTasks()
+	if err != nil {
+		return nil, err
+	}
+	tasks = slices.DeleteFunc(tasks, func(t planner.Task) bool {
+		return t.System() != "" || (!includeDone && t.Status.Done())
+	})
+	tasks = nextOccurrences(tasks, time.Now())
+	vectors, err := s.vectors(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+	q, err := s.embedder.Embed(ctx, []string{s.text(query)})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	for _, t := range tasks {
+		if score := embed.Cosine(q[0], vectors[t.ID]); score >= minScore {
+			results = append(results, Result{Task: t, Score: score})
+		}
+	}
+	slices.SortStableFunc(results, func(a, b Result) int {
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		}
+		return 0
+	})
+	if len(results) > 0 {
+		best := results[0].Score
+		results = slices.DeleteFunc(results, func(r Result) bool { return r.Score < relScore*best })
+	}
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// nextOccurrences keeps one task per series: the first occurrence that
+// has not ended by now, or else the last one. Tasks in no series are kept
+// as they are, and the order is kept.
+func nextOccurrences(tasks []planner.Task, now time.Time) []planner.Task {
+	keep := make(map[int]planner.Task) // Series -> the occurrence kept
+	for _, t := range tasks {
+		series := t.Series()
+		if series == 0 {
+			continue
+		}
+		kept, ok := keep[series]
+		switch {
+		case !ok:
+		case kept.EndTime.After(now):
+			if !t.EndTime.After(now) || !t.StartTime.Before(kept.StartTime) {
+				continue
+			}
+		case !t.EndTime.After(now) && !t.StartTime.After(kept.StartTime):
+			continue
+		}
+		keep[series] = t
+	}
+	return slices.DeleteFunc(tasks, func(t planner.Task) bool {
+		series := t.Series()
+		return series != 0 && keep[series].ID != t.ID
+	})
+}
+
+// vectors returns the vector of each task, embedding those that have none
+// from the current model or were edited since
+func (s *Searcher) vectors(ctx context.Context, tasks []planner.Task) (map[int][]float32, error) {
+	model := s.embedder.Model()
+	stored, err := s.planner.Embeddings(model)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int][]float32, len(tasks))
+	var stale []planner.Embedding
+	var texts []string
+	for _, t := range tasks {
+		text := Text(t)
+		digest := digestOf(text)
+		if s.redactor != nil {
+			// Vectors of masked texts are kept apart from plain ones
+			digest = digestOf("redacted\n" + text)
+		}
+		if e, ok := stored[t.ID]; ok && e.Digest == digest {
+			out[t.ID] = e.Vector
+			continue
+		}
+		stale = append(stale, planner.Embedding{TaskID: t.ID, Digest: digest})
+		texts = append(texts, s.text(text))
+	}
+
+	for start := 0; start < len(stale); start += batchSize {
+		end := min(start+batchSize, len(stale))
+		vecs, err := s.embedder.Embed(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range vecs {
+			stale[start+i].Vector = v
+			out[stale[start+i].TaskID] = v
+		}
+		if err := s.planner.SaveEmbeddings(model, stale[start:end]); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// Text is what is embedded of a task: its title, description, tags and
+// location
+func Text(t planner.Task) string {
+	parts := []string{t.Title}
+	if t.Description != "" {
+		parts = append(parts, t.Description)
+	}
+	if len(t.Tags) > 0 {
+		parts = append(parts, strings.Join(t.Tags, " "))
+	}
+	if loc, ok := t.FieldString(planner.FieldLocation); ok && loc != "" {
+		parts = append(parts, loc)
+	}
+	return strings.Join(parts, "\n")
+}
+
+func digestOf(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"gomentum/internal/planner"
+)
+
+// maxCaptureBody bounds the size of a /capture request
+const maxCaptureBody = 64 << 10
+
+// capture is a page sent to /capture by a browser extension or bookmarklet
+type capture struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+	Note  string `json:"note"`
+}
+
+// allowCORS lets pages on any origin call the endpoint. Requests are
+// authenticated by token rather than cookies, so this exposes nothing.
+func allowCORS(w http.ResponseWriter) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+}
+
+// checkToken responds with an error and returns false unless r is
+// authorized
+func (s *Server) checkToken(w http.ResponseWriter, r *http.Request) bool {
+	if s.cfg.Token == "" {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "this endpoint is disabled; set server.token to enable it"})
+		return false
+	}
+	if !s.authorized(r) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid or missing token"})
+		return false
+	}
+	return true
+}
+
+func (s *Server) handleCapturePreflight(w http.ResponseWriter, r *http.Request) {
+	allowCORS(w)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleCapture adds a page to the inbox as a backlog task, with the link
+// in its url field and the note as its description
+func (s *Server) handleCapture(w http.ResponseWriter, r *http.Request) {
+	allowCORS(w)
+	if !s.checkToken(w, r) {
+		return
+	}
+
+	c, err := readCapture(w, r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	now := time.Now()
+	task := planner.Task{
+		Title:       c.Title,
+		Description: c.Note,
+		StartTime:   now,
+		EndTime:     now,
+		Status:      planner.StatusBacklog,
+	}
+	task.SetField(planner.FieldURL, c.URL)
+	ids, err := s.planner.AddTasks([]planner.Task{task})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	task, err = s.planner.GetTask(ids[0])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, task)
+}
+
+// readCapture decodes a JSON or form body and checks the link. A missing
+// title falls back to the link itself.
+func readCapture(w http.ResponseWriter, r *http.Request) (capture, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxCaptureBody)
+	var c capture
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/json" {
+		if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+			return capture{}, fmt.Errorf("%w: invalid JSON: %v", planner.ErrValidation, err)
+		}
+	} else {
+		if err := r.ParseForm(); err != nil {
+			return capture{}, fmt.Errorf("%w: invalid form: %v", planner.ErrValidation, err)
+		}
+		c = capture{URL: r.PostForm.Get("url"), Title: r.PostForm.Get("title"), Note: r.PostForm.Get("note")}
+	}
+
+	c.URL, c.Title, c.Note = strings.TrimSpace(c.URL), strings.TrimSpace(c.Title), strings.TrimSpace(c.Note)
+	u, err := url.Parse(c.URL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return capture{}, fmt.Errorf("%w: url must be an http or https link", planner.ErrValidation)
+	}
+	if c.Title == "" {
+		c.Title = c.URL
+	}
+	return c, nil
+}
@@ -0,0 +1,264 @@
+package server
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gomentum/internal/i18n"
+	"gomentum/internal/planner"
+	"gomentum/internal/schedule"
+)
+
+// The feeds list the upcoming tasks and a summary per day, in Atom at
+// /feed.atom and RSS 2.0 at /feed.rss, for feed readers and automation
+// platforms. They need the server token, which feed readers can only pass
+// as ?token=.
+
+const (
+	defaultFeedDays = 7
+	maxFeedDays     = 31
+)
+
+// feedEntry is one item of a feed, either a task or a day summary
+type feedEntry struct {
+	ID      string
+	Title   string
+	Link    string
+	Content string
+	Updated time.Time
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    []atomLink  `xml:"link"`
+	Content atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link,omitempty"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	ID          string `xml:",chardata"`
+}
+
+func (s *Server) handleAtomFeed(w http.ResponseWriter, r *http.Request) {
+	entries, ok := s.feedEntries(w, r)
+	if !ok {
+		return
+	}
+	feed := atomFeed{
+		ID:      "urn:gomentum:feed",
+		Title:   i18n.T("feed.title"),
+		Updated: feedUpdated(entries).Format(time.RFC3339),
+		Link:    []atomLink{{Href: publicBase(s.cfg) + "/feed.atom", Rel: "self"}},
+	}
+	for _, e := range entries {
+		entry := atomEntry{
+			ID:      e.ID,
+			Title:   e.Title,
+			Updated: e.Updated.Format(time.RFC3339),
+			Content: atomContent{Type: "text", Body: e.Content},
+		}
+		if e.Link != "" {
+			entry.Link = []atomLink{{Href: e.Link}}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	writeFeed(w, "application/atom+xml", feed)
+}
+
+func (s *Server) handleRSSFeed(w http.ResponseWriter, r *http.Request) {
+	entries, ok := s.feedEntries(w, r)
+	if !ok {
+		return
+	}
+	feed := rssFeed{Version: "2.0", Channel: rssChannel{
+		Title:         i18n.T("feed.title"),
+		Link:          publicBase(s.cfg),
+		Description:   i18n.T("feed.title"),
+		LastBuildDate: feedUpdated(entries).Format(time.RFC1123Z),
+	}}
+	for _, e := range entries {
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       e.Title,
+			Link:        e.Link,
+			GUID:        rssGUID{ID: e.ID},
+			PubDate:     e.Updated.Format(time.RFC1123Z),
+			Description: e.Content,
+		})
+	}
+	writeFeed(w, "application/rss+xml", feed)
+}
+
+// feedEntries checks the feed token and lists the entries for the ?days=
+// (7 by default) starting today. It reports false once it has responded
+// with an error.
+func (s *Server) feedEntries(w http.ResponseWriter, r *http.Request) ([]feedEntry, bool) {
+	if s.cfg.FeedToken == "" {
+		http.Error(w, "Feeds are disabled; set server.feed_token to enable them", http.StatusNotFound)
+		return nil, false
+	}
+	// Feed readers can only keep the token in the URL, which is why it
+	// grants nothing else
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		token = r.URL.Query().Get("token")
+	}
+	if !sameToken(token, s.cfg.FeedToken) {
+		http.Error(w, "Invalid or missing token", http.StatusUnauthorized)
+		return nil, false
+	}
+	days := defaultFeedDays
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxFeedDays {
+			http.Error(w, fmt.Sprintf("days must be between 1 and %d", maxFeedDays), http.StatusBadRequest)
+			return nil, false
+		}
+		days = n
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	tasks, err := s.planner.QueryTasks(planner.Filter{From: today, To: today.AddDate(0, 0, days)})
+	if err != nil {
+		slog.Error("Failed to render feed", "error", err)
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return nil, false
+	}
+	return feedDays(tasks, today, days), true
+}
+
+// feedDays lists, for each day that has tasks and for today, a summary
+// followed by an entry per task. Finished, parked and system tasks are
+// left out. A summary is updated whenever one of its tasks is, so feed
+// readers pick up a changed plan.
+func feedDays(tasks []planner.Task, today time.Time, days int) []feedEntry {
+	byDay := make(map[string][]planner.Task)
+	for _, t := range tasks {
+		if !t.Status.Active() || t.System() != "" {
+			continue
+		}
+		key := t.StartTime.Local().Format("2006-01-02")
+		byDay[key] = append(byDay[key], t)
+	}
+
+	var entries []feedEntry
+	for i := 0; i < days; i++ {
+		day := today.AddDate(0, 0, i)
+		key := day.Format("2006-01-02")
+		dayTasks := byDay[key]
+		if len(dayTasks) == 0 && i > 0 {
+			continue
+		}
+
+		summary := feedEntry{
+			ID:      "urn:gomentum:day:" + key,
+			Title:   i18n.T("feed.day_title", i18n.FormatDateLong(day)),
+			Updated: today,
+		}
+		var work time.Duration
+		var lines []string
+		for _, t := range dayTasks {
+			if schedule.Work(t) {
+				work += t.EndTime.Sub(t.StartTime)
+			}
+			if t.UpdatedAt.After(summary.Updated) {
+				summary.Updated = t.UpdatedAt
+			}
+			lines = append(lines, fmt.Sprintf("%s–%s %s", i18n.FormatTime(t.StartTime), i18n.FormatTime(t.EndTime), t.Title))
+		}
+		if len(dayTasks) == 0 {
+			summary.Content = i18n.T("feed.day_empty")
+		} else {
+			summary.Content = i18n.T("feed.day_summary", len(dayTasks), schedule.FormatHours(work), schedule.FormatHours(schedule.DailyCapacity())) +
+				"\n\n" + strings.Join(lines, "\n")
+		}
+		entries = append(entries, summary)
+
+		for _, t := range dayTasks {
+			e := feedEntry{
+				ID:      fmt.Sprintf("urn:gomentum:task:%d", t.ID),
+				Title:   fmt.Sprintf("%s %s", i18n.FormatDateTime(t.StartTime), t.Title),
+				Content: fmt.Sprintf("%s – %s", i18n.FormatDateTime(t.StartTime), i18n.FormatTime(t.EndTime)),
+				Updated: t.UpdatedAt,
+			}
+			if t.Description != "" {
+				e.Content += "\n\n" + t.Description
+			}
+			e.Link, _ = t.FieldString(planner.FieldURL)
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// feedUpdated is when the newest entry changed
+func feedUpdated(entries []feedEntry) time.Time {
+	var latest time.Time
+	for _, e := range entries {
+		if e.Updated.After(latest) {
+			latest = e.Updated
+		}
+	}
+	return latest
+}
+
+func writeFeed(w http.ResponseWriter, contentType string, feed any) {
+	w.Header().Set("Content-Type", contentType+"; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, no-cache")
+	if _, err := w.Write([]byte(xml.Header)); err != nil {
+		return
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(feed); err != nil {
+		slog.Error("Failed to encode feed", "error", err)
+	}
+}
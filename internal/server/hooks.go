@@ -0,0 +1,51 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"gomentum/internal/planner"
+	"gomentum/internal/webhooks"
+)
+
+// handleHookCreateTask adds a task from the JSON payload of an automation
+// platform, mapped onto the task by webhooks.fields. The payload must be
+// signed with webhooks.secret.
+func (s *Server) handleHookCreateTask(w http.ResponseWriter, r *http.Request) {
+	if s.hookSecret == "" {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "this endpoint is disabled; set webhooks.secret to enable it"})
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCaptureBody))
+	if err != nil {
+		writeError(w, fmt.Errorf("%w: failed to read the body: %v", planner.ErrValidation, err))
+		return
+	}
+	if !webhooks.Verify(s.hookSecret, body, r.Header.Get("X-Gomentum-Signature")) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid or missing X-Gomentum-Signature"})
+		return
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		writeError(w, fmt.Errorf("%w: the body must be a JSON object: %v", planner.ErrValidation, err))
+		return
+	}
+	task, err := webhooks.TaskFrom(payload, s.hookFields)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	ids, err := s.planner.AddTasks([]planner.Task{task})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	task, err = s.planner.GetTask(ids[0])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, task)
+}
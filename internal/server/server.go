@@ -0,0 +1,167 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"gomentum/internal/config"
+	gmcp "gomentum/internal/mcp"
+	"gomentum/internal/planner"
+
+	mcpserver "github.com/mark3labs/mcp-go/server"
+)
+
+// Server exposes Gomentum over HTTP: the MCP tools via SSE for external
+// clients, plus a small JSON API for scripts and integrations.
+type Server struct {
+	cfg        config.ServerConfig
+	hookFields map[string]string // Maps incoming webhook payloads onto tasks
+	hookSecret string            // Verifies their signature
+	planner    *planner.Planner
+	mux        *http.ServeMux
+	httpServer *http.Server
+}
+
+// NewServer creates a new HTTP server instance
+func NewServer(cfg config.ServerConfig, hooks config.WebhooksConfig, p *planner.Planner, ms *gmcp.Server) *Server {
+	s := &Server{
+		cfg:        cfg,
+		hookFields: hooks.Fields,
+		hookSecret: hooks.Secret,
+		planner:    p,
+		mux:        http.NewServeMux(),
+	}
+
+	sseOpts := []mcpserver.SSEOption{mcpserver.WithKeepAlive(true)}
+	if cfg.BaseURL != "" {
+		sseOpts = append(sseOpts, mcpserver.WithBaseURL(strings.TrimSuffix(cfg.BaseURL, "/")))
+	} else {
+		// The listen address (e.g. 0.0.0.0 in containers) is not reachable as-is
+		sseOpts = append(sseOpts, mcpserver.WithUseFullURLForMessageEndpoint(false))
+	}
+	sse := mcpserver.NewSSEServer(ms.MCPServer(), sseOpts...)
+	s.mux.Handle("/sse", s.protect(sse.SSEHandler()))
+	s.mux.Handle("/message", s.protect(sse.MessageHandler()))
+
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+	s.mux.Handle("GET /api/tasks", s.protect(http.HandlerFunc(s.handleListTasks)))
+	s.mux.HandleFunc("GET /share/{token}", s.handleShare)
+	s.mux.HandleFunc("POST /capture", s.handleCapture)
+	s.mux.HandleFunc("OPTIONS /capture", s.handleCapturePreflight)
+	s.mux.HandleFunc("GET /feed.atom", s.handleAtomFeed)
+	s.mux.HandleFunc("GET /feed.rss", s.handleRSSFeed)
+	s.mux.HandleFunc("POST /hooks/create-task", s.handleHookCreateTask)
+
+	s.httpServer = &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+// Start serves HTTP until Shutdown is called
+func (s *Server) Start() error {
+	slog.Info("HTTP server listening", "addr", s.cfg.Addr)
+	if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("http server failed: %w", err)
+	}
+	return nil
+}
+
+// Shutdown gracefully stops the server
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
+
+// protect guards the routes that read or change every task, the MCP tools
+// included. With server.token set, requests must carry it; without one,
+// only clients on this machine are served, so a server listening on
+// 0.0.0.0, as in the container, is not open to the network.
+func (s *Server) protect(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case s.cfg.Token != "" && !s.authorized(r):
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid or missing token"})
+		case s.cfg.Token == "" && !fromLoopback(r):
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": "only local clients are served; set server.token to allow remote ones"})
+		default:
+			h.ServeHTTP(w, r)
+		}
+	})
+}
+
+// authorized reports whether r carries the server token as
+// "Authorization: Bearer <token>". A token parameter is not accepted, as
+// URLs end up in proxy logs and browser history. Without a configured
+// token nothing is authorized.
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && s.cfg.Token != "" && sameToken(token, s.cfg.Token)
+}
+
+// sameToken compares a token from a request with want in constant time
+func sameToken(token, want string) bool {
+	return subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(want)) == 1
+}
+
+// fromLoopback reports whether r comes from this machine
+func fromLoopback(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleListTasks(w http.ResponseWriter, r *http.Request) {
+	tasks, err := s.planner.ListTasks()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if tasks == nil {
+		tasks = []planner.Task{}
+	}
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("Failed to encode response", "error", err)
+	}
+}
+
+// writeError responds with the status and code matching the kind of err
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, statusFor(err), map[string]string{"error": err.Error(), "code": planner.Code(err)})
+}
+
+// statusFor maps planner error kinds to HTTP status codes
+func statusFor(err error) int {
+	switch planner.Code(err) {
+	case planner.CodeNotFound:
+		return http.StatusNotFound
+	case planner.CodeConflict:
+		return http.StatusConflict
+	case planner.CodeValidation:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"errors"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"gomentum/internal/config"
+	"gomentum/internal/i18n"
+	"gomentum/internal/planner"
+	"gomentum/internal/schedule"
+)
+
+// ShareURL returns the public link for a share token
+func ShareURL(cfg config.ServerConfig, token string) string {
+	return publicBase(cfg) + "/share/" + token
+}
+
+// publicBase is the URL the server is reached at, without a trailing slash
+func publicBase(cfg config.ServerConfig) string {
+	if base := strings.TrimSuffix(cfg.BaseURL, "/"); base != "" {
+		return base
+	}
+	return "http://" + cfg.Addr
+}
+
+var shareTemplate = template.Must(template.New("share").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 40rem; margin: 2rem auto; padding: 0 1rem; color: #222; }
+h1 { font-size: 1.4rem; }
+h2 { font-size: 1.1rem; margin-top: 1.5rem; border-bottom: 1px solid #ddd; }
+ul { list-style: none; padding: 0; }
+li { padding: .3rem .5rem; margin: .2rem 0; border-radius: 4px; }
+.busy { background: #f3d6d6; }
+.free { background: #d6f3dc; }
+.time { font-variant-numeric: tabular-nums; margin-right: .5rem; }
+footer { margin-top: 2rem; color: #777; font-size: .85rem; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{range .Days}}<h2>{{.Date}}</h2>
+<ul>
+{{range .Entries}}<li class="{{if .Busy}}busy{{else}}free{{end}}"{{with .Color}} style="border-left: .3rem solid {{.}}"{{end}}><span class="time">{{.Start}} – {{.End}}</span>{{.Label}}</li>
+{{else}}<li>{{$.Empty}}</li>
+{{end}}</ul>
+{{end}}<footer>{{.Footer}}</footer>
+</body>
+</html>
+`))
+
+type shareEntry struct {
+	Start, End string
+	Label      string
+	Busy       bool
+	Color      string // "#RRGGBB" of the task's color label, with details only
+	at         time.Time
+}
+
+type shareDay struct {
+	Date    string
+	Entries []shareEntry
+}
+
+type sharePage struct {
+	Lang   string
+	Title  string
+	Days   []shareDay
+	Empty  string
+	Footer string
+}
+
+func (s *Server) handleShare(w http.ResponseWriter, r *http.Request) {
+	share, err := s.planner.GetShare(r.PathValue("token"))
+	if errors.Is(err, planner.ErrNotFound) {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to load share", "error", err)
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	now := time.Now()
+	if share.Expired(now) {
+		http.Error(w, "This link has expired", http.StatusGone)
+		return
+	}
+
+	tasks, err := s.planner.ListTasks()
+	if err != nil {
+		slog.Error("Failed to render share", "error", err)
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+
+	page := sharePage{
+		Lang:   i18n.Current(),
+		Title:  i18n.T("share.title", i18n.FormatDateLong(share.From.Local()), i18n.FormatDateLong(share.To.Local().Add(-time.Second))),
+		Empty:  i18n.T("share.unavailable"),
+		Footer: i18n.T("share.expires", i18n.FormatDateTime(share.ExpiresAt)),
+		Days:   shareDays(tasks, share),
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	if err := shareTemplate.Execute(w, page); err != nil {
+		slog.Error("Failed to render share", "error", err)
+	}
+}
+
+// shareDays lists busy and free time per day of the share. Busy blocks
+// only carry task titles when the share was created with details.
+func shareDays(tasks []planner.Task, share planner.Share) []shareDay {
+	// Working hours are local; stored times may come back in UTC
+	from, to := share.From.Local(), share.To.Local()
+
+	var days []shareDay
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		end := day.AddDate(0, 0, 1)
+		if end.After(to) {
+			end = to
+		}
+
+		var entries []shareEntry
+		for _, t := range tasks {
+			if !schedule.Busy(t) || !t.StartTime.Before(end) || !t.EndTime.After(day) {
+				continue
+			}
+			e := shareEntry{
+				Start: i18n.FormatTime(t.StartTime), End: i18n.FormatTime(t.EndTime),
+				Label: i18n.T("share.busy"), Busy: true, at: t.StartTime,
+			}
+			if share.Details {
+				e.Label, e.Color = t.Title, planner.ColorHex(t.Color)
+			}
+			entries = append(entries, e)
+		}
+		for _, f := range schedule.FreeSlots(tasks, day, end, 0) {
+			entries = append(entries, shareEntry{
+				Start: i18n.FormatTime(f.Start), End: i18n.FormatTime(f.End),
+				Label: i18n.T("share.free"), at: f.Start,
+			})
+		}
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].at.Before(entries[j].at) })
+		days = append(days, shareDay{Date: i18n.FormatDateLong(day), Entries: entries})
+	}
+	return days
+}
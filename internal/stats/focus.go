@@ -0,0 +1,189 @@
+package stats
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gomentum/internal/config"
+	"gomentum/internal/planner"
+)
+
+// MinBlock is how long a flexible task must be to count as a planned
+// deep-work block. Fixed tasks are meetings and appointments.
+const MinBlock = time.Hour
+
+var (
+	mu           sync.RWMutex
+	distractions []string
+)
+
+// Configure sets the words that mark activity as distracting
+func Configure(cfg config.ActivityConfig) {
+	mu.Lock()
+	defer mu.Unlock()
+	distractions = distractions[:0]
+	for _, d := range cfg.Distractions {
+		if d = strings.ToLower(strings.TrimSpace(d)); d != "" {
+			distractions = append(distractions, d)
+		}
+	}
+}
+
+// Distracting reports whether a span of activity is not focused work:
+// the tracker rated it unproductive, or its app, title or category
+// contains one of the configured distractions
+func Distracting(a planner.Activity) bool {
+	if a.Productivity < 0 {
+		return true
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	text := strings.ToLower(a.App + "\n" + a.Title + "\n" + a.Category)
+	for _, d := range distractions {
+		if strings.Contains(text, d) {
+			return true
+		}
+	}
+	return false
+}
+
+// Focus contrasts the deep-work blocks planned in a period with the
+// activity recorded by a time tracker
+type Focus struct {
+	RecordedHours float64 `json:"recorded_hours"`
+	FocusedHours  float64 `json:"focused_hours"` // Recorded, minus distractions
+
+	Blocks            int     `json:"blocks"` // Planned deep-work blocks, see MinBlock
+	BlockHours        float64 `json:"block_hours"`
+	BlockActiveHours  float64 `json:"block_active_hours"`  // Recorded inside the blocks
+	BlockFocusedHours float64 `json:"block_focused_hours"` // Focused inside the blocks
+	// BlockFocusRate is block_focused_hours / block_hours: how much of the
+	// protected time went to focused work
+	BlockFocusRate float64 `json:"block_focus_rate"`
+
+	// TopDistractions are the apps that took the most distracting time,
+	// and TopBlockApps the ones used most during the blocks
+	TopDistractions []AppTime    `json:"top_distractions,omitempty"`
+	TopBlockApps    []AppTime    `json:"top_block_apps,omitempty"`
+	LeastFocused    []BlockFocus `json:"least_focused_blocks,omitempty"`
+}
+
+// AppTime is the time spent in one app
+type AppTime struct {
+	App   string  `json:"app"`
+	Hours float64 `json:"hours"`
+}
+
+// BlockFocus is the focused share of one planned block
+type BlockFocus struct {
+	TaskID int     `json:"task_id"`
+	Title  string  `json:"title"`
+	Start  string  `json:"start"` // YYYY-MM-DD HH:MM
+	Rate   float64 `json:"focus_rate"`
+}
+
+// topN bounds the app and block lists
+const topN = 5
+
+// ComputeFocus compares the blocks among tasks starting in [from, to)
+// with the activity recorded then. It returns nil without activity.
+func ComputeFocus(tasks []planner.Task, activity []planner.Activity, from, to time.Time) *Focus {
+	if len(activity) == 0 {
+		return nil
+	}
+	f := &Focus{}
+	var recorded, focused, blockTotal, blockActive, blockFocused time.Duration
+	distracted := make(map[string]time.Duration)
+	blockApps := make(map[string]time.Duration)
+
+	for _, a := range activity {
+		d := overlap(a.Start, a.End, from, to)
+		recorded += d
+		if Distracting(a) {
+			distracted[a.App] += d
+		} else {
+			focused += d
+		}
+	}
+
+	var blocks []BlockFocus
+	for _, t := range tasks {
+		length := t.EndTime.Sub(t.StartTime)
+		if t.StartTime.Before(from) || !t.StartTime.Before(to) || !t.Flexible || length < MinBlock || t.Status == planner.StatusBacklog || t.System() != "" {
+			continue
+		}
+		f.Blocks++
+		blockTotal += length
+		var inBlock time.Duration
+		for _, a := range activity {
+			d := overlap(a.Start, a.End, t.StartTime, t.EndTime)
+			if d == 0 {
+				continue
+			}
+			blockActive += d
+			blockApps[a.App] += d
+			if !Distracting(a) {
+				inBlock += d
+			}
+		}
+		blockFocused += inBlock
+		blocks = append(blocks, BlockFocus{
+			TaskID: t.ID,
+			Title:  t.Title,
+			Start:  t.StartTime.Local().Format("2006-01-02 15:04"),
+			Rate:   round(min(1, inBlock.Hours()/length.Hours())),
+		})
+	}
+
+	f.RecordedHours = round(recorded.Hours())
+	f.FocusedHours = round(focused.Hours())
+	f.BlockHours = round(blockTotal.Hours())
+	f.BlockActiveHours = round(blockActive.Hours())
+	f.BlockFocusedHours = round(blockFocused.Hours())
+	if blockTotal > 0 {
+		f.BlockFocusRate = round(min(1, blockFocused.Hours()/blockTotal.Hours()))
+	}
+	f.TopDistractions = top(distracted)
+	f.TopBlockApps = top(blockApps)
+	sort.SliceStable(blocks, func(i, j int) bool { return blocks[i].Rate < blocks[j].Rate })
+	f.LeastFocused = blocks[:min(topN, len(blocks))]
+	return f
+}
+
+// overlap is how much of [start, end) lies in [from, to)
+func overlap(start, end, from, to time.Time) time.Duration {
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// top returns the apps with the most time, longest first
+func top(byApp map[string]time.Duration) []AppTime {
+	apps := make([]AppTime, 0, len(byApp))
+	for app, d := range byApp {
+		if d > 0 {
+			apps = append(apps, AppTime{App: app, Hours: d.Hours()})
+		}
+	}
+	// Ties go by name so the result is stable
+	sort.Slice(apps, func(i, j int) bool {
+		if apps[i].Hours != apps[j].Hours {
+			return apps[i].Hours > apps[j].Hours
+		}
+		return apps[i].App < apps[j].App
+	})
+	apps = apps[:min(topN, len(apps))]
+	for i := range apps {
+		apps[i].Hours = round(apps[i].Hours)
+	}
+	return apps
+}
@@ -0,0 +1,95 @@
+// Package stats summarizes how a past period of the plan went: how much
+// was done, which day was busiest, how late work tends to finish and, with
+// imported screen time, how focused the planned deep work really was.
+package stats
+
+import (
+	"math"
+	"time"
+
+	"gomentum/internal/planner"
+)
+
+// Report summarizes the tasks starting in [From, To)
+type Report struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	Tasks     int `json:"tasks"`     // Scheduled tasks; backlog and breaks excluded
+	Completed int `json:"completed"` // Of those, marked completed or another done status
+	Missed    int `json:"missed"`    // Ended before now but not completed
+	Backlog   int `json:"backlog"`
+
+	// CompletionRate is completed / (completed + missed); tasks still
+	// ahead do not count. Zero when nothing was due.
+	CompletionRate float64 `json:"completion_rate"`
+	PlannedHours   float64 `json:"planned_hours"`
+
+	// BusiestDay is the date (YYYY-MM-DD) with the most planned hours
+	BusiestDay   string  `json:"busiest_day,omitempty"`
+	BusiestHours float64 `json:"busiest_hours,omitempty"`
+
+	// AverageOverrunMinutes is how long after their scheduled end completed
+	// tasks were marked done, on average. Early completions count as zero.
+	// The completion time is the task's last edit, so it is an estimate.
+	AverageOverrunMinutes float64 `json:"average_overrun_minutes"`
+
+	// Focus compares the planned deep-work blocks with imported screen
+	// time; nil unless activity was recorded, see ComputeFocus
+	Focus *Focus `json:"focus,omitempty"`
+}
+
+// Compute builds the report for tasks starting between from and to
+func Compute(tasks []planner.Task, from, to, now time.Time) Report {
+	r := Report{From: from, To: to}
+	byDay := make(map[string]time.Duration)
+	var planned, overrun time.Duration
+	var finished int
+
+	for _, t := range tasks {
+		if t.StartTime.Before(from) || !t.StartTime.Before(to) || t.System() != "" {
+			continue
+		}
+		if t.Status == planner.StatusBacklog {
+			r.Backlog++
+			continue
+		}
+		r.Tasks++
+		d := t.EndTime.Sub(t.StartTime)
+		planned += d
+		byDay[t.StartTime.Local().Format("2006-01-02")] += d
+
+		switch {
+		case t.Status.Done():
+			r.Completed++
+			if !t.UpdatedAt.IsZero() {
+				finished++
+				if late := t.UpdatedAt.Sub(t.EndTime); late > 0 {
+					overrun += late
+				}
+			}
+		case t.EndTime.Before(now):
+			r.Missed++
+		}
+	}
+
+	if due := r.Completed + r.Missed; due > 0 {
+		r.CompletionRate = round(float64(r.Completed) / float64(due))
+	}
+	r.PlannedHours = round(planned.Hours())
+	for day, d := range byDay {
+		// Ties go to the earlier day so the result is stable
+		if d.Hours() > r.BusiestHours || (d.Hours() == r.BusiestHours && day < r.BusiestDay) {
+			r.BusiestDay, r.BusiestHours = day, d.Hours()
+		}
+	}
+	r.BusiestHours = round(r.BusiestHours)
+	if finished > 0 {
+		r.AverageOverrunMinutes = math.Round((overrun / time.Duration(finished)).Minutes())
+	}
+	return r
+}
+
+func round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
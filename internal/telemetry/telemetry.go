@@ -0,0 +1,332 @@
+// Package telemetry reports anonymous usage to a maintainer-run endpoint
+// when the user opts in. Reports only hold feature usage counts and crash
+// signatures (panic type and function names), never task content, and
+// every function is a no-op unless telemetry.enabled is set.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"gomentum/internal/config"
+	"gomentum/internal/version"
+)
+
+// stateFile keeps unsent counts across short-lived CLI runs
+const stateFile = "telemetry.json"
+
+// maxFrames is how many function names make up a crash signature
+const maxFrames = 5
+
+// defaultInterval is how often reports go out without telemetry.interval
+const defaultInterval = 24 * time.Hour
+
+// closeTimeout bounds the report sent on exit
+const closeTimeout = 5 * time.Second
+
+// Crash is a panic reduced to where it happened
+type Crash struct {
+	Signature string   `json:"signature"`
+	Type      string   `json:"type"`   // Go type of the panic value, not its message
+	Frames    []string `json:"frames"` // Innermost function names
+	Count     int      `json:"count"`
+}
+
+// Report is the payload sent to the endpoint
+type Report struct {
+	InstallID string            `json:"install_id"` // Random, generated on first use
+	Version   string            `json:"version"`
+	OS        string            `json:"os"`
+	Arch      string            `json:"arch"`
+	Since     time.Time         `json:"since"`
+	Counts    map[string]int    `json:"counts"`
+	Crashes   map[string]*Crash `json:"crashes,omitempty"`
+}
+
+var (
+	mu      sync.Mutex
+	sending sync.Mutex // Held for a whole send, so a report goes out once
+	cfg     config.TelemetryConfig
+	dir     string
+	enabled bool
+	pending = newReport()
+	client  = &http.Client{Timeout: 10 * time.Second}
+)
+
+func newReport() *Report {
+	return &Report{Counts: map[string]int{}, Crashes: map[string]*Crash{}}
+}
+
+// Init turns telemetry on when the config opts in. State is kept in
+// configDir next to the config file.
+func Init(c config.TelemetryConfig, configDir string) {
+	mu.Lock()
+	defer mu.Unlock()
+	cfg, dir = c, configDir
+	enabled = c.Enabled && c.Endpoint != ""
+	if c.Enabled && c.Endpoint == "" {
+		slog.Warn("Telemetry is enabled but telemetry.endpoint is empty; nothing will be reported")
+	}
+}
+
+// Count records one use of a feature, e.g. "cmd.share" or "tool.add_task"
+func Count(feature string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if enabled {
+		pending.Counts[feature]++
+	}
+}
+
+// RecordPanic stores the signature of a recovered panic and saves it right
+// away, since the process is usually about to exit. Call it directly from
+// the deferred function that recovered r.
+func RecordPanic(r any) {
+	mu.Lock()
+	if !enabled {
+		mu.Unlock()
+		return
+	}
+	// Skip runtime.Callers, RecordPanic and the deferred function
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	var names []string
+	for len(names) < maxFrames {
+		f, more := frames.Next()
+		if f.Function != "" && !strings.HasPrefix(f.Function, "runtime.") {
+			names = append(names, f.Function)
+		}
+		if !more {
+			break
+		}
+	}
+	typ := fmt.Sprintf("%T", r)
+	sum := sha256.Sum256([]byte(typ + "\n" + strings.Join(names, "\n")))
+	sig := hex.EncodeToString(sum[:6])
+	if c, ok := pending.Crashes[sig]; ok {
+		c.Count++
+	} else {
+		pending.Crashes[sig] = &Crash{Signature: sig, Type: typ, Frames: names, Count: 1}
+	}
+	mu.Unlock()
+
+	if err := Save(); err != nil {
+		slog.Warn("Failed to save telemetry", "error", err)
+	}
+}
+
+// Save merges the counts of this process into the state file so a later
+// Run can send them
+func Save() error {
+	mu.Lock()
+	defer mu.Unlock()
+	if !enabled || (len(pending.Counts) == 0 && len(pending.Crashes) == 0) {
+		return nil
+	}
+	state, err := load()
+	if err != nil {
+		return err
+	}
+	merge(state, pending)
+	if err := store(state); err != nil {
+		return err
+	}
+	pending = newReport()
+	return nil
+}
+
+// Run sends a report when one is due: right away, as the last one may be
+// older than telemetry.interval, and then every interval until ctx is
+// cancelled. Close sends one on exit, so sessions shorter than the
+// interval still report.
+func Run(ctx context.Context) {
+	mu.Lock()
+	on, every := enabled, interval()
+	mu.Unlock()
+	if !on {
+		return
+	}
+
+	if err := send(ctx, true); err != nil {
+		slog.Warn("Failed to send telemetry", "error", err)
+	}
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := Save(); err != nil {
+				slog.Warn("Failed to save telemetry", "error", err)
+			}
+			return
+		case <-ticker.C:
+			if err := send(ctx, true); err != nil {
+				slog.Warn("Failed to send telemetry", "error", err)
+			}
+		}
+	}
+}
+
+// Close saves what this process counted and sends a report if one is due.
+// The daemon and TUI call it on exit.
+func Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	if err := send(ctx, true); err != nil {
+		slog.Warn("Failed to send telemetry", "error", err)
+	}
+}
+
+// Send posts everything collected so far and clears it on success
+func Send(ctx context.Context) error {
+	return send(ctx, false)
+}
+
+// send posts the collected report, only once telemetry.interval has passed
+// since the last one if onlyDue is set. The request runs without holding
+// mu, so counting goes on meanwhile; what was sent is then subtracted from
+// the state.
+func send(ctx context.Context, onlyDue bool) error {
+	sending.Lock()
+	defer sending.Unlock()
+	if err := Save(); err != nil {
+		return err
+	}
+	mu.Lock()
+	if !enabled {
+		mu.Unlock()
+		return nil
+	}
+	state, err := load()
+	endpoint, due := cfg.Endpoint, err == nil && (!onlyDue || time.Since(state.Since) >= interval())
+	mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if !due || (len(state.Counts) == 0 && len(state.Crashes) == 0) {
+		return nil
+	}
+
+	body, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gomentum/"+version.Version)
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send report: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to send report: %s", resp.Status)
+	}
+
+	// Start a new period with what was counted since the snapshot
+	mu.Lock()
+	defer mu.Unlock()
+	next, err := load()
+	if err != nil {
+		return err
+	}
+	subtract(next, state)
+	next.Since = time.Now()
+	return store(next)
+}
+
+// interval returns telemetry.interval or its default; mu must be held
+func interval() time.Duration {
+	if cfg.Interval > 0 {
+		return cfg.Interval
+	}
+	return defaultInterval
+}
+
+func merge(dst, src *Report) {
+	for k, v := range src.Counts {
+		dst.Counts[k] += v
+	}
+	for sig, c := range src.Crashes {
+		if d, ok := dst.Crashes[sig]; ok {
+			d.Count += c.Count
+		} else {
+			dst.Crashes[sig] = c
+		}
+	}
+}
+
+// subtract removes the sent counts and crashes from dst
+func subtract(dst, sent *Report) {
+	for k, v := range sent.Counts {
+		if dst.Counts[k] -= v; dst.Counts[k] <= 0 {
+			delete(dst.Counts, k)
+		}
+	}
+	for sig, c := range sent.Crashes {
+		if d, ok := dst.Crashes[sig]; ok {
+			if d.Count -= c.Count; d.Count <= 0 {
+				delete(dst.Crashes, sig)
+			}
+		}
+	}
+}
+
+// load reads the state file, creating the install ID on first use
+func load() (*Report, error) {
+	r := newReport()
+	data, err := os.ReadFile(filepath.Join(dir, stateFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read telemetry state: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, r); err != nil {
+			slog.Warn("Discarding unreadable telemetry state", "error", err)
+			r = newReport()
+		}
+		if r.Counts == nil {
+			r.Counts = map[string]int{}
+		}
+		if r.Crashes == nil {
+			r.Crashes = map[string]*Crash{}
+		}
+	}
+	if r.InstallID == "" {
+		buf := make([]byte, 16)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("failed to generate install ID: %w", err)
+		}
+		r.InstallID = hex.EncodeToString(buf)
+		r.Since = time.Now()
+	}
+	r.Version, r.OS, r.Arch = version.Version, runtime.GOOS, runtime.GOARCH
+	return r, nil
+}
+
+func store(r *Report) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry state: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, stateFile), data, 0600); err != nil {
+		return fmt.Errorf("failed to write telemetry state: %w", err)
+	}
+	return nil
+}
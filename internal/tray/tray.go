@@ -0,0 +1,41 @@
+package tray
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gomentum/internal/i18n"
+	"gomentum/internal/planner"
+)
+
+// maxTooltip is the tooltip limit of the Windows notification area (including NUL)
+const maxTooltip = 127
+
+// tooltip summarizes the next pending tasks for the tray icon
+func tooltip(p *planner.Planner, now time.Time) string {
+	tasks, err := p.ListTasks()
+	if err != nil {
+		return "Gomentum"
+	}
+
+	lines := []string{"Gomentum"}
+	for _, t := range tasks {
+		if !t.Status.Active() || t.EndTime.Before(now) {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s %s", i18n.FormatTime(t.StartTime), t.Title))
+		if len(lines) > 3 {
+			break
+		}
+	}
+	if len(lines) == 1 {
+		lines = append(lines, i18n.T("tray.no_upcoming"))
+	}
+
+	tip := strings.Join(lines, "\n")
+	if r := []rune(tip); len(r) > maxTooltip {
+		tip = string(r[:maxTooltip-1]) + "…"
+	}
+	return tip
+}
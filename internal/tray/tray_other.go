@@ -0,0 +1,15 @@
+//go:build !windows
+
+package tray
+
+import (
+	"fmt"
+	"runtime"
+
+	"gomentum/internal/daemon"
+)
+
+// Run is only available on Windows; use `gomentum daemon` elsewhere
+func Run(d *daemon.Daemon) error {
+	return fmt.Errorf("tray mode is not supported on %s, use `gomentum daemon` instead", runtime.GOOS)
+}
@@ -0,0 +1,100 @@
+//go:build windows
+
+package tray
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"time"
+
+	"gomentum/internal/daemon"
+	"gomentum/internal/popup"
+
+	"github.com/tadvi/systray"
+)
+
+// Run shows the tray icon on top of the daemon services and blocks until
+// the user picks "Quit".
+func Run(d *daemon.Daemon) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tray, err := systray.New()
+	if err != nil {
+		return fmt.Errorf("failed to create tray icon: %w", err)
+	}
+
+	// Resource ID 0 falls back to the stock application icon
+	if err := tray.Show(0, tooltip(d.Planner(), time.Now())); err != nil {
+		return fmt.Errorf("failed to show tray icon: %w", err)
+	}
+
+	tray.AppendMenu("Quick add...", func() {
+		go quickAdd(d, tray)
+	})
+	tray.AppendMenu("Open Gomentum", func() {
+		if err := openTerminal(); err != nil {
+			slog.Error("Failed to open Gomentum", "error", err)
+		}
+	})
+	tray.AppendSeparator()
+	tray.AppendMenu("Quit", func() {
+		cancel()
+		tray.Stop()
+		os.Exit(0)
+	})
+
+	d.Start(ctx, func(err error) {
+		slog.Error("Tray service stopped", "error", err)
+		_ = tray.ShowMessage("Gomentum", err.Error(), false)
+	})
+
+	// Keep the tooltip current
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				_ = tray.SetTooltip(tooltip(d.Planner(), now))
+			}
+		}
+	}()
+
+	return tray.Run()
+}
+
+// quickAdd prompts for a single line and hands it to the agent
+func quickAdd(d *daemon.Daemon, tray *systray.Systray) {
+	text, err := popup.Dialog("Gomentum", "What should I plan?")
+	if err != nil {
+		slog.Error("Quick-add prompt failed", "error", err)
+		return
+	}
+	if text == "" {
+		return
+	}
+
+	d.QuickAdd(text, func(reply string, err error) {
+		if err != nil {
+			_ = tray.ShowMessage("Gomentum", "Quick-add failed: "+err.Error(), false)
+			return
+		}
+		_ = tray.ShowMessage("Gomentum", reply, false)
+		_ = tray.SetTooltip(tooltip(d.Planner(), time.Now()))
+	})
+}
+
+// openTerminal starts the TUI in a new console window
+func openTerminal() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	return exec.Command("cmd", "/c", "start", "", exe).Start()
+}
@@ -2,13 +2,20 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"gomentum/internal/agent"
+	"gomentum/internal/calendar"
 	"gomentum/internal/config"
+	"gomentum/internal/i18n"
 	"gomentum/internal/planner"
+	"gomentum/internal/prompts"
+	"gomentum/internal/schedule"
+	"gomentum/internal/search"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textarea"
@@ -41,17 +48,56 @@ type taskItem struct {
 	id          int
 	title       string
 	description string
-	status      string
+	status      planner.Status
+	date        string
 	startTime   string
 	endTime     string
+	start       time.Time
+	deadline    string // Empty when the task has no deadline
 	state       string
+	priority    string // Priority icon, already styled; empty for medium
+	tag         string // Project glyph, see tagIcons
+	label       string // Color label, already styled
+	pin         string // Fixed-task icon; empty for flexible tasks
+	fields      string // Custom fields as "key: value" pairs
+	tags        string // Tags as "+work +errand"
+	mark        string // Set on tasks the last agent turn changed
+	picked      string // Set on tasks marked for a batch action
+	compact     bool   // Shown on one line, so the title carries the time
 }
 
-func (t taskItem) Title() string { return fmt.Sprintf("%s %s", t.state, t.title) }
+func (t taskItem) Title() string {
+	state := t.state
+	if t.mark != "" {
+		state = t.mark + " " + state
+	}
+	if t.picked != "" {
+		state = t.picked + " " + state
+	}
+	if t.compact {
+		state = fmt.Sprintf("%s %s %s", t.date, t.startTime, state)
+	}
+	for _, icon := range []string{t.label, t.pin, t.priority, t.tag} {
+		if icon != "" {
+			state += " " + icon
+		}
+	}
+	if t.tags != "" {
+		return fmt.Sprintf("%s %s %s", state, t.title, t.tags)
+	}
+	return fmt.Sprintf("%s %s", state, t.title)
+}
 func (t taskItem) Description() string {
-	return fmt.Sprintf("[%s - %s] %s", t.startTime, t.endTime, t.description)
+	desc := t.description
+	if t.fields != "" {
+		desc = strings.TrimSpace(fmt.Sprintf("{%s} %s", t.fields, desc))
+	}
+	if t.deadline != "" {
+		return fmt.Sprintf("[%s %s - %s] (%s) %s", t.date, t.startTime, t.endTime, i18n.T("tui.due", t.deadline), desc)
+	}
+	return fmt.Sprintf("[%s %s - %s] %s", t.date, t.startTime, t.endTime, desc)
 }
-func (t taskItem) FilterValue() string { return t.title }
+func (t taskItem) FilterValue() string { return strings.TrimSpace(t.title + " " + t.tags) }
 
 type errMsg error
 
@@ -60,12 +106,16 @@ type model struct {
 	textarea    textarea.Model
 	taskList    list.Model
 	senderStyle lipgloss.Style
+	theme       Theme
+	icons       iconSet
+	tagIcons    tagIcons
 	err         error
 
 	// App state
-	cfg     *config.Config
-	planner *planner.Planner
-	agent   agent.Agent
+	cfg      *config.Config
+	planner  *planner.Planner
+	agent    agent.Agent
+	commands []prompts.Prompt
 
 	// Chat state
 	messages    []string
@@ -74,15 +124,86 @@ type model struct {
 
 	// Streaming
 	sub chan string
+	// Receives the IDs of the tasks a turn changed, just before sub closes
+	turn chan []int
+
+	// Tasks changed by the last agent turn, highlighted for a few seconds.
+	// highlightGen tells stale clear timers from the current one.
+	highlight    map[int]bool
+	highlightGen int
+
+	// The last turn, which Ctrl+R regenerates and Ctrl+Z takes back for
+	// editing: the prompt sent, the text typed and where it starts in
+	// messages
+	lastPrompt string
+	lastInput  string
+	turnStart  int
+
+	// Prompts received while the agent was busy
+	pending []string
+
+	// Task marked with Ctrl+O, waiting for a second one to swap with
+	swapID int
+
+	// Tasks marked with Space for a batch action
+	marked map[int]bool
+
+	// Tasks referenced as #ID by the last reply; Ctrl+Y cycles through
+	// them in the sidebar
+	refs   []int
+	refIdx int
+
+	// Completions for the word being typed; Tab accepts the selected one
+	// and Shift+Tab moves the selection
+	suggestions []suggestion
+	suggestIdx  int
+	// Values of the project field, offered as completions
+	projects []string
+
+	// Mini-calendar shown instead of the task list; nil while closed
+	month *monthView
+	// Question shown above the task list; nil while none is open
+	prompt *prompt
+
+	// Day or week the task list is limited to, starting at rangeFrom;
+	// Shift+arrows page and zoom, Alt+G asks for a date to go to
+	rangeScope string
+	rangeFrom  time.Time
+	// Saved smart list the task list shows, "" for every task; Alt+L
+	// cycles through them
+	smartList string
+	// Tasks found by /search, best first, listed instead of the smart
+	// list and range while searchQuery is set
+	searcher    *search.Searcher
+	searchQuery string
+	searchIDs   []int
+	// Only tasks with this tag are listed, "" for every task; Alt+A
+	// cycles through the tags
+	tagFilter string
+	// Select the now line once the list is next refreshed: at startup
+	// and after the range changed
+	followNow bool
+
+	// One line per task without descriptions; Ctrl+L toggles
+	compact bool
+
+	// Layout preset (split, chat or tasks) and the sidebar width in
+	// percent; Ctrl+G and Ctrl+Left/Right change them
+	layout  string
+	sidebar int
+	// Pane shown while the terminal is too narrow for the split layout
+	tab string
 
 	// Layout
 	width  int
 	height int
 }
 
-func InitialModel(cfg *config.Config, p *planner.Planner, ag agent.Agent) model {
+func InitialModel(cfg *config.Config, p *planner.Planner, ag agent.Agent, commands []prompts.Prompt) model {
+	theme := resolveTheme(cfg.Theme)
+
 	ta := textarea.New()
-	ta.Placeholder = "Ask Gomentum to plan your day..."
+	ta.Placeholder = i18n.T("tui.placeholder")
 	ta.Focus()
 
 	ta.Prompt = "┃ "
@@ -93,50 +214,74 @@ func InitialModel(cfg *config.Config, p *planner.Planner, ag agent.Agent) model
 
 	// Remove cursor line styling
 	ta.FocusedStyle.CursorLine = lipgloss.NewStyle()
+	ta.FocusedStyle.Prompt = theme.promptStyle()
 	ta.ShowLineNumbers = false
 
 	vp := viewport.New(30, 5)
-	vp.SetContent(`Welcome to Gomentum!
-Type a message to start planning.`)
+	vp.SetContent(i18n.T("tui.welcome"))
 
 	ta.KeyMap.InsertNewline.SetEnabled(false)
 
 	// Initialize Task List
 	items := []list.Item{}
 	l := list.New(items, list.NewDefaultDelegate(), 0, 0)
-	l.Title = "Tasks"
+	l.Title = listTitle(time.Now())
 	l.SetShowHelp(false)
 
-	return model{
+	m := model{
 		textarea:    ta,
 		messages:    []string{},
 		viewport:    vp,
 		taskList:    l,
-		senderStyle: lipgloss.NewStyle().Foreground(lipgloss.Color("5")),
+		senderStyle: theme.promptStyle(),
+		theme:       theme,
+		icons:       resolveIcons(cfg.Icons),
+		tagIcons:    newTagIcons(cfg.TagIcons),
 		err:         nil,
 		cfg:         cfg,
 		planner:     p,
 		agent:       ag,
+		commands:    commands,
 		sub:         make(chan string),
+		followNow:   true,
 	}
+	// A broken embeddings config already failed the agent; nil turns /search off
+	m.searcher, _ = search.New(cfg.Embeddings, cfg.Agent.Redact, p)
+	m.setDensity(cfg.Density == "compact")
+	layout, sidebar := resolveLayout(cfg.Layout.Preset, cfg.Layout.Sidebar)
+	m.sidebar = sidebar
+	m.tab = layoutChat
+	m.setLayout(layout)
+	return m
+}
+
+// setDensity switches the task list between one and two lines per task
+func (m *model) setDensity(compact bool) {
+	m.compact = compact
+	d := m.theme.listStyles(&m.taskList)
+	d.ShowDescription = !compact
+	if compact {
+		d.SetSpacing(0)
+	}
+	m.taskList.SetDelegate(d)
 }
 
 func (m model) Init() tea.Cmd {
-	return tea.Batch(textarea.Blink, m.refreshTasks)
+	return tea.Batch(textarea.Blink, m.refreshTasks, tick())
 }
 
-func taskStateLabel(status string, end time.Time, now time.Time) string {
-	switch status {
-	case "completed":
-		return "✓ Completed"
-	case "in_progress":
-		return "… In progress"
-	default:
-		if end.Before(now) {
-			return "⚠ Overdue"
-		}
-		return "• Pending"
-	}
+// tickMsg fires on every minute of the wall clock
+type tickMsg time.Time
+
+// tick schedules the next refresh so states like overdue and the date in
+// the title change without a key press
+func tick() tea.Cmd {
+	return tea.Every(time.Minute, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+// listTitle shows today's date (with the secondary calendar, if enabled)
+func listTitle(now time.Time) string {
+	return fmt.Sprintf("%s · %s", i18n.T("tui.tasks_title"), calendar.Annotate(i18n.FormatDate(now), now))
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -146,6 +291,50 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		lCmd  tea.Cmd
 	)
 
+	// The date prompt and month view take every key while open
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case m.prompt != nil:
+			return m.promptKey(key)
+		case key.Type == tea.KeyCtrlT:
+			return m, m.toggleMonth()
+		case m.month != nil:
+			return m.monthKey(key)
+		case m.taskList.SettingFilter():
+			// Keys belong to the filter being typed
+		default:
+			if cmd, ok := m.batchKey(key); ok {
+				return m, cmd
+			}
+		}
+		switch key.String() {
+		case "ctrl+@":
+			return m, m.toggleMark()
+		case " ":
+			if m.pane() == layoutTasks && !m.taskList.SettingFilter() {
+				return m, m.toggleMark()
+			}
+		case "shift+left":
+			return m, m.pageRange(-1)
+		case "shift+right":
+			return m, m.pageRange(1)
+		case "shift+up":
+			return m, m.zoomRange(-1)
+		case "shift+down":
+			return m, m.zoomRange(1)
+		case "alt+g":
+			m.openGoto()
+			return m, nil
+		case "alt+e":
+			m.openExport()
+			return m, nil
+		case "alt+l":
+			return m, m.nextSmartList()
+		case "alt+a":
+			return m, m.nextTagFilter()
+		}
+	}
+
 	m.textarea, tiCmd = m.textarea.Update(msg)
 	m.taskList, lCmd = m.taskList.Update(msg)
 	m.viewport, vpCmd = m.viewport.Update(msg)
@@ -154,25 +343,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-
-		// Layout: 30% Sidebar, 70% Chat
-		sidebarWidth := int(float64(msg.Width) * 0.3)
-		chatWidth := msg.Width - sidebarWidth - 4 // Margins
-
-		m.taskList.SetSize(sidebarWidth, msg.Height-2)
-
-		m.textarea.SetWidth(chatWidth)
-		m.viewport.Width = chatWidth
-		m.viewport.Height = msg.Height - m.textarea.Height() - 4
-
-		m.renderChat()
+		m.resize()
 
 	case tea.KeyMsg:
 		switch msg.Type {
 		case tea.KeyCtrlC, tea.KeyEsc:
 			return m, tea.Quit
 		case tea.KeyEnter:
-			if m.isThinking {
+			if m.isThinking || m.pane() == layoutTasks {
 				return m, nil
 			}
 
@@ -181,21 +359,108 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
-			m.messages = append(m.messages, "**You**: "+input)
-			m.renderChat()
 			m.textarea.Reset()
-			m.viewport.GotoBottom()
-
-			m.isThinking = true
-			m.currentResp = ""
-			m.sub = make(chan string) // Reset channel
+			m.suggestions = nil
+			if strings.TrimSpace(input) == remindersCommand {
+				m.messages = append(m.messages, reminderHistory(m.planner, 10))
+				m.renderChat()
+				m.viewport.GotoBottom()
+				return m, nil
+			}
+			if arg, ok := isDebugCommand(input); ok {
+				m.messages = append(m.messages, "*"+toggleDebug(arg)+"*")
+				m.renderChat()
+				m.viewport.GotoBottom()
+				return m, nil
+			}
+			if query, ok := isSearchCommand(input); ok {
+				switch {
+				case m.searcher == nil:
+					m.messages = append(m.messages, "*"+i18n.T("tui.search_off")+"*")
+					m.renderChat()
+					m.viewport.GotoBottom()
+					return m, nil
+				case query == "":
+					// Back to every task
+					m.searchQuery, m.searchIDs, m.followNow = "", nil, true
+					return m, m.refreshTasks
+				}
+				return m, m.runSearch(query)
+			}
+			prompt, ok := expandCommand(m.commands, strings.TrimSpace(input))
+			if !ok {
+				m.messages = append(m.messages, "*"+prompt+"*")
+				m.renderChat()
+				return m, nil
+			}
+			cmd := m.submit(prompt)
+			m.lastInput = strings.TrimSpace(input)
+			return m, cmd
+		case tea.KeyCtrlR:
+			return m, m.regenerate()
+		case tea.KeyCtrlZ:
+			m.editLast()
+			return m, nil
+		case tea.KeyCtrlX:
+			// Pin the selected task in place, or let the engines move it again
+			if item, ok := m.taskList.SelectedItem().(taskItem); ok {
+				return m, m.toggleFlexible(item.id)
+			}
+			return m, nil
+		case tea.KeyCtrlO:
+			// The first press marks a task, the second swaps it with the selection
+			item, ok := m.taskList.SelectedItem().(taskItem)
+			if !ok {
+				return m, nil
+			}
+			if m.swapID == 0 || m.swapID == item.id {
+				m.swapID = item.id
+				m.messages = append(m.messages, "*"+i18n.T("tui.swap_marked", item.title)+"*")
+				m.renderChat()
+				return m, nil
+			}
+			id := m.swapID
+			m.swapID = 0
+			return m, m.swapTasks(id, item.id)
+		case tea.KeyCtrlY:
+			m.nextRef()
+			return m, nil
+		case tea.KeyCtrlL:
+			m.setDensity(!m.compact)
+			return m, m.refreshTasks
+		case tea.KeyTab:
+			if len(m.suggestions) > 0 {
+				m.acceptSuggestion()
+				return m, nil
+			}
+			if m.narrow() {
+				m.switchTab()
+				return m, nil
+			}
+		case tea.KeyShiftTab:
+			if len(m.suggestions) > 0 {
+				m.cycleSuggestion()
+				return m, nil
+			}
+		case tea.KeyCtrlG:
+			m.nextLayout()
+			return m, nil
+		case tea.KeyCtrlLeft:
+			m.resizeSidebar(-sidebarStep)
+			return m, nil
+		case tea.KeyCtrlRight:
+			m.resizeSidebar(sidebarStep)
+			return m, nil
+		}
+		m.updateSuggestions()
 
-			// Start agent interaction
-			return m, tea.Batch(
-				m.startChat(input),
-				waitForActivity(m.sub),
-			)
+	// Quick-add requests bridged from another Gomentum process
+	case quickAddMsg:
+		if m.isThinking {
+			m.pending = append(m.pending, string(msg))
+			return m, nil
 		}
+		return m, m.submit(string(msg))
 
 	// We handle custom messages here for streaming
 	case tokenMsg:
@@ -206,44 +471,151 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case finishMsg:
 		m.isThinking = false
-		m.messages = append(m.messages, "**Gomentum**: "+m.currentResp)
+		m.messages = append(m.messages, assistantPrefix()+markTaskRefs(m.currentResp))
+		m.refs, m.refIdx = taskRefs(m.currentResp), 0
 		m.currentResp = ""
+		unmark := m.highlightChanged()
+		// Run the next queued quick-add, if any
+		if len(m.pending) > 0 {
+			next := m.pending[0]
+			m.pending = m.pending[1:]
+			return m, tea.Batch(m.refreshTasks, unmark, m.submit(next))
+		}
 		// Refresh tasks after agent is done, as it might have changed them
+		return m, tea.Batch(m.refreshTasks, unmark)
+
+	case clearHighlightMsg:
+		if int(msg) != m.highlightGen {
+			return m, nil
+		}
+		m.highlight = nil
+		return m, m.refreshTasks
+
+	case flexibleMsg:
+		key := "tui.now_flexible"
+		if !msg.flexible {
+			key = "tui.now_fixed"
+		}
+		m.messages = append(m.messages, "*"+i18n.T(key, msg.title)+"*")
+		m.renderChat()
+		return m, m.refreshTasks
+
+	case batchMsg:
+		m.marked = nil
+		m.messages = append(m.messages, "*"+string(msg)+"*")
+		m.renderChat()
 		return m, m.refreshTasks
 
+	case exportedMsg:
+		m.messages = append(m.messages, "*"+string(msg)+"*")
+		m.renderChat()
+		return m, nil
+
+	case swappedMsg:
+		m.messages = append(m.messages, "*"+i18n.T("tui.swapped", msg.a, msg.b)+"*")
+		m.renderChat()
+		return m, m.refreshTasks
+
+	case tasksChangedMsg:
+		return m, m.refreshTasks
+
+	case monthMsg:
+		if m.month != nil && msg.month.Equal(firstOfMonth(m.month.cursor)) {
+			m.month.month, m.month.counts, m.month.busy = msg.month, msg.counts, msg.busy
+		}
+		return m, nil
+
+	case tickMsg:
+		return m, tea.Batch(m.refreshTasks, tick())
+
+	case updateAvailableMsg:
+		m.messages = append(m.messages, "*"+i18n.T("tui.update_available", string(msg))+"*")
+		m.renderChat()
+		return m, nil
+
 	case errMsg:
 		m.err = msg
 		return m, nil
 
-	case []list.Item:
-		m.taskList.SetItems(msg)
+	case searchMsg:
+		if len(msg.ids) == 0 {
+			m.messages = append(m.messages, "*"+i18n.T("tui.search_none", msg.query)+"*")
+			m.renderChat()
+			m.viewport.GotoBottom()
+			return m, nil
+		}
+		m.searchQuery, m.searchIDs = msg.query, msg.ids
+		// Select the best match
+		m.taskList.ResetSelected()
+		return m, m.refreshTasks
+
+	case tasksMsg:
+		m.taskList.Title = listTitle(time.Now())
+		if label := m.rangeLabel(); label != "" {
+			// The range takes the place of today's date
+			m.taskList.Title = i18n.T("tui.tasks_title") + " · " + label
+		}
+		m.smartList = msg.smartList
+		if m.smartList != "" {
+			m.taskList.Title += " · " + i18n.T("tui.smart_list", m.smartList)
+		}
+		if m.tagFilter != "" {
+			m.taskList.Title += " · +" + m.tagFilter
+		}
+		if m.searchQuery != "" {
+			m.taskList.Title += " · " + i18n.T("tui.search_title", m.searchQuery)
+		}
+		if msg.tags != "" {
+			m.taskList.Title += " · " + msg.tags
+		}
+		if len(m.marked) > 0 {
+			m.taskList.Title += " · " + i18n.T("tui.marked", len(m.marked))
+		}
+		if len(msg.warnings) > 0 {
+			m.taskList.Title += " · " + m.icons.Warning + " " + strings.Join(msg.warnings, "; ")
+		}
+		// The now line stays selected as it moves down the list
+		_, onNow := m.taskList.SelectedItem().(nowItem)
+		m.taskList.SetItems(msg.items)
+		if (onNow || m.followNow) && m.taskList.FilterState() == list.Unfiltered {
+			m.selectNow()
+		}
+		m.followNow = false
+		m.projects = msg.projects
+		if m.month != nil {
+			return m, tea.Batch(tiCmd, vpCmd, lCmd, m.loadMonth(m.month.cursor))
+		}
 	}
 
 	return m, tea.Batch(tiCmd, vpCmd, lCmd)
 }
 
 func (m model) View() string {
+	chat := m.viewport.View()
+	if len(m.suggestions) > 0 {
+		// The popup covers the bottom of the chat so the input stays put
+		lines := strings.Split(chat, "\n")
+		if n := len(lines) - len(m.suggestions) - 1; n >= 0 {
+			chat = strings.Join(append(lines[:n], "", m.suggestionView()), "\n")
+		}
+	}
 	chatView := fmt.Sprintf(
 		"%s\n\n%s",
-		m.viewport.View(),
+		chat,
 		m.textarea.View(),
 	)
 
-	return lipgloss.JoinHorizontal(
-		lipgloss.Top,
-		appStyle.Render(m.taskList.View()),
-		appStyle.Render(chatView),
-	)
+	return m.panes(chatView)
 }
 
 func (m *model) renderChat() {
 	content := strings.Join(m.messages, "\n\n")
 	if m.currentResp != "" {
-		content += "\n\n**Gomentum**: " + m.currentResp
+		content += "\n\n" + assistantPrefix() + m.currentResp
 	}
 
 	renderer, _ := glamour.NewTermRenderer(
-		glamour.WithStandardStyle("dark"),
+		glamour.WithStandardStyle(m.theme.Glamour),
 		glamour.WithWordWrap(m.viewport.Width),
 	)
 	str, err := renderer.Render(content)
@@ -254,30 +626,227 @@ func (m *model) renderChat() {
 	}
 }
 
+// stateLabel is the task's status icon and label, in the color
+// configured for custom statuses
+func (m model) stateLabel(t planner.Task, now time.Time) string {
+	key, label := taskState(t.Status, t.EndTime, now)
+	label = m.icons.State[key] + " " + label
+	if def, _ := t.Status.Def(); def.Color != "" && m.theme.Name != "no-color" {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(def.Color)).Render(label)
+	}
+	return label
+}
+
+// highlightFor is how long tasks changed by a turn stay marked
+const highlightFor = 5 * time.Second
+
+// clearHighlightMsg ends the highlight started with the same generation
+type clearHighlightMsg int
+
+// highlightChanged marks the tasks the finished turn changed and returns
+// the timer that clears the mark
+func (m *model) highlightChanged() tea.Cmd {
+	var ids []int
+	select {
+	case ids = <-m.turn:
+	default:
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	m.highlightGen++
+	m.highlight = make(map[int]bool, len(ids))
+	for _, id := range ids {
+		m.highlight[id] = true
+	}
+	gen := m.highlightGen
+	return tea.Tick(highlightFor, func(time.Time) tea.Msg { return clearHighlightMsg(gen) })
+}
+
+// changedMark is the marker in front of highlighted tasks
+func (m model) changedMark() string {
+	if m.theme.NoColor {
+		return m.icons.Changed
+	}
+	return lipgloss.NewStyle().Foreground(m.theme.Success).Bold(true).Render(m.icons.Changed)
+}
+
+// tasksMsg carries the refreshed task list, capacity warnings and the
+// project summary for the list title
+type tasksMsg struct {
+	items    []list.Item
+	warnings []string
+	tags     string   // Today's open tasks per project glyph
+	projects []string // Values of the project field, for completion
+	// The smart list shown, "" if none or it no longer exists
+	smartList string
+}
+
 func (m model) refreshTasks() tea.Msg {
 	tasks, err := m.planner.ListTasks()
 	if err != nil {
 		return errMsg(err)
 	}
 
-	items := []list.Item{}
 	now := time.Now()
-	for _, t := range tasks {
+	shown, smart := tasks, m.smartList
+	if m.searchQuery != "" {
+		// Search results stand in for the smart list and the range
+		shown = searchResults(tasks, m.searchIDs)
+	} else if smart != "" {
+		shown, err = m.smartListTasks(now)
+		if errors.Is(err, planner.ErrNotFound) {
+			// The list was deleted meanwhile
+			shown, smart, err = tasks, "", nil
+		}
+		if err != nil {
+			return errMsg(err)
+		}
+	}
+	if m.searchQuery == "" && smart == "" && m.rangeScope != rangeAll {
+		if shown, err = m.planner.TasksBetween(m.dateRange()); err != nil {
+			return errMsg(err)
+		}
+	}
+	if m.tagFilter != "" {
+		shown = slices.DeleteFunc(slices.Clone(shown), func(t planner.Task) bool {
+			return !slices.Contains(t.Tags, m.tagFilter)
+		})
+	}
+
+	items := []list.Item{}
+	for _, t := range shown {
+		var mark, picked string
+		if m.highlight[t.ID] {
+			mark = m.changedMark()
+		}
+		if m.marked[t.ID] {
+			picked = m.icons.Marked
+		}
+		var pin string
+		if !t.Flexible {
+			pin = m.icons.Fixed
+		}
+		var deadline string
+		if !t.Deadline.IsZero() {
+			deadline = i18n.FormatDateTime(t.Deadline)
+		}
+		var labels []string
+		for _, tag := range t.Tags {
+			labels = append(labels, "+"+tag)
+		}
+		var fields []string
+		for _, k := range t.FieldKeys() {
+			v, _ := t.FieldString(k)
+			fields = append(fields, k+": "+v)
+		}
 		items = append(items, taskItem{
 			id:          t.ID,
 			title:       t.Title,
 			description: t.Description,
 			status:      t.Status,
-			startTime:   t.StartTime.Local().Format("15:04"),
-			endTime:     t.EndTime.Local().Format("15:04"),
-			state:       taskStateLabel(t.Status, t.EndTime, now),
+			date:        calendar.Annotate(i18n.FormatDate(t.StartTime), t.StartTime),
+			startTime:   i18n.FormatTime(t.StartTime),
+			endTime:     i18n.FormatTime(t.EndTime),
+			start:       t.StartTime,
+			deadline:    deadline,
+			state:       m.stateLabel(t, now),
+			priority:    m.theme.priority(m.icons.Priority[t.Priority], t.Priority),
+			tag:         m.tagIcons.icon(t),
+			label:       m.theme.label(m.icons.Label, m.tagIcons.color(t)),
+			pin:         pin,
+			fields:      strings.Join(fields, ", "),
+			tags:        strings.Join(labels, " "),
+			mark:        mark,
+			picked:      picked,
+			compact:     m.compact,
 		})
 	}
-	return items
+
+	if m.searchQuery == "" {
+		items = m.insertNow(items, now)
+	}
+
+	var warnings []string
+	for _, d := range schedule.Overloaded(tasks, now, 7) {
+		warnings = append(warnings, d.Warning())
+	}
+	return tasksMsg{items: items, warnings: warnings, tags: m.tagIcons.summary(tasks, now),
+		projects: fieldValues(tasks, m.tagIcons.field), smartList: smart}
+}
+
+// flexibleMsg reports that a task was pinned or unpinned
+type flexibleMsg struct {
+	title    string
+	flexible bool
+}
+
+// toggleFlexible flips whether the scheduling engines may move a task
+func (m model) toggleFlexible(id int) tea.Cmd {
+	return func() tea.Msg {
+		t, err := m.planner.GetTask(id)
+		if err != nil {
+			return errMsg(err)
+		}
+		t.Flexible = !t.Flexible
+		if err := m.planner.UpdateTask(t); err != nil {
+			return errMsg(err)
+		}
+		return flexibleMsg{title: t.Title, flexible: t.Flexible}
+	}
+}
+
+// swappedMsg reports that two tasks exchanged their time slots
+type swappedMsg struct {
+	a, b string
+}
+
+// swapTasks exchanges the time slots of two tasks
+func (m model) swapTasks(idA, idB int) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.planner.SwapTasks(idA, idB); err != nil {
+			return errMsg(err)
+		}
+		a, err := m.planner.GetTask(idA)
+		if err != nil {
+			return errMsg(err)
+		}
+		b, err := m.planner.GetTask(idB)
+		if err != nil {
+			return errMsg(err)
+		}
+		return swappedMsg{a: a.Title, b: b.Title}
+	}
+}
+
+// submit records the user prompt and starts an agent turn
+func (m *model) submit(input string) tea.Cmd {
+	m.lastPrompt, m.lastInput, m.turnStart = input, input, len(m.messages)
+	m.messages = append(m.messages, "**"+i18n.T("tui.you")+"**: "+input)
+	m.renderChat()
+	m.viewport.GotoBottom()
+
+	m.isThinking = true
+	m.currentResp = ""
+	m.sub = make(chan string) // Reset channel
+	m.turn = make(chan []int, 1)
+
+	// Start agent interaction
+	return tea.Batch(
+		m.startChat(expandTaskRefs(m.planner, input)),
+		waitForActivity(m.sub),
+	)
+}
+
+func assistantPrefix() string {
+	return "**" + i18n.T("tui.assistant") + "**: "
 }
 
 // Custom messages
 type tokenMsg string
+type quickAddMsg string
+type updateAvailableMsg string
+type tasksChangedMsg struct{}
 type finishMsg struct{}
 type errorMsg error
 
@@ -294,13 +863,14 @@ func waitForActivity(sub chan string) tea.Cmd {
 func (m model) startChat(input string) tea.Cmd {
 	return func() tea.Msg {
 		go func() {
-			_, err := m.agent.Chat(context.Background(), input, func(token string) {
+			resp, err := m.agent.Chat(context.Background(), input, func(token string) {
 				m.sub <- token
 			})
+			m.turn <- resp.TaskIDs()
 			if err != nil {
 				// We can't easily send error to channel if it expects string
 				// For now, just log or send as text
-				m.sub <- fmt.Sprintf("\nError: %v", err)
+				m.sub <- "\n" + i18n.T("tui.error", err)
 			}
 			close(m.sub)
 		}()
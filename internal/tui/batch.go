@@ -0,0 +1,175 @@
+package tui
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gomentum/internal/i18n"
+	"gomentum/internal/planner"
+	"gomentum/internal/projects"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Tasks marked with Space (Ctrl+Space while the chat takes the keys) are
+// changed together: Alt+C completes, Alt+D deletes, Alt+T retags and
+// Alt+S shifts all of them in one transaction, and Alt+M merges two of
+// them. Esc clears the marks.
+
+// toggleMark marks or unmarks the selected task and moves to the next
+// item, so Space can run down the list
+func (m *model) toggleMark() tea.Cmd {
+	item, ok := m.taskList.SelectedItem().(taskItem)
+	m.taskList.CursorDown()
+	if !ok {
+		return nil
+	}
+	if m.marked[item.id] {
+		delete(m.marked, item.id)
+	} else {
+		if m.marked == nil {
+			m.marked = make(map[int]bool)
+		}
+		m.marked[item.id] = true
+	}
+	return m.refreshTasks
+}
+
+// markedIDs returns the marked tasks in ascending order
+func (m model) markedIDs() []int {
+	ids := make([]int, 0, len(m.marked))
+	for id := range m.marked {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// batchMsg reports a finished batch action; the marks are cleared then
+type batchMsg string
+
+// batch applies fn to the marked tasks and reports done, a message taking
+// the number of tasks
+func (m model) batch(done string, fn func(ids []int) error) tea.Cmd {
+	ids := m.markedIDs()
+	return func() tea.Msg {
+		if err := fn(ids); err != nil {
+			return errMsg(err)
+		}
+		return batchMsg(i18n.T(done, len(ids)))
+	}
+}
+
+// batchKey runs the batch action bound to key. It reports false if key
+// is not one, or no task is marked.
+func (m *model) batchKey(key tea.KeyMsg) (tea.Cmd, bool) {
+	n := len(m.marked)
+	if n == 0 {
+		return nil, false
+	}
+	switch key.String() {
+	case "esc":
+		m.marked = nil
+		return m.refreshTasks, true
+	case "alt+c":
+		return m.batch("tui.batch_completed", func(ids []int) error {
+			return m.planner.SetTasksStatus(ids, planner.StatusCompleted)
+		}), true
+	case "alt+d":
+		m.openPrompt(i18n.T("tui.batch_delete", n), i18n.T("tui.confirm_hint"), func(m *model, answer string) (tea.Cmd, string) {
+			if !confirmed(answer) {
+				return nil, ""
+			}
+			return m.batch("tui.batch_deleted", m.planner.DeleteTasks), ""
+		})
+	case "alt+t":
+		field := m.tagIcons.field
+		if field == "" {
+			field = "project"
+		}
+		m.openPrompt(i18n.T("tui.batch_retag", n, field), i18n.T("tui.batch_retag_hint"), func(m *model, answer string) (tea.Cmd, string) {
+			var value any
+			if answer = strings.TrimSpace(answer); answer != "" && answer != "-" {
+				value = answer
+			}
+			return m.batch("tui.batch_retagged", func(ids []int) error {
+				if err := m.planner.SetTasksField(ids, field, value); err != nil || value == nil {
+					return err
+				}
+				// The new project brings its defaults
+				for _, id := range ids {
+					t, err := m.planner.GetTask(id)
+					if err != nil {
+						return err
+					}
+					if projects.Apply(&t) {
+						if err := m.planner.UpdateTask(t); err != nil {
+							return err
+						}
+					}
+				}
+				return nil
+			}), ""
+		})
+	case "alt+s":
+		m.openPrompt(i18n.T("tui.batch_shift", n), i18n.T("tui.batch_shift_hint"), func(m *model, answer string) (tea.Cmd, string) {
+			delta, ok := parseShift(answer)
+			if !ok {
+				return nil, i18n.T("tui.batch_shift_invalid", answer)
+			}
+			return m.batch("tui.batch_shifted", func(ids []int) error {
+				return m.planner.ShiftTasks(ids, delta)
+			}), ""
+		})
+	case "alt+m":
+		if n != 2 {
+			m.messages = append(m.messages, "*"+i18n.T("tui.batch_merge_two")+"*")
+			m.renderChat()
+			return nil, true
+		}
+		// The older task is kept, as imports add the newer copies
+		ids := m.markedIDs()
+		keep, drop := ids[0], ids[1]
+		m.openPrompt(i18n.T("tui.batch_merge", drop, keep), i18n.T("tui.confirm_hint"), func(m *model, answer string) (tea.Cmd, string) {
+			if !confirmed(answer) {
+				return nil, ""
+			}
+			return m.mergeTasks(keep, drop), ""
+		})
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+// mergeTasks folds task drop into keep
+func (m model) mergeTasks(keep, drop int) tea.Cmd {
+	return func() tea.Msg {
+		t, err := m.planner.MergeTasks(keep, drop, planner.MergeOptions{})
+		if err != nil {
+			return errMsg(err)
+		}
+		return batchMsg(i18n.T("tui.batch_merged", drop, t.Title))
+	}
+}
+
+// confirmed reports whether answer is a yes: "y", "yes" or the locale's
+// own word
+func confirmed(answer string) bool {
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes" || answer == strings.ToLower(i18n.T("tui.yes"))
+}
+
+// parseShift reads how far to move tasks: a Go duration such as "+1h30m"
+// or "-15m", or whole days such as "+2d"
+func parseShift(s string) (time.Duration, bool) {
+	s = strings.TrimSpace(s)
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		return time.Duration(n) * 24 * time.Hour, err == nil && n != 0
+	}
+	d, err := time.ParseDuration(s)
+	return d, err == nil && d != 0
+}
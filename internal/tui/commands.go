@@ -0,0 +1,31 @@
+package tui
+
+import (
+	"strings"
+
+	"gomentum/internal/i18n"
+	"gomentum/internal/prompts"
+)
+
+// expandCommand turns "/name details" into the text of the prompt called
+// name. Other input is returned unchanged. For an unknown command it
+// returns a notice listing the available ones and ok=false.
+func expandCommand(list []prompts.Prompt, input string) (string, bool) {
+	if !strings.HasPrefix(input, "/") {
+		return input, true
+	}
+	name, rest, _ := strings.Cut(strings.TrimPrefix(input, "/"), " ")
+	if p, ok := prompts.Find(list, name); ok {
+		return p.Render(rest), true
+	}
+	return i18n.T("tui.unknown_command", name, commandList(list)), false
+}
+
+// commandList renders the prompt commands as "/a, /b"
+func commandList(list []prompts.Prompt) string {
+	names := prompts.Names(list)
+	for i, n := range names {
+		names[i] = "/" + n
+	}
+	return strings.Join(names, ", ")
+}
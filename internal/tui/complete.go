@@ -0,0 +1,181 @@
+package tui
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gomentum/internal/planner"
+	"gomentum/internal/prompts"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// maxSuggestions is how many completions the popup shows at once
+	maxSuggestions = 5
+	// minCompleteLen is how much of a word must be typed before titles
+	// and projects are suggested
+	minCompleteLen = 3
+)
+
+// suggestion is a completion for the word being typed
+type suggestion struct {
+	label  string // Shown in the popup
+	insert string // Replaces the word
+}
+
+// suggest completes the last word of input: "/" starts a command, "#" a
+// task reference and anything else a task title or project
+func suggest(input string, commands []prompts.Prompt, items []list.Item, projects []string) []suggestion {
+	if input == "" || strings.HasSuffix(input, " ") {
+		return nil
+	}
+	word := input[strings.LastIndex(input, " ")+1:]
+	lower := strings.ToLower(word)
+
+	var out []suggestion
+	add := func(label, insert string) bool {
+		if insert == word {
+			return true
+		}
+		for _, s := range out {
+			if s.insert == insert {
+				return true
+			}
+		}
+		out = append(out, suggestion{label: label, insert: insert})
+		return len(out) < maxSuggestions
+	}
+
+	switch {
+	case strings.HasPrefix(input, "/") && word == input:
+		names := append([]string{strings.TrimPrefix(remindersCommand, "/"), strings.TrimPrefix(debugCommand, "/"), strings.TrimPrefix(searchCommand, "/")}, prompts.Names(commands)...)
+		for _, n := range names {
+			if !strings.HasPrefix(n, lower[1:]) {
+				continue
+			}
+			label := "/" + n
+			if p, ok := prompts.Find(commands, n); ok && p.Description != "" {
+				label += " · " + p.Description
+			}
+			if !add(label, "/"+n) {
+				break
+			}
+		}
+
+	case strings.HasPrefix(word, "#"):
+		q := lower[1:]
+		_, numeric := strconv.Atoi(q)
+		for _, it := range items {
+			t, ok := it.(taskItem)
+			if !ok {
+				continue
+			}
+			id := strconv.Itoa(t.id)
+			if q != "" && !strings.HasPrefix(id, q) && (numeric == nil || !strings.Contains(strings.ToLower(t.title), q)) {
+				continue
+			}
+			if !add("#"+id+" "+t.title, "#"+id) {
+				break
+			}
+		}
+
+	case utf8.RuneCountInString(word) >= minCompleteLen:
+		for _, v := range projects {
+			if strings.HasPrefix(strings.ToLower(v), lower) && !add(v, v) {
+				return out
+			}
+		}
+		for _, it := range items {
+			t, ok := it.(taskItem)
+			if ok && wordPrefix(strings.ToLower(t.title), lower) && !add(t.title, t.title) {
+				break
+			}
+		}
+	}
+	return out
+}
+
+// wordPrefix reports whether any word of s starts with prefix
+func wordPrefix(s, prefix string) bool {
+	for _, w := range strings.Fields(s) {
+		if strings.HasPrefix(w, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldValues lists the distinct values of a custom field, such as the
+// projects offered as completions
+func fieldValues(tasks []planner.Task, key string) []string {
+	if key == "" {
+		return nil
+	}
+	seen := map[string]bool{}
+	var values []string
+	for _, t := range tasks {
+		v, ok := t.FieldString(key)
+		if !ok || v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return values
+}
+
+// updateSuggestions recomputes the popup after the input changed
+func (m *model) updateSuggestions() {
+	var next []suggestion
+	if m.textarea.Focused() {
+		next = suggest(m.textarea.Value(), m.commands, m.taskList.Items(), m.projects)
+	}
+	if len(next) != len(m.suggestions) || m.suggestIdx >= len(next) {
+		m.suggestIdx = 0
+	}
+	m.suggestions = next
+}
+
+// acceptSuggestion replaces the word being typed with the selected
+// completion
+func (m *model) acceptSuggestion() {
+	s := m.suggestions[m.suggestIdx]
+	input := m.textarea.Value()
+	m.textarea.SetValue(input[:strings.LastIndex(input, " ")+1] + s.insert + " ")
+	m.suggestions, m.suggestIdx = nil, 0
+}
+
+// cycleSuggestion moves the popup selection to the next completion
+func (m *model) cycleSuggestion() {
+	m.suggestIdx = (m.suggestIdx + 1) % len(m.suggestions)
+}
+
+// suggestionView renders the popup, one completion per line
+func (m model) suggestionView() string {
+	lines := make([]string, len(m.suggestions))
+	for i, s := range m.suggestions {
+		label := s.label
+		if w := m.textarea.Width(); w > 4 && lipgloss.Width(label) > w-2 {
+			label = truncate(label, w-3) + "…"
+		}
+		lines[i] = m.theme.tabStyle(i == m.suggestIdx).Render(label)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// truncate cuts s to at most n cells
+func truncate(s string, n int) string {
+	var b strings.Builder
+	for _, r := range s {
+		if lipgloss.Width(b.String()+string(r)) > n {
+			break
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
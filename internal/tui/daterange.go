@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"slices"
+	"time"
+
+	"gomentum/internal/i18n"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Date ranges of the task list: every task, or a single day or week
+const (
+	rangeAll  = ""
+	rangeDay  = "day"
+	rangeWeek = "week"
+)
+
+// rangeScopes is the order Shift+Up narrows and Shift+Down widens the
+// range in
+var rangeScopes = []string{rangeDay, rangeWeek, rangeAll}
+
+// dateRange returns the start and end of the visible day or week
+func (m model) dateRange() (time.Time, time.Time) {
+	if m.rangeScope == rangeWeek {
+		return m.rangeFrom, m.rangeFrom.AddDate(0, 0, 7)
+	}
+	return m.rangeFrom, m.rangeFrom.AddDate(0, 0, 1)
+}
+
+// rangeLabel describes the visible range for the list title; empty while
+// every task is shown
+func (m model) rangeLabel() string {
+	switch m.rangeScope {
+	case rangeDay:
+		return i18n.FormatDate(m.rangeFrom)
+	case rangeWeek:
+		return i18n.T("tui.range_week", i18n.FormatDate(m.rangeFrom), i18n.FormatDate(m.rangeFrom.AddDate(0, 0, 6)))
+	}
+	return ""
+}
+
+// showRange limits the list to the day or week containing day, or lifts
+// the limit for rangeAll
+func (m *model) showRange(scope string, day time.Time) tea.Cmd {
+	day = startOfDay(day)
+	if scope == rangeWeek {
+		day = i18n.StartOfWeek(day)
+	}
+	m.rangeScope, m.rangeFrom = scope, day
+	m.taskList.ResetSelected()
+	m.followNow = true
+	return m.refreshTasks
+}
+
+// pageRange shows the next (dir 1) or previous (dir -1) day or week. From
+// the full list, it moves away from today one day at a time.
+func (m *model) pageRange(dir int) tea.Cmd {
+	switch m.rangeScope {
+	case rangeAll:
+		return m.showRange(rangeDay, time.Now().AddDate(0, 0, dir))
+	case rangeWeek:
+		return m.showRange(rangeWeek, m.rangeFrom.AddDate(0, 0, 7*dir))
+	}
+	return m.showRange(rangeDay, m.rangeFrom.AddDate(0, 0, dir))
+}
+
+// zoomRange widens (dir 1) a day to its week and a week to every task, or
+// narrows (dir -1) the other way. Narrowing picks today if it is in view.
+func (m *model) zoomRange(dir int) tea.Cmd {
+	i := slices.Index(rangeScopes, m.rangeScope) + dir
+	if i < 0 || i >= len(rangeScopes) {
+		return nil
+	}
+	day := m.rangeFrom
+	from, to := m.dateRange()
+	if now := time.Now(); m.rangeScope == rangeAll || (!now.Before(from) && now.Before(to)) {
+		day = now
+	}
+	return m.showRange(rangeScopes[i], day)
+}
+
+// showSidebar makes sure the task list is visible, for views that take
+// over its place
+func (m *model) showSidebar() {
+	if m.pane() != layoutChat {
+		return
+	}
+	if m.narrow() {
+		m.tab = layoutTasks
+		m.resize()
+	} else {
+		m.setLayout(layoutSplit)
+	}
+}
+
+// openGoto asks for a date and shows that day, or its week if the list
+// shows weeks
+func (m *model) openGoto() {
+	m.openPrompt(i18n.T("tui.goto_prompt"), i18n.T("tui.goto_placeholder"), func(m *model, answer string) (tea.Cmd, string) {
+		day, ok := i18n.ParseDay(answer, time.Now())
+		if !ok {
+			return nil, i18n.T("tui.goto_invalid", answer)
+		}
+		scope := m.rangeScope
+		if scope == rangeAll {
+			scope = rangeDay
+		}
+		return m.showRange(scope, day), ""
+	})
+}
@@ -0,0 +1,36 @@
+package tui
+
+import (
+	"strings"
+
+	"gomentum/internal/debuglog"
+	"gomentum/internal/i18n"
+)
+
+// debugCommand turns the debug log of LLM traffic on and off in the TUI
+// and plain mode: "/debug" toggles it, "/debug on" and "/debug off" set it
+const debugCommand = "/debug"
+
+// isDebugCommand reports whether input is /debug, returning its argument
+func isDebugCommand(input string) (string, bool) {
+	name, arg, _ := strings.Cut(strings.TrimSpace(input), " ")
+	return strings.TrimSpace(arg), name == debugCommand
+}
+
+// toggleDebug runs /debug with arg and says whether the log is now on
+func toggleDebug(arg string) string {
+	switch strings.ToLower(arg) {
+	case "":
+		debuglog.SetEnabled(!debuglog.Enabled())
+	case "on":
+		debuglog.SetEnabled(true)
+	case "off":
+		debuglog.SetEnabled(false)
+	default:
+		return i18n.T("tui.debug_usage")
+	}
+	if debuglog.Enabled() {
+		return i18n.T("tui.debug_on", debuglog.Path())
+	}
+	return i18n.T("tui.debug_off")
+}
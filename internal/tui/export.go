@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"os"
+	"strings"
+
+	"gomentum/internal/exporter"
+	"gomentum/internal/i18n"
+	"gomentum/internal/planner"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// exportedMsg reports a finished export
+type exportedMsg string
+
+// shownIDs returns the tasks an export covers: the marked ones, or else
+// every task the list shows after its filter and date range
+func (m model) shownIDs() []int {
+	if len(m.marked) > 0 {
+		return m.markedIDs()
+	}
+	var ids []int
+	for _, item := range m.taskList.VisibleItems() {
+		if t, ok := item.(taskItem); ok {
+			ids = append(ids, t.id)
+		}
+	}
+	return ids
+}
+
+// openExport asks for a file to export the shown tasks to. The extension
+// picks the format: .md, .ics or .csv.
+func (m *model) openExport() {
+	ids := m.shownIDs()
+	if len(ids) == 0 {
+		return
+	}
+	m.openPrompt(i18n.T("tui.export_prompt", len(ids)), i18n.T("tui.export_hint"), func(m *model, answer string) (tea.Cmd, string) {
+		path := strings.TrimSpace(answer)
+		if path == "" {
+			return nil, ""
+		}
+		return m.exportTasks(ids, exporter.ExpandHome(path)), ""
+	})
+}
+
+// exportTasks writes the tasks to path, replacing the file if it exists
+func (m model) exportTasks(ids []int, path string) tea.Cmd {
+	return func() tea.Msg {
+		tasks, err := m.planner.QueryTasks(planner.Filter{IDs: ids})
+		if err != nil {
+			return errMsg(err)
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return errMsg(err)
+		}
+		err = exporter.Write(f, exporter.FormatOf(path), tasks)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return errMsg(err)
+		}
+		return exportedMsg(i18n.T("tui.exported", len(tasks), path))
+	}
+}
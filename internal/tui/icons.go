@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"log/slog"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"gomentum/internal/i18n"
+	"gomentum/internal/planner"
+)
+
+// iconSet holds the symbols drawn in front of task states and titles
+type iconSet struct {
+	Name string
+	// State icons, keyed like taskState: completed, in_progress, backlog,
+	// overdue, pending, custom and custom_done
+	State map[string]string
+	// Priority icons; medium and unset priorities have none
+	Priority map[string]string
+	Fixed    string // Tasks the engines must not move
+	Label    string // Drawn in a task's color label
+	Changed  string // Tasks the last agent turn changed
+	Marked   string // Tasks marked for a batch action
+	Warning  string // Capacity warnings in the list title
+	// Month view cells, from lightly to fully booked days
+	Density [3]string
+	Now     string // Repeated to draw the current-time line
+}
+
+var iconSets = map[string]iconSet{
+	"emoji": {
+		Name: "emoji",
+		State: map[string]string{
+			"completed": "✅", "in_progress": "⏳", "backlog": "📥", "overdue": "⏰",
+			"pending": "🔲", "custom": "🔹", "custom_done": "✅",
+		},
+		Priority: map[string]string{planner.PriorityLow: "🔽", planner.PriorityHigh: "🔼", planner.PriorityUrgent: "🔥"},
+		Fixed:    "📌",
+		Label:    "●",
+		Changed:  "✨",
+		Marked:   "🔘",
+		Warning:  "🚨",
+		Density:  [3]string{"░", "▒", "█"},
+		Now:      "─",
+	},
+	"unicode": {
+		Name: "unicode",
+		State: map[string]string{
+			"completed": "✓", "in_progress": "…", "backlog": "◦", "overdue": "⚠",
+			"pending": "•", "custom": "•", "custom_done": "✓",
+		},
+		Priority: map[string]string{planner.PriorityLow: "↓", planner.PriorityHigh: "↑", planner.PriorityUrgent: "‼"},
+		Fixed:    "⚑",
+		Label:    "●",
+		Changed:  "✱",
+		Marked:   "▣",
+		Warning:  "⚠",
+		Density:  [3]string{"░", "▒", "█"},
+		Now:      "─",
+	},
+	"ascii": {
+		Name: "ascii",
+		State: map[string]string{
+			"completed": "[x]", "in_progress": "[~]", "backlog": "[-]", "overdue": "[!]",
+			"pending": "[ ]", "custom": "[.]", "custom_done": "[x]",
+		},
+		Priority: map[string]string{planner.PriorityLow: "v", planner.PriorityHigh: "^", planner.PriorityUrgent: "!!"},
+		Fixed:    "#",
+		Label:    "*",
+		Changed:  "*",
+		Marked:   "+",
+		Warning:  "!",
+		Density:  [3]string{".", ":", "#"},
+		Now:      "-",
+	},
+}
+
+// resolveIcons picks the configured icon set; "auto" or empty detects
+// what the terminal can draw
+func resolveIcons(name string) iconSet {
+	if name == "" || name == "auto" {
+		name = detectIcons()
+	}
+	set, ok := iconSets[name]
+	if !ok {
+		slog.Warn("Unknown icon set, using unicode", "icons", name)
+		set = iconSets["unicode"]
+	}
+	return set
+}
+
+// emojiTerminals are TERM_PROGRAM values of terminals known to draw color
+// emoji at a predictable width
+var emojiTerminals = []string{"iTerm.app", "Apple_Terminal", "WezTerm", "vscode", "ghostty", "Hyper", "Tabby"}
+
+// detectIcons guesses the richest icon set the terminal can show: emoji in
+// terminals known to handle them, plain Unicode symbols under a UTF-8
+// locale, and ASCII on consoles and everywhere else
+func detectIcons() string {
+	if os.Getenv("WT_SESSION") != "" { // Windows Terminal
+		return "emoji"
+	}
+	if runtime.GOOS == "windows" {
+		return "ascii"
+	}
+	term := os.Getenv("TERM")
+	if term == "linux" || term == "dumb" || !utf8Locale() {
+		return "ascii"
+	}
+	program := os.Getenv("TERM_PROGRAM")
+	for _, t := range emojiTerminals {
+		if program == t {
+			return "emoji"
+		}
+	}
+	if strings.Contains(term, "kitty") || strings.Contains(term, "ghostty") || strings.Contains(term, "wezterm") {
+		return "emoji"
+	}
+	return "unicode"
+}
+
+// utf8Locale reports whether the locale variables select UTF-8. As in the
+// C library, the first one set wins.
+func utf8Locale() bool {
+	for _, v := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := os.Getenv(v); locale != "" {
+			locale = strings.ToLower(strings.ReplaceAll(locale, "-", ""))
+			return strings.Contains(locale, "utf8")
+		}
+	}
+	return false
+}
+
+// taskState returns the key of a task's state in iconSet.State and its
+// label, e.g. ("overdue", "Overdue"). Custom statuses are labeled by name.
+func taskState(status planner.Status, end time.Time, now time.Time) (string, string) {
+	switch status {
+	case planner.StatusCompleted:
+		return "completed", i18n.T("status.completed")
+	case planner.StatusInProgress:
+		return "in_progress", i18n.T("status.in_progress")
+	case planner.StatusBacklog:
+		return "backlog", i18n.T("status.backlog")
+	}
+	if def, ok := status.Def(); ok && def.Custom {
+		if def.Done {
+			return "custom_done", string(status)
+		}
+		return "custom", string(status)
+	}
+	if end.Before(now) {
+		return "overdue", i18n.T("status.overdue")
+	}
+	return "pending", i18n.T("status.pending")
+}
@@ -0,0 +1,167 @@
+package tui
+
+import (
+	"log/slog"
+	"slices"
+
+	"gomentum/internal/i18n"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Layout presets: both panes, or one of them at full width
+const (
+	layoutSplit = "split"
+	layoutChat  = "chat"
+	layoutTasks = "tasks"
+)
+
+// layouts is the order Ctrl+G cycles through
+var layouts = []string{layoutSplit, layoutChat, layoutTasks}
+
+// Bounds and step of the sidebar width, in percent of the terminal
+const (
+	minSidebar  = 15
+	maxSidebar  = 70
+	sidebarStep = 5
+)
+
+// narrowWidth is the terminal width below which the split layout shows
+// one pane at a time as tabs; side by side, neither pane fits in 80 columns
+const narrowWidth = 100
+
+// resolveLayout checks the configured preset and sidebar width
+func resolveLayout(preset string, sidebar int) (string, int) {
+	if preset == "" {
+		preset = layoutSplit
+	}
+	if !slices.Contains(layouts, preset) {
+		slog.Warn("Unknown layout, using split", "layout", preset)
+		preset = layoutSplit
+	}
+	if sidebar == 0 {
+		sidebar = 30
+	}
+	return preset, min(max(sidebar, minSidebar), maxSidebar)
+}
+
+// setLayout switches the preset
+func (m *model) setLayout(preset string) {
+	m.layout = preset
+	m.resize()
+}
+
+// narrow reports whether the split layout is shown as tabs
+func (m model) narrow() bool {
+	return m.layout == layoutSplit && m.width > 0 && m.width < narrowWidth
+}
+
+// pane is the layout actually shown: the current tab on narrow terminals
+func (m model) pane() string {
+	if m.narrow() {
+		return m.tab
+	}
+	return m.layout
+}
+
+// switchTab flips between the task and chat tabs of the narrow layout
+func (m *model) switchTab() {
+	if m.tab == layoutTasks {
+		m.tab = layoutChat
+	} else {
+		m.tab = layoutTasks
+	}
+	m.resize()
+}
+
+// nextLayout cycles through the presets
+func (m *model) nextLayout() {
+	i := slices.Index(layouts, m.layout)
+	m.setLayout(layouts[(i+1)%len(layouts)])
+}
+
+// resizeSidebar widens (delta > 0) or narrows the task list in the split
+// layout, switching to it first if one pane is hidden
+func (m *model) resizeSidebar(delta int) {
+	if m.layout != layoutSplit {
+		m.setLayout(layoutSplit)
+		return
+	}
+	m.sidebar = min(max(m.sidebar+delta, minSidebar), maxSidebar)
+	m.resize()
+}
+
+// resize fits the panes to the terminal size and layout. The task list
+// only takes keys while the chat is hidden, so j/k and / do not also type
+// into the input.
+func (m *model) resize() {
+	pane := m.pane()
+	if pane == layoutTasks {
+		m.textarea.Blur()
+	} else {
+		m.textarea.Focus()
+	}
+	if m.width == 0 {
+		return
+	}
+
+	height := m.height
+	if m.narrow() {
+		height-- // Tab bar
+	}
+	sidebarWidth := m.width * m.sidebar / 100
+	chatWidth := m.width - sidebarWidth - 4 // Margins
+	switch pane {
+	case layoutChat:
+		chatWidth = m.width - 4
+	case layoutTasks:
+		sidebarWidth = m.width - 4
+	}
+
+	m.taskList.SetSize(sidebarWidth, height-2)
+
+	m.textarea.SetWidth(chatWidth)
+	m.viewport.Width = chatWidth
+	m.viewport.Height = height - m.textarea.Height() - 4
+
+	m.renderChat()
+}
+
+// sidebarView is the task list, with a prompt above it while one is
+// open, or the month view
+func (m model) sidebarView() string {
+	if m.prompt != nil {
+		return m.promptView()
+	}
+	if m.month != nil {
+		return lipgloss.NewStyle().Width(m.taskList.Width()).Height(m.taskList.Height()).Render(m.monthGrid())
+	}
+	return m.taskList.View()
+}
+
+// panes renders the visible panes side by side, or the current tab below
+// the tab bar
+func (m model) panes(chatView string) string {
+	var view string
+	switch m.pane() {
+	case layoutChat:
+		view = appStyle.Render(chatView)
+	case layoutTasks:
+		view = appStyle.Render(m.sidebarView())
+	default:
+		return lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			appStyle.Render(m.sidebarView()),
+			appStyle.Render(chatView),
+		)
+	}
+	if !m.narrow() {
+		return view
+	}
+	tabs := lipgloss.JoinHorizontal(lipgloss.Top,
+		m.theme.tabStyle(m.tab == layoutTasks).Render(i18n.T("tui.tasks_title")),
+		m.theme.tabStyle(m.tab == layoutChat).Render(i18n.T("tui.chat_title")),
+		m.theme.tabStyle(false).Render(i18n.T("tui.switch_tab")),
+	)
+	return lipgloss.JoinVertical(lipgloss.Left, tabs, view)
+}
@@ -0,0 +1,206 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gomentum/internal/i18n"
+	"gomentum/internal/planner"
+	"gomentum/internal/schedule"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// monthView is the mini-calendar Ctrl+T shows in place of the task list.
+// Every day shows how booked it is; Enter jumps the list to that day.
+type monthView struct {
+	cursor time.Time // Selected day, at midnight
+	// Tasks and booked time per day of the month the counts were loaded
+	// for; index 0 is the 1st
+	month  time.Time
+	counts []int
+	busy   []time.Duration
+}
+
+// monthMsg carries the per-day counts of a month
+type monthMsg struct {
+	month  time.Time
+	counts []int
+	busy   []time.Duration
+}
+
+// cellWidth is the width of one day in the grid: two digits, the density
+// glyph and a space
+const cellWidth = 4
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func firstOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// addMonths moves t by n months, keeping the day where the target month
+// has it (Jan 31 becomes Feb 28, not Mar 3)
+func addMonths(t time.Time, n int) time.Time {
+	first := firstOfMonth(t).AddDate(0, n, 0)
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), last)-1)
+}
+
+// loadMonth counts the tasks starting on each day of the month of day.
+// Backlog tasks have no date that matters and breaks are no work, so they
+// are left out.
+func (m model) loadMonth(day time.Time) tea.Cmd {
+	first := firstOfMonth(day)
+	return func() tea.Msg {
+		tasks, err := m.planner.QueryTasks(planner.Filter{From: first, To: first.AddDate(0, 1, 0)})
+		if err != nil {
+			return errMsg(err)
+		}
+		days := first.AddDate(0, 1, -1).Day()
+		msg := monthMsg{month: first, counts: make([]int, days), busy: make([]time.Duration, days)}
+		for _, t := range tasks {
+			d := t.StartTime.Local().Day() - 1
+			if t.Status == planner.StatusBacklog || t.System() != "" || d >= days {
+				continue
+			}
+			msg.counts[d]++
+			if schedule.Work(t) {
+				msg.busy[d] += t.EndTime.Sub(t.StartTime)
+			}
+		}
+		return msg
+	}
+}
+
+// toggleMonth opens the month view on today, showing the sidebar if the
+// chat fills the screen, or closes it
+func (m *model) toggleMonth() tea.Cmd {
+	if m.month != nil {
+		m.month = nil
+		return nil
+	}
+	today := startOfDay(time.Now())
+	m.month = &monthView{cursor: today}
+	m.showSidebar()
+	return m.loadMonth(today)
+}
+
+// monthKey handles keys while the month view is open: arrows (or h/j/k/l)
+// move by day and week, PgUp/PgDn by month, t returns to today and Enter
+// jumps the list to the selected day, or shows its day or week if the list
+// is limited to one
+func (m model) monthKey(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	mv := m.month
+	switch key.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "ctrl+t":
+		m.month = nil
+		return m, nil
+	case "enter":
+		m.month = nil
+		if m.rangeScope != rangeAll {
+			return m, m.showRange(m.rangeScope, mv.cursor)
+		}
+		m.jumpToDay(mv.cursor)
+		return m, nil
+	case "left", "h":
+		mv.cursor = mv.cursor.AddDate(0, 0, -1)
+	case "right", "l":
+		mv.cursor = mv.cursor.AddDate(0, 0, 1)
+	case "up", "k":
+		mv.cursor = mv.cursor.AddDate(0, 0, -7)
+	case "down", "j":
+		mv.cursor = mv.cursor.AddDate(0, 0, 7)
+	case "pgup", "[":
+		mv.cursor = addMonths(mv.cursor, -1)
+	case "pgdown", "]":
+		mv.cursor = addMonths(mv.cursor, 1)
+	case "t":
+		mv.cursor = startOfDay(time.Now())
+	default:
+		return m, nil
+	}
+	if !firstOfMonth(mv.cursor).Equal(mv.month) {
+		return m, m.loadMonth(mv.cursor)
+	}
+	return m, nil
+}
+
+// jumpToDay selects the first task starting on or after day, clearing any
+// filter. It reports false if no task is that late.
+func (m *model) jumpToDay(day time.Time) bool {
+	if m.taskList.FilterState() != list.Unfiltered {
+		m.taskList.ResetFilter()
+	}
+	for i, item := range m.taskList.Items() {
+		if t, ok := item.(taskItem); ok && !t.start.Before(day) {
+			m.taskList.Select(i)
+			return true
+		}
+	}
+	return false
+}
+
+// monthGrid renders the month view: the month, weekday headers, one row
+// per week and a summary of the selected day
+func (m model) monthGrid() string {
+	mv := m.month
+	first := firstOfMonth(mv.cursor)
+	loaded := first.Equal(mv.month)
+	today := startOfDay(time.Now())
+	cell := lipgloss.NewStyle().Width(cellWidth)
+
+	var b strings.Builder
+	b.WriteString(m.taskList.Styles.Title.Render(i18n.FormatMonth(first)) + "\n\n")
+
+	start := i18n.WeekStart()
+	for i := 0; i < 7; i++ {
+		name := i18n.WeekdayShort(time.Weekday((int(start) + i) % 7))
+		b.WriteString(cell.Render(truncate(name, cellWidth)))
+	}
+	b.WriteString("\n")
+
+	offset := (int(first.Weekday()) - int(start) + 7) % 7
+	b.WriteString(strings.Repeat(" ", offset*cellWidth))
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		if day.Day() > 1 && (offset+day.Day()-1)%7 == 0 {
+			b.WriteString("\n")
+		}
+		glyph := " "
+		if loaded {
+			glyph = m.density(mv.counts[day.Day()-1], mv.busy[day.Day()-1])
+		}
+		label := m.theme.dayStyle(day.Equal(mv.cursor), day.Equal(today)).Render(fmt.Sprintf("%2d%s", day.Day(), glyph))
+		b.WriteString(label + " ")
+	}
+
+	b.WriteString("\n\n")
+	if loaded {
+		d := mv.cursor.Day() - 1
+		b.WriteString(i18n.T("tui.month_day", i18n.FormatDate(mv.cursor), mv.counts[d], schedule.FormatHours(mv.busy[d])) + "\n")
+	}
+	b.WriteString(m.theme.tabStyle(false).Padding(0).Render(i18n.T("tui.month_hint")))
+	return b.String()
+}
+
+// density is the glyph for how booked a day is, relative to the daily
+// capacity; days without tasks stay blank
+func (m model) density(count int, busy time.Duration) string {
+	capacity := schedule.DailyCapacity()
+	switch {
+	case count == 0:
+		return " "
+	case busy < capacity/3:
+		return m.icons.Density[0]
+	case busy < capacity*2/3:
+		return m.icons.Density[1]
+	}
+	return m.icons.Density[2]
+}
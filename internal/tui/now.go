@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"gomentum/internal/i18n"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// nowItem is the line marking the current time between the tasks that
+// have started and those still to come. The minute tick moves it along.
+type nowItem struct {
+	line string // Already styled
+	next string // The next task and when it starts
+}
+
+func (n nowItem) Title() string       { return n.line }
+func (n nowItem) Description() string { return n.next }
+func (n nowItem) FilterValue() string { return "" }
+
+// insertNow adds the now line before the first task that starts later,
+// unless the list is limited to a day or week that does not include now
+func (m model) insertNow(items []list.Item, now time.Time) []list.Item {
+	if len(items) == 0 {
+		return items
+	}
+	if m.rangeScope != rangeAll {
+		if from, to := m.dateRange(); now.Before(from) || !now.Before(to) {
+			return items
+		}
+	}
+	i := slices.IndexFunc(items, func(it list.Item) bool {
+		t, ok := it.(taskItem)
+		return ok && t.start.After(now)
+	})
+	n := nowItem{line: m.nowLine(now)}
+	if i < 0 {
+		i = len(items)
+	} else {
+		t := items[i].(taskItem)
+		n.next = i18n.T("tui.now_next", t.title, i18n.FormatTime(t.start))
+	}
+	return slices.Insert(items, i, list.Item(n))
+}
+
+// nowLine draws the current time across the list, in the error color so
+// it stands out from the tasks
+func (m model) nowLine(now time.Time) string {
+	label := " " + i18n.T("tui.now", i18n.FormatTime(now)) + " "
+	rest := max(m.taskList.Width()-2-lipgloss.Width(label)-2, 0)
+	line := strings.Repeat(m.icons.Now, 2) + label + strings.Repeat(m.icons.Now, rest)
+	if m.theme.NoColor {
+		return line
+	}
+	return lipgloss.NewStyle().Foreground(m.theme.Error).Render(line)
+}
+
+// selectNow moves the selection to the now line, scrolling it into view.
+// It reports false if the list has none.
+func (m *model) selectNow() bool {
+	for i, item := range m.taskList.Items() {
+		if _, ok := item.(nowItem); ok {
+			m.taskList.Select(i)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,227 @@
+package tui
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"gomentum/internal/agent"
+	"gomentum/internal/calendar"
+	"gomentum/internal/config"
+	"gomentum/internal/i18n"
+	"gomentum/internal/instance"
+	"gomentum/internal/planner"
+	"gomentum/internal/prompts"
+	"gomentum/internal/reminder"
+	"gomentum/internal/schedule"
+	"gomentum/internal/search"
+)
+
+// runPlain is the screen-reader friendly interface: one line in, linear
+// text out, and every state change announced as its own sentence.
+func runPlain(cfg *config.Config, p *planner.Planner, ag agent.Agent, commands []prompts.Prompt, lock *instance.Lock, runReminders bool) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// All asynchronous announcements go through one channel so lines never interleave
+	notices := make(chan string, 16)
+	prompts := make(chan string, 16)
+
+	if runReminders {
+		go reminder.Run(ctx, p, cfg, func(t planner.Task) {
+			notices <- i18n.T("plain.reminder", i18n.FormatTime(t.StartTime), t.Title)
+		})
+	}
+	if lock != nil {
+		lock.SetHandler(bridgeHandler(func(text string) {
+			prompts <- text
+		}))
+	}
+	if cfg.Update.CheckOnStartup && !cfg.Update.DisableNetwork {
+		go checkForUpdate(func(tag string) {
+			notices <- i18n.T("tui.update_available", tag)
+		})
+	}
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	// A broken embeddings config already failed the agent; nil turns /search off
+	searcher, _ := search.New(cfg.Embeddings, cfg.Agent.Redact, p)
+
+	fmt.Println(i18n.T("tui.welcome"))
+	fmt.Println(i18n.T("plain.help"))
+	announceTasks(p)
+	fmt.Print("> ")
+
+	// The last prompt sent, for /retry and /edit
+	var last string
+	for {
+		select {
+		case notice := <-notices:
+			fmt.Println()
+			fmt.Println(notice)
+			fmt.Print("> ")
+
+		case text := <-prompts:
+			fmt.Println()
+			fmt.Println(i18n.T("plain.quickadd", text))
+			plainTurn(ctx, p, ag, text)
+			last = text
+			fmt.Print("> ")
+
+		case line, ok := <-lines:
+			if !ok {
+				return
+			}
+			input := strings.TrimSpace(line)
+			if input == "/retry" || strings.HasPrefix(input, "/edit ") {
+				prompt := last
+				if input != "/retry" {
+					prompt = strings.TrimSpace(strings.TrimPrefix(input, "/edit "))
+				}
+				err := agent.ErrNothingToRewind
+				if last != "" {
+					err = ag.Rewind()
+				}
+				if err != nil {
+					fmt.Println(rewindError(err))
+				} else {
+					plainTurn(ctx, p, ag, prompt)
+					last = prompt
+				}
+				fmt.Print("> ")
+				continue
+			}
+			if arg, ok := isDebugCommand(input); ok {
+				fmt.Println(toggleDebug(arg))
+				fmt.Print("> ")
+				continue
+			}
+			if query, ok := isSearchCommand(input); ok {
+				plainSearch(ctx, searcher, query)
+				fmt.Print("> ")
+				continue
+			}
+			switch input {
+			case "":
+			case "/quit", "/exit":
+				return
+			case "/tasks":
+				announceTasks(p)
+			case remindersCommand:
+				fmt.Println(reminderHistory(p, 10))
+			case "/help":
+				fmt.Println(i18n.T("plain.help"))
+				if len(commands) > 0 {
+					fmt.Println(i18n.T("plain.prompts", commandList(commands)))
+				}
+			default:
+				prompt, ok := expandCommand(commands, input)
+				if !ok {
+					fmt.Println(prompt)
+					break
+				}
+				plainTurn(ctx, p, ag, prompt)
+				last = prompt
+			}
+			fmt.Print("> ")
+		}
+	}
+}
+
+// plainTurn runs one agent turn and announces what changed
+func plainTurn(ctx context.Context, p *planner.Planner, ag agent.Agent, input string) {
+	before, _ := p.ListTasks()
+
+	fmt.Println(i18n.T("plain.thinking"))
+	fmt.Print(i18n.T("tui.assistant") + ": ")
+	_, err := ag.Chat(ctx, expandTaskRefs(p, input), func(token string) {
+		fmt.Print(token)
+	})
+	fmt.Println()
+	if err != nil {
+		fmt.Println(i18n.T("tui.error", err))
+		return
+	}
+	fmt.Println(i18n.T("plain.done"))
+
+	after, _ := p.ListTasks()
+	if summary := taskChanges(before, after); summary != "" {
+		fmt.Println(summary)
+	}
+}
+
+// taskChanges describes how the task list changed in one sentence
+func taskChanges(before, after []planner.Task) string {
+	old := make(map[int]planner.Task, len(before))
+	for _, t := range before {
+		old[t.ID] = t
+	}
+
+	var added, updated int
+	for _, t := range after {
+		prev, ok := old[t.ID]
+		switch {
+		case !ok:
+			added++
+		case !reflect.DeepEqual(prev, t):
+			updated++
+		}
+		delete(old, t.ID)
+	}
+	removed := len(old)
+
+	if added+updated+removed == 0 {
+		return ""
+	}
+	return i18n.T("plain.changes", added, updated, removed)
+}
+
+// announceTasks reads the task list as plain sentences
+func announceTasks(p *planner.Planner) {
+	tasks, err := p.ListTasks()
+	if err != nil {
+		fmt.Println(i18n.T("tui.error", err))
+		return
+	}
+	if len(tasks) == 0 {
+		fmt.Println(i18n.T("plain.no_tasks"))
+		return
+	}
+
+	fmt.Println(i18n.T("plain.task_count", len(tasks)))
+	for _, t := range tasks {
+		fmt.Println(plainTaskLine(t))
+	}
+	for _, d := range schedule.Overloaded(tasks, time.Now(), 7) {
+		fmt.Println(d.Warning())
+	}
+}
+
+// plainTaskLine describes a task in one sentence
+func plainTaskLine(t planner.Task) string {
+	date := calendar.Annotate(i18n.FormatDate(t.StartTime), t.StartTime)
+	line := i18n.T("plain.task_line", t.ID, t.Title, date,
+		i18n.FormatTime(t.StartTime), i18n.FormatTime(t.EndTime), plainStatus(t))
+	if !t.Deadline.IsZero() {
+		line += ", " + i18n.T("tui.due", i18n.FormatDateTime(t.Deadline))
+	}
+	return line
+}
+
+// plainStatus is the status label without its icon
+func plainStatus(t planner.Task) string {
+	_, label := taskState(t.Status, t.EndTime, time.Now())
+	return label
+}
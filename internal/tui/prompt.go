@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// prompt is a one-line question shown above the task list, such as the
+// date to go to. It takes every key until answered or dismissed with Esc.
+type prompt struct {
+	input textinput.Model
+	err   string
+	// submit acts on the answer; a non-empty message keeps the prompt
+	// open and is shown below it
+	submit func(m *model, answer string) (tea.Cmd, string)
+}
+
+// openPrompt asks a question above the task list, showing it first if
+// the chat fills the screen
+func (m *model) openPrompt(label, placeholder string, submit func(m *model, answer string) (tea.Cmd, string)) {
+	m.showSidebar()
+	ti := textinput.New()
+	ti.Prompt = label + " "
+	ti.Placeholder = placeholder
+	ti.Width = max(m.taskList.Width()-lipgloss.Width(ti.Prompt)-1, 10)
+	ti.Cursor.SetMode(cursor.CursorStatic)
+	ti.Focus()
+	m.prompt = &prompt{input: ti, submit: submit}
+}
+
+// promptKey handles keys while a prompt is open
+func (m model) promptKey(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.prompt = nil
+		return m, nil
+	case tea.KeyEnter:
+		p := m.prompt
+		m.prompt = nil
+		cmd, msg := p.submit(&m, p.input.Value())
+		if msg != "" {
+			p.err = msg
+			m.prompt = p
+		}
+		return m, cmd
+	}
+	p := *m.prompt
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(key)
+	p.err = ""
+	m.prompt = &p
+	return m, cmd
+}
+
+// promptView renders the prompt with the list below it
+func (m model) promptView() string {
+	l := m.taskList
+	l.SetSize(l.Width(), l.Height()-2)
+	note := ""
+	if m.prompt.err != "" {
+		note = errorMessageStyle(m.prompt.err)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, m.prompt.input.View(), note, l.View())
+}
@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"gomentum/internal/planner"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// taskRef matches task references such as "#12". A letter, digit, & or #
+// right before the # rules out things like C#1 and &#123;.
+var taskRef = regexp.MustCompile(`(^|[^\w&#])#(\d+)\b`)
+
+// taskRefs returns the IDs referenced in text, in order of appearance
+func taskRefs(text string) []int {
+	var ids []int
+	for _, m := range taskRef.FindAllStringSubmatch(text, -1) {
+		id, err := strconv.Atoi(m[2])
+		if err == nil && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// expandTaskRefs appends the details of every referenced task to a
+// prompt, so the agent need not look them up. Unknown IDs are left as
+// they are.
+func expandTaskRefs(p *planner.Planner, text string) string {
+	var tasks []planner.Task
+	for _, id := range taskRefs(text) {
+		if t, err := p.GetTask(id); err == nil {
+			tasks = append(tasks, t)
+		}
+	}
+	if len(tasks) == 0 {
+		return text
+	}
+	data, err := json.Marshal(tasks)
+	if err != nil {
+		return text
+	}
+	return text + "\n\nReferenced tasks: " + string(data)
+}
+
+// markTaskRefs renders references in bold so they stand out in a reply
+func markTaskRefs(text string) string {
+	return taskRef.ReplaceAllString(text, "$1**#$2**")
+}
+
+// focusTask selects the task in the sidebar, clearing any filter that
+// hides it. It reports false if the task is not in the list.
+func (m *model) focusTask(id int) bool {
+	if m.taskList.FilterState() != list.Unfiltered {
+		m.taskList.ResetFilter()
+	}
+	for i, item := range m.taskList.Items() {
+		if t, ok := item.(taskItem); ok && t.id == id {
+			m.taskList.Select(i)
+			if m.narrow() {
+				m.tab = layoutTasks
+				m.resize()
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// nextRef focuses the next task referenced by the last reply, wrapping
+// around after the last one
+func (m *model) nextRef() {
+	for range m.refs {
+		id := m.refs[m.refIdx%len(m.refs)]
+		m.refIdx++
+		if m.focusTask(id) {
+			return
+		}
+	}
+}
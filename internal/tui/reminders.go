@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"strings"
+
+	"gomentum/internal/i18n"
+	"gomentum/internal/planner"
+	"gomentum/internal/reminder"
+)
+
+// remindersCommand shows the reminder history in the TUI and plain mode
+const remindersCommand = "/reminders"
+
+// reminderHistory lists the latest reminders and how each channel fared,
+// one markdown list item per reminder
+func reminderHistory(p *planner.Planner, limit int) string {
+	history, err := p.ReminderHistory(0, limit)
+	if err != nil {
+		return i18n.T("tui.error", err)
+	}
+	if len(history) == 0 {
+		return i18n.T("tui.reminders_empty")
+	}
+
+	lines := []string{i18n.T("tui.reminders_title")}
+	for _, r := range history {
+		title := r.Title
+		if title == "" {
+			title = i18n.T("tui.deleted_task", r.TaskID)
+		}
+		var results []string
+		for _, d := range r.Deliveries {
+			results = append(results, deliveryResult(d))
+		}
+		lines = append(lines, "- "+i18n.T("tui.reminder_line", i18n.FormatDateTime(r.Start), title, strings.Join(results, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func deliveryResult(d planner.Delivery) string {
+	switch {
+	case !d.DeliveredAt.IsZero():
+		return i18n.T("tui.delivery_sent", d.Channel, i18n.FormatTime(d.DeliveredAt))
+	case d.Attempts >= reminder.MaxAttempts:
+		return i18n.T("tui.delivery_failed", d.Channel, d.Attempts, d.LastError)
+	}
+	return i18n.T("tui.delivery_retrying", d.Channel, d.Attempts, d.LastError)
+}
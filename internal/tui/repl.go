@@ -2,11 +2,24 @@ package tui
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"gomentum/internal/agent"
+	"gomentum/internal/calendar"
 	"gomentum/internal/config"
+	"gomentum/internal/i18n"
+	"gomentum/internal/instance"
 	"gomentum/internal/mcp"
+	"gomentum/internal/netconf"
 	"gomentum/internal/planner"
+	"gomentum/internal/projects"
+	"gomentum/internal/prompts"
+	"gomentum/internal/reminder"
+	"gomentum/internal/schedule"
+	"gomentum/internal/stats"
+	"gomentum/internal/telemetry"
+	"gomentum/internal/update"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -14,7 +27,6 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/gen2brain/beeep"
 )
 
 // WaitPressEnter pauses execution to allow user to read output before window closes
@@ -40,19 +52,45 @@ func WaitPressEnter() {
 	}
 }
 
+// Options controls how the interface is rendered
+type Options struct {
+	// Plain uses linear, screen-reader friendly output: no altscreen,
+	// box drawing or colors.
+	Plain bool
+}
+
 // Start launches the Bubble Tea TUI for Gomentum
-func Start() {
+func Start(opts Options) {
 	// Determine config path
-	homeDir, err := os.UserHomeDir()
+	configDir, err := config.DefaultDir()
 	if err != nil {
 		fmt.Printf("Error getting user home directory: %v\n", err)
 		os.Exit(1)
 	}
-	configDir := filepath.Join(homeDir, ".gomentum")
 	configPath := filepath.Join(configDir, "config.yaml")
 
-	// Check if config exists
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+	// Only one instance may run the reminder poller at a time
+	// If the background daemon holds the lock, it already owns the reminders
+	lock, running, err := instance.Acquire(configDir, "tui")
+	runReminders := true
+	switch {
+	case errors.Is(err, instance.ErrRunning) && running.Mode != "tui":
+		slog.Info("Daemon is running; TUI will not poll reminders", "pid", running.PID)
+		runReminders = false
+	case errors.Is(err, instance.ErrRunning):
+		fmt.Printf("Gomentum is already running (PID %d).\n", running.PID)
+		fmt.Println("Use `gomentum quickadd \"...\"` to send it a request instead.")
+		WaitPressEnter()
+		os.Exit(1)
+	case err != nil:
+		slog.Warn("Failed to acquire instance lock", "error", err)
+	default:
+		defer lock.Release()
+	}
+
+	// Check if config exists. Deployments configured purely through
+	// environment variables or .env skip the interactive setup.
+	if _, err := os.Stat(configPath); os.IsNotExist(err) && !envConfigured(configPath) {
 		fmt.Println("Configuration file not found. Starting first-run setup...")
 
 		// Create directory
@@ -119,6 +157,35 @@ func Start() {
 		os.Exit(1)
 	}
 
+	if err := i18n.Init(cfg.Locale); err != nil {
+		slog.Warn("Failed to load locale", "locale", cfg.Locale, "error", err)
+	}
+	i18n.Configure(cfg.Format)
+	calendar.SetSecondary(cfg.SecondaryCalendar)
+	schedule.Configure(cfg.Schedule)
+	stats.Configure(cfg.Activity)
+	projects.Configure(cfg.TagIcons.Field, cfg.Projects)
+	planner.ConfigureStatuses(cfg.Statuses)
+	telemetry.Init(cfg.Telemetry, configDir)
+	if err := netconf.Configure(cfg.Network); err != nil {
+		fmt.Printf("\nError loading config: %v\n", err)
+		WaitPressEnter()
+		os.Exit(1)
+	}
+
+	// Inside a git repository the plan may live with the code
+	project, err := cfg.UseProjectDatabase(askProjectDatabase)
+	if err != nil {
+		fmt.Printf("\nError loading config: %v\n", err)
+		WaitPressEnter()
+		os.Exit(1)
+	}
+	if project {
+		slog.Info("Using project database", "path", cfg.Database.Path)
+		// A running daemon only polls the global database
+		runReminders = true
+	}
+
 	// Initialize Planner
 	p, err := planner.NewPlanner(cfg.Database.Path)
 	if err != nil {
@@ -132,6 +199,12 @@ func Start() {
 	// Initialize MCP Server
 	ms := mcp.NewServer(p)
 
+	// Prompt workflows, run as /commands
+	commands, err := prompts.Load(prompts.Dir(configDir))
+	if err != nil {
+		slog.Warn("Failed to load prompts", "error", err)
+	}
+
 	// Initialize Agent
 	ag, err := agent.NewAgent(cfg, ms, p)
 	if err != nil {
@@ -142,13 +215,52 @@ func Start() {
 		os.Exit(1)
 	}
 
+	// Usage reports are sent while the TUI runs (a no-op unless opted in)
+	telemetryCtx, stopTelemetry := context.WithCancel(context.Background())
+	go telemetry.Run(telemetryCtx)
+	defer func() {
+		stopTelemetry()
+		telemetry.Close()
+	}()
+
+	if opts.Plain {
+		telemetry.Count("tui.plain")
+		runPlain(cfg, p, ag, commands, lock, runReminders)
+		return
+	}
+	telemetry.Count("tui")
+
 	// Start background reminder
-	go startReminder(p)
+	if runReminders {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go reminder.Run(ctx, p, cfg, nil)
+	}
 
 	// Start Bubble Tea Program
 	// Note: WithAltScreen might cause issues if the terminal closes immediately after exit.
 	// But for a TUI app, it's standard.
-	prog := tea.NewProgram(InitialModel(cfg, p, ag), tea.WithAltScreen())
+	prog := tea.NewProgram(InitialModel(cfg, p, ag, commands), tea.WithAltScreen())
+	if lock != nil {
+		lock.SetHandler(bridgeHandler(func(text string) {
+			prog.Send(quickAddMsg(text))
+		}))
+	}
+	if cfg.Update.CheckOnStartup && !cfg.Update.DisableNetwork {
+		go checkForUpdate(func(tag string) {
+			prog.Send(updateAvailableMsg(tag))
+		})
+	}
+
+	// Tasks changed elsewhere, e.g. by an MCP client talking to the daemon,
+	// show up in the sidebar within a second
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	go func() {
+		if err := p.Watch(watchCtx, time.Second, func() { prog.Send(tasksChangedMsg{}) }); err != nil {
+			slog.Warn("Failed to watch for task changes", "error", err)
+		}
+	}()
 	if _, err := prog.Run(); err != nil {
 		fmt.Printf("Alas, there's been an error: %v", err)
 		WaitPressEnter()
@@ -156,30 +268,47 @@ func Start() {
 	}
 }
 
-func startReminder(p *planner.Planner) {
-	// Check every 10 seconds for better responsiveness
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
+// askProjectDatabase offers to create the project database at path
+func askProjectDatabase(path string) bool {
+	fmt.Printf("Create a project database at %s? [y/N]: ", path)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
 
-	for range ticker.C {
-		// Find tasks that are due now (or past due)
-		// We pass 0 duration because we want to trigger exactly at StartTime,
-		// not 15 minutes before.
-		tasks, err := p.GetUpcomingTasks(0)
-		if err != nil {
-			continue
-		}
+// envConfigured reports whether a usable config can be built without a file
+func envConfigured(configPath string) bool {
+	_, err := config.LoadConfig(configPath)
+	return err == nil
+}
 
-		for _, t := range tasks {
-			// Send system notification
-			msg := fmt.Sprintf("Time: %s\n%s", t.StartTime.Local().Format("15:04"), t.Description)
-			if err := beeep.Notify("Gomentum Reminder", msg, ""); err != nil {
-				// Silently fail or log to file if needed, but don't print to stdout
-				slog.Error("System notification failed", "error", err)
-			}
+// checkForUpdate calls notify when a newer release is published
+func checkForUpdate(notify func(tag string)) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-			// Mark as reminded
-			_ = p.MarkAsReminded(t.ID)
+	rel, err := update.NewUpdater().Check(ctx)
+	if err != nil {
+		slog.Warn("Update check failed", "error", err)
+		return
+	}
+	if rel != nil {
+		notify(rel.Tag)
+	}
+}
+
+// bridgeHandler forwards requests from other Gomentum processes into the UI
+func bridgeHandler(quickAdd func(text string)) instance.Handler {
+	return func(req instance.Request) instance.Response {
+		switch req.Command {
+		case "quickadd":
+			if strings.TrimSpace(req.Text) == "" {
+				return instance.Response{Message: "empty quick-add text"}
+			}
+			quickAdd(req.Text)
+			return instance.Response{OK: true, Message: "Sent to running Gomentum instance"}
+		default:
+			return instance.Response{Message: fmt.Sprintf("unknown command: %s", req.Command)}
 		}
 	}
 }
@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"errors"
+
+	"gomentum/internal/agent"
+	"gomentum/internal/i18n"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// rewind takes back the last turn: the agent forgets it and the chat
+// drops the prompt and everything shown after it. Without a turn to take
+// back it shows a notice and returns false.
+func (m *model) rewind() bool {
+	if m.isThinking {
+		return false
+	}
+	err := agent.ErrNothingToRewind
+	if m.lastPrompt != "" {
+		err = m.agent.Rewind()
+	}
+	if err != nil {
+		m.messages = append(m.messages, "*"+rewindError(err)+"*")
+		m.renderChat()
+		return false
+	}
+	m.messages = m.messages[:m.turnStart]
+	m.lastPrompt = ""
+	m.renderChat()
+	return true
+}
+
+// rewindError explains why a turn could not be taken back
+func rewindError(err error) string {
+	if errors.Is(err, agent.ErrNothingToRewind) {
+		return i18n.T("tui.nothing_to_rewind")
+	}
+	return i18n.T("tui.error", err)
+}
+
+// regenerate sends the last prompt again for a new reply
+func (m *model) regenerate() tea.Cmd {
+	prompt := m.lastPrompt
+	if !m.rewind() {
+		return nil
+	}
+	return m.submit(prompt)
+}
+
+// editLast takes back the last turn and puts its message in the input
+func (m *model) editLast() {
+	input := m.lastInput
+	if !m.rewind() {
+		return
+	}
+	m.textarea.SetValue(input)
+	m.updateSuggestions()
+}
@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gomentum/internal/i18n"
+	"gomentum/internal/planner"
+	"gomentum/internal/search"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// searchCommand lists the tasks closest in meaning to its argument, best
+// first: "/search dentist" finds "Dental checkup". In the TUI the results
+// replace the task list until "/search" alone shows every task again.
+const searchCommand = "/search"
+
+// isSearchCommand reports whether input is /search, returning the query
+func isSearchCommand(input string) (string, bool) {
+	name, query, _ := strings.Cut(strings.TrimSpace(input), " ")
+	return strings.TrimSpace(query), name == searchCommand
+}
+
+// searchMsg carries the IDs of the tasks found for a query, best first
+type searchMsg struct {
+	query string
+	ids   []int
+}
+
+// runSearch searches off the UI thread, as the embeddings endpoint may be
+// remote
+func (m model) runSearch(query string) tea.Cmd {
+	searcher := m.searcher
+	return func() tea.Msg {
+		results, err := searcher.Search(context.Background(), query, search.DefaultLimit, false)
+		if err != nil {
+			return errMsg(err)
+		}
+		ids := make([]int, len(results))
+		for i, r := range results {
+			ids[i] = r.ID
+		}
+		return searchMsg{query: query, ids: ids}
+	}
+}
+
+// searchResults picks the tasks of ids in their order, skipping any
+// deleted since the search
+func searchResults(tasks []planner.Task, ids []int) []planner.Task {
+	byID := make(map[int]planner.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+	var out []planner.Task
+	for _, id := range ids {
+		if t, ok := byID[id]; ok {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// plainSearch runs /search in plain mode and prints the tasks found
+func plainSearch(ctx context.Context, s *search.Searcher, query string) {
+	switch {
+	case s == nil:
+		fmt.Println(i18n.T("tui.search_off"))
+		return
+	case query == "":
+		fmt.Println(i18n.T("tui.search_usage"))
+		return
+	}
+	results, err := s.Search(ctx, query, search.DefaultLimit, false)
+	if err != nil {
+		fmt.Println(i18n.T("tui.error", err))
+		return
+	}
+	if len(results) == 0 {
+		fmt.Println(i18n.T("tui.search_none", query))
+		return
+	}
+	fmt.Println(i18n.T("plain.search_count", len(results), query))
+	for _, r := range results {
+		fmt.Println(plainTaskLine(r.Task))
+	}
+}
@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"gomentum/internal/i18n"
+	"gomentum/internal/planner"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// nextSmartList shows the next saved smart list in the task list, and
+// every task again after the last one
+func (m *model) nextSmartList() tea.Cmd {
+	lists, err := m.planner.SmartLists()
+	if err != nil {
+		return func() tea.Msg { return errMsg(err) }
+	}
+	if len(lists) == 0 && m.smartList == "" {
+		m.messages = append(m.messages, "*"+i18n.T("tui.smart_lists_none")+"*")
+		m.renderChat()
+		return nil
+	}
+	i := slices.IndexFunc(lists, func(l planner.SmartList) bool { return strings.EqualFold(l.Name, m.smartList) })
+	m.smartList = ""
+	if i+1 < len(lists) {
+		m.smartList = lists[i+1].Name
+	}
+	m.taskList.ResetSelected()
+	m.followNow = true
+	return m.refreshTasks
+}
+
+// nextTagFilter limits the task list to the next tag, and shows every
+// task again after the last one
+func (m *model) nextTagFilter() tea.Cmd {
+	all, err := m.planner.Tags()
+	if err != nil {
+		return func() tea.Msg { return errMsg(err) }
+	}
+	if len(all) == 0 && m.tagFilter == "" {
+		m.messages = append(m.messages, "*"+i18n.T("tui.tags_none")+"*")
+		m.renderChat()
+		return nil
+	}
+	i := slices.Index(all, m.tagFilter)
+	m.tagFilter = ""
+	if i+1 < len(all) {
+		m.tagFilter = all[i+1]
+	}
+	m.taskList.ResetSelected()
+	m.followNow = true
+	return m.refreshTasks
+}
+
+// smartListTasks returns the tasks of the shown smart list that overlap
+// the visible day or week, if one is set
+func (m model) smartListTasks(now time.Time) ([]planner.Task, error) {
+	l, err := m.planner.GetSmartList(m.smartList)
+	if err != nil {
+		return nil, err
+	}
+	f, err := planner.ParseQuery(l.Query, now)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := m.planner.QueryTasks(f)
+	if err != nil || m.rangeScope == rangeAll {
+		return tasks, err
+	}
+	from, to := m.dateRange()
+	return slices.DeleteFunc(tasks, func(t planner.Task) bool {
+		return !t.StartTime.Before(to) || (t.StartTime.Before(from) && !t.EndTime.After(from))
+	}), nil
+}
@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gomentum/internal/config"
+	"gomentum/internal/planner"
+)
+
+// defaultTagIcons are Nerd Font glyphs for common projects
+var defaultTagIcons = map[string]string{
+	"work":     "\uf0b1", // briefcase
+	"personal": "\uf007", // user
+	"home":     "\uf015", // house
+	"family":   "\uf0c0", // users
+	"health":   "\uf21e", // heartbeat
+	"study":    "\uf02d", // book
+	"finance":  "\uf155", // dollar
+}
+
+// tagIcons looks up the glyph for a task's project, or whatever custom
+// field is configured
+type tagIcons struct {
+	field  string
+	icons  map[string]string // Lower-case field value -> glyph
+	colors map[string]string // Lower-case field value -> color label
+}
+
+// newTagIcons drops Nerd Font glyphs unless the font is enabled, so
+// terminals without it do not show boxes
+func newTagIcons(cfg config.TagIconsConfig) tagIcons {
+	src := cfg.Icons
+	if len(src) == 0 {
+		src = defaultTagIcons
+	}
+	icons := make(map[string]string, len(src))
+	for value, glyph := range src {
+		if glyph == "" || (!cfg.NerdFont && nerdGlyph(glyph)) {
+			continue
+		}
+		icons[strings.ToLower(value)] = glyph
+	}
+	colors := make(map[string]string, len(cfg.Colors))
+	for value, color := range cfg.Colors {
+		if color != "" && planner.ValidColor(color) {
+			colors[strings.ToLower(value)] = color
+		}
+	}
+	return tagIcons{field: cfg.Field, icons: icons, colors: colors}
+}
+
+// nerdGlyph reports whether s uses the private use areas Nerd Fonts patch
+// their icons into
+func nerdGlyph(s string) bool {
+	for _, r := range s {
+		if (r >= 0xE000 && r <= 0xF8FF) || r >= 0xF0000 {
+			return true
+		}
+	}
+	return false
+}
+
+// icon returns the glyph for t, or "" if its field has none
+func (ti tagIcons) icon(t planner.Task) string {
+	if ti.field == "" || len(ti.icons) == 0 {
+		return ""
+	}
+	value, ok := t.FieldString(ti.field)
+	if !ok {
+		return ""
+	}
+	return ti.icons[strings.ToLower(value)]
+}
+
+// color returns t's color label, or else the one of its field value
+func (ti tagIcons) color(t planner.Task) string {
+	if t.Color != "" || ti.field == "" {
+		return t.Color
+	}
+	value, _ := t.FieldString(ti.field)
+	return ti.colors[strings.ToLower(value)]
+}
+
+// summary counts today's unfinished tasks per glyph, e.g. "💼 3  🏡 1",
+// in the order the glyphs first appear
+func (ti tagIcons) summary(tasks []planner.Task, now time.Time) string {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	end := day.AddDate(0, 0, 1)
+	var order []string
+	counts := make(map[string]int)
+	for _, t := range tasks {
+		if t.Status.Done() || t.StartTime.Before(day) || !t.StartTime.Before(end) {
+			continue
+		}
+		glyph := ti.icon(t)
+		if glyph == "" {
+			continue
+		}
+		if counts[glyph] == 0 {
+			order = append(order, glyph)
+		}
+		counts[glyph]++
+	}
+	parts := make([]string, len(order))
+	for i, glyph := range order {
+		parts[i] = fmt.Sprintf("%s %d", glyph, counts[glyph])
+	}
+	return strings.Join(parts, "  ")
+}
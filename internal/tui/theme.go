@@ -0,0 +1,205 @@
+package tui
+
+import (
+	"log/slog"
+	"os"
+
+	"gomentum/internal/planner"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Theme is a named color palette for the TUI
+type Theme struct {
+	Name string
+
+	TitleFg  lipgloss.Color
+	TitleBg  lipgloss.Color
+	Selected lipgloss.Color // Selected task in the sidebar
+	Muted    lipgloss.Color // Secondary text
+	Prompt   lipgloss.Color // Textarea prompt
+	Success  lipgloss.Color
+	Warning  lipgloss.Color // High priority
+	Error    lipgloss.Color // Errors and urgent priority
+
+	// Glamour style used to render the chat
+	Glamour string
+
+	// NoColor strips all colors (NO_COLOR or theme: no-color)
+	NoColor bool
+}
+
+// The colorblind palettes use the Okabe-Ito colors, which stay distinct
+// under deuteranopia and protanopia by avoiding red/green contrasts.
+var themes = map[string]Theme{
+	"default": {
+		Name:     "default",
+		TitleFg:  "#FFFDF5",
+		TitleBg:  "#25A065",
+		Selected: "#AD58B4",
+		Muted:    "#777777",
+		Prompt:   "5",
+		Success:  "#04B575",
+		Warning:  "#FFA500",
+		Error:    "#FF0000",
+		Glamour:  "dark",
+	},
+	"high-contrast": {
+		Name:     "high-contrast",
+		TitleFg:  "#000000",
+		TitleBg:  "#FFFF00",
+		Selected: "#FFFF00",
+		Muted:    "#FFFFFF",
+		Prompt:   "#FFFFFF",
+		Success:  "#00FFFF",
+		Warning:  "#FFFF00",
+		Error:    "#FF00FF",
+		Glamour:  "dark",
+	},
+	"deuteranopia": {
+		Name:     "deuteranopia",
+		TitleFg:  "#FFFFFF",
+		TitleBg:  "#0072B2",
+		Selected: "#E69F00",
+		Muted:    "#999999",
+		Prompt:   "#56B4E9",
+		Success:  "#0072B2",
+		Warning:  "#F0E442",
+		Error:    "#D55E00",
+		Glamour:  "dark",
+	},
+	"protanopia": {
+		Name:     "protanopia",
+		TitleFg:  "#000000",
+		TitleBg:  "#56B4E9",
+		Selected: "#F0E442",
+		Muted:    "#999999",
+		Prompt:   "#56B4E9",
+		Success:  "#0072B2",
+		Warning:  "#CC79A7",
+		Error:    "#E69F00",
+		Glamour:  "dark",
+	},
+	"no-color": {
+		Name:    "no-color",
+		Glamour: "notty",
+		NoColor: true,
+	},
+}
+
+// ThemeNames lists the available themes
+func ThemeNames() []string {
+	return []string{"default", "high-contrast", "deuteranopia", "protanopia", "no-color"}
+}
+
+// resolveTheme picks the configured theme. NO_COLOR (https://no-color.org)
+// always wins over the config.
+func resolveTheme(name string) Theme {
+	if os.Getenv("NO_COLOR") != "" {
+		name = "no-color"
+	}
+	if name == "" {
+		name = "default"
+	}
+	th, ok := themes[name]
+	if !ok {
+		slog.Warn("Unknown theme, using default", "theme", name)
+		th = themes["default"]
+	}
+	if th.NoColor {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+	return th
+}
+
+// listStyles applies the theme to the task list and its delegate
+func (th Theme) listStyles(l *list.Model) list.DefaultDelegate {
+	d := list.NewDefaultDelegate()
+	if th.NoColor {
+		l.Styles.Title = lipgloss.NewStyle().Bold(true).Padding(0, 1)
+		d.Styles.SelectedTitle = d.Styles.SelectedTitle.Bold(true)
+		return d
+	}
+
+	l.Styles.Title = lipgloss.NewStyle().
+		Foreground(th.TitleFg).
+		Background(th.TitleBg).
+		Padding(0, 1)
+	d.Styles.SelectedTitle = d.Styles.SelectedTitle.
+		Foreground(th.Selected).
+		BorderLeftForeground(th.Selected)
+	d.Styles.SelectedDesc = d.Styles.SelectedDesc.
+		Foreground(th.Selected).
+		BorderLeftForeground(th.Selected)
+	d.Styles.NormalDesc = d.Styles.NormalDesc.Foreground(th.Muted)
+	return d
+}
+
+// label draws glyph in a task's color; without colors it names the color
+func (th Theme) label(glyph, color string) string {
+	hex := planner.ColorHex(color)
+	switch {
+	case hex == "":
+		return ""
+	case th.NoColor:
+		return "[" + color + "]"
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(hex)).Render(glyph)
+}
+
+// priority colors the priority icon of a task: urgent like an error, high
+// as a warning and low muted
+func (th Theme) priority(icon, p string) string {
+	if icon == "" || th.NoColor {
+		return icon
+	}
+	var color lipgloss.Color
+	switch p {
+	case planner.PriorityUrgent:
+		color = th.Error
+	case planner.PriorityHigh:
+		color = th.Warning
+	case planner.PriorityLow:
+		color = th.Muted
+	default:
+		return icon
+	}
+	return lipgloss.NewStyle().Foreground(color).Bold(p == planner.PriorityUrgent).Render(icon)
+}
+
+// tabStyle styles a tab of the narrow layout and the completion popup
+func (th Theme) tabStyle(active bool) lipgloss.Style {
+	s := lipgloss.NewStyle().Padding(0, 1)
+	switch {
+	case th.NoColor && active:
+		return s.Bold(true).Underline(true)
+	case th.NoColor:
+		return s
+	case active:
+		return s.Foreground(th.TitleFg).Background(th.TitleBg)
+	}
+	return s.Foreground(th.Muted)
+}
+
+// dayStyle styles a day of the month view: the cursor stands out like an
+// active tab and today is underlined
+func (th Theme) dayStyle(selected, today bool) lipgloss.Style {
+	s := lipgloss.NewStyle().Underline(today)
+	switch {
+	case selected && th.NoColor:
+		return s.Reverse(true)
+	case selected:
+		return s.Foreground(th.TitleFg).Background(th.TitleBg)
+	}
+	return s
+}
+
+// promptStyle colors the sender label and textarea prompt
+func (th Theme) promptStyle() lipgloss.Style {
+	if th.NoColor {
+		return lipgloss.NewStyle()
+	}
+	return lipgloss.NewStyle().Foreground(th.Prompt)
+}
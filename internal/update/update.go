@@ -0,0 +1,285 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"gomentum/internal/version"
+)
+
+// ErrNetworkDisabled is returned when update.disable_network is set
+var ErrNetworkDisabled = errors.New("network checks are disabled in config (update.disable_network)")
+
+const (
+	apiBase       = "https://api.github.com"
+	checksumsName = "checksums.txt"
+	maxDownload   = 200 << 20 // 200 MiB
+)
+
+// Release describes a published GitHub release
+type Release struct {
+	Tag    string  `json:"tag_name"`
+	URL    string  `json:"html_url"`
+	Assets []Asset `json:"assets"`
+}
+
+// Asset is a downloadable file attached to a release
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Updater checks for and installs new releases
+type Updater struct {
+	client  *http.Client
+	repo    string
+	current string
+}
+
+// NewUpdater creates an updater for the running build
+func NewUpdater() *Updater {
+	return &Updater{
+		client:  &http.Client{Timeout: 60 * time.Second},
+		repo:    version.Repo,
+		current: version.Version,
+	}
+}
+
+// Latest fetches the most recent release
+func (u *Updater) Latest(ctx context.Context) (*Release, error) {
+	url := fmt.Sprintf("%s/repos/%s/releases/latest", apiBase, u.repo)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest release: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to query latest release: %s", resp.Status)
+	}
+
+	var rel Release
+	if err := json.NewDecoder(resp.Body).Decode(&rel); err != nil {
+		return nil, fmt.Errorf("failed to decode release: %w", err)
+	}
+	return &rel, nil
+}
+
+// Check returns the latest release if it is newer than the running build,
+// or nil when already up to date.
+func (u *Updater) Check(ctx context.Context) (*Release, error) {
+	rel, err := u.Latest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !Newer(rel.Tag, u.current) {
+		return nil, nil
+	}
+	return rel, nil
+}
+
+// Apply downloads the archive for this platform, verifies it against the
+// release checksums and replaces the executable at exe. The previous binary
+// is kept next to it with an ".old" suffix.
+func (u *Updater) Apply(ctx context.Context, rel *Release, exe string) error {
+	archiveName := ArchiveName(rel.Tag, runtime.GOOS, runtime.GOARCH)
+	archive, ok := findAsset(rel, archiveName)
+	if !ok {
+		return fmt.Errorf("release %s has no asset %s", rel.Tag, archiveName)
+	}
+	sums, ok := findAsset(rel, checksumsName)
+	if !ok {
+		return fmt.Errorf("release %s has no %s, refusing to install unverified binary", rel.Tag, checksumsName)
+	}
+
+	sumData, err := u.download(ctx, sums.URL)
+	if err != nil {
+		return err
+	}
+	want, err := lookupChecksum(sumData, archiveName)
+	if err != nil {
+		return err
+	}
+
+	data, err := u.download(ctx, archive.URL)
+	if err != nil {
+		return err
+	}
+	got := sha256.Sum256(data)
+	if hex.EncodeToString(got[:]) != want {
+		return fmt.Errorf("checksum mismatch for %s", archiveName)
+	}
+
+	binary, err := extractBinary(archiveName, data)
+	if err != nil {
+		return err
+	}
+	return replaceExecutable(exe, binary)
+}
+
+func (u *Updater) download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	return data, nil
+}
+
+// ArchiveName matches the naming used by the release workflow
+func ArchiveName(tag, goos, goarch string) string {
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+	return fmt.Sprintf("gomentum_%s_%s_%s%s", tag, goos, goarch, ext)
+}
+
+func findAsset(rel *Release, name string) (Asset, bool) {
+	for _, a := range rel.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Asset{}, false
+}
+
+// lookupChecksum parses sha256sum output ("<hex>  <name>")
+func lookupChecksum(data []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("no checksum listed for %s", name)
+}
+
+func extractBinary(archiveName string, data []byte) ([]byte, error) {
+	if strings.HasSuffix(archiveName, ".zip") {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open zip: %w", err)
+		}
+		for _, f := range zr.File {
+			if filepath.Base(f.Name) == "gomentum.exe" {
+				rc, err := f.Open()
+				if err != nil {
+					return nil, err
+				}
+				defer rc.Close()
+				return io.ReadAll(io.LimitReader(rc, maxDownload))
+			}
+		}
+		return nil, fmt.Errorf("gomentum.exe not found in %s", archiveName)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if hdr.Typeflag == tar.TypeReg && filepath.Base(hdr.Name) == "gomentum" {
+			return io.ReadAll(io.LimitReader(tr, maxDownload))
+		}
+	}
+	return nil, fmt.Errorf("gomentum binary not found in %s", archiveName)
+}
+
+// replaceExecutable swaps the binary. Renaming the running file is allowed
+// on all supported platforms, including Windows.
+func replaceExecutable(exe string, binary []byte) error {
+	info, err := os.Stat(exe)
+	if err != nil {
+		return fmt.Errorf("failed to stat executable: %w", err)
+	}
+
+	newPath := exe + ".new"
+	oldPath := exe + ".old"
+	if err := os.WriteFile(newPath, binary, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+
+	_ = os.Remove(oldPath)
+	if err := os.Rename(exe, oldPath); err != nil {
+		os.Remove(newPath)
+		return fmt.Errorf("failed to move current binary: %w", err)
+	}
+	if err := os.Rename(newPath, exe); err != nil {
+		// Roll back so the user is not left without a binary
+		_ = os.Rename(oldPath, exe)
+		return fmt.Errorf("failed to install new binary: %w", err)
+	}
+	return nil
+}
+
+// Newer reports whether release tag a is newer than b. Development builds
+// ("dev") are never considered outdated by the startup check.
+func Newer(a, b string) bool {
+	if b == "dev" || b == "" {
+		return false
+	}
+	pa, pb := parseVersion(a), parseVersion(b)
+	for i := 0; i < 3; i++ {
+		if pa[i] != pb[i] {
+			return pa[i] > pb[i]
+		}
+	}
+	return false
+}
+
+func parseVersion(v string) [3]int {
+	var out [3]int
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	for i, part := range strings.SplitN(v, ".", 3) {
+		n, _ := strconv.Atoi(part)
+		out[i] = n
+	}
+	return out
+}
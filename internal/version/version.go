@@ -0,0 +1,9 @@
+package version
+
+// Version is the release tag of this build, set at link time:
+//
+//	go build -ldflags "-X gomentum/internal/version.Version=v0.2.0" ./cmd/gomentum
+var Version = "dev"
+
+// Repo is the GitHub repository releases are published to
+const Repo = "zuquanzhi/gomentum"
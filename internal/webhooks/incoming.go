@@ -0,0 +1,151 @@
+package webhooks
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gomentum/internal/planner"
+	"gomentum/internal/projects"
+)
+
+// Properties lists the task properties an incoming payload can set.
+// duration is in minutes; project, location and url set custom fields.
+var Properties = []string{"title", "description", "start_time", "end_time", "duration", "deadline", "priority", "project", "location", "url"}
+
+// TaskFrom builds a task from the payload of an incoming webhook. fields
+// maps properties to payload keys, where a dotted key such as
+// "event.start" reaches into nested objects and a number into arrays;
+// unmapped properties are read from keys of the same name. A task without
+// a start time goes to the inbox; one with a start time needs an end
+// time, a duration or a project with a default duration.
+func TaskFrom(payload map[string]any, fields map[string]string) (planner.Task, error) {
+	for prop := range fields {
+		if !slices.Contains(Properties, prop) {
+			return planner.Task{}, fmt.Errorf("%w: unknown property %q in webhooks.fields, use one of %s", planner.ErrValidation, prop, strings.Join(Properties, ", "))
+		}
+	}
+	value := func(prop string) any {
+		key := prop
+		if k, ok := fields[prop]; ok {
+			key = k
+		}
+		return lookup(payload, key)
+	}
+	text := func(prop string) string {
+		switch v := value(prop).(type) {
+		case nil:
+			return ""
+		case string:
+			return strings.TrimSpace(v)
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			return strings.TrimSpace(fmt.Sprint(v))
+		}
+	}
+
+	t := planner.Task{Title: text("title"), Description: text("description"), Flexible: true}
+	if t.Title == "" {
+		return planner.Task{}, fmt.Errorf("%w: the payload has no title; map it with webhooks.fields.title", planner.ErrValidation)
+	}
+	custom := map[string]string{"project": projects.Field(), "location": planner.FieldLocation, "url": planner.FieldURL}
+	for prop, key := range custom {
+		if v := text(prop); v != "" {
+			t.SetField(key, v)
+		}
+	}
+
+	if p := strings.ToLower(text("priority")); p != "" {
+		if !slices.Contains(planner.Priorities, p) {
+			return planner.Task{}, fmt.Errorf("%w: unknown priority %q, use one of %s", planner.ErrValidation, p, strings.Join(planner.Priorities, ", "))
+		}
+		t.Priority = p
+	}
+	var err error
+	if t.Deadline, err = parseTime(value("deadline")); err != nil {
+		return planner.Task{}, fmt.Errorf("%w: invalid deadline: %v", planner.ErrValidation, err)
+	}
+	if t.StartTime, err = parseTime(value("start_time")); err != nil {
+		return planner.Task{}, fmt.Errorf("%w: invalid start_time: %v", planner.ErrValidation, err)
+	}
+	if t.EndTime, err = parseTime(value("end_time")); err != nil {
+		return planner.Task{}, fmt.Errorf("%w: invalid end_time: %v", planner.ErrValidation, err)
+	}
+
+	if t.StartTime.IsZero() {
+		now := time.Now()
+		t.StartTime, t.EndTime, t.Status = now, now, planner.StatusBacklog
+		projects.Apply(&t)
+		return t, nil
+	}
+	if t.EndTime.IsZero() {
+		if d := text("duration"); d != "" {
+			minutes, err := strconv.ParseFloat(d, 64)
+			if err != nil || minutes <= 0 {
+				return planner.Task{}, fmt.Errorf("%w: duration %q is not a positive number of minutes", planner.ErrValidation, d)
+			}
+			t.EndTime = t.StartTime.Add(time.Duration(minutes * float64(time.Minute)))
+		} else if d, ok := projects.Lookup(projects.Of(t)); ok && d.Duration > 0 {
+			t.EndTime = t.StartTime.Add(d.Duration)
+		} else {
+			return planner.Task{}, fmt.Errorf("%w: a task with a start_time needs an end_time, a duration or a project with a default duration", planner.ErrValidation)
+		}
+	}
+	if !t.EndTime.After(t.StartTime) {
+		return planner.Task{}, fmt.Errorf("%w: end_time must be after start_time", planner.ErrValidation)
+	}
+	projects.Apply(&t)
+	return t, nil
+}
+
+// lookup follows a dotted key through nested objects and arrays
+func lookup(v any, key string) any {
+	for _, part := range strings.Split(key, ".") {
+		switch node := v.(type) {
+		case map[string]any:
+			v = node[part]
+		case []any:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil
+			}
+			v = node[i]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+// parseTime reads RFC 3339, a local "2006-01-02 15:04" (or with a T), a
+// local date, or Unix seconds. Nothing yields the zero time.
+func parseTime(v any) (time.Time, error) {
+	switch v := v.(type) {
+	case nil:
+		return time.Time{}, nil
+	case float64:
+		return time.Unix(int64(v), 0), nil
+	case string:
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return time.Time{}, nil
+		}
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t, nil
+		}
+		for _, layout := range []string{"2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"} {
+			if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+				return t, nil
+			}
+		}
+		if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return time.Unix(secs, 0), nil
+		}
+		return time.Time{}, fmt.Errorf("%q is not a date or time", v)
+	default:
+		return time.Time{}, fmt.Errorf("%v is not a date or time", v)
+	}
+}
@@ -0,0 +1,226 @@
+// Package webhooks connects Gomentum to automation platforms such as
+// Zapier or n8n. Outgoing webhooks post task events as signed JSON while
+// the daemon runs; TaskFrom turns the payload of an incoming webhook into
+// a task through the configured field mapping.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gomentum/internal/config"
+	"gomentum/internal/planner"
+	"gomentum/internal/version"
+)
+
+// Events posted to outgoing webhooks
+const (
+	TaskCreated   = "task.created"
+	TaskUpdated   = "task.updated"
+	TaskCompleted = "task.completed"
+	TaskDeleted   = "task.deleted"
+)
+
+// Events lists every event, in the order a task goes through them
+var Events = []string{TaskCreated, TaskUpdated, TaskCompleted, TaskDeleted}
+
+const (
+	// pollInterval is how often the database is checked for changes
+	pollInterval = 2 * time.Second
+	// timeout bounds each request so a slow endpoint cannot hold up others
+	timeout = 10 * time.Second
+	// attempts is how often a failed delivery is tried in all
+	attempts = 3
+)
+
+// Event is the payload of an outgoing webhook
+type Event struct {
+	Event string       `json:"event"`
+	Time  time.Time    `json:"time"`
+	Task  planner.Task `json:"task"`
+}
+
+// Sign returns the X-Gomentum-Signature of body: "sha256=" and the hex
+// HMAC-SHA256 of the body keyed with secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the X-Gomentum-Signature of body
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(strings.TrimSpace(signature)))
+}
+
+// Validate reports webhooks without an http(s) URL or with unknown events
+func Validate(hooks []config.WebhookConfig) error {
+	for _, h := range hooks {
+		u, err := url.Parse(h.URL)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("webhook URL %q is not an http or https link", h.URL)
+		}
+		for _, e := range h.Events {
+			if !slices.Contains(Events, e) {
+				return fmt.Errorf("unknown webhook event %q", e)
+			}
+		}
+	}
+	return nil
+}
+
+// Run posts an event to the outgoing webhooks for every task that is
+// created, changed, completed or deleted, until ctx is cancelled. Changes
+// are found by comparing the tasks after each database change, so they
+// are caught whichever process makes them. System tasks such as breaks
+// are left out.
+func Run(ctx context.Context, cfg config.WebhooksConfig, p *planner.Planner) {
+	if len(cfg.Outgoing) == 0 {
+		return
+	}
+	if err := Validate(cfg.Outgoing); err != nil {
+		slog.Error("Webhooks are disabled", "error", err)
+		return
+	}
+	tasks, err := snapshot(p)
+	if err != nil {
+		slog.Error("Webhooks stopped", "error", err)
+		return
+	}
+
+	// Events are sent in order by one goroutine, so a slow endpoint delays
+	// the events but never the watch
+	queue := make(chan Event, 256)
+	go func() {
+		for e := range queue {
+			deliver(ctx, cfg.Outgoing, e)
+		}
+	}()
+	defer close(queue)
+
+	err = p.Watch(ctx, pollInterval, func() {
+		next, err := snapshot(p)
+		if err != nil {
+			slog.Error("Failed to read tasks for webhooks", "error", err)
+			return
+		}
+		for _, e := range diff(tasks, next, time.Now()) {
+			select {
+			case queue <- e:
+			default:
+				slog.Warn("Webhook queue is full, dropping event", "event", e.Event, "task", e.Task.ID)
+			}
+		}
+		tasks = next
+	})
+	if err != nil {
+		slog.Error("Webhooks stopped", "error", err)
+	}
+}
+
+// snapshot returns the tasks by ID, without system tasks
+func snapshot(p *planner.Planner) (map[int]planner.Task, error) {
+	list, err := p.ListTasks()
+	if err != nil {
+		return nil, err
+	}
+	tasks := make(map[int]planner.Task, len(list))
+	for _, t := range list {
+		if t.System() == "" {
+			tasks[t.ID] = t
+		}
+	}
+	return tasks, nil
+}
+
+// diff lists the events that lead from before to after, by task ID. A
+// task that turns done is completed rather than updated.
+func diff(before, after map[int]planner.Task, now time.Time) []Event {
+	var events []Event
+	for id, t := range after {
+		old, ok := before[id]
+		switch {
+		case !ok:
+			events = append(events, Event{Event: TaskCreated, Time: now, Task: t})
+		case t.Status.Done() && !old.Status.Done():
+			events = append(events, Event{Event: TaskCompleted, Time: now, Task: t})
+		case !t.UpdatedAt.Equal(old.UpdatedAt):
+			events = append(events, Event{Event: TaskUpdated, Time: now, Task: t})
+		}
+	}
+	for id, t := range before {
+		if _, ok := after[id]; !ok {
+			events = append(events, Event{Event: TaskDeleted, Time: now, Task: t})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Task.ID < events[j].Task.ID })
+	return events
+}
+
+// deliver posts e to every webhook subscribed to it, retrying failures
+// with a growing delay
+func deliver(ctx context.Context, hooks []config.WebhookConfig, e Event) {
+	body, err := json.Marshal(e)
+	if err != nil {
+		slog.Error("Failed to encode webhook event", "event", e.Event, "error", err)
+		return
+	}
+	for _, h := range hooks {
+		if len(h.Events) > 0 && !slices.Contains(h.Events, e.Event) {
+			continue
+		}
+		delay := time.Second
+		for attempt := 1; ; attempt++ {
+			err := post(ctx, h, e.Event, body)
+			if err == nil {
+				slog.Info("Webhook sent", "event", e.Event, "task", e.Task.ID, "url", h.URL)
+				break
+			}
+			if attempt == attempts || ctx.Err() != nil {
+				slog.Warn("Webhook failed", "event", e.Event, "task", e.Task.ID, "url", h.URL, "error", err)
+				break
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+	}
+}
+
+func post(ctx context.Context, h config.WebhookConfig, event string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gomentum/"+version.Version)
+	req.Header.Set("X-Gomentum-Event", event)
+	if h.Secret != "" {
+		req.Header.Set("X-Gomentum-Signature", Sign(h.Secret, body))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}